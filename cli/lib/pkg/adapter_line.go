@@ -0,0 +1,398 @@
+package cliadpt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type (
+	// LineFormat defines how the LineAdapter reads the lines and reports their results
+	LineFormat int
+
+	// LineConfig is the configuration of the LineAdapter
+	LineConfig struct {
+		// Reader is where the lines are read from, os.Stdin is used if nil
+		Reader io.Reader
+		// Format is LineFormatText by default
+		Format LineFormat
+		// Bindings are rules used to identify which function/use case to be invoked for each line
+		Bindings
+		// EnvGetter is the function used to get environment variables
+		EnvGetter
+		// NewFlagSet returns the FlagSet used to parse a line. A FlagSet keeps the values parsed, so a new one is
+		// needed for each line.
+		NewFlagSet func() FlagSet
+		// ArgumentsValidationDisabled enable or disable the check to find any flag in the non-flags Array
+		ArgumentsValidationDisabled bool
+		// Stdout receives the prompt, the output of the handlers and, in LineFormatNDJSON, the results. os.Stdout is
+		// used if nil.
+		Stdout io.Writer
+		// Stderr receives the handlers error output and, in LineFormatText, the line errors. os.Stderr is used if nil.
+		Stderr io.Writer
+		// Prompt returns the text written to Stdout before reading each line, it is usually set only when the Reader
+		// is a terminal (see IsTerminal)
+		Prompt func(LineState) string
+		// History receives every line run, if not nil
+		History History
+		// OnResult is invoked after each line is run and its result reported
+		OnResult func(context.Context, LineResult)
+		// StopOnError stops reading lines after the first one that does not exit with zero
+		StopOnError bool
+		// MaxLineSize is the maximum size of a line, DefaultMaxLineSize is used if zero
+		MaxLineSize int
+	}
+
+	// LineState is given to the Prompt function
+	LineState struct {
+		// Number is the number of the line to be read, starting at 1
+		Number int
+		// Continuation is true when the line continues the previous one, that has an open quote or ends with backslash
+		Continuation bool
+		// LastExitCode is the exit code of the last line run
+		LastExitCode int
+	}
+
+	// LineResult is the result of running a line
+	LineResult struct {
+		// Number is the number of the first line of the command
+		Number int
+		// Text is the line as read, including its continuations
+		Text string
+		// Args are the arguments given to the bindings
+		Args     []string
+		ExitCode int
+		Err      error
+		// Stdout and Stderr are the handler outputs, they are kept only in LineFormatNDJSON, otherwise the handlers
+		// write directly to LineConfig.Stdout and LineConfig.Stderr
+		Stdout []byte
+		Stderr []byte
+	}
+
+	// LineAdapter reads newline delimited commands, like an interactive shell, and runs each one as the command line
+	// arguments of a SingleFlagSetAdapter
+	LineAdapter struct {
+		LineConfig
+	}
+
+	// lineRecord is the LineFormatNDJSON input record when it is an object
+	lineRecord struct {
+		Args []string          `json:"args"`
+		Env  map[string]string `json:"env,omitempty"`
+	}
+
+	// lineResultRecord is the LineFormatNDJSON result
+	lineResultRecord struct {
+		Line     int    `json:"line"`
+		ExitCode int    `json:"exitCode"`
+		Stdout   string `json:"stdout,omitempty"`
+		Stderr   string `json:"stderr,omitempty"`
+		Error    string `json:"error,omitempty"`
+	}
+
+	// lineCommand is a command read, err is set when it could not be parsed
+	lineCommand struct {
+		number int
+		text   string
+		args   []string
+		env    map[string]string
+		err    error
+	}
+
+	lineRead struct {
+		text string
+		err  error
+	}
+
+	// lineReader reads one line per request, so that Run can wait for it or for the context cancellation
+	lineReader struct {
+		requests chan struct{}
+		lines    chan lineRead
+		err      error
+	}
+)
+
+const (
+	// LineFormatText reads each line as shell arguments (see SplitLine) and reports the errors to Stderr
+	LineFormatText LineFormat = iota
+	// LineFormatNDJSON reads each line as a JSON array of arguments or as an object with the "args" array and an
+	// optional "env" object that overrides the environment variables. The result of each line is written to Stdout
+	// as a JSON object with the "line", "exitCode", "stdout", "stderr" and "error" fields.
+	LineFormatNDJSON
+)
+
+const (
+	DefaultMaxLineSize = 1 << 20
+
+	// LineExitCodeFailure is the exit code of a line whose handler failed or panicked
+	LineExitCodeFailure = 1
+	// LineExitCodeUsage is the exit code of a line that could not be parsed or matched by no binding
+	LineExitCodeUsage = 2
+)
+
+func NewLineAdapter(config LineConfig) (Adapter, error) {
+	if config.Bindings == nil {
+		return nil, NewInvalidConfigError(fmt.Errorf("config.Bindings is mandatory"))
+	}
+	if config.NewFlagSet == nil {
+		return nil, NewInvalidConfigError(fmt.Errorf("config.NewFlagSet is mandatory"))
+	}
+	if config.Format != LineFormatText && config.Format != LineFormatNDJSON {
+		return nil, NewInvalidConfigError(fmt.Errorf("config.Format=[%d] is not valid", config.Format))
+	}
+	if config.Reader == nil {
+		config.Reader = os.Stdin
+	}
+	if config.EnvGetter == nil {
+		config.EnvGetter = os.LookupEnv
+	}
+	if config.Stdout == nil {
+		config.Stdout = os.Stdout
+	}
+	if config.Stderr == nil {
+		config.Stderr = os.Stderr
+	}
+	if config.MaxLineSize <= 0 {
+		config.MaxLineSize = DefaultMaxLineSize
+	}
+	return LineAdapter{LineConfig: config}, nil
+}
+
+// IsTerminal returns true if the reader is a terminal, what can be used to decide if LineConfig.Prompt is set
+func IsTerminal(reader io.Reader) bool {
+	file, ok := reader.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Run reads and runs the lines until the end of the Reader or the context cancellation and returns the exit code of
+// the last line run. The given args are prepended to the arguments of every line. A Reader that blocks, like a
+// terminal, is left with a pending read when the context is canceled.
+func (l LineAdapter) Run(ctx context.Context, args ...string) (exitCode int) {
+	reader := newLineReader(l.Reader, l.MaxLineSize)
+	defer reader.close()
+	state := LineState{}
+	for {
+		var result LineResult
+		command, err := l.read(ctx, reader, &state)
+		switch {
+		case err != nil && (errors.Is(err, io.EOF) || ctx.Err() != nil):
+			return exitCode
+		case err != nil:
+			result = LineResult{Number: state.Number, ExitCode: LineExitCodeFailure, Err: err}
+		case command.err == nil && len(command.args) == 0:
+			continue
+		default:
+			if l.History != nil && command.err == nil {
+				l.History.Add(command.text)
+			}
+			result = l.execute(ctx, command, args)
+		}
+		l.report(ctx, result)
+		exitCode, state.LastExitCode = result.ExitCode, result.ExitCode
+		if err != nil || (l.StopOnError && exitCode != 0) {
+			return exitCode
+		}
+	}
+}
+
+// read returns the next command, reading the continuation lines in LineFormatText
+func (l LineAdapter) read(ctx context.Context, reader *lineReader, state *LineState) (lineCommand, error) {
+	var text strings.Builder
+	state.Continuation = false
+	command := lineCommand{number: state.Number + 1}
+	for {
+		state.Number++
+		if l.Prompt != nil {
+			_, _ = io.WriteString(l.Stdout, l.Prompt(*state))
+		}
+		line, err := reader.next(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) && state.Continuation {
+				command.text = text.String()
+				_, command.err = SplitLine(command.text)
+				return command, nil
+			}
+			return command, err
+		}
+		if l.Format == LineFormatNDJSON {
+			command.text = line
+			command.args, command.env, command.err = parseLineRecord(line)
+			return command, nil
+		}
+		text.WriteString(line)
+		command.text = text.String()
+		command.args, command.err = SplitLine(command.text)
+		if !errors.As(command.err, &ErrIncompleteLine{}) {
+			return command, nil
+		}
+		text.WriteString("\n")
+		state.Continuation = true
+	}
+}
+
+func parseLineRecord(line string) ([]string, map[string]string, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, nil, nil
+	}
+	if strings.HasPrefix(line, "[") {
+		var args []string
+		if err := json.Unmarshal([]byte(line), &args); err != nil {
+			return nil, nil, fmt.Errorf("line.Record: %w", err)
+		}
+		return args, nil, nil
+	}
+	var record lineRecord
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil, nil, fmt.Errorf("line.Record: %w", err)
+	}
+	if len(record.Args) == 0 {
+		return nil, nil, fmt.Errorf("line.Record: args is mandatory in [%s]", line)
+	}
+	return record.Args, record.Env, nil
+}
+
+func (l LineAdapter) execute(ctx context.Context, command lineCommand, prefix []string) LineResult {
+	result := LineResult{
+		Number: command.number,
+		Text:   command.text,
+		Args:   append(append([]string(nil), prefix...), command.args...),
+	}
+	if command.err != nil {
+		result.ExitCode, result.Err = LineExitCodeUsage, command.err
+		return result
+	}
+
+	output := NewOutput()
+	output.Stdout, output.Stderr = l.Stdout, l.Stderr
+	var stdout, stderr bytes.Buffer
+	if l.Format == LineFormatNDJSON {
+		output.Stdout, output.Stderr = &stdout, &stderr
+	}
+	result.ExitCode, result.Err = l.run(ctx, result.Args, l.envGetter(command.env), output)
+	result.Stdout, result.Stderr = stdout.Bytes(), stderr.Bytes()
+	return result
+}
+
+func (l LineAdapter) envGetter(env map[string]string) EnvGetter {
+	if len(env) == 0 {
+		return l.EnvGetter
+	}
+	return func(name string) (string, bool) {
+		if value, found := env[name]; found {
+			return value, true
+		}
+		return l.EnvGetter(name)
+	}
+}
+
+// run uses a SingleFlagSetAdapter to parse the args and invoke the binding, converting its panics to errors
+func (l LineAdapter) run(ctx context.Context, args []string, envGetter EnvGetter, output *Output) (exitCode int, err error) {
+	adapter := SingleFlagSetAdapter{Config: Config{
+		OsArgsUseDisabled:           true,
+		ArgumentsValidationDisabled: l.ArgumentsValidationDisabled,
+		Bindings:                    l.Bindings,
+		EnvGetter:                   envGetter,
+		FlagSet:                     l.NewFlagSet(),
+	}}
+	input := Input{FlagSet: adapter.FlagSet, Args: args, EnvGetter: envGetter}
+	if err := recoverAsError(func() { input = adapter.parse(input, args) }); err != nil {
+		return LineExitCodeUsage, err
+	}
+	if err := recoverAsError(func() { exitCode = adapter.doRun(ctx, input, output) }); err != nil {
+		if errors.As(err, &ErrUseCaseNotFound{}) {
+			return LineExitCodeUsage, err
+		}
+		return LineExitCodeFailure, err
+	}
+	return exitCode, nil
+}
+
+func recoverAsError(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+	f()
+	return nil
+}
+
+func (l LineAdapter) report(ctx context.Context, result LineResult) {
+	if l.Format == LineFormatNDJSON {
+		record := lineResultRecord{
+			Line:     result.Number,
+			ExitCode: result.ExitCode,
+			Stdout:   string(result.Stdout),
+			Stderr:   string(result.Stderr),
+		}
+		if result.Err != nil {
+			record.Error = result.Err.Error()
+		}
+		_ = json.NewEncoder(l.Stdout).Encode(record)
+	} else if result.Err != nil {
+		_, _ = fmt.Fprintf(l.Stderr, "line %d: %v\n", result.Number, result.Err)
+	}
+	if l.OnResult != nil {
+		l.OnResult(ctx, result)
+	}
+}
+
+func newLineReader(reader io.Reader, maxLineSize int) *lineReader {
+	l := &lineReader{requests: make(chan struct{}), lines: make(chan lineRead, 1)}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, min(maxLineSize, 64*1024)), maxLineSize)
+	go func() {
+		for range l.requests {
+			if scanner.Scan() {
+				l.lines <- lineRead{text: scanner.Text()}
+				continue
+			}
+			err := scanner.Err()
+			if err == nil {
+				err = io.EOF
+			}
+			l.lines <- lineRead{err: err}
+			return
+		}
+	}()
+	return l
+}
+
+// next returns the next line, io.EOF at the end of the reader, or the context error if it is canceled first
+func (l *lineReader) next(ctx context.Context) (string, error) {
+	if l.err != nil {
+		return "", l.err
+	}
+	select {
+	case l.requests <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	select {
+	case read := <-l.lines:
+		l.err = read.err
+		return read.text, read.err
+	case <-ctx.Done():
+		l.err = ctx.Err()
+		return "", l.err
+	}
+}
+
+func (l *lineReader) close() {
+	close(l.requests)
+}
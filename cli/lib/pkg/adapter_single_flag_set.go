@@ -40,15 +40,14 @@ func (s SingleFlagSetAdapter) Run(ctx context.Context, args ...string) (exitCode
 	if err != nil {
 		panic(err)
 	}
-	return s.doRun(ctx, s.parse(input))
+	return s.doRun(ctx, s.parse(input, os.Args), NewOutput())
 }
 
-func (s SingleFlagSetAdapter) doRun(ctx context.Context, input Input) int {
+func (s SingleFlagSetAdapter) doRun(ctx context.Context, input Input, output *Output) int {
 	var (
 		useCaseFound bool
 	)
 
-	output := NewOutput()
 	for _, binding := range s.Bindings {
 		if binding.EvaluateCondition(input) {
 			if err := binding.Invoke(ctx, input, output); err != nil {
@@ -66,8 +65,8 @@ func (s SingleFlagSetAdapter) doRun(ctx context.Context, input Input) int {
 	return output.ExitActionFunc()
 }
 
-func (s SingleFlagSetAdapter) isDashDashWasUsed() bool {
-	for _, arg := range os.Args {
+func (s SingleFlagSetAdapter) isDashDashWasUsed(args []string) bool {
+	for _, arg := range args {
 		if arg == "--" {
 			return true
 		}
@@ -85,7 +84,9 @@ func (s SingleFlagSetAdapter) isThereAnyFlagInTheNonFlagsArray(input Input) erro
 	return nil
 }
 
-func (s SingleFlagSetAdapter) parse(input Input) Input {
+// parse parses the input flags and checks that no flag was given after the non-flag arguments, unless the args, the
+// command line used to get the input, has the -- separator.
+func (s SingleFlagSetAdapter) parse(input Input, args []string) Input {
 	if !input.FlagSet.Parsed() {
 		if err := input.FlagSet.Parse(input.Args); err != nil {
 			panic(err)
@@ -97,7 +98,7 @@ func (s SingleFlagSetAdapter) parse(input Input) Input {
 	}
 
 	// If -- is used to separate flags from non-flag arguments, then no additional validation is performed
-	if s.isDashDashWasUsed() {
+	if s.isDashDashWasUsed(args) {
 		return input
 	}
 
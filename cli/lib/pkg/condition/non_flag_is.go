@@ -0,0 +1,17 @@
+package condition
+
+import (
+	cliadpt "github.com/smart-libs/go-adapter/cli/lib/pkg"
+)
+
+// NonFlagIs returns true when the non-flag argument at pos is the given value, it is useful to select a binding by a
+// command name, like the first word of a line read by the LineAdapter.
+func NonFlagIs(pos int, value string) func(cliadpt.Input) bool {
+	return func(input cliadpt.Input) bool {
+		if input.FlagSet != nil {
+			args := input.FlagSet.Args()
+			return pos < len(args) && args[pos] == value
+		}
+		return false
+	}
+}
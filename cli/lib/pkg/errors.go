@@ -22,3 +22,17 @@ func (e ErrNilConfig) Error() string { return "flagset.Config is nil" }
 func (e ErrUseCaseNotFound) Error() string {
 	return fmt.Sprintf("flagset.UseCase: [%s] not found to run args=%v", e.UseCaseName, e.Args)
 }
+
+// ErrIncompleteLine is returned by SplitLine when the line ends inside a quoted string or with a backslash, so the
+// LineAdapter reads the next line as its continuation.
+type ErrIncompleteLine struct {
+	Line  string
+	Quote rune
+}
+
+func (e ErrIncompleteLine) Error() string {
+	if e.Quote != 0 {
+		return fmt.Sprintf("line.Split: unterminated %c quote in [%s]", e.Quote, e.Line)
+	}
+	return fmt.Sprintf("line.Split: line [%s] ends with backslash", e.Line)
+}
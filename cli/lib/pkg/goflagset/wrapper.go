@@ -60,4 +60,12 @@ func (f *wrapper) GetValue(flagName string) (value any, foundVar bool) {
 	return
 }
 
+// NewFactory returns a function that wraps a new flag.FlagSet on every call, as needed by cliadpt.LineConfig to
+// parse each line with its own flags
+func NewFactory(newFlagSet func() *flag.FlagSet) func() cliadpt.FlagSet {
+	return func() cliadpt.FlagSet {
+		return &wrapper{newFlagSet()}
+	}
+}
+
 var _ cliadpt.FlagSet = &wrapper{}
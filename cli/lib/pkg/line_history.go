@@ -0,0 +1,45 @@
+package cliadpt
+
+import "sync"
+
+type (
+	// History receives the lines run by the LineAdapter, a line editor can use it to recall them
+	History interface {
+		// Add appends the line to the history
+		Add(line string)
+		// Entries returns the lines in the order they were added
+		Entries() []string
+	}
+
+	// MemoryHistory keeps the last lines in memory, a line equal to the previous one is not added again
+	MemoryHistory struct {
+		mutex   sync.Mutex
+		max     int
+		entries []string
+	}
+)
+
+// NewMemoryHistory returns a MemoryHistory that keeps up to max lines, all lines are kept if max is zero
+func NewMemoryHistory(max int) *MemoryHistory {
+	return &MemoryHistory{max: max}
+}
+
+func (h *MemoryHistory) Add(line string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if n := len(h.entries); n > 0 && h.entries[n-1] == line {
+		return
+	}
+	h.entries = append(h.entries, line)
+	if h.max > 0 && len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+}
+
+func (h *MemoryHistory) Entries() []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return append([]string(nil), h.entries...)
+}
+
+var _ History = &MemoryHistory{}
@@ -0,0 +1,69 @@
+package cliadpt
+
+import "strings"
+
+// SplitLine splits the line into arguments like a POSIX shell does, but without any expansion: words are separated
+// by blanks, single quotes preserve the quoted text, double quotes preserve it except for the backslash before " and
+// \, a backslash outside quotes escapes the next character, a backslash before a new line joins the lines, and #
+// starts a comment when it begins a word. It returns ErrIncompleteLine when a quote is not closed or the line ends
+// with a backslash.
+func SplitLine(line string) ([]string, error) {
+	var (
+		args    []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+			if r == '\n' {
+				continue
+			}
+			if quote == '"' && r != '"' && r != '\\' {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			inWord = true
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case r == '#' && !inWord:
+			return args, nil
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, ErrIncompleteLine{Line: line, Quote: quote}
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
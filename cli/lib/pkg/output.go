@@ -2,16 +2,22 @@ package cliadpt
 
 import (
 	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"io"
+	"os"
 )
 
 type (
 	Output struct {
 		ExitActionFunc func() int
+		// Stdout and Stderr are the writers used by the print output params, the LineAdapter replaces them to
+		// report the output of each line
+		Stdout io.Writer
+		Stderr io.Writer
 	}
 
 	OutputSpec = sdkparam.OutputSpecs[*Output]
 )
 
 func NewOutput() *Output {
-	return &Output{ExitActionFunc: func() int { return 0 }}
+	return &Output{ExitActionFunc: func() int { return 0 }, Stdout: os.Stdout, Stderr: os.Stderr}
 }
@@ -36,22 +36,32 @@ func init() {
 		)
 }
 
-func newPrintOutParamSpec(file string, writer io.Writer, mask string, options ...sdkparam.Option) sdkparam.OutputParamSpec[*Output] {
+func newPrintOutParamSpec(file string, writer func(*Output) io.Writer, mask string, options ...sdkparam.Option) sdkparam.OutputParamSpec[*Output] {
 	specName := fmt.Sprintf("%s:%s", printOutParam, file)
 	return sdkparam.NewOutputParamSpec[*Output](sdkparam.NewSpec(specName, options...), printMessageTo(writer, mask))
 }
 
 func NewPrintStdoutOutParamSpec(mask string, options ...sdkparam.Option) sdkparam.OutputParamSpec[*Output] {
-	return newPrintOutParamSpec(printStdout, os.Stdout, mask, options...)
+	return newPrintOutParamSpec(printStdout, func(output *Output) io.Writer {
+		if output.Stdout == nil {
+			return os.Stdout
+		}
+		return output.Stdout
+	}, mask, options...)
 }
 
 func NewPrintStderrOutParamSpec(mask string, options ...sdkparam.Option) sdkparam.OutputParamSpec[*Output] {
-	return newPrintOutParamSpec(printStderr, os.Stderr, mask, options...)
+	return newPrintOutParamSpec(printStderr, func(output *Output) io.Writer {
+		if output.Stderr == nil {
+			return os.Stderr
+		}
+		return output.Stderr
+	}, mask, options...)
 }
 
-func printMessageTo(writer io.Writer, mask string) func(_ *Output, value any) error {
-	return func(_ *Output, value any) error {
-		_, err := fmt.Fprintf(writer, mask, value)
+func printMessageTo(writer func(*Output) io.Writer, mask string) func(output *Output, value any) error {
+	return func(output *Output, value any) error {
+		_, err := fmt.Fprintf(writer(output), mask, value)
 		return err
 	}
 }
@@ -0,0 +1,199 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	cliadpt "github.com/smart-libs/go-adapter/cli/lib/pkg"
+	"github.com/smart-libs/go-adapter/cli/lib/pkg/condition"
+	"github.com/smart-libs/go-adapter/cli/lib/pkg/goflagset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitLine(t *testing.T) {
+	for line, expected := range map[string][]string{
+		``:                          nil,
+		`  # only a comment`:        nil,
+		`add  1 2`:                  {"add", "1", "2"},
+		`echo 'a b' "c d" e\ f`:     {"echo", "a b", "c d", "e f"},
+		`echo "say \"hi\" \n" 'x\'`: {"echo", `say "hi" \n`, `x\`},
+		`echo '' "" a#b # comment`:  {"echo", "", "", "a#b"},
+		"echo a\\\nb \\\nc":         {"echo", "ab", "c"},
+		"echo 'a\nb'":               {"echo", "a\nb"},
+	} {
+		args, err := cliadpt.SplitLine(line)
+		if assert.NoError(t, err, line) {
+			assert.Equal(t, expected, args, line)
+		}
+	}
+	for line, quote := range map[string]rune{`echo 'a`: '\'', `echo "a`: '"', `echo a\`: 0} {
+		_, err := cliadpt.SplitLine(line)
+		var incomplete cliadpt.ErrIncompleteLine
+		if assert.ErrorAs(t, err, &incomplete, line) {
+			assert.Equal(t, quote, incomplete.Quote, line)
+		}
+	}
+}
+
+type (
+	lineTest struct {
+		stdout  bytes.Buffer
+		stderr  bytes.Buffer
+		results []cliadpt.LineResult
+		// notified receives a value after every result, if not nil
+		notified chan struct{}
+	}
+
+	sumRequest struct {
+		Verbose bool     `flag:"v"`
+		Numbers []string `non-flags:"*"`
+		Base    string   `env:"BASE"`
+	}
+
+	sumResponse struct {
+		Sum    string `print:"stdout" mask:"%s\n"`
+		Status int    `status:""`
+	}
+)
+
+func (l *lineTest) adapter(t *testing.T, input io.Reader, config cliadpt.LineConfig) cliadpt.Adapter {
+	sum := func(req sumRequest) sumResponse {
+		total := 0
+		for _, n := range req.Numbers[1:] {
+			var value int
+			if _, err := fmt.Sscan(n, &value); err != nil {
+				return sumResponse{Sum: err.Error(), Status: 3}
+			}
+			total += value
+		}
+		if req.Verbose {
+			return sumResponse{Sum: fmt.Sprintf("%s%v=%d", req.Base, req.Numbers[1:], total)}
+		}
+		return sumResponse{Sum: fmt.Sprint(req.Base, total)}
+	}
+	fail := func(req struct {
+		Args []string `non-flags:"*"`
+	}) struct {
+		Err error `error:"panic"`
+	} {
+		return struct {
+			Err error `error:"panic"`
+		}{Err: errors.New("failed: " + strings.Join(req.Args[1:], " "))}
+	}
+	config.Reader = input
+	config.Stdout, config.Stderr = &l.stdout, &l.stderr
+	config.EnvGetter = func(string) (string, bool) { return "", false }
+	config.NewFlagSet = goflagset.NewFactory(func() *flag.FlagSet {
+		flagSet := flag.NewFlagSet("line", flag.ContinueOnError)
+		flagSet.SetOutput(io.Discard)
+		flagSet.Bool("v", false, "verbose")
+		return flagSet
+	})
+	config.Bindings = cliadpt.Bindings{
+		cliadpt.NewBindingBuilderWithCondition(condition.NonFlagIs(0, "sum")).InvokeHandler(sum).Build(),
+		cliadpt.NewBindingBuilderWithCondition(condition.NonFlagIs(0, "fail")).InvokeHandler(fail).Build(),
+	}
+	config.OnResult = func(_ context.Context, result cliadpt.LineResult) {
+		l.results = append(l.results, result)
+		if l.notified != nil {
+			l.notified <- struct{}{}
+		}
+	}
+	adapter, err := cliadpt.NewLineAdapter(config)
+	require.NoError(t, err)
+	return adapter
+}
+
+func (l *lineTest) exitCodes() (codes []int) {
+	for _, result := range l.results {
+		codes = append(codes, result.ExitCode)
+	}
+	return
+}
+
+func Test_LineAdapter_Text(t *testing.T) {
+	var (
+		lines   lineTest
+		prompts []cliadpt.LineState
+	)
+	history := cliadpt.NewMemoryHistory(0)
+	input := strings.NewReader("sum 1 2\n\n-v sum 3 \\\n 4\nsum x\nfail 'a\nb'\nunknown\n-x sum\nsum 'open")
+	adapter := lines.adapter(t, input, cliadpt.LineConfig{
+		History: history,
+		Prompt: func(state cliadpt.LineState) string {
+			prompts = append(prompts, state)
+			if state.Continuation {
+				return "> "
+			}
+			return "$ "
+		},
+	})
+
+	exitCode := adapter.Run(context.Background())
+
+	assert.Equal(t, cliadpt.LineExitCodeUsage, exitCode)
+	assert.Equal(t, []int{0, 0, 3, 1, 2, 2, 2}, lines.exitCodes())
+	assert.Equal(t, "$ 3\n$ $ > [3 4]=7\n$ expected integer\n$ > $ $ $ > $ ", lines.stdout.String())
+	stderr := lines.stderr.String()
+	assert.Contains(t, stderr, "line 6: failed: a\nb\n")
+	assert.Contains(t, stderr, "line 8: flagset.UseCase")
+	assert.Contains(t, stderr, "line 9: flag provided but not defined: -x\n")
+	assert.Contains(t, stderr, "line 10: line.Split: unterminated ' quote")
+	assert.Equal(t, []string{"-v", "sum", "3", "4"}, lines.results[1].Args)
+	assert.Equal(t, 3, lines.results[1].Number)
+	assert.Equal(t, []string{"sum 1 2", "-v sum 3 \\\n 4", "sum x", "fail 'a\nb'", "unknown", "-x sum"}, history.Entries())
+	if assert.Len(t, prompts, 12) {
+		assert.Equal(t, cliadpt.LineState{Number: 4, Continuation: true}, prompts[3])
+		assert.Equal(t, cliadpt.LineState{Number: 6, LastExitCode: 3}, prompts[5])
+	}
+}
+
+func Test_LineAdapter_NDJSON(t *testing.T) {
+	var lines lineTest
+	input := strings.NewReader(`["sum","1","2"]` + "\n" +
+		`{"args":["sum","5"],"env":{"BASE":"b"}}` + "\n" +
+		`{"env":{}}` + "\n" +
+		`["sum","9"]` + "\n")
+	adapter := lines.adapter(t, input, cliadpt.LineConfig{Format: cliadpt.LineFormatNDJSON, StopOnError: true})
+
+	exitCode := adapter.Run(context.Background(), "-v")
+
+	assert.Equal(t, cliadpt.LineExitCodeUsage, exitCode)
+	assert.Equal(t, `{"line":1,"exitCode":0,"stdout":"[1 2]=3\n"}`+"\n"+
+		`{"line":2,"exitCode":0,"stdout":"b[5]=5\n"}`+"\n"+
+		`{"line":3,"exitCode":2,"error":"line.Record: args is mandatory in [{\"env\":{}}]"}`+"\n", lines.stdout.String())
+	assert.Empty(t, lines.stderr.String())
+	assert.Len(t, lines.results, 3, "StopOnError stops after the first failure")
+}
+
+func Test_LineAdapter_StopsOnContextCancellation(t *testing.T) {
+	lines := lineTest{notified: make(chan struct{})}
+	reader, writer := io.Pipe()
+	defer func() { _ = writer.Close() }()
+	adapter := lines.adapter(t, reader, cliadpt.LineConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int)
+	go func() { done <- adapter.Run(ctx) }()
+
+	for _, line := range []string{"sum 1 1\n", "sum 2 2\n"} {
+		_, err := io.WriteString(writer, line)
+		require.NoError(t, err)
+		<-lines.notified
+	}
+	cancel()
+	select {
+	case exitCode := <-done:
+		assert.Equal(t, 0, exitCode)
+		assert.Equal(t, "2\n4\n", lines.stdout.String())
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run() did not return after the context cancellation")
+	}
+}
@@ -0,0 +1,411 @@
+package fsadpt
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	sdkhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	DefaultDebounce     = 500 * time.Millisecond
+	DefaultPollInterval = time.Second
+)
+
+type (
+	Adapter interface {
+		Start(ctx context.Context) error
+		Stop(ctx context.Context) error
+	}
+
+	Config struct {
+		// Dir is the watched directory
+		Dir string
+		// Recursive watches the subdirectories too, except the ones where the MoveTo markers move the files
+		Recursive bool
+		// Bindings are the file handlers
+		Bindings
+		// Watcher is how the directory is watched, WatcherAuto if zero
+		Watcher WatcherKind
+		// PollInterval is the interval between the directory scans of WatcherPolling, DefaultPollInterval if zero
+		PollInterval time.Duration
+		// Debounce is the time without events after which a file is dispatched, DefaultDebounce if zero. It is at
+		// least twice the PollInterval when polling, so that a file is dispatched after a scan found it unchanged.
+		Debounce time.Duration
+		// Concurrency is the maximum number of handlers running at the same time, 1 if zero. The same file is never
+		// handled concurrently.
+		Concurrency int
+		// SkipExisting does not dispatch the files that exist when the adapter starts, by default they are dispatched
+		// as OpCreate events, what gets the files that landed while the adapter was stopped
+		SkipExisting bool
+		// Logger logs the handler errors, the marked files and the watcher fallback, it is slog.Default() if nil
+		Logger *slog.Logger
+	}
+
+	DefaultAdapter struct {
+		config    Config
+		tree      tree
+		semaphore chan struct{}
+
+		locker    sync.Mutex
+		started   bool
+		stopped   bool
+		watcher   watcher
+		debounce  time.Duration
+		loopDone  chan struct{}
+		runCtx    context.Context
+		cancelRun context.CancelFunc
+		runs      sync.WaitGroup
+
+		mutex    sync.Mutex
+		draining bool
+		pending  map[string]*pendingFile
+	}
+
+	// pendingFile is the dispatch state of a file, from its first event until it is handled and its events stop
+	pendingFile struct {
+		ops        Op
+		timer      *time.Timer
+		generation int
+		running    bool
+		again      bool
+		marked     bool
+		attempt    int
+	}
+
+	// outcome is what is done with the file after it is handled
+	outcome struct {
+		retry  bool
+		delay  time.Duration
+		marked bool
+	}
+)
+
+// NewAdapter creates the Adapter that dispatches the events of the files in the config directory to the bindings
+func NewAdapter(config Config) (Adapter, error) {
+	if config.Dir == "" {
+		return nil, serror.IllegalConfigParamValue("Dir", config.Dir)
+	}
+	dir, err := filepath.Abs(config.Dir)
+	if err != nil {
+		return nil, serror.IllegalConfigParamValue("Dir", config.Dir)
+	}
+	config.Dir = dir
+	config.Watcher = cmp.Or(config.Watcher, WatcherAuto)
+	if config.Watcher < WatcherAuto || config.Watcher > WatcherPolling {
+		return nil, serror.IllegalConfigParamValue("Watcher", config.Watcher)
+	}
+	config.PollInterval = cmp.Or(config.PollInterval, DefaultPollInterval)
+	config.Debounce = cmp.Or(config.Debounce, DefaultDebounce)
+	config.Concurrency = max(config.Concurrency, 1)
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	bindings := make(Bindings, 0, len(config.Bindings))
+	for _, binding := range config.Bindings {
+		if !IsBindingValid(binding) {
+			return nil, serror.IllegalConfigParamValue("Binding", binding.Glob)
+		}
+		binding.Ops = cmp.Or(binding.Ops, OpLanded)
+		binding.Attempts = max(binding.Attempts, 1)
+		bindings = append(bindings, binding)
+	}
+	config.Bindings = bindings
+
+	adapter := &DefaultAdapter{config: config, semaphore: make(chan struct{}, config.Concurrency)}
+	adapter.tree = tree{dir: dir, recursive: config.Recursive, excluded: adapter.excluded}
+	return adapter, nil
+}
+
+// Start watches the directory in background and dispatches the existing files, unless Config.SkipExisting is set
+func (d *DefaultAdapter) Start(ctx context.Context) error {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	if d.stopped {
+		return fmt.Errorf("adapter already stopped")
+	}
+	if d.started {
+		return fmt.Errorf("adapter already started")
+	}
+
+	w, kind, err := d.newWatcher()
+	if err != nil {
+		return err
+	}
+	d.watcher, d.debounce = w, d.config.Debounce
+	if kind == WatcherPolling {
+		d.debounce = max(d.debounce, 2*d.config.PollInterval)
+	}
+	d.runCtx, d.cancelRun = context.WithCancel(context.WithoutCancel(ctx))
+	d.pending = map[string]*pendingFile{}
+	d.loopDone = make(chan struct{})
+	go d.loop(w.events())
+	d.started = true
+
+	if !d.config.SkipExisting {
+		d.dispatchExisting()
+	}
+	return nil
+}
+
+// Stop stops watching, drops the files waiting for the debounce or a retry, and waits for the handlers in progress
+// until the context is done, then their context is canceled. The dropped files are dispatched again on the next
+// start, unless Config.SkipExisting is set.
+func (d *DefaultAdapter) Stop(ctx context.Context) error {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	if !d.started || d.stopped {
+		d.stopped = true
+		return nil
+	}
+	d.stopped = true
+
+	d.mutex.Lock()
+	d.draining = true
+	for _, p := range d.pending {
+		if p.timer != nil {
+			p.timer.Stop()
+		}
+	}
+	d.mutex.Unlock()
+	d.watcher.close()
+	<-d.loopDone
+
+	done := make(chan struct{})
+	go func() {
+		d.runs.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	d.cancelRun()
+	return err
+}
+
+func (d *DefaultAdapter) newWatcher() (watcher, WatcherKind, error) {
+	if d.config.Watcher != WatcherPolling {
+		w, err := newInotifyWatcher(d.tree)
+		if err == nil {
+			return w, WatcherInotify, nil
+		}
+		if d.config.Watcher == WatcherInotify {
+			return nil, 0, err
+		}
+		d.config.Logger.Warn("fsadpt: inotify not available, polling the directory", slog.String("dir", d.config.Dir),
+			slog.Any("error", err))
+	}
+	w, err := newPollingWatcher(d.tree, d.config.PollInterval)
+	return w, WatcherPolling, err
+}
+
+// excluded returns true for the directories where the bindings markers move the files
+func (d *DefaultAdapter) excluded(name string) bool {
+	for _, binding := range d.config.Bindings {
+		for _, marker := range []Marker{binding.Done, binding.Failed} {
+			if move, ok := marker.(moveMarker); ok && move.excludes(d.config.Dir, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (d *DefaultAdapter) loop(events <-chan rawEvent) {
+	defer close(d.loopDone)
+	for event := range events {
+		if event.overflow {
+			d.config.Logger.Warn("fsadpt: events lost, rescanning the directory", slog.String("dir", d.config.Dir))
+			d.dispatchExisting()
+			continue
+		}
+		d.notify(event.name, event.op)
+	}
+}
+
+// dispatchExisting notifies the files in the directory as created
+func (d *DefaultAdapter) dispatchExisting() {
+	files, err := d.tree.scan()
+	if err != nil {
+		d.config.Logger.Error("fsadpt: directory scan failed", slog.String("dir", d.config.Dir), slog.Any("error", err))
+		return
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		d.notify(name, OpCreate)
+	}
+}
+
+// notify adds the operation to the file and restarts its debounce, unless the file is being handled: in this case it
+// is dispatched again after the handler returns
+func (d *DefaultAdapter) notify(name string, op Op) {
+	if !d.config.Bindings.matchName(name) {
+		return
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.draining {
+		return
+	}
+	p := d.pending[name]
+	if p == nil {
+		p = &pendingFile{}
+		d.pending[name] = p
+	}
+	if p.marked {
+		if op&OpLanded == 0 {
+			return // the marker moved or renamed the file
+		}
+		p.marked = false
+	}
+	p.ops |= op
+	if p.running {
+		p.again = true
+		return
+	}
+	d.arm(name, p, d.debounce)
+}
+
+// arm dispatches the file after the delay, replacing the previous timer
+func (d *DefaultAdapter) arm(name string, p *pendingFile, delay time.Duration) {
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.generation++
+	generation := p.generation
+	p.timer = time.AfterFunc(delay, func() { d.dispatch(name, generation) })
+}
+
+func (d *DefaultAdapter) dispatch(name string, generation int) {
+	d.mutex.Lock()
+	p := d.pending[name]
+	if d.draining || p == nil || p.generation != generation || p.running {
+		d.mutex.Unlock()
+		return
+	}
+	p.timer = nil
+	if p.ops == 0 {
+		delete(d.pending, name)
+		d.mutex.Unlock()
+		return
+	}
+	p.attempt++
+	event := Event{
+		Dir:     d.config.Dir,
+		Name:    name,
+		Path:    filepath.Join(d.config.Dir, filepath.FromSlash(name)),
+		Op:      p.ops,
+		Attempt: p.attempt,
+	}
+	p.ops, p.running = 0, true
+	d.runs.Add(1)
+	d.mutex.Unlock()
+
+	defer d.runs.Done()
+	select {
+	case d.semaphore <- struct{}{}:
+	case <-d.runCtx.Done():
+		d.finish(event, outcome{})
+		return
+	}
+	defer func() { <-d.semaphore }()
+	d.finish(event, d.handle(event))
+}
+
+// handle invokes the binding that matches the event and marks the file as processed or failed
+func (d *DefaultAdapter) handle(event Event) outcome {
+	event, exists := d.stat(event)
+	binding, found := d.config.Bindings.find(event)
+	if !found {
+		return outcome{}
+	}
+	result := &Result{}
+	if err := sdkhandler.Invoke(d.runCtx, binding.Handler, event, result); err != nil {
+		_ = NewOutErrorParamSpec().SetValue(result, err)
+	}
+
+	marker := binding.Done
+	if result.Err != nil {
+		d.config.Logger.Error("fsadpt: file handler failed", slog.String("path", event.Path),
+			slog.String("op", event.Op.String()), slog.Int("attempt", event.Attempt), slog.Any("error", result.Err))
+		if event.Attempt < binding.Attempts {
+			return outcome{retry: true, delay: binding.RetryDelay}
+		}
+		marker = binding.Failed
+	}
+	if marker == nil || !exists {
+		return outcome{}
+	}
+	target, err := marker.Mark(event)
+	if err != nil {
+		d.config.Logger.Error("fsadpt: file mark failed", slog.String("path", event.Path), slog.Any("marker", marker),
+			slog.Any("error", err))
+		return outcome{}
+	}
+	d.config.Logger.Debug("fsadpt: file marked", slog.String("path", event.Path), slog.String("target", target))
+	return outcome{marked: true}
+}
+
+// stat sets the file size and modification time and keeps in the event only the operations consistent with the
+// file existence
+func (d *DefaultAdapter) stat(event Event) (Event, bool) {
+	info, err := os.Stat(event.Path)
+	if err != nil || !info.Mode().IsRegular() {
+		event.Op &^= OpLanded
+		return event, false
+	}
+	event.Op &^= OpRemove | OpRename
+	if event.Op == 0 {
+		event.Op = OpCreate // removed and then created again
+	}
+	event.Size, event.ModTime = info.Size(), info.ModTime()
+	return event, true
+}
+
+// finish dispatches the file again if it changed while it was handled or if it must be retried, otherwise it waits
+// for the debounce to forget it, ignoring the events caused by the marker
+func (d *DefaultAdapter) finish(event Event, result outcome) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	p := d.pending[event.Name]
+	p.running = false
+	if d.draining {
+		return
+	}
+	if result.retry {
+		p.ops |= event.Op
+	} else {
+		p.attempt = 0
+	}
+	if p.marked = result.marked; p.marked {
+		p.ops &^= OpRemove | OpRename
+		p.again = p.again && p.ops != 0
+	}
+	switch {
+	case p.again:
+		p.again = false
+		d.arm(event.Name, p, d.debounce)
+	case result.retry:
+		d.arm(event.Name, p, result.delay)
+	case result.marked:
+		d.arm(event.Name, p, d.debounce)
+	default:
+		delete(d.pending, event.Name)
+	}
+}
@@ -0,0 +1,240 @@
+package fsadpt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fileInput struct {
+	Path    string    `fspath:""`
+	Name    string    `fsname:""`
+	Op      string    `fsop:""`
+	Size    int64     `fssize:""`
+	ModTime time.Time `fsmodtime:""`
+	Attempt int       `attempt:""`
+}
+
+var (
+	testLogger   = slog.New(slog.NewTextHandler(io.Discard, nil))
+	testWatchers = []WatcherKind{WatcherInotify, WatcherPolling}
+)
+
+func startTestAdapter(t *testing.T, config Config) Adapter {
+	t.Helper()
+	config.Debounce = 50 * time.Millisecond
+	config.PollInterval = 20 * time.Millisecond
+	config.Logger = testLogger
+	adapter, err := NewAdapter(config)
+	if err != nil {
+		t.Fatalf("NewAdapter() error = %v", err)
+	}
+	if err := adapter.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = adapter.Stop(context.Background()) })
+	return adapter
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitFor[T any](t *testing.T, values <-chan T) T {
+	t.Helper()
+	select {
+	case value := <-values:
+		return value
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for the handler")
+	}
+	var zero T
+	return zero
+}
+
+func expectNothing[T any](t *testing.T, values <-chan T) {
+	t.Helper()
+	select {
+	case value := <-values:
+		t.Errorf("unexpected handler call with %v", value)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func waitForFile(t *testing.T, path string) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+	}
+	t.Fatalf("file %s not found", path)
+}
+
+func Test_Adapter_LandedFiles(t *testing.T) {
+	for _, kind := range testWatchers {
+		t.Run(kind.String(), func(t *testing.T) {
+			dir := t.TempDir()
+			received := make(chan fileInput, 10)
+			startTestAdapter(t, Config{Dir: dir, Recursive: true, Watcher: kind, Bindings: Bindings{
+				NewBindingBuilderUsingGlob("in/*.csv").WithDone(MoveTo("done")).
+					WithHandlerFunc(func(in fileInput) error {
+						received <- in
+						return nil
+					}),
+			}})
+
+			path := filepath.Join(dir, "in", "orders.csv")
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			f, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for range 3 { // the writes are debounced
+				_, _ = f.WriteString("id;total\n")
+				time.Sleep(10 * time.Millisecond)
+			}
+			_ = f.Close()
+			writeFile(t, filepath.Join(dir, "in", "orders.txt"), "ignored")
+			writeFile(t, filepath.Join(dir, "orders.csv"), "ignored")
+
+			in := waitFor(t, received)
+			if in.Path != path || in.Name != "in/orders.csv" || in.Size != 27 || in.Attempt != 1 || in.ModTime.IsZero() {
+				t.Errorf("input = %+v", in)
+			}
+			if in.Op != "create" && in.Op != "create|write" {
+				t.Errorf("op = %q, want create", in.Op)
+			}
+			waitForFile(t, filepath.Join(dir, "done", "in", "orders.csv"))
+			expectNothing(t, received)
+		})
+	}
+}
+
+func Test_Adapter_ExistingFiles(t *testing.T) {
+	for _, skip := range []bool{false, true} {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "a.json"), "{}")
+		writeFile(t, filepath.Join(dir, "sub", "b.json"), "{}")
+		received := make(chan fileInput, 10)
+		startTestAdapter(t, Config{Dir: dir, SkipExisting: skip, Bindings: Bindings{
+			NewBindingBuilderUsingGlob("*.json").WithHandlerFunc(func(in fileInput) {
+				received <- in
+			}),
+		}})
+		if skip {
+			expectNothing(t, received)
+			continue
+		}
+		if in := waitFor(t, received); in.Name != "a.json" || in.Op != "create" {
+			t.Errorf("input = %+v", in)
+		}
+		expectNothing(t, received) // the subdirectories are not watched
+	}
+}
+
+func Test_Adapter_RetryAndFailed(t *testing.T) {
+	for _, kind := range testWatchers {
+		t.Run(kind.String(), func(t *testing.T) {
+			dir := t.TempDir()
+			attempts := make(chan int, 10)
+			startTestAdapter(t, Config{Dir: dir, Watcher: kind, Bindings: Bindings{
+				NewBindingBuilderUsingGlob("*.xml").
+					WithRetry(3, 10*time.Millisecond).
+					WithDone(MoveTo("done")).
+					WithFailed(RenameWithSuffix(".failed")).
+					WithHandlerFunc(func(in fileInput) error {
+						attempts <- in.Attempt
+						return errors.New("invalid file")
+					}),
+			}})
+
+			writeFile(t, filepath.Join(dir, "feed.xml"), "<feed/>")
+			for expected := 1; expected <= 3; expected++ {
+				if attempt := waitFor(t, attempts); attempt != expected {
+					t.Errorf("attempt = %d, want %d", attempt, expected)
+				}
+			}
+			waitForFile(t, filepath.Join(dir, "feed.xml.failed"))
+			expectNothing(t, attempts)
+		})
+	}
+}
+
+func Test_Adapter_RemovedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "lock.pid"), "1")
+	received := make(chan fileInput, 10)
+	startTestAdapter(t, Config{Dir: dir, SkipExisting: true, Bindings: Bindings{
+		NewBindingBuilderUsingGlob("*.pid").WithOps(OpRemove | OpRename).WithHandlerFunc(func(in fileInput) {
+			received <- in
+		}),
+	}})
+
+	writeFile(t, filepath.Join(dir, "other.pid"), "2")
+	if err := os.Remove(filepath.Join(dir, "lock.pid")); err != nil {
+		t.Fatal(err)
+	}
+	if in := waitFor(t, received); in.Name != "lock.pid" || in.Op != "remove" || in.Size != 0 {
+		t.Errorf("input = %+v", in)
+	}
+	expectNothing(t, received)
+}
+
+func Test_Adapter_StopWaitsForHandlers(t *testing.T) {
+	dir := t.TempDir()
+	started, release := make(chan struct{}), make(chan struct{})
+	var finished atomic.Bool
+	adapter := startTestAdapter(t, Config{Dir: dir, Bindings: Bindings{
+		NewBindingBuilderUsingGlob("*").WithHandlerFunc(func() {
+			close(started)
+			<-release
+			finished.Store(true)
+		}),
+	}})
+	writeFile(t, filepath.Join(dir, "slow.bin"), "x")
+	waitFor(t, started)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	if err := adapter.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+	if !finished.Load() {
+		t.Error("Stop() returned before the handler")
+	}
+}
+
+func Test_NewAdapter_InvalidConfig(t *testing.T) {
+	handler := NewBindingBuilderUsingGlob("*").WithHandlerFunc(func() {})
+	for name, config := range map[string]Config{
+		"no dir":       {Bindings: Bindings{handler}},
+		"bad glob":     {Dir: ".", Bindings: Bindings{NewBindingBuilderUsingGlob("[").WithHandlerFunc(func() {})}},
+		"no handler":   {Dir: ".", Bindings: Bindings{{Condition: Condition{Glob: "*"}}}},
+		"watcher kind": {Dir: ".", Watcher: WatcherPolling + 1, Bindings: Bindings{handler}},
+	} {
+		if _, err := NewAdapter(config); err == nil {
+			t.Errorf("%s: NewAdapter() error = nil", name)
+		}
+	}
+	adapter, _ := NewAdapter(Config{Dir: filepath.Join(t.TempDir(), "missing"), Bindings: Bindings{handler}})
+	if err := adapter.Start(context.Background()); err == nil {
+		t.Error("Start() on a missing directory error = nil")
+	}
+}
@@ -0,0 +1,77 @@
+package fsadpt
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+type (
+	// Condition identifies the files whose events are handled by the binding
+	Condition struct {
+		// Glob is matched against the file name when it has no /, otherwise against the file path relative to the
+		// watched directory, see path.Match
+		Glob string
+		// Ops are the operations handled, OpLanded if zero. The event is dispatched if it has any of them.
+		Ops Op
+	}
+
+	Binding struct {
+		Condition
+		Handler
+
+		// Done marks the file after the handler succeeds, the file is left as is if nil
+		Done Marker
+		// Failed marks the file after the last attempt fails, the file is left as is if nil, so it is dispatched
+		// again on its next change or when the adapter restarts
+		Failed Marker
+		// Attempts is the maximum number of times a file is dispatched while the handler fails, 1 if zero
+		Attempts int
+		// RetryDelay is the time between the attempts
+		RetryDelay time.Duration
+	}
+
+	// Bindings are the file handlers, an event is dispatched to the first binding whose condition matches it
+	Bindings []Binding
+)
+
+func IsBindingValid(binding Binding) bool {
+	if binding.Handler == nil || binding.Glob == "" {
+		return false
+	}
+	_, err := path.Match(binding.Glob, "")
+	return err == nil
+}
+
+// matchName returns true if the glob matches the file name, relative to the watched directory
+func (c Condition) matchName(name string) bool {
+	if !strings.Contains(c.Glob, "/") {
+		name = path.Base(name)
+	}
+	matched, _ := path.Match(c.Glob, name)
+	return matched
+}
+
+func (c Condition) match(event Event) bool {
+	return event.Op&c.Ops != 0 && c.matchName(event.Name)
+}
+
+// find returns the first binding whose condition matches the event
+func (b Bindings) find(event Event) (Binding, bool) {
+	for _, binding := range b {
+		if binding.match(event) {
+			return binding, true
+		}
+	}
+	return Binding{}, false
+}
+
+// matchName returns true if any binding glob matches the file name
+func (b Bindings) matchName(name string) bool {
+	for _, binding := range b {
+		if binding.matchName(name) {
+			return true
+		}
+	}
+	return false
+}
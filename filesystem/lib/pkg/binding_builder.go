@@ -0,0 +1,59 @@
+package fsadpt
+
+import (
+	"time"
+
+	tagbasedhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler/tagbased"
+)
+
+type (
+	HandlerBuildingStep interface {
+		// WithOps sets the operations handled, OpLanded by default
+		WithOps(ops Op) HandlerBuildingStep
+		// WithDone sets how the file is marked after the handler succeeds
+		WithDone(marker Marker) HandlerBuildingStep
+		// WithFailed sets how the file is marked after the last attempt fails
+		WithFailed(marker Marker) HandlerBuildingStep
+		// WithRetry sets the maximum number of attempts and the delay between them
+		WithRetry(attempts int, delay time.Duration) HandlerBuildingStep
+		WithHandlerFunc(handler any) Binding
+	}
+
+	BaseBuilder struct {
+		Binding
+	}
+)
+
+// NewBindingBuilderUsingGlob creates the builder of a binding for the files that match the glob, see Condition
+func NewBindingBuilderUsingGlob(glob string) HandlerBuildingStep {
+	return &BaseBuilder{Binding: Binding{Condition: Condition{Glob: glob}}}
+}
+
+func (b *BaseBuilder) WithOps(ops Op) HandlerBuildingStep {
+	b.Ops = ops
+	return b
+}
+
+func (b *BaseBuilder) WithDone(marker Marker) HandlerBuildingStep {
+	b.Done = marker
+	return b
+}
+
+func (b *BaseBuilder) WithFailed(marker Marker) HandlerBuildingStep {
+	b.Failed = marker
+	return b
+}
+
+func (b *BaseBuilder) WithRetry(attempts int, delay time.Duration) HandlerBuildingStep {
+	b.Attempts, b.RetryDelay = attempts, delay
+	return b
+}
+
+func (b *BaseBuilder) WithHandlerFunc(handler any) Binding {
+	b.Handler = tagbasedhandler.NewBuilderForFunc[Event, *Result](handler).
+		WithInTagBasedFactory(createInParamSpecFactory()).
+		WithOutTagBasedFactory(createOutParamSpecFactory()).
+		WithOutErrorParamSpec(NewOutErrorParamSpec()).
+		Build()
+	return b.Binding
+}
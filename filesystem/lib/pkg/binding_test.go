@@ -0,0 +1,51 @@
+package fsadpt
+
+import "testing"
+
+func Test_Condition_MatchName(t *testing.T) {
+	for _, test := range []struct {
+		glob    string
+		name    string
+		matched bool
+	}{
+		{"*.csv", "orders.csv", true},
+		{"*.csv", "in/orders.csv", true},
+		{"*.csv", "orders.csv.done", false},
+		{"in/*.csv", "in/orders.csv", true},
+		{"in/*.csv", "orders.csv", false},
+		{"in/*.csv", "in/2026/orders.csv", false},
+		{"in/*/*.csv", "in/2026/orders.csv", true},
+	} {
+		if matched := (Condition{Glob: test.glob}).matchName(test.name); matched != test.matched {
+			t.Errorf("Glob %q matchName(%q) = %v, want %v", test.glob, test.name, matched, test.matched)
+		}
+	}
+}
+
+func Test_Op(t *testing.T) {
+	op := OpCreate | OpWrite
+	if op.String() != "create|write" || !op.Has(OpCreate) || op.Has(OpCreate|OpRemove) || op.Has(0) {
+		t.Errorf("Op %v", op)
+	}
+	if (OpRemove | OpRename).String() != "remove|rename" {
+		t.Errorf("Op %v", OpRemove|OpRename)
+	}
+}
+
+func Test_MoveTo_Excludes(t *testing.T) {
+	marker := MoveTo("archive/done").(moveMarker)
+	for name, excluded := range map[string]bool{
+		"archive/done":     true,
+		"archive/done/a":   true,
+		"archive":          false,
+		"archive/done2":    false,
+		"archive/done2/in": false,
+	} {
+		if marker.excludes("/data", name) != excluded {
+			t.Errorf("excludes(%q) = %v", name, !excluded)
+		}
+	}
+	if MoveTo("/elsewhere").(moveMarker).excludes("/data", "elsewhere") {
+		t.Error("an absolute dir outside the watched one excludes nothing")
+	}
+}
@@ -0,0 +1,25 @@
+package fsadpt
+
+import (
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	converterdefault "github.com/smart-libs/go-crosscutting/converter/lib/pkg/default"
+)
+
+var (
+	// ConverterRegistry it is the default converters registry for the file system adapter
+	ConverterRegistry = converterdefault.NewRegistry()
+
+	// Converters is the list of converter.Converters used by the file system adapter, it tries first the file system
+	// adapter conversions and then the default converter.Converters
+	Converters = converter.NewConvertersList(
+		converterdefault.NewConverters(ConverterRegistry),
+		converterdefault.Converters,
+	)
+)
+
+func init() {
+	converter.AddHandler[Op, string](ConverterRegistry, func(op Op, target *string) error {
+		*target = op.String()
+		return nil
+	})
+}
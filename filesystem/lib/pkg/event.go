@@ -0,0 +1,67 @@
+package fsadpt
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// OpCreate is a file created or moved into the directory
+	OpCreate Op = 1 << iota
+	// OpWrite is a file whose content changed
+	OpWrite
+	// OpRemove is a file removed
+	OpRemove
+	// OpRename is a file moved out of the directory or renamed, the event has its old name
+	OpRename
+
+	// OpLanded is the default of the bindings: a file created or changed, what happens when a file lands
+	OpLanded = OpCreate | OpWrite
+)
+
+type (
+	// Op is the set of operations done to a file since it was dispatched for the last time
+	Op uint32
+
+	// Event is the input of the file system handlers
+	Event struct {
+		// Dir is the watched directory, Config.Dir as an absolute path
+		Dir string
+		// Name is the file path relative to Dir, using / as separator
+		Name string
+		// Path is the absolute path of the file
+		Path string
+		// Op is the set of operations done to the file since the last event, the debounce merges them
+		Op Op
+		// Size is the file size, zero if it was removed
+		Size int64
+		// ModTime is the file modification time, zero if it was removed
+		ModTime time.Time
+		// Attempt is 1 on the first dispatch of the file and it is incremented on each retry
+		Attempt int
+	}
+
+	// Result is the output of the file system handlers, it is set from the handler error by OutErrorParamSpec
+	Result struct {
+		// Err is the handler error, nil marks the file as processed and an error retries it
+		Err error
+	}
+)
+
+// Has returns true if o has all the given operations
+func (o Op) Has(op Op) bool {
+	return o&op == op && op != 0
+}
+
+func (o Op) String() string {
+	var names []string
+	for _, op := range []struct {
+		Op
+		name string
+	}{{OpCreate, "create"}, {OpWrite, "write"}, {OpRemove, "remove"}, {OpRename, "rename"}} {
+		if o&op.Op != 0 {
+			names = append(names, op.name)
+		}
+	}
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,27 @@
+package fsadpt
+
+import (
+	"context"
+
+	sdkhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler"
+)
+
+type (
+	funcBasedHandler struct {
+		invokeFunc func(ctx context.Context, input Event, output *Result) error
+	}
+
+	Handler = sdkhandler.Handler[Event, *Result]
+)
+
+func (f funcBasedHandler) Invoke(ctx context.Context, input Event, output *Result) error {
+	return f.invokeFunc(ctx, input, output)
+}
+
+// MakeHandler adapts a function to Handler
+func MakeHandler(invoker func(ctx context.Context, input Event, output *Result) error) Handler {
+	if invoker == nil {
+		invoker = func(context.Context, Event, *Result) error { return nil }
+	}
+	return funcBasedHandler{invokeFunc: invoker}
+}
@@ -0,0 +1,81 @@
+package fsadpt
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	// Marker marks a file as processed, or failed, so that it is not dispatched again, even after a restart. The
+	// file is marked only after the handler returns, so a crash before it makes the file be dispatched again: the
+	// handlers must be idempotent.
+	Marker interface {
+		// Mark moves or renames the event file and returns its new path
+		Mark(event Event) (string, error)
+	}
+
+	moveMarker struct {
+		dir string
+	}
+
+	suffixMarker struct {
+		suffix string
+	}
+)
+
+// MoveTo returns the Marker that moves the file to dir, relative to the watched directory unless it is absolute,
+// keeping the file path relative to the watched directory. A file with the same name in dir is replaced. The dir is
+// not watched.
+func MoveTo(dir string) Marker {
+	if dir == "" {
+		panic(serror.IllegalArgumentValue("dir", dir))
+	}
+	return moveMarker{dir: dir}
+}
+
+// RenameWithSuffix returns the Marker that appends the suffix to the file name, the bindings globs must not match
+// the renamed file
+func RenameWithSuffix(suffix string) Marker {
+	if suffix == "" {
+		panic(serror.IllegalArgumentValue("suffix", suffix))
+	}
+	return suffixMarker{suffix: suffix}
+}
+
+func (m moveMarker) Mark(event Event) (string, error) {
+	target := filepath.Join(m.root(event.Dir), filepath.FromSlash(event.Name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", err
+	}
+	return target, os.Rename(event.Path, target)
+}
+
+func (m moveMarker) root(dir string) string {
+	if filepath.IsAbs(m.dir) {
+		return m.dir
+	}
+	return filepath.Join(dir, m.dir)
+}
+
+// excludes returns true if the file name, relative to the watched directory, is inside the marker directory
+func (m moveMarker) excludes(dir, name string) bool {
+	relative, err := filepath.Rel(dir, m.root(dir))
+	if err != nil || relative == ".." || strings.HasPrefix(relative, "../") {
+		return false
+	}
+	relative = filepath.ToSlash(relative)
+	return name == relative || strings.HasPrefix(name, relative+"/")
+}
+
+func (m moveMarker) String() string { return "move to " + path.Clean(m.dir) }
+
+func (s suffixMarker) Mark(event Event) (string, error) {
+	target := event.Path + s.suffix
+	return target, os.Rename(event.Path, target)
+}
+
+func (s suffixMarker) String() string { return "rename with suffix " + s.suffix }
@@ -0,0 +1,26 @@
+package fsadpt
+
+const (
+	// TagPath gets the absolute path of the file
+	TagPath = "fspath"
+	// TagName gets the file path relative to the watched directory
+	TagName = "fsname"
+	// TagOp gets the operations done to the file, as Op or as a string like "create|write"
+	TagOp = "fsop"
+	// TagSize gets the file size
+	TagSize = "fssize"
+	// TagModTime gets the file modification time
+	TagModTime = "fsmodtime"
+	// TagAttempt gets the dispatch number of the file, 1 on the first one
+	TagAttempt = "attempt"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().
+		AddOption1(TagPath, "", func(input Event) (any, error) { return input.Path, nil }).
+		AddOption1(TagName, "", func(input Event) (any, error) { return input.Name, nil }).
+		AddOption1(TagOp, "", func(input Event) (any, error) { return input.Op, nil }).
+		AddOption1(TagSize, "", func(input Event) (any, error) { return input.Size, nil }).
+		AddOption1(TagModTime, "", func(input Event) (any, error) { return input.ModTime, nil }).
+		AddOption1(TagAttempt, "", func(input Event) (any, error) { return input.Attempt, nil })
+}
@@ -0,0 +1,21 @@
+package fsadpt
+
+import (
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+)
+
+var (
+	inParamSpecFactoryRegistry tagbased.InputParamSpecFactoryRegistry[Event]
+)
+
+func getInputParamSpecFactoryRegistry() tagbased.InputParamSpecFactoryRegistry[Event] {
+	if inParamSpecFactoryRegistry == nil {
+		inParamSpecFactoryRegistry = tagbased.NewInputParamSpecFactoryRegistry[Event](Converters)
+	}
+
+	return inParamSpecFactoryRegistry
+}
+
+func createInParamSpecFactory() tagbased.InputParamSpecFactory[Event] {
+	return tagbased.NewsInputParamSpecFactory(getInputParamSpecFactoryRegistry())
+}
@@ -0,0 +1,32 @@
+package fsadpt
+
+import (
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"github.com/smart-libs/go-crosscutting/assertions/lib/pkg/check"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	OutErrorParamSpec struct{}
+)
+
+func (o OutErrorParamSpec) Name() string               { return "error" }
+func (o OutErrorParamSpec) Options() []sdkparam.Option { return nil }
+
+// SetValue sets the Result error, it is logged by the adapter and it decides if the file is retried or marked
+func (o OutErrorParamSpec) SetValue(output *Result, value any) error {
+	if check.IsNil(value) {
+		return nil // no error
+	}
+	if output == nil {
+		return serror.CmpError.New("fsadpt.OutErrorParamSpec.SetValue: output is nil")
+	}
+	if err, ok := value.(error); ok {
+		output.Err = err
+	}
+	return nil
+}
+
+func NewOutErrorParamSpec() sdkparam.OutputParamSpec[*Result] {
+	return OutErrorParamSpec{}
+}
@@ -0,0 +1,95 @@
+package fsadpt
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// WatcherAuto uses inotify on Linux and polling on the other systems or when inotify fails, it is the default
+	WatcherAuto WatcherKind = iota + 1
+	// WatcherInotify uses the Linux inotify API
+	WatcherInotify
+	// WatcherPolling scans the directory at each Config.PollInterval, it works on any file system, including the
+	// network ones that do not support inotify
+	WatcherPolling
+)
+
+type (
+	// WatcherKind is how the directory is watched
+	WatcherKind int
+
+	// watcher notifies the file events of the tree until it is closed, then it closes the events channel
+	watcher interface {
+		events() <-chan rawEvent
+		close()
+	}
+
+	// rawEvent is a file event before the debounce, overflow means that events were lost
+	rawEvent struct {
+		name     string
+		op       Op
+		overflow bool
+	}
+
+	// tree is the watched directory, excluded returns true for the directory names that must not be watched
+	tree struct {
+		dir       string
+		recursive bool
+		excluded  func(name string) bool
+	}
+
+	fileState struct {
+		size    int64
+		modTime time.Time
+	}
+)
+
+// scan returns the state of the regular files of the tree by their names
+func (t tree) scan() (map[string]fileState, error) {
+	files := map[string]fileState{}
+	err := filepath.WalkDir(t.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == t.dir {
+				return err
+			}
+			return nil // removed while walking
+		}
+		name := t.name(path)
+		if entry.IsDir() {
+			if path != t.dir && (!t.recursive || t.excluded(name)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := entry.Info(); err == nil && info.Mode().IsRegular() {
+			files[name] = fileState{size: info.Size(), modTime: info.ModTime()}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// name returns the path relative to the tree directory using / as separator
+func (t tree) name(path string) string {
+	name, err := filepath.Rel(t.dir, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(name)
+}
+
+func (w WatcherKind) String() string {
+	switch w {
+	case WatcherAuto:
+		return "auto"
+	case WatcherInotify:
+		return "inotify"
+	case WatcherPolling:
+		return "polling"
+	default:
+		return fmt.Sprintf("WatcherKind(%d)", int(w))
+	}
+}
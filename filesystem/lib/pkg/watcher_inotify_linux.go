@@ -0,0 +1,137 @@
+//go:build linux
+
+package fsadpt
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	inotifyFileMask = syscall.IN_CREATE | syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_MOVED_TO |
+		syscall.IN_MOVED_FROM | syscall.IN_DELETE
+	inotifyBufferSize = 64 * 1024
+)
+
+type (
+	// inotifyWatcher watches the tree directories with inotify, the subdirectories created later are added too
+	inotifyWatcher struct {
+		tree
+		fd      int
+		file    *os.File
+		watches map[int32]string
+		out     chan rawEvent
+	}
+)
+
+func newInotifyWatcher(t tree) (watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	// the file is nonblocking, so Read uses the runtime poller and Close interrupts it
+	w := &inotifyWatcher{tree: t, fd: fd, file: os.NewFile(uintptr(fd), "inotify"), watches: map[int32]string{},
+		out: make(chan rawEvent, 64)}
+	if _, err := w.add("."); err != nil {
+		_ = w.file.Close()
+		return nil, err
+	}
+	go w.read()
+	return w, nil
+}
+
+func (w *inotifyWatcher) events() <-chan rawEvent { return w.out }
+
+func (w *inotifyWatcher) close() { _ = w.file.Close() }
+
+// add watches the directory, and its subdirectories if the tree is recursive, returning the files found in them
+func (w *inotifyWatcher) add(name string) ([]string, error) {
+	wd, err := syscall.InotifyAddWatch(w.fd, filepath.Join(w.dir, filepath.FromSlash(name)), inotifyFileMask|syscall.IN_ONLYDIR)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_add_watch", err)
+	}
+	w.watches[int32(wd)] = name
+	if !w.recursive && name != "." {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(filepath.Join(w.dir, filepath.FromSlash(name)))
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		child := path.Join(name, entry.Name())
+		switch {
+		case !entry.IsDir():
+			files = append(files, child)
+		case w.recursive && !w.excluded(child):
+			found, err := w.add(child)
+			if err != nil && name == "." {
+				return nil, err
+			}
+			files = append(files, found...)
+		}
+	}
+	return files, nil
+}
+
+func (w *inotifyWatcher) read() {
+	defer close(w.out)
+	buffer := make([]byte, inotifyBufferSize)
+	for {
+		n, err := w.file.Read(buffer)
+		if err != nil {
+			return
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buffer[offset]))
+			start := offset + syscall.SizeofInotifyEvent
+			offset = start + int(event.Len)
+			w.handle(event.Wd, event.Mask, strings.TrimRight(string(buffer[start:offset]), "\x00"))
+		}
+	}
+}
+
+func (w *inotifyWatcher) handle(wd int32, mask uint32, fileName string) {
+	if mask&syscall.IN_Q_OVERFLOW != 0 {
+		w.out <- rawEvent{overflow: true}
+		return
+	}
+	if mask&syscall.IN_IGNORED != 0 {
+		delete(w.watches, wd)
+		return
+	}
+	dir, found := w.watches[wd]
+	if !found || fileName == "" {
+		return
+	}
+	name := path.Join(dir, fileName)
+	if mask&syscall.IN_ISDIR != 0 {
+		if mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 && w.recursive && !w.excluded(name) {
+			// the files created before the watch was added have no events
+			files, _ := w.add(name)
+			for _, file := range files {
+				w.out <- rawEvent{name: file, op: OpCreate}
+			}
+		}
+		return
+	}
+	var op Op
+	switch {
+	case mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0:
+		op = OpCreate
+	case mask&(syscall.IN_MODIFY|syscall.IN_CLOSE_WRITE) != 0:
+		op = OpWrite
+	case mask&syscall.IN_DELETE != 0:
+		op = OpRemove
+	case mask&syscall.IN_MOVED_FROM != 0:
+		op = OpRename
+	default:
+		return
+	}
+	w.out <- rawEvent{name: name, op: op}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package fsadpt
+
+import (
+	"errors"
+)
+
+func newInotifyWatcher(tree) (watcher, error) {
+	return nil, errors.New("fsadpt: inotify is supported only on Linux")
+}
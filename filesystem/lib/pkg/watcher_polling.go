@@ -0,0 +1,82 @@
+package fsadpt
+
+import (
+	"slices"
+	"time"
+)
+
+type (
+	// pollingWatcher compares the scans of the tree to find the files created, changed and removed
+	pollingWatcher struct {
+		tree
+		interval time.Duration
+		files    map[string]fileState
+		out      chan rawEvent
+		done     chan struct{}
+	}
+)
+
+func newPollingWatcher(t tree, interval time.Duration) (watcher, error) {
+	files, err := t.scan()
+	if err != nil {
+		return nil, err
+	}
+	w := &pollingWatcher{tree: t, interval: interval, files: files, out: make(chan rawEvent), done: make(chan struct{})}
+	go w.poll()
+	return w, nil
+}
+
+func (w *pollingWatcher) events() <-chan rawEvent { return w.out }
+
+func (w *pollingWatcher) close() { close(w.done) }
+
+func (w *pollingWatcher) poll() {
+	defer close(w.out)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+		files, err := w.scan()
+		if err != nil {
+			continue // the directory may be back on the next scan
+		}
+		for _, event := range w.diff(files) {
+			select {
+			case w.out <- event:
+			case <-w.done:
+				return
+			}
+		}
+		w.files = files
+	}
+}
+
+func (w *pollingWatcher) diff(files map[string]fileState) []rawEvent {
+	var events []rawEvent
+	for name, state := range files {
+		if previous, found := w.files[name]; !found {
+			events = append(events, rawEvent{name: name, op: OpCreate})
+		} else if previous.size != state.size || !previous.modTime.Equal(state.modTime) {
+			events = append(events, rawEvent{name: name, op: OpWrite})
+		}
+	}
+	for name := range w.files {
+		if _, found := files[name]; !found {
+			events = append(events, rawEvent{name: name, op: OpRemove})
+		}
+	}
+	slices.SortFunc(events, func(a, b rawEvent) int {
+		if a.name < b.name {
+			return -1
+		}
+		if a.name > b.name {
+			return 1
+		}
+		return 0
+	})
+	return events
+}
@@ -0,0 +1,59 @@
+package gqladpt
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+type (
+	Binding struct {
+		// Operation tells whether Field is a field of the Query or of the Mutation root type
+		Operation OperationType
+		// Field is the name of the root field resolved by the handler
+		Field string
+		// Description is the description of the field in the schema
+		Description string
+		Handler
+
+		// Args are the field arguments, they are set by WithHandlerFunc from the arg fields of the handler input
+		Args []Arg
+		// Result is the Go type of the field value, it is set by WithHandlerFunc from the result field of the
+		// handler output. The fields without Result are Boolean and resolve to true when the handler succeeds.
+		Result reflect.Type
+	}
+
+	// Arg is a field argument of the schema
+	Arg struct {
+		Name string
+		// Type is the Go type of the argument, it is mapped to the GraphQL input type like the field types
+		Type reflect.Type
+		// Mandatory makes the argument non-null
+		Mandatory bool
+	}
+
+	// Bindings are the fields served by the Schema
+	Bindings []Binding
+)
+
+var (
+	namePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+)
+
+// IsBindingValid returns false if the binding has no handler, if the operation is neither a query nor a mutation,
+// or if the field name is not a valid GraphQL name. The names starting with "__" are reserved for introspection.
+func IsBindingValid(binding Binding) bool {
+	if binding.Handler == nil || !binding.Operation.IsValid() || !isValidName(binding.Field) {
+		return false
+	}
+	for _, arg := range binding.Args {
+		if !isValidName(arg.Name) || arg.Type == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isValidName(name string) bool {
+	return namePattern.MatchString(name) && !strings.HasPrefix(name, "__")
+}
@@ -0,0 +1,82 @@
+package gqladpt
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	tagbasedhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler/tagbased"
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+)
+
+type (
+	HandlerBuildingStep interface {
+		WithDescription(description string) HandlerBuildingStep
+		WithHandlerFunc(handler any) Binding
+	}
+
+	BaseBuilder struct {
+		Binding
+	}
+
+	// declaredParams are the args and the result of the schema field, they are recorded while the handler builder
+	// walks the handler input and output so that the schema declares exactly the params the handler binds
+	declaredParams struct {
+		args   []Arg
+		result reflect.Type
+	}
+)
+
+// NewBindingBuilderUsingQuery starts the binding of a field of the Query root type
+func NewBindingBuilderUsingQuery(field string) HandlerBuildingStep {
+	return &BaseBuilder{Binding: Binding{Operation: OperationQuery, Field: field}}
+}
+
+// NewBindingBuilderUsingMutation starts the binding of a field of the Mutation root type
+func NewBindingBuilderUsingMutation(field string) HandlerBuildingStep {
+	return &BaseBuilder{Binding: Binding{Operation: OperationMutation, Field: field}}
+}
+
+func (b *BaseBuilder) WithDescription(description string) HandlerBuildingStep {
+	b.Description = description
+	return b
+}
+
+func (b *BaseBuilder) WithHandlerFunc(handler any) Binding {
+	declared := &declaredParams{}
+	b.Handler = tagbasedhandler.NewBuilderForFunc[Request, *Response](handler).
+		WithInTagBasedFactory(declared.inFactory(createInParamSpecFactory())).
+		WithOutTagBasedFactory(declared.outFactory(createOutParamSpecFactory())).
+		WithOutErrorParamSpec(NewOutErrorParamSpec()).
+		Build()
+	b.Args = declared.args
+	b.Result = declared.result
+	return b.Binding
+}
+
+// inFactory records the arg fields the handler builder creates specs for, in the order it walks the input structs
+func (d *declaredParams) inFactory(factory tagbased.InputParamSpecFactory[Request]) tagbased.InputParamSpecFactory[Request] {
+	return tagbased.InputParamSpecFactoryFunc[Request](func(field reflect.StructField) (sdkparam.InputParamSpec[Request], error) {
+		spec, err := factory.CreateInputParamSpec(field)
+		if name, found := field.Tag.Lookup(TagArg); found && err == nil {
+			d.args = append(d.args, Arg{Name: name, Type: field.Type, Mandatory: isMandatory(field)})
+		}
+		return spec, err
+	})
+}
+
+// outFactory records the type of the result field the handler builder creates a spec for
+func (d *declaredParams) outFactory(factory tagbased.OutputParamSpecFactory[*Response]) tagbased.OutputParamSpecFactory[*Response] {
+	return tagbased.OutputParamSpecFactoryFunc[*Response](func(field reflect.StructField) (sdkparam.OutputParamSpec[*Response], error) {
+		spec, err := factory.CreateOutputParamSpec(field)
+		if _, found := field.Tag.Lookup(TagResult); found && err == nil {
+			d.result = field.Type
+		}
+		return spec, err
+	})
+}
+
+func isMandatory(field reflect.StructField) bool {
+	return slices.Contains(strings.Split(field.Tag.Get("assert"), ","), "mandatory")
+}
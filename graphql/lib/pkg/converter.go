@@ -0,0 +1,82 @@
+package gqladpt
+
+import (
+	"context"
+	"errors"
+
+	sdkconverter "github.com/smart-libs/go-adapter/sdk/lib/pkg/converter"
+	sdkerror "github.com/smart-libs/go-adapter/sdk/lib/pkg/error"
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	converterdefault "github.com/smart-libs/go-crosscutting/converter/lib/pkg/default"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+var (
+	// ConverterRegistry it is the default converters registry for the GraphQL adapter
+	ConverterRegistry = converterdefault.NewRegistry()
+
+	// Converters is the list of converter.Converters used by the GraphQL adapter, it tries first the GraphQL
+	// adapter conversions and then the default converter.Converters
+	Converters = converter.NewConvertersList(
+		converterdefault.NewConverters(ConverterRegistry),
+		sdkconverter.JSONConverters{Name: "args"},
+		converterdefault.Converters,
+	)
+
+	errorCodes sdkerror.Registry[string]
+)
+
+func init() {
+	converter.AddHandler[error, Error](ConverterRegistry, errorToError)
+}
+
+// RegisterErrorCode makes the handler errors that satisfy the condition be answered with the given code in the error
+// extensions. The registered conditions are evaluated in the registration order before the default ones.
+func RegisterErrorCode(condition func(err error) bool, code string) {
+	errorCodes.Register(condition, code)
+}
+
+// errorToError maps the handler error to the field error, the handlers can return an *Error to choose the code. The
+// INTERNAL_SERVER_ERROR errors get InternalErrorMessage, so the details of the unexpected errors and panics are not
+// sent to the clients.
+func errorToError(err error, to *Error) error {
+	if err == nil {
+		return nil
+	}
+	var gqlErr *Error
+	if errors.As(err, &gqlErr) {
+		*to = *gqlErr
+		return nil
+	}
+	to.Message = err.Error()
+
+	callbacks := errorCodes.Callbacks(func(code string) { to.Code = code }, 4)
+
+	callbacks = append(callbacks,
+		serror.CallbackCondition{
+			Condition: serror.IsIllegalArgumentError,
+			Callback:  func(err error) { to.Code = CodeBadUserInput },
+		},
+		serror.CallbackCondition{
+			Condition: serror.IsNotFoundError,
+			Callback:  func(err error) { to.Code = CodeNotFound },
+		},
+		serror.CallbackCondition{
+			Condition: serror.IsDuplicateError,
+			Callback:  func(err error) { to.Code = CodeConflict },
+		},
+		serror.CallbackCondition{
+			Condition: func(err error) bool {
+				return serror.IsTimeoutError(err) || errors.Is(err, context.DeadlineExceeded)
+			},
+			Callback: func(err error) { to.Code = CodeTimeout },
+		},
+	)
+	if !serror.IdentifyRootCause(err, func(error) { to.Code = CodeInternalServerError }, callbacks...) {
+		to.Code = CodeInternalServerError
+	}
+	if to.Code == CodeInternalServerError {
+		to.Message = InternalErrorMessage
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package gqladpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	// DefaultMaxRequestSize is the limit of the request bodies
+	DefaultMaxRequestSize = 10 << 20
+)
+
+// NewHTTPBinding returns the httpadpt.Binding that executes the GraphQL requests POSTed to path as JSON. The results
+// are sent with 200, including the ones with field errors, and the bodies that are not a GraphQL request get 400.
+// The bodies larger than DefaultMaxRequestSize get 413.
+func NewHTTPBinding(path string, schema *Schema) httpadpt.Binding {
+	return httpadpt.Binding{
+		Condition: httpadpt.Condition{Path: &path, Methods: []string{http.MethodPost}},
+		Handler: httpadpt.MakeHandler(func(ctx context.Context, input httpadpt.Request, output *httpadpt.Response) error {
+			return handleHTTPRequest(ctx, schema, input, output)
+		}),
+	}
+}
+
+func handleHTTPRequest(ctx context.Context, schema *Schema, input httpadpt.Request, output *httpadpt.Response) error {
+	const fName = "gqladpt.handleHTTPRequest"
+	if err := httpadpt.IsResponseNil(output); err != nil {
+		return serror.CmpError.Wrap(err, "%s: invalid output", fName)
+	}
+	payload, err := httpadpt.ReadBody(input, DefaultMaxRequestSize)
+	var maxBytesError *http.MaxBytesError
+	if err != nil && !errors.As(err, &maxBytesError) {
+		return serror.CmpError.Wrap(err, "%s: failed to read the body", fName)
+	}
+
+	statusCode := http.StatusOK
+	var result *graphql.Result
+	var params Params
+	switch {
+	case maxBytesError != nil:
+		statusCode = http.StatusRequestEntityTooLarge
+		result = newErrorResult("the request body is too large")
+	case json.Unmarshal(payload, &params) != nil:
+		statusCode = http.StatusBadRequest
+		result = newErrorResult("the request body is not a GraphQL request")
+	case params.Query == "":
+		statusCode = http.StatusBadRequest
+		result = newErrorResult("the request has no query")
+	default:
+		result = schema.Execute(ctx, params)
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		return serror.CmpError.Wrap(err, "%s: failed to marshal the result", fName)
+	}
+	output.StatusCode = &statusCode
+	output.Header = map[string][]string{"Content-Type": {"application/json"}}
+	output.Body = body
+	return nil
+}
+
+func newErrorResult(message string) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(message)}}
+}
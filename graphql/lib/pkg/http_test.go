@@ -0,0 +1,62 @@
+package gqladpt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gonethttp "github.com/smart-libs/go-adapter/http/impl/gonethttp/pkg"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+)
+
+func Test_NewHTTPBinding(t *testing.T) {
+	binding := NewHTTPBinding("/graphql", newTestSchema(t))
+
+	tests := []struct {
+		name       string
+		body       string
+		statusCode int
+		expected   string
+	}{
+		{
+			name:       "query",
+			body:       `{"query":"query Get($id: String!) { order(id: $id) { id } }","variables":{"id":"o-1"},"operationName":"Get"}`,
+			statusCode: http.StatusOK,
+			expected:   `{"data":{"order":{"id":"o-1"}}}`,
+		},
+		{
+			name:       "field error",
+			body:       `{"query":"{ order(id: \"o-9\") { id } }"}`,
+			statusCode: http.StatusOK,
+			expected: `{"data":{"order":null},"errors":[{"message":"*","locations":[{"line":1,"column":3}],` +
+				`"path":["order"],"extensions":{"code":"NOT_FOUND"}}]}`,
+		},
+		{
+			name:       "not a GraphQL request",
+			body:       `{"query":`,
+			statusCode: http.StatusBadRequest,
+			expected:   `{"data":null,"errors":[{"message":"*","locations":[]}]}`,
+		},
+		{
+			name:       "no query",
+			body:       `{}`,
+			statusCode: http.StatusBadRequest,
+			expected:   `{"data":null,"errors":[{"message":"*","locations":[]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := gonethttp.NewRequest(httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body)))
+			var response httpadpt.Response
+			if err := binding.Handler.Invoke(context.Background(), request, &response); err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if response.StatusCode == nil || *response.StatusCode != tt.statusCode {
+				t.Fatalf("StatusCode = %v, want %d", response.StatusCode, tt.statusCode)
+			}
+			assertJSONEqual(t, response.Body, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package gqladpt
+
+import (
+	"encoding/json"
+)
+
+const (
+	// TagArg gets the field argument by name, the field type also gives the argument type of the schema
+	TagArg = "arg"
+	// TagArgs gets all the field arguments, as json.RawMessage or unmarshalled into the field type
+	TagArgs = "args"
+	// TagField gets the name of the resolved root field
+	TagField = "field"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().
+		AddOption2(TagArg, getArgInParamValue).
+		AddOption1(TagArgs, "", getArgsInParamValue).
+		AddOption1(TagField, "", func(input Request) (any, error) { return input.Field, nil })
+}
+
+// getArgInParamValue returns the argument as JSON, the absent and null ones are nil so the default and mandatory
+// options apply
+func getArgInParamValue(input Request, name string) (any, error) {
+	value, found := input.Arg(name)
+	if !found || value == nil {
+		return nil, nil
+	}
+	return marshalArg(name, value)
+}
+
+func getArgsInParamValue(input Request) (any, error) {
+	if input.Args == nil {
+		return nil, nil
+	}
+	return marshalArg("args", input.Args)
+}
+
+// marshalArg returns the coerced argument value as JSON, the jsonConverters unmarshal it into the field type
+func marshalArg(name string, value any) (any, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, asBadUserInput(name, err)
+	}
+	return json.RawMessage(raw), nil
+}
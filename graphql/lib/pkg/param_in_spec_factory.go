@@ -0,0 +1,61 @@
+package gqladpt
+
+import (
+	"reflect"
+
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	// badUserInputSpecFactory creates the specs whose failures, like the unmet assertions, are illegal arguments so
+	// that they are answered with CodeBadUserInput
+	badUserInputSpecFactory struct {
+		factory tagbased.InputParamSpecFactory[Request]
+	}
+
+	badUserInputSpec struct {
+		sdkparam.InputParamSpec[Request]
+	}
+)
+
+var (
+	inParamSpecFactoryRegistry tagbased.InputParamSpecFactoryRegistry[Request]
+)
+
+func getInputParamSpecFactoryRegistry() tagbased.InputParamSpecFactoryRegistry[Request] {
+	if inParamSpecFactoryRegistry == nil {
+		inParamSpecFactoryRegistry = tagbased.NewInputParamSpecFactoryRegistry[Request](Converters)
+	}
+
+	return inParamSpecFactoryRegistry
+}
+
+func createInParamSpecFactory() tagbased.InputParamSpecFactory[Request] {
+	return badUserInputSpecFactory{factory: tagbased.NewsInputParamSpecFactory(getInputParamSpecFactoryRegistry())}
+}
+
+func (f badUserInputSpecFactory) CreateInputParamSpec(field reflect.StructField) (sdkparam.InputParamSpec[Request], error) {
+	spec, err := f.factory.CreateInputParamSpec(field)
+	if err != nil {
+		return nil, err
+	}
+	return badUserInputSpec{InputParamSpec: spec}, nil
+}
+
+func (s badUserInputSpec) CopyValue(input Request, target any) error {
+	return asBadUserInput(s.Name(), s.InputParamSpec.CopyValue(input, target))
+}
+
+func (s badUserInputSpec) GetValue(input Request) (any, error) {
+	value, err := s.InputParamSpec.GetValue(input)
+	return value, asBadUserInput(s.Name(), err)
+}
+
+func asBadUserInput(name string, err error) error {
+	if err == nil || serror.IsIllegalArgumentError(err) {
+		return err
+	}
+	return serror.IllegalArgumentValueWithCause("args", name, err)
+}
@@ -0,0 +1,22 @@
+package gqladpt
+
+import (
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	// TagResult sets the value of the resolved field, the tagged field type gives the field type of the schema
+	TagResult = "result"
+)
+
+func init() {
+	getOutParamSpecFactoryRegistry().AddOption1(TagResult, "", setResult)
+}
+
+func setResult(output *Response, value any) error {
+	if output == nil {
+		return serror.CmpError.New("gqladpt.setResult: output is nil")
+	}
+	output.Result = value
+	return nil
+}
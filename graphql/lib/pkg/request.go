@@ -0,0 +1,41 @@
+package gqladpt
+
+const (
+	// OperationQuery is the operation type of the fields of the Query root type
+	OperationQuery OperationType = "query"
+	// OperationMutation is the operation type of the fields of the Mutation root type
+	OperationMutation OperationType = "mutation"
+)
+
+type (
+	// OperationType tells whether a binding is a query or a mutation
+	OperationType string
+
+	// Request is the input of the GraphQL handlers, it is the resolution of one root field
+	Request struct {
+		// Operation is the type of the operation that selected the field
+		Operation OperationType
+		// Field is the name of the resolved root field
+		Field string
+		// Args are the field arguments, already coerced by the schema and with the variables replaced
+		Args map[string]any
+	}
+
+	// Params is the GraphQL request sent by the clients, see Schema.Execute
+	Params struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName,omitempty"`
+		Variables     map[string]any `json:"variables,omitempty"`
+	}
+)
+
+// Arg returns the argument value and whether it was given
+func (r Request) Arg(name string) (any, bool) {
+	value, found := r.Args[name]
+	return value, found
+}
+
+// IsValid returns true if the operation type is a query or a mutation
+func (o OperationType) IsValid() bool {
+	return o == OperationQuery || o == OperationMutation
+}
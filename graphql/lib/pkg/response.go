@@ -0,0 +1,55 @@
+package gqladpt
+
+import (
+	"maps"
+)
+
+// The codes set in the extensions of the errors, the names follow the ones used by the GraphQL servers in the wild
+const (
+	CodeBadUserInput        = "BAD_USER_INPUT"
+	CodeNotFound            = "NOT_FOUND"
+	CodeConflict            = "CONFLICT"
+	CodeTimeout             = "TIMEOUT"
+	CodeInternalServerError = "INTERNAL_SERVER_ERROR"
+)
+
+const (
+	// ExtensionCode is the error extension that holds the code
+	ExtensionCode = "code"
+
+	// InternalErrorMessage is the message of the INTERNAL_SERVER_ERROR errors created from the handler errors
+	InternalErrorMessage = "internal error"
+)
+
+type (
+	// Response is the output of the GraphQL handlers, Result is set by the result tag and Error by the handler error
+	Response struct {
+		Result any
+		Error  *Error
+	}
+
+	// Error is the error of a field resolution, Code and Details are sent as the extensions of the error. The
+	// handlers can return it, or wrap it, to answer with a specific code and details.
+	Error struct {
+		Code    string
+		Message string
+		Details map[string]any
+	}
+)
+
+// NewError creates the Error with the given code, message and optional details
+func NewError(code string, message string, details map[string]any) *Error {
+	return &Error{Code: code, Message: message, Details: details}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions returns the details and the code, it makes the graphql-go executor send them as the error extensions
+func (e *Error) Extensions() map[string]any {
+	extensions := make(map[string]any, len(e.Details)+1)
+	maps.Copy(extensions, e.Details)
+	extensions[ExtensionCode] = e.Code
+	return extensions
+}
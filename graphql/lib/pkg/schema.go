@@ -0,0 +1,130 @@
+package gqladpt
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+	sdkhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	Config struct {
+		// Bindings are the fields of the Query and Mutation root types, at least one query is required
+		Bindings
+	}
+
+	// Schema is the GraphQL schema derived from the handlers of the bindings, the transports give it the requests
+	// received and send back the results, see NewHTTPBinding
+	Schema struct {
+		schema graphql.Schema
+	}
+)
+
+// NewSchema derives the schema from the bindings of the config. The field arguments are given by the arg fields of
+// the handler inputs and the field types by the result field of the handler outputs, the Go types are mapped to the
+// GraphQL ones as below:
+//
+//   - bool, the integers, the floats and string are Boolean, Int, Float and String
+//   - time.Time is DateTime, and the maps, interfaces, []byte and json.RawMessage are JSON
+//   - the slices and arrays are lists, and the named structs are objects, or input objects with the Input suffix
+//   - the output values that cannot be nil are non-null, and the args are nullable unless they are mandatory
+func NewSchema(config Config) (*Schema, error) {
+	mapper := newTypeMapper()
+	roots := map[OperationType]graphql.Fields{OperationQuery: {}, OperationMutation: {}}
+	for _, binding := range config.Bindings {
+		if !IsBindingValid(binding) {
+			return nil, serror.IllegalConfigParamValue("Binding", binding.Field)
+		}
+		if _, found := roots[binding.Operation][binding.Field]; found {
+			return nil, serror.IllegalConfigParamValue("Binding", binding.Field)
+		}
+		field, err := newRootField(mapper, binding)
+		if err != nil {
+			return nil, fmt.Errorf("gqladpt.NewSchema: field=[%s]: %w", binding.Field, err)
+		}
+		roots[binding.Operation][binding.Field] = field
+	}
+	if len(roots[OperationQuery]) == 0 {
+		return nil, serror.IllegalConfigParamValue("Bindings", "no query")
+	}
+
+	schemaConfig := graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: roots[OperationQuery]}),
+	}
+	if len(roots[OperationMutation]) > 0 {
+		schemaConfig.Mutation = graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: roots[OperationMutation]})
+	}
+	schema, err := graphql.NewSchema(schemaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("gqladpt.NewSchema: %w", err)
+	}
+	return &Schema{schema: schema}, nil
+}
+
+// Execute executes the query of the params, the errors of the handlers are in the result errors with their code in
+// the extensions
+func (s *Schema) Execute(ctx context.Context, params Params) *graphql.Result {
+	return graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  params.Query,
+		VariableValues: params.Variables,
+		OperationName:  params.OperationName,
+		Context:        ctx,
+	})
+}
+
+// GraphQLSchema returns the graphql-go schema, for instance to serve it with other graphql-go tools
+func (s *Schema) GraphQLSchema() graphql.Schema {
+	return s.schema
+}
+
+func newRootField(mapper *typeMapper, binding Binding) (*graphql.Field, error) {
+	var fieldType graphql.Output = graphql.Boolean
+	if binding.Result != nil {
+		var err error
+		if fieldType, err = mapper.outputType(binding.Result); err != nil {
+			return nil, err
+		}
+	}
+	args := graphql.FieldConfigArgument{}
+	for _, arg := range binding.Args {
+		if _, found := args[arg.Name]; found {
+			return nil, serror.IllegalConfigParamValue("Args", arg.Name)
+		}
+		argType, err := mapper.inputType(arg.Type)
+		if err != nil {
+			return nil, fmt.Errorf("arg=[%s]: %w", arg.Name, err)
+		}
+		if arg.Mandatory {
+			argType = graphql.NewNonNull(argType)
+		}
+		args[arg.Name] = &graphql.ArgumentConfig{Type: argType}
+	}
+	return &graphql.Field{
+		Type:        fieldType,
+		Args:        args,
+		Description: binding.Description,
+		Resolve:     newRootFieldResolver(binding),
+	}, nil
+}
+
+// newRootFieldResolver invokes the handler, the fields of the result are resolved from the Go value
+func newRootFieldResolver(binding Binding) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		request := Request{Operation: binding.Operation, Field: p.Info.FieldName, Args: p.Args}
+		response := &Response{}
+		if err := sdkhandler.Invoke(p.Context, binding.Handler, request, response); err != nil {
+			_ = NewOutErrorParamSpec().SetValue(response, err)
+		}
+		if response.Error != nil {
+			return nil, response.Error
+		}
+		if binding.Result == nil {
+			return true, nil
+		}
+		return resolvedValue(reflect.ValueOf(response.Result)), nil
+	}
+}
@@ -0,0 +1,332 @@
+package gqladpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	testStatus string
+
+	testItem struct {
+		SKU      string `json:"sku"`
+		Quantity int
+	}
+
+	testAudit struct {
+		CreatedAt time.Time
+	}
+
+	testOrder struct {
+		testAudit
+		ID         string
+		Status     testStatus
+		Items      []testItem
+		Total      *float64
+		Attributes map[string]any
+		Parent     *testOrder
+		secret     string
+	}
+
+	getOrderInput struct {
+		ID string `arg:"id" assert:"mandatory"`
+	}
+
+	orderOutput struct {
+		Order *testOrder `result:""`
+	}
+
+	createOrderInput struct {
+		Request struct {
+			Items []testItem `arg:"items" assert:"mandatory"`
+			Field string     `field:""`
+		}
+		Note *string `arg:"note"`
+	}
+)
+
+var errLocked = errors.New("order locked")
+
+func newTestSchema(t *testing.T) *Schema {
+	t.Helper()
+	total := 10.5
+	orders := map[string]*testOrder{
+		"o-1": {ID: "o-1", Status: "open", Items: []testItem{{SKU: "a", Quantity: 2}}, Total: &total,
+			Attributes: map[string]any{"channel": "web"}, testAudit: testAudit{CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}},
+	}
+	orders["o-2"] = &testOrder{ID: "o-2", Status: "closed", Parent: orders["o-1"], secret: "s"}
+
+	schema, err := NewSchema(Config{Bindings: Bindings{
+		NewBindingBuilderUsingQuery("order").WithDescription("Gets an order").
+			WithHandlerFunc(func(in getOrderInput) (orderOutput, error) {
+				order, found := orders[in.ID]
+				if !found {
+					return orderOutput{}, serror.NotFoundError.New("order not found")
+				}
+				return orderOutput{Order: order}, nil
+			}),
+		NewBindingBuilderUsingQuery("orderIDs").WithHandlerFunc(func() (struct {
+			IDs []string `result:""`
+		}, error) {
+			return struct {
+				IDs []string `result:""`
+			}{IDs: []string{"o-1", "o-2"}}, nil
+		}),
+		NewBindingBuilderUsingMutation("createOrder").
+			WithHandlerFunc(func(ctx context.Context, in createOrderInput) (*orderOutput, error) {
+				if len(in.Request.Items) == 0 {
+					return nil, serror.IllegalArgumentValue("items", in.Request.Items)
+				}
+				order := &testOrder{ID: in.Request.Field + "-1", Status: "open", Items: in.Request.Items}
+				if in.Note != nil {
+					order.Attributes = map[string]any{"note": *in.Note}
+				}
+				return &orderOutput{Order: order}, nil
+			}),
+		NewBindingBuilderUsingMutation("deleteOrder").WithHandlerFunc(func(in getOrderInput) error {
+			switch in.ID {
+			case "locked":
+				return errLocked
+			case "limited":
+				return NewError("RATE_LIMITED", "too many requests", map[string]any{"retryAfter": 5})
+			case "panic":
+				panic("boom")
+			}
+			return nil
+		}),
+	}})
+	if err != nil {
+		t.Fatalf("NewSchema() error = %v", err)
+	}
+	return schema
+}
+
+func Test_Schema_Execute(t *testing.T) {
+	RegisterErrorCode(func(err error) bool { return errors.Is(err, errLocked) }, "LOCKED")
+	schema := newTestSchema(t)
+
+	tests := []struct {
+		name     string
+		params   Params
+		expected string
+	}{
+		{
+			name: "query with variables and nested objects",
+			params: Params{
+				Query:     `query Get($id: String!) { order(id: $id) { id status createdAt total attributes items { sku quantity } parent { id } } }`,
+				Variables: map[string]any{"id": "o-1"},
+			},
+			expected: `{"data":{"order":{"id":"o-1","status":"open","createdAt":"2026-01-02T03:04:05Z","total":10.5,` +
+				`"attributes":{"channel":"web"},"items":[{"sku":"a","quantity":2}],"parent":null}}}`,
+		},
+		{
+			name:     "recursive type",
+			params:   Params{Query: `{ order(id: "o-2") { id items { sku } parent { id status } } }`},
+			expected: `{"data":{"order":{"id":"o-2","items":null,"parent":{"id":"o-1","status":"open"}}}}`,
+		},
+		{
+			name:     "list result",
+			params:   Params{Query: `{ orderIDs }`},
+			expected: `{"data":{"orderIDs":["o-1","o-2"]}}`,
+		},
+		{
+			name:     "mutation with input objects",
+			params:   Params{Query: `mutation { createOrder(items: [{sku: "b", quantity: 3}], note: "gift") { id items { sku quantity } attributes } }`},
+			expected: `{"data":{"createOrder":{"id":"createOrder-1","items":[{"sku":"b","quantity":3}],"attributes":{"note":"gift"}}}}`,
+		},
+		{
+			name:     "mutation without result",
+			params:   Params{Query: `mutation { deleteOrder(id: "o-1") }`},
+			expected: `{"data":{"deleteOrder":true}}`,
+		},
+		{
+			name:     "not found",
+			params:   Params{Query: `{ order(id: "o-9") { id } }`},
+			expected: `{"data":{"order":null},"errors":[{"message":"*","locations":null,"path":["order"],"extensions":{"code":"NOT_FOUND"}}]}`,
+		},
+		{
+			name:     "illegal argument",
+			params:   Params{Query: `mutation { createOrder(items: []) { id } }`},
+			expected: `{"data":{"createOrder":null},"errors":[{"message":"*","locations":null,"path":["createOrder"],"extensions":{"code":"BAD_USER_INPUT"}}]}`,
+		},
+		{
+			name:     "registered error code",
+			params:   Params{Query: `mutation { deleteOrder(id: "locked") }`},
+			expected: `{"data":{"deleteOrder":null},"errors":[{"message":"order locked","locations":null,"path":["deleteOrder"],"extensions":{"code":"LOCKED"}}]}`,
+		},
+		{
+			name:   "error returned by the handler",
+			params: Params{Query: `mutation { deleteOrder(id: "limited") }`},
+			expected: `{"data":{"deleteOrder":null},"errors":[{"message":"too many requests","locations":null,"path":["deleteOrder"],` +
+				`"extensions":{"code":"RATE_LIMITED","retryAfter":5}}]}`,
+		},
+		{
+			name:     "panic",
+			params:   Params{Query: `mutation { deleteOrder(id: "panic") }`},
+			expected: `{"data":{"deleteOrder":null},"errors":[{"message":"internal error","locations":null,"path":["deleteOrder"],"extensions":{"code":"INTERNAL_SERVER_ERROR"}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := schema.Execute(context.Background(), tt.params)
+			for i := range result.Errors {
+				result.Errors[i].Locations = nil // compared by Test_NewHTTPBinding
+			}
+			got, _ := json.Marshal(result)
+			assertJSONEqual(t, got, tt.expected)
+		})
+	}
+}
+
+func Test_Schema_Execute_ValidationErrors(t *testing.T) {
+	schema := newTestSchema(t)
+	for _, query := range []string{
+		`{ order { id } }`,
+		`{ order(id: 1) { id } }`,
+		`{ order(id: "o-1") { secret } }`,
+		`{ order(id: "o-1") }`,
+		`mutation { order(id: "o-1") { id } }`,
+	} {
+		result := schema.Execute(context.Background(), Params{Query: query})
+		if !result.HasErrors() || result.Data != nil {
+			t.Errorf("Execute(%s) = %+v, want validation errors", query, result)
+		}
+	}
+}
+
+func Test_NewSchema_InvalidBindings(t *testing.T) {
+	handler := MakeHandler(nil)
+	query := Binding{Operation: OperationQuery, Field: "ok", Handler: handler}
+	type item struct{ A int }
+	otherItem := func() any {
+		type item struct{ B int }
+		return item{}
+	}()
+
+	tests := []struct {
+		name     string
+		bindings Bindings
+	}{
+		{name: "no query", bindings: Bindings{{Operation: OperationMutation, Field: "m", Handler: handler}}},
+		{name: "no handler", bindings: Bindings{{Operation: OperationQuery, Field: "q"}}},
+		{name: "invalid operation", bindings: Bindings{query, {Operation: "subscription", Field: "s", Handler: handler}}},
+		{name: "invalid field name", bindings: Bindings{{Operation: OperationQuery, Field: "my-field", Handler: handler}}},
+		{name: "reserved field name", bindings: Bindings{{Operation: OperationQuery, Field: "__schema", Handler: handler}}},
+		{name: "duplicated field", bindings: Bindings{query, query}},
+		{
+			name: "duplicated arg",
+			bindings: Bindings{NewBindingBuilderUsingQuery("q").WithHandlerFunc(func(in struct {
+				ID    string `arg:"id"`
+				Inner struct {
+					ID string `arg:"id"`
+				}
+			}) error {
+				return nil
+			})},
+		},
+		{
+			name:     "unsupported type",
+			bindings: Bindings{{Operation: OperationQuery, Field: "q", Handler: handler, Result: reflect.TypeFor[chan int]()}},
+		},
+		{
+			name: "anonymous struct",
+			bindings: Bindings{{Operation: OperationQuery, Field: "q", Handler: handler,
+				Result: reflect.TypeFor[struct{ A int }]()}},
+		},
+		{
+			name: "type name clash",
+			bindings: Bindings{
+				{Operation: OperationQuery, Field: "q1", Handler: handler, Result: reflect.TypeFor[item]()},
+				{Operation: OperationQuery, Field: "q2", Handler: handler, Result: reflect.TypeOf(otherItem)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSchema(Config{Bindings: tt.bindings}); err == nil {
+				t.Error("NewSchema() error = nil, want error")
+			}
+		})
+	}
+}
+
+func Test_WithHandlerFunc_DeclaredParams(t *testing.T) {
+	binding := NewBindingBuilderUsingMutation("createOrder").
+		WithHandlerFunc(func(context.Context, createOrderInput) (*orderOutput, error) { return nil, nil })
+
+	expectedArgs := []Arg{
+		{Name: "items", Type: reflect.TypeFor[[]testItem](), Mandatory: true},
+		{Name: "note", Type: reflect.TypeFor[*string]()},
+	}
+	if !reflect.DeepEqual(binding.Args, expectedArgs) {
+		t.Errorf("Args = %+v, want %+v", binding.Args, expectedArgs)
+	}
+	if binding.Result != reflect.TypeFor[*testOrder]() {
+		t.Errorf("Result = %v, want *testOrder", binding.Result)
+	}
+
+	binding = NewBindingBuilderUsingMutation("deleteOrder").WithHandlerFunc(func(getOrderInput) error { return nil })
+	if binding.Result != nil {
+		t.Errorf("Result = %v, want nil", binding.Result)
+	}
+}
+
+func Test_lowerCamelCase(t *testing.T) {
+	for name, expected := range map[string]string{"ID": "id", "OrderID": "orderID", "IDRef": "idRef", "Name": "name", "sku": "sku"} {
+		if got := lowerCamelCase(name); got != expected {
+			t.Errorf("lowerCamelCase(%s) = %s, want %s", name, got, expected)
+		}
+	}
+}
+
+// assertJSONEqual compares the payloads ignoring the messages expected as "*"
+func assertJSONEqual(t *testing.T, got []byte, expected string) {
+	t.Helper()
+	var gotValue, expectedValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid payload %s: %v", got, err)
+	}
+	_ = json.Unmarshal([]byte(expected), &expectedValue)
+	if !matchJSON(gotValue, expectedValue) {
+		t.Errorf("payload = %s, want %s", got, expected)
+	}
+}
+
+func matchJSON(got, expected any) bool {
+	switch expected := expected.(type) {
+	case string:
+		gotString, ok := got.(string)
+		return ok && (expected == "*" || expected == gotString)
+	case map[string]any:
+		gotMap, ok := got.(map[string]any)
+		if !ok || len(gotMap) != len(expected) {
+			return false
+		}
+		for key, value := range expected {
+			if !matchJSON(gotMap[key], value) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		gotSlice, ok := got.([]any)
+		if !ok || len(gotSlice) != len(expected) {
+			return false
+		}
+		for i := range expected {
+			if !matchJSON(gotSlice[i], expected[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	gotJSON, _ := json.Marshal(got)
+	expectedJSON, _ := json.Marshal(expected)
+	return string(gotJSON) == string(expectedJSON)
+}
@@ -0,0 +1,327 @@
+package gqladpt
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+)
+
+type (
+	// typeMapper maps the Go types to the GraphQL types. The struct types are named after the Go type, with the
+	// Input suffix for the input objects, and they are shared by all the fields of the schema.
+	typeMapper struct {
+		objects      map[reflect.Type]*graphql.Object
+		inputObjects map[reflect.Type]*graphql.InputObject
+		names        map[string]reflect.Type
+	}
+
+	// structField is a field of the GraphQL object or input object, the fields of the embedded structs are promoted
+	structField struct {
+		name  string
+		index []int
+		field reflect.StructField
+	}
+)
+
+var (
+	// JSON is the scalar of the map, interface and json.RawMessage types, its values are sent as they are
+	JSON = graphql.NewScalar(graphql.ScalarConfig{
+		Name:         "JSON",
+		Description:  "The `JSON` scalar type represents any JSON value.",
+		Serialize:    serializeJSON,
+		ParseValue:   func(value any) any { return value },
+		ParseLiteral: parseJSONLiteral,
+	})
+
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+	bytesType      = reflect.TypeFor[[]byte]()
+)
+
+func newTypeMapper() *typeMapper {
+	return &typeMapper{
+		objects:      map[reflect.Type]*graphql.Object{},
+		inputObjects: map[reflect.Type]*graphql.InputObject{},
+		names:        map[string]reflect.Type{},
+	}
+}
+
+// outputType returns the type of the values of goType, the values that cannot be nil are non-null
+func (m *typeMapper) outputType(goType reflect.Type) (graphql.Output, error) {
+	if goType.Kind() == reflect.Pointer {
+		return m.nullableOutputType(goType.Elem())
+	}
+	outType, err := m.nullableOutputType(goType)
+	if err != nil || !isNonNull(goType) {
+		return outType, err
+	}
+	return graphql.NewNonNull(outType), nil
+}
+
+func (m *typeMapper) nullableOutputType(goType reflect.Type) (graphql.Output, error) {
+	if scalar := scalarType(goType); scalar != nil {
+		return scalar, nil
+	}
+	switch goType.Kind() {
+	case reflect.Slice, reflect.Array:
+		elemType, err := m.outputType(goType.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return graphql.NewList(elemType), nil
+	case reflect.Struct:
+		return m.object(goType)
+	default:
+		return nil, fmt.Errorf("no GraphQL output type for the Go type=[%s]", goType)
+	}
+}
+
+func (m *typeMapper) object(goType reflect.Type) (*graphql.Object, error) {
+	if object, found := m.objects[goType]; found {
+		return object, nil
+	}
+	if err := m.reserveName(goType, goType.Name()); err != nil {
+		return nil, err
+	}
+	fields := graphql.Fields{}
+	object := graphql.NewObject(graphql.ObjectConfig{
+		Name:   goType.Name(),
+		Fields: graphql.FieldsThunk(func() graphql.Fields { return fields }),
+	})
+	m.objects[goType] = object // registered before its fields to support the recursive types
+
+	for _, field := range structFields(goType) {
+		fieldType, err := m.outputType(field.field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", goType.Name(), field.field.Name, err)
+		}
+		fields[field.name] = &graphql.Field{Type: fieldType, Resolve: newStructFieldResolver(field.index)}
+	}
+	return object, nil
+}
+
+// inputType returns the type of the arguments of goType, the arguments are nullable so that the absent ones get the
+// zero value, see Arg.Mandatory
+func (m *typeMapper) inputType(goType reflect.Type) (graphql.Input, error) {
+	if goType.Kind() == reflect.Pointer {
+		goType = goType.Elem()
+	}
+	if scalar := scalarType(goType); scalar != nil {
+		return scalar, nil
+	}
+	switch goType.Kind() {
+	case reflect.Slice, reflect.Array:
+		elemType, err := m.inputType(goType.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return graphql.NewList(elemType), nil
+	case reflect.Struct:
+		return m.inputObject(goType)
+	default:
+		return nil, fmt.Errorf("no GraphQL input type for the Go type=[%s]", goType)
+	}
+}
+
+func (m *typeMapper) inputObject(goType reflect.Type) (*graphql.InputObject, error) {
+	if inputObject, found := m.inputObjects[goType]; found {
+		return inputObject, nil
+	}
+	name := goType.Name() + "Input"
+	if err := m.reserveName(goType, name); err != nil {
+		return nil, err
+	}
+	fields := graphql.InputObjectConfigFieldMap{}
+	inputObject := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   name,
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap { return fields }),
+	})
+	m.inputObjects[goType] = inputObject
+
+	for _, field := range structFields(goType) {
+		fieldType, err := m.inputType(field.field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", goType.Name(), field.field.Name, err)
+		}
+		fields[field.name] = &graphql.InputObjectFieldConfig{Type: fieldType}
+	}
+	return inputObject, nil
+}
+
+// reserveName fails if the name is taken by another Go type, what happens with the types of different packages
+// that have the same name, or if the type is anonymous
+func (m *typeMapper) reserveName(goType reflect.Type, name string) error {
+	if goType.Name() == "" {
+		return fmt.Errorf("no GraphQL type for the anonymous struct=[%s], the struct types must be named", goType)
+	}
+	if reserved, found := m.names[name]; found && reserved != goType {
+		return fmt.Errorf("the GraphQL type name=[%s] is used by the Go types=[%s] and [%s]", name, reserved, goType)
+	}
+	m.names[name] = goType
+	return nil
+}
+
+func scalarType(goType reflect.Type) *graphql.Scalar {
+	if goType == timeType {
+		return graphql.DateTime
+	}
+	if isBytesType(goType) {
+		return JSON
+	}
+	switch goType.Kind() {
+	case reflect.Bool:
+		return graphql.Boolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return graphql.Int
+	case reflect.Float32, reflect.Float64:
+		return graphql.Float
+	case reflect.String:
+		return graphql.String
+	case reflect.Map, reflect.Interface:
+		return JSON
+	}
+	return nil
+}
+
+func isNonNull(goType reflect.Type) bool {
+	switch goType.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return false
+	}
+	return true
+}
+
+// structFields returns the exported fields of the struct named as their JSON names, the untagged ones are named in
+// lower camel case, like ID as id and OrderID as orderID
+func structFields(structType reflect.Type) []structField {
+	var fields []structField
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || (field.Anonymous && indirect(field.Type).Kind() == reflect.Struct) {
+			continue // the fields of the embedded structs are visited too
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = lowerCamelCase(field.Name)
+		}
+		if isValidName(name) {
+			fields = append(fields, structField{name: name, index: field.Index, field: field})
+		}
+	}
+	return fields
+}
+
+func indirect(goType reflect.Type) reflect.Type {
+	if goType.Kind() == reflect.Pointer {
+		return goType.Elem()
+	}
+	return goType
+}
+
+func lowerCamelCase(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) {
+		upper-- // the last upper case letter starts the next word, like the R of IDRef
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// newStructFieldResolver resolves the struct field, the embedded nil pointers resolve to nil
+func newStructFieldResolver(index []int) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source := reflect.Indirect(reflect.ValueOf(p.Source))
+		if source.Kind() != reflect.Struct {
+			return nil, nil
+		}
+		value, err := source.FieldByIndexErr(index)
+		if err != nil {
+			return nil, nil
+		}
+		return resolvedValue(value), nil
+	}
+}
+
+// resolvedValue returns the value as expected by the graphql-go scalars, the named types like "type Status string"
+// are converted to their underlying types and the nil pointers to nil
+func resolvedValue(value reflect.Value) any {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if value.IsNil() {
+			return nil
+		}
+		if value.Kind() == reflect.Pointer && value.Elem().Kind() != reflect.Struct {
+			return resolvedValue(value.Elem())
+		}
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.String:
+		return value.String()
+	}
+	return value.Interface()
+}
+
+func serializeJSON(value any) any {
+	if raw, ok := value.(json.RawMessage); ok {
+		var decoded any
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil
+		}
+		return decoded
+	}
+	return value
+}
+
+func parseJSONLiteral(valueAST ast.Value) any {
+	switch valueAST.GetKind() {
+	case kinds.ObjectValue:
+		object := map[string]any{}
+		for _, field := range valueAST.(*ast.ObjectValue).Fields {
+			object[field.Name.Value] = parseJSONLiteral(field.Value)
+		}
+		return object
+	case kinds.ListValue:
+		var list []any
+		for _, value := range valueAST.(*ast.ListValue).Values {
+			list = append(list, parseJSONLiteral(value))
+		}
+		return list
+	case kinds.IntValue:
+		return graphql.Int.ParseLiteral(valueAST)
+	case kinds.FloatValue:
+		return graphql.Float.ParseLiteral(valueAST)
+	case kinds.BooleanValue:
+		return graphql.Boolean.ParseLiteral(valueAST)
+	case kinds.StringValue, kinds.EnumValue:
+		return valueAST.GetValue()
+	}
+	return nil
+}
+
+func isBytesType(valueType reflect.Type) bool {
+	return valueType == rawMessageType || valueType == bytesType
+}
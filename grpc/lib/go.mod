@@ -0,0 +1,21 @@
+module github.com/smart-libs/go-adapter/grpc/lib
+
+go 1.25
+
+require (
+	github.com/smart-libs/go-adapter/sdk/lib v0.0.1
+	github.com/smart-libs/go-crosscutting/assertions/lib v0.0.6
+	github.com/smart-libs/go-crosscutting/converter/lib v0.0.2
+	github.com/smart-libs/go-crosscutting/serror/lib v0.0.2
+	google.golang.org/grpc v1.73.0
+	google.golang.org/protobuf v1.36.8
+)
+
+require (
+	github.com/joomcode/errorx v1.2.0 // indirect
+	github.com/smart-libs/go-adapter/interfaces v0.0.1 // indirect
+	golang.org/x/net v0.38.0 // indirect
+	golang.org/x/sys v0.31.0 // indirect
+	golang.org/x/text v0.23.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250324211829-b45e905df463 // indirect
+)
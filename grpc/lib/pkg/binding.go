@@ -0,0 +1,41 @@
+package grpcadpt
+
+import (
+	"google.golang.org/protobuf/proto"
+)
+
+type (
+	// Condition identifies the gRPC method handled by the binding
+	Condition struct {
+		// Service is the fully qualified service name, like "package.Service"
+		Service string
+		// Method is the method name inside the service
+		Method string
+		// ServerStreaming is true for the methods that return a stream of messages
+		ServerStreaming bool
+	}
+
+	Binding struct {
+		Condition
+		Handler
+
+		// RequestType is a message of the request type, a new message of this type is decoded on each call
+		RequestType proto.Message
+		// ResponseType is a message of the response type, a new message of this type is the initial
+		// Response.Message of the unary methods
+		ResponseType proto.Message
+	}
+
+	// Bindings are the methods served by the gRPC adapter, they are grouped in services by Condition.Service
+	Bindings []Binding
+)
+
+// FullMethod returns the method name in the /package.Service/Method format
+func (c Condition) FullMethod() string {
+	return "/" + c.Service + "/" + c.Method
+}
+
+func IsBindingValid(binding Binding) bool {
+	return binding.Handler != nil && binding.Service != "" && binding.Method != "" && binding.RequestType != nil &&
+		binding.ResponseType != nil
+}
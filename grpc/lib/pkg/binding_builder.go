@@ -0,0 +1,47 @@
+package grpcadpt
+
+import (
+	tagbasedhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler/tagbased"
+	"google.golang.org/protobuf/proto"
+)
+
+type (
+	MessagesBuildingStep interface {
+		// WithMessages sets the request and response message types, the given messages are used only as prototypes
+		WithMessages(request, response proto.Message) HandlerBuildingStep
+	}
+
+	HandlerBuildingStep interface {
+		WithHandlerFunc(handler any) Binding
+	}
+
+	BaseBuilder struct {
+		Binding
+	}
+)
+
+// NewUnaryBindingBuilder starts the binding of a method that receives one message and returns one message
+func NewUnaryBindingBuilder(service, method string) MessagesBuildingStep {
+	return &BaseBuilder{Binding: Binding{Condition: Condition{Service: service, Method: method}}}
+}
+
+// NewServerStreamingBindingBuilder starts the binding of a method that receives one message and returns a stream,
+// the handler shall return a channel of messages using the grpc tag
+func NewServerStreamingBindingBuilder(service, method string) MessagesBuildingStep {
+	return &BaseBuilder{Binding: Binding{Condition: Condition{Service: service, Method: method, ServerStreaming: true}}}
+}
+
+func (b *BaseBuilder) WithMessages(request, response proto.Message) HandlerBuildingStep {
+	b.RequestType = request
+	b.ResponseType = response
+	return b
+}
+
+func (b *BaseBuilder) WithHandlerFunc(handler any) Binding {
+	b.Handler = tagbasedhandler.NewBuilderForFunc[Request, *Response](handler).
+		WithInTagBasedFactory(createInParamSpecFactory()).
+		WithOutTagBasedFactory(createOutParamSpecFactory()).
+		WithOutErrorParamSpec(NewOutErrorParamSpec()).
+		Build()
+	return b.Binding
+}
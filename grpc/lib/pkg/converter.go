@@ -0,0 +1,96 @@
+package grpcadpt
+
+import (
+	"context"
+	"errors"
+
+	sdkerror "github.com/smart-libs/go-adapter/sdk/lib/pkg/error"
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	converterdefault "github.com/smart-libs/go-crosscutting/converter/lib/pkg/default"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ConverterRegistry it is the default converters registry for the gRPC adapter
+	ConverterRegistry = converterdefault.NewRegistry()
+
+	// Converters is the list of converter.Converters used by the gRPC adapter, it tries first the gRPC adapter
+	// conversions and then the default converter.Converters
+	Converters = converter.NewConvertersList(
+		converterdefault.NewConverters(ConverterRegistry),
+		converterdefault.Converters,
+	)
+
+	errorCodes sdkerror.Registry[codes.Code]
+)
+
+func init() {
+	converter.AddHandler[error, codes.Code](ConverterRegistry, errorToCode)
+	converter.AddHandler[[]string, string](ConverterRegistry, firstStringPtrFromStringArray)
+}
+
+// RegisterErrorCode makes the errors that satisfy the condition be returned with the given code. The registered
+// conditions are evaluated in the registration order before the default ones.
+func RegisterErrorCode(condition func(err error) bool, code codes.Code) {
+	errorCodes.Register(condition, code)
+}
+
+// firstStringPtrFromStringArray returns the first metadata value, most of the keys have only one value
+func firstStringPtrFromStringArray(values []string, first *string) error {
+	if len(values) > 0 {
+		*first = values[0]
+	} else {
+		*first = ""
+	}
+	return nil
+}
+
+func errorToCode(err error, to *codes.Code) error {
+	if err == nil {
+		*to = codes.OK
+		return nil
+	}
+	if grpcStatus, ok := status.FromError(err); ok {
+		*to = grpcStatus.Code()
+		return nil
+	}
+
+	callbacks := errorCodes.Callbacks(func(code codes.Code) { *to = code }, 8)
+
+	callbacks = append(callbacks,
+		serror.CallbackCondition{
+			Condition: func(err error) bool { return errors.Is(err, context.Canceled) },
+			Callback:  func(err error) { *to = codes.Canceled },
+		},
+		serror.CallbackCondition{
+			Condition: func(err error) bool { return errors.Is(err, context.DeadlineExceeded) },
+			Callback:  func(err error) { *to = codes.DeadlineExceeded },
+		},
+		serror.CallbackCondition{
+			Condition: serror.IsIllegalArgumentError,
+			Callback:  func(err error) { *to = codes.InvalidArgument },
+		},
+		serror.CallbackCondition{
+			Condition: serror.IsNotFoundError,
+			Callback:  func(err error) { *to = codes.NotFound },
+		},
+		serror.CallbackCondition{
+			Condition: serror.IsDuplicateError,
+			Callback:  func(err error) { *to = codes.AlreadyExists },
+		},
+		serror.CallbackCondition{
+			Condition: serror.IsTimeoutError,
+			Callback:  func(err error) { *to = codes.DeadlineExceeded },
+		},
+		serror.CallbackCondition{
+			Condition: serror.IsIllegalConfigError,
+			Callback:  func(err error) { *to = codes.FailedPrecondition },
+		},
+	)
+	if !serror.IdentifyRootCause(err, func(error) { *to = codes.Internal }, callbacks...) {
+		*to = codes.Internal
+	}
+	return nil
+}
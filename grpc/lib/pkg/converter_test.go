@@ -0,0 +1,38 @@
+package grpcadpt
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"google.golang.org/grpc/codes"
+)
+
+func Test_errorToCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{name: "nil", err: nil, expected: codes.OK},
+		{name: "canceled", err: fmt.Errorf("wrapped: %w", context.Canceled), expected: codes.Canceled},
+		{name: "deadline", err: context.DeadlineExceeded, expected: codes.DeadlineExceeded},
+		{name: "illegal argument", err: serror.IllegalArgumentValue("p", 1), expected: codes.InvalidArgument},
+		{name: "duplicate", err: serror.DuplicateError.New("exists"), expected: codes.AlreadyExists},
+		{name: "timeout", err: serror.WrapAsTimeout(fmt.Errorf("slow")), expected: codes.DeadlineExceeded},
+		{name: "illegal config", err: serror.IllegalConfigParamValue("p", 1), expected: codes.FailedPrecondition},
+		{name: "internal", err: fmt.Errorf("boom"), expected: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got codes.Code
+			if err := errorToCode(tt.err, &got); err != nil {
+				t.Fatalf("errorToCode() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("errorToCode() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package grpcadpt
+
+import (
+	"fmt"
+)
+
+const (
+	// TagMessage gets the request message when the tag value is empty, or the field named by the tag value. The
+	// field name can be the proto or the JSON name, and the nested fields are separated by dots, like "customer.id".
+	TagMessage = "grpc"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().AddOption2(TagMessage, getMessageInParamValue)
+}
+
+func getMessageInParamValue(input Request, path string) (any, error) {
+	if input.Message == nil {
+		return nil, nil
+	}
+	if path == "" {
+		return input.Message, nil
+	}
+	message, field, err := findField(input.Message.ProtoReflect(), path, false)
+	if err != nil {
+		return nil, fmt.Errorf("grpcadpt.getMessageInParamValue: %w", err)
+	}
+	return getFieldValue(message, field), nil
+}
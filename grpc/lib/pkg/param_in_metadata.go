@@ -0,0 +1,18 @@
+package grpcadpt
+
+const (
+	// TagMetadata gets the incoming metadata values of the key in the tag value
+	TagMetadata = "grpcmeta"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().AddOption2(TagMetadata, getMetadataInParamValue)
+}
+
+func getMetadataInParamValue(input Request, key string) (any, error) {
+	values := input.Metadata.Get(key)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return values, nil
+}
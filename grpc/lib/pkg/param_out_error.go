@@ -0,0 +1,56 @@
+package grpcadpt
+
+import (
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"github.com/smart-libs/go-crosscutting/assertions/lib/pkg/check"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// InternalErrorMessage is the message of the Internal and Unknown statuses created from the handler errors
+	InternalErrorMessage = "internal error"
+)
+
+type (
+	OutErrorParamSpec struct{}
+)
+
+func (o OutErrorParamSpec) Name() string               { return "error" }
+func (o OutErrorParamSpec) Options() []sdkparam.Option { return nil }
+
+// SetValue sets the Response.Status from the error and discards the response messages
+func (o OutErrorParamSpec) SetValue(output *Response, value any) error {
+	if check.IsNil(value) {
+		return nil // no error
+	}
+	if output == nil {
+		return serror.CmpError.New("grpcadpt.OutErrorParamSpec.SetValue: output is nil")
+	}
+	if err, ok := value.(error); ok {
+		output.Status = errorToStatus(err)
+		output.Message = nil
+		output.Stream = nil
+	}
+	return nil
+}
+
+func NewOutErrorParamSpec() sdkparam.OutputParamSpec[*Response] {
+	return OutErrorParamSpec{}
+}
+
+// errorToStatus returns the status of the errors created by the status package, or a new one with the code mapped
+// by the registered conditions and the error message. The Internal and Unknown statuses get InternalErrorMessage, so
+// the details of the unexpected errors and panics are not sent to the clients.
+func errorToStatus(err error) *status.Status {
+	if grpcStatus, ok := status.FromError(err); ok {
+		return grpcStatus
+	}
+	var code codes.Code
+	_ = errorToCode(err, &code)
+	if code == codes.Internal || code == codes.Unknown {
+		return status.New(code, InternalErrorMessage)
+	}
+	return status.New(code, err.Error())
+}
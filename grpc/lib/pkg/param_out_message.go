@@ -0,0 +1,50 @@
+package grpcadpt
+
+import (
+	"github.com/smart-libs/go-crosscutting/assertions/lib/pkg/check"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	getOutParamSpecFactoryRegistry().AddOption2(TagMessage, setMessageOutParamValue)
+}
+
+// setMessageOutParamValue replaces the response message when the tag value is empty, or sets the field named by the
+// tag value. With an empty tag value, a channel of messages becomes the Response.Stream.
+func setMessageOutParamValue(output *Response, path string, value any) error {
+	const fName = "grpcadpt.setMessageOutParamValue"
+	if output == nil {
+		return serror.CmpError.New(fName + ": output is nil")
+	}
+	if check.IsNil(value) {
+		return nil
+	}
+	if path == "" {
+		return setMessage(output, value)
+	}
+	if output.Message == nil {
+		return serror.CmpError.New("%s: cannot set field=[%s] because the response message is nil", fName, path)
+	}
+	message, field, err := findField(output.Message.ProtoReflect(), path, true)
+	if err == nil {
+		err = setFieldValue(message, field, value)
+	}
+	if err != nil {
+		return serror.CmpError.Wrap(err, "%s: failed to set field=[%s]", fName, path)
+	}
+	return nil
+}
+
+func setMessage(output *Response, value any) error {
+	if message, ok := value.(proto.Message); ok {
+		output.Message = message
+		return nil
+	}
+	stream, err := NewChanStream(value)
+	if err != nil {
+		return serror.CmpError.Wrap(err, "grpcadpt.setMessage: value=[%T] is neither a proto.Message nor a stream", value)
+	}
+	output.Stream = stream
+	return nil
+}
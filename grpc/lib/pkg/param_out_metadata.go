@@ -0,0 +1,50 @@
+package grpcadpt
+
+import (
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// TagTrailer sets the trailer metadata key in the tag value
+	TagTrailer = "grpctrailer"
+)
+
+func init() {
+	getOutParamSpecFactoryRegistry().AddOption2(TagMetadata, func(output *Response, key string, value any) error {
+		return setMetadataOutParamValue(output, &output.Header, key, value)
+	})
+	getOutParamSpecFactoryRegistry().AddOption2(TagTrailer, func(output *Response, key string, value any) error {
+		return setMetadataOutParamValue(output, &output.Trailer, key, value)
+	})
+}
+
+// setMetadataOutParamValue appends the value to the metadata key, a []string value appends all its elements
+func setMetadataOutParamValue(output *Response, md *metadata.MD, key string, value any) error {
+	const fName = "grpcadpt.setMetadataOutParamValue"
+	if output == nil {
+		return serror.CmpError.New(fName + ": output is nil")
+	}
+	var values []string
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case []string:
+		values = typed
+	default:
+		asString, err := converter.To[string](Converters, value)
+		if err != nil {
+			return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+		}
+		values = []string{asString}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	if *md == nil {
+		*md = metadata.MD{}
+	}
+	md.Append(key, values...)
+	return nil
+}
@@ -0,0 +1,175 @@
+package grpcadpt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// findField resolves a path of field names separated by dots, each one can be the proto or the JSON name. It returns
+// the message holding the last field. If mutable is false and an intermediate message is not set, the returned
+// message is nil. If mutable is true, the intermediate messages are created.
+func findField(message protoreflect.Message, path string, mutable bool) (protoreflect.Message, protoreflect.FieldDescriptor, error) {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fields := message.Descriptor().Fields()
+		field := fields.ByName(protoreflect.Name(name))
+		if field == nil {
+			field = fields.ByJSONName(name)
+		}
+		if field == nil {
+			return nil, nil, fmt.Errorf("message=[%s] has no field=[%s]", message.Descriptor().FullName(), name)
+		}
+		if i == len(names)-1 {
+			return message, field, nil
+		}
+		if field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() {
+			return nil, nil, fmt.Errorf("field=[%s] of message=[%s] is not a message", name, message.Descriptor().FullName())
+		}
+		switch {
+		case mutable:
+			message = message.Mutable(field).Message()
+		case message.Has(field):
+			message = message.Get(field).Message()
+		default:
+			return nil, field, nil
+		}
+	}
+	return message, nil, nil
+}
+
+// getFieldValue returns the Go value of the field, or nil if it is not set. The enums are returned as int32, the
+// messages as proto.Message, the repeated fields as slices and the map fields as maps.
+func getFieldValue(message protoreflect.Message, field protoreflect.FieldDescriptor) any {
+	if message == nil || !message.Has(field) {
+		return nil
+	}
+	value := message.Get(field)
+	switch {
+	case field.IsList():
+		list := value.List()
+		slice := reflect.MakeSlice(reflect.SliceOf(goTypeOf(field, list.Get(0))), list.Len(), list.Len())
+		for i := 0; i < list.Len(); i++ {
+			slice.Index(i).Set(reflect.ValueOf(toGoValue(field, list.Get(i))))
+		}
+		return slice.Interface()
+
+	case field.IsMap():
+		entries := value.Map()
+		var mapValue reflect.Value
+		entries.Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+			goValue := toGoValue(field.MapValue(), value)
+			if !mapValue.IsValid() {
+				keyType := reflect.TypeOf(key.Interface())
+				mapValue = reflect.MakeMapWithSize(reflect.MapOf(keyType, goTypeOf(field.MapValue(), value)), entries.Len())
+			}
+			mapValue.SetMapIndex(reflect.ValueOf(key.Interface()), reflect.ValueOf(goValue))
+			return true
+		})
+		return mapValue.Interface()
+
+	default:
+		return toGoValue(field, value)
+	}
+}
+
+func toGoValue(field protoreflect.FieldDescriptor, value protoreflect.Value) any {
+	switch field.Kind() {
+	case protoreflect.EnumKind:
+		return int32(value.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return value.Message().Interface()
+	default:
+		return value.Interface()
+	}
+}
+
+func goTypeOf(field protoreflect.FieldDescriptor, sample protoreflect.Value) reflect.Type {
+	return reflect.TypeOf(toGoValue(field, sample))
+}
+
+// setFieldValue converts the value to the field type and sets it. The repeated fields accept slices whose elements
+// can be converted to the field type. The map fields are not supported.
+func setFieldValue(message protoreflect.Message, field protoreflect.FieldDescriptor, value any) error {
+	switch {
+	case field.IsMap():
+		return fmt.Errorf("map field=[%s] is not supported", field.FullName())
+
+	case field.IsList():
+		slice := reflect.ValueOf(value)
+		if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+			return fmt.Errorf("field=[%s] is repeated, but value type=[%T] is not a slice", field.FullName(), value)
+		}
+		list := message.NewField(field).List()
+		for i := 0; i < slice.Len(); i++ {
+			element, err := toProtoValue(field, slice.Index(i).Interface())
+			if err != nil {
+				return err
+			}
+			list.Append(element)
+		}
+		message.Set(field, protoreflect.ValueOfList(list))
+		return nil
+
+	default:
+		protoValue, err := toProtoValue(field, value)
+		if err != nil {
+			return err
+		}
+		message.Set(field, protoValue)
+		return nil
+	}
+}
+
+func toProtoValue(field protoreflect.FieldDescriptor, value any) (protoreflect.Value, error) {
+	var (
+		result protoreflect.Value
+		err    error
+	)
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		result, err = convertToProtoValue(value, protoreflect.ValueOfBool)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		result, err = convertToProtoValue(value, protoreflect.ValueOfInt32)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		result, err = convertToProtoValue(value, protoreflect.ValueOfInt64)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		result, err = convertToProtoValue(value, protoreflect.ValueOfUint32)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		result, err = convertToProtoValue(value, protoreflect.ValueOfUint64)
+	case protoreflect.FloatKind:
+		result, err = convertToProtoValue(value, protoreflect.ValueOfFloat32)
+	case protoreflect.DoubleKind:
+		result, err = convertToProtoValue(value, protoreflect.ValueOfFloat64)
+	case protoreflect.StringKind:
+		result, err = convertToProtoValue(value, protoreflect.ValueOfString)
+	case protoreflect.BytesKind:
+		result, err = convertToProtoValue(value, protoreflect.ValueOfBytes)
+	case protoreflect.EnumKind:
+		result, err = convertToProtoValue(value, func(number int32) protoreflect.Value {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(number))
+		})
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		message, ok := value.(proto.Message)
+		if !ok || message.ProtoReflect().Descriptor().FullName() != field.Message().FullName() {
+			return result, fmt.Errorf("field=[%s] expects message=[%s], not type=[%T]", field.FullName(),
+				field.Message().FullName(), value)
+		}
+		result = protoreflect.ValueOfMessage(message.ProtoReflect())
+	default:
+		err = fmt.Errorf("field=[%s] kind=[%s] is not supported", field.FullName(), field.Kind())
+	}
+	return result, err
+}
+
+func convertToProtoValue[T any](value any, valueOf func(T) protoreflect.Value) (protoreflect.Value, error) {
+	converted, err := converter.To[T](Converters, value)
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+	return valueOf(converted), nil
+}
@@ -0,0 +1,82 @@
+package grpcadpt
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func Test_getMessageInParamValue(t *testing.T) {
+	input := Request{Message: &typepb.Type{
+		Name:    "Order",
+		Oneofs:  []string{"payment", "shipping"},
+		Syntax:  typepb.Syntax_SYNTAX_EDITIONS,
+		Fields:  []*typepb.Field{{Name: "id"}},
+		Edition: "2023",
+	}}
+	tests := []struct {
+		name     string
+		path     string
+		expected any
+		wantErr  bool
+	}{
+		{name: "proto name", path: "name", expected: "Order"},
+		{name: "repeated scalar", path: "oneofs", expected: []string{"payment", "shipping"}},
+		{name: "enum", path: "syntax", expected: int32(typepb.Syntax_SYNTAX_EDITIONS)},
+		{name: "unset field", path: "source_context", expected: nil},
+		{name: "unset nested field", path: "source_context.file_name", expected: nil},
+		{name: "unknown field", path: "unknown", wantErr: true},
+		{name: "not a message", path: "name.first", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getMessageInParamValue(input, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got = %#v, want %#v", got, tt.expected)
+			}
+		})
+	}
+
+	fields, err := getMessageInParamValue(input, "fields")
+	if typed, ok := fields.([]*typepb.Field); err != nil || !ok || typed[0].GetName() != "id" {
+		t.Errorf("repeated message = %#v, err = %v", fields, err)
+	}
+}
+
+func Test_setMessageOutParamValue(t *testing.T) {
+	output := &Response{Message: &typepb.Type{}}
+	values := map[string]any{
+		"name":                     42,
+		"oneofs":                   []string{"a", "b"},
+		"syntax":                   1,
+		"sourceContext.file_name":  "order.proto",
+		"source_context.file_name": "order.proto",
+	}
+	for path, value := range values {
+		if err := setMessageOutParamValue(output, path, value); err != nil {
+			t.Fatalf("setMessageOutParamValue(%s) error = %v", path, err)
+		}
+	}
+	expected := &typepb.Type{
+		Name:          "42",
+		Oneofs:        []string{"a", "b"},
+		Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "order.proto"},
+	}
+	if got := output.Message.(*typepb.Type); got.GetName() != expected.GetName() || !reflect.DeepEqual(got.GetOneofs(), expected.GetOneofs()) ||
+		got.GetSyntax() != expected.GetSyntax() || got.GetSourceContext().GetFileName() != "order.proto" {
+		t.Errorf("message = %v", got)
+	}
+
+	if err := setMessageOutParamValue(output, "source_context", &typepb.Field{}); err == nil {
+		t.Error("a message of another type should be rejected")
+	}
+	if err := setMessageOutParamValue(output, "", "not a message"); err == nil {
+		t.Error("a value that is neither a message nor a channel should be rejected")
+	}
+}
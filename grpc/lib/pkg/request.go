@@ -0,0 +1,19 @@
+package grpcadpt
+
+import (
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+type (
+	// Request is the input of the gRPC handlers, it holds the request message decoded by the server and the metadata
+	// sent by the client
+	Request struct {
+		// FullMethod is the method name in the /package.Service/Method format
+		FullMethod string
+		// Message is the request message, its type is the Binding.RequestType
+		Message proto.Message
+		// Metadata is the incoming metadata, the keys are lower case
+		Metadata metadata.MD
+	}
+)
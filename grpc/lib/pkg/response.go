@@ -0,0 +1,64 @@
+package grpcadpt
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type (
+	// MessageStream returns the next message sent by a server streaming method, ok is false when there are no more
+	// messages
+	MessageStream func(ctx context.Context) (message proto.Message, ok bool, err error)
+
+	Response struct {
+		// Message is the response of the unary methods. It is created by the adapter with the Binding.ResponseType
+		// before the handler is invoked, so the grpc tag can set its fields.
+		Message proto.Message
+
+		// Stream when set provides the messages of the server streaming methods, they are sent until it returns ok
+		// false, an error or the client cancels the call
+		Stream MessageStream
+
+		// Header is the metadata sent before the first response message
+		Header metadata.MD
+
+		// Trailer is the metadata sent with the status when the call ends
+		Trailer metadata.MD
+
+		// Status is set by OutErrorParamSpec when the handler fails
+		Status *status.Status
+	}
+)
+
+// IsStreaming returns true if the response messages are provided by Stream instead of Message
+func (r *Response) IsStreaming() bool {
+	return r != nil && r.Stream != nil
+}
+
+// NewChanStream returns a MessageStream that receives the messages from a channel whose element type implements
+// proto.Message, like <-chan *pb.Item, until it is closed
+func NewChanStream(channel any) (MessageStream, error) {
+	value := reflect.ValueOf(channel)
+	if value.Kind() != reflect.Chan || value.Type().ChanDir()&reflect.RecvDir == 0 ||
+		!value.Type().Elem().Implements(reflect.TypeFor[proto.Message]()) {
+		return nil, fmt.Errorf("grpcadpt.NewChanStream: type=[%T] is not a channel of proto.Message", channel)
+	}
+	return func(ctx context.Context) (proto.Message, bool, error) {
+		chosen, received, ok := reflect.Select([]reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			{Dir: reflect.SelectRecv, Chan: value},
+		})
+		if chosen == 0 {
+			return nil, false, ctx.Err()
+		}
+		if !ok || received.IsNil() {
+			return nil, ok, nil
+		}
+		return received.Interface().(proto.Message), true, nil
+	}, nil
+}
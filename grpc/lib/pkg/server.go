@@ -0,0 +1,210 @@
+package grpcadpt
+
+import (
+	"context"
+	"fmt"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type (
+	Config struct {
+		// Bindings are the methods served
+		Bindings
+		// ServerOptions are given to grpc.NewServer, like the interceptors and the credentials
+		ServerOptions []grpc.ServerOption
+	}
+
+	// service is the implementation given to grpc.ServiceRegistrar.RegisterService, the methods are dispatched by the
+	// handlers of the grpc.ServiceDesc
+	service struct{}
+)
+
+// NewServer creates a grpc.Server with the given options and registers the bindings
+func NewServer(config Config) (*grpc.Server, error) {
+	descs, err := NewServiceDescs(config.Bindings)
+	if err != nil {
+		return nil, err
+	}
+	server := grpc.NewServer(config.ServerOptions...)
+	for i := range descs {
+		server.RegisterService(&descs[i], service{})
+	}
+	return server, nil
+}
+
+// Register registers the bindings in the registrar, like an existing *grpc.Server, so they can be served with
+// services generated by protoc
+func Register(registrar grpc.ServiceRegistrar, bindings Bindings) error {
+	descs, err := NewServiceDescs(bindings)
+	if err != nil {
+		return err
+	}
+	for i := range descs {
+		registrar.RegisterService(&descs[i], service{})
+	}
+	return nil
+}
+
+// NewServiceDescs returns one grpc.ServiceDesc for each service of the bindings, in the order they first appear
+func NewServiceDescs(bindings Bindings) ([]grpc.ServiceDesc, error) {
+	var (
+		descs   []grpc.ServiceDesc
+		indexes = map[string]int{}
+		methods = map[string]bool{}
+	)
+	for _, binding := range bindings {
+		if !IsBindingValid(binding) {
+			return nil, serror.IllegalConfigParamValue("Binding", binding.FullMethod())
+		}
+		if methods[binding.FullMethod()] {
+			return nil, serror.IllegalConfigParamValue("Binding", fmt.Sprintf("duplicated %s", binding.FullMethod()))
+		}
+		methods[binding.FullMethod()] = true
+
+		index, found := indexes[binding.Service]
+		if !found {
+			index = len(descs)
+			indexes[binding.Service] = index
+			descs = append(descs, grpc.ServiceDesc{ServiceName: binding.Service, HandlerType: (*any)(nil)})
+		}
+		if binding.ServerStreaming {
+			descs[index].Streams = append(descs[index].Streams, grpc.StreamDesc{
+				StreamName:    binding.Method,
+				Handler:       binding.streamHandler,
+				ServerStreams: true,
+			})
+		} else {
+			descs[index].Methods = append(descs[index].Methods, grpc.MethodDesc{
+				MethodName: binding.Method,
+				Handler:    binding.unaryHandler,
+			})
+		}
+	}
+	return descs, nil
+}
+
+func (b Binding) unaryHandler(srv any, ctx context.Context, decode func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	request := b.RequestType.ProtoReflect().New().Interface()
+	if err := decode(request); err != nil {
+		return nil, err
+	}
+	invoke := func(ctx context.Context, request any) (any, error) {
+		output := &Response{Message: b.ResponseType.ProtoReflect().New().Interface()}
+		err := b.invoke(ctx, request.(proto.Message), output)
+		if len(output.Header) > 0 {
+			_ = grpc.SetHeader(ctx, output.Header)
+		}
+		if len(output.Trailer) > 0 {
+			_ = grpc.SetTrailer(ctx, output.Trailer)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if output.Message == nil {
+			return b.ResponseType.ProtoReflect().New().Interface(), nil
+		}
+		return output.Message, nil
+	}
+	if interceptor == nil {
+		return invoke(ctx, request)
+	}
+	return interceptor(ctx, request, &grpc.UnaryServerInfo{Server: srv, FullMethod: b.FullMethod()}, invoke)
+}
+
+func (b Binding) streamHandler(_ any, stream grpc.ServerStream) error {
+	request := b.RequestType.ProtoReflect().New().Interface()
+	if err := stream.RecvMsg(request); err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	output := &Response{}
+	err := b.invoke(ctx, request, output)
+	if len(output.Trailer) > 0 {
+		defer stream.SetTrailer(output.Trailer)
+	}
+	if len(output.Header) > 0 {
+		if headerErr := stream.SendHeader(output.Header); err == nil {
+			err = headerErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if output.Stream == nil {
+		if output.Message == nil {
+			return nil
+		}
+		return stream.SendMsg(output.Message)
+	}
+
+	for {
+		message, ok, err := nextMessage(ctx, output.Stream)
+		if err != nil {
+			return errorToStatus(err).Err()
+		}
+		if !ok {
+			return nil
+		}
+		if message == nil {
+			continue
+		}
+		if err := b.checkResponseType(message); err != nil {
+			return err
+		}
+		if err := stream.SendMsg(message); err != nil {
+			return err
+		}
+	}
+}
+
+// nextMessage returns the next message of the stream, a panic of the stream is returned as an internal error
+func nextMessage(ctx context.Context, stream MessageStream) (message proto.Message, ok bool, err error) {
+	defer func() {
+		if panicArg := recover(); panicArg != nil {
+			err = serror.WrapAsInternalError(fmt.Errorf("%v", panicArg))
+		}
+	}()
+	return stream(ctx)
+}
+
+// invoke calls the binding handler and returns the status error of the handler error, of the panic or of the
+// Response.Status
+func (b Binding) invoke(ctx context.Context, request proto.Message, output *Response) (err error) {
+	defer func() {
+		if panicArg := recover(); panicArg != nil {
+			// input params that fail the assertions panic with a classified error, e.g. InvalidArgument
+			panicErr, ok := panicArg.(error)
+			if !ok {
+				panicErr = serror.WrapAsInternalError(fmt.Errorf("%v", panicArg))
+			}
+			err = errorToStatus(panicErr).Err()
+		}
+	}()
+
+	incoming, _ := metadata.FromIncomingContext(ctx)
+	input := Request{FullMethod: b.FullMethod(), Message: request, Metadata: incoming}
+	if err := b.Handler.Invoke(ctx, input, output); err != nil {
+		return errorToStatus(err).Err()
+	}
+	if output.Status != nil && output.Status.Code() != codes.OK {
+		return output.Status.Err()
+	}
+	if output.Message != nil && output.Stream == nil {
+		return b.checkResponseType(output.Message)
+	}
+	return nil
+}
+
+func (b Binding) checkResponseType(message proto.Message) error {
+	expected := b.ResponseType.ProtoReflect().Descriptor().FullName()
+	if got := message.ProtoReflect().Descriptor().FullName(); got != expected {
+		return status.Errorf(codes.Internal, "%s: response message=[%s] is not a %s", b.FullMethod(), got, expected)
+	}
+	return nil
+}
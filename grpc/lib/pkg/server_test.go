@@ -0,0 +1,279 @@
+package grpcadpt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/typepb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const testService = "test.Types"
+
+var errTestUnavailable = errors.New("backend unavailable")
+
+func init() {
+	RegisterErrorCode(func(err error) bool { return errors.Is(err, errTestUnavailable) }, codes.Unavailable)
+}
+
+type (
+	describeInput struct {
+		Name     string `grpc:"name" assert:"mandatory"`
+		Syntax   int32  `grpc:"syntax"`
+		FileName string `grpc:"sourceContext.file_name"`
+		Tenant   string `grpcmeta:"x-tenant" assert:"mandatory"`
+	}
+
+	describeOutput struct {
+		Value    string `grpc:"value"`
+		ServedBy string `grpcmeta:"x-served-by"`
+		Cost     int    `grpctrailer:"x-cost"`
+	}
+
+	listFieldsInput struct {
+		Type *typepb.Type `grpc:""`
+	}
+
+	listFieldsOutput struct {
+		Fields <-chan *wrapperspb.StringValue `grpc:""`
+	}
+)
+
+func startTestServer(t *testing.T, bindings Bindings) *grpc.ClientConn {
+	t.Helper()
+	server, err := NewServer(Config{Bindings: bindings})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func describeBinding(handler any) Binding {
+	return NewUnaryBindingBuilder(testService, "Describe").
+		WithMessages(&typepb.Type{}, &wrapperspb.StringValue{}).
+		WithHandlerFunc(handler)
+}
+
+func Test_UnaryBinding(t *testing.T) {
+	conn := startTestServer(t, Bindings{describeBinding(func(in describeInput) (describeOutput, error) {
+		return describeOutput{
+			Value:    in.Tenant + ":" + in.Name + ":" + in.FileName,
+			ServedBy: "grpcadpt",
+			Cost:     int(in.Syntax) + 1,
+		}, nil
+	})})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "acme")
+	request := &typepb.Type{
+		Name:          "Order",
+		Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "order.proto"},
+	}
+	response := &wrapperspb.StringValue{}
+	var header, trailer metadata.MD
+	if err := conn.Invoke(ctx, "/test.Types/Describe", request, response, grpc.Header(&header), grpc.Trailer(&trailer)); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if response.GetValue() != "acme:Order:order.proto" {
+		t.Errorf("response = %q", response.GetValue())
+	}
+	if got := header.Get("x-served-by"); len(got) != 1 || got[0] != "grpcadpt" {
+		t.Errorf("header = %v", header)
+	}
+	if got := trailer.Get("x-cost"); len(got) != 1 || got[0] != "2" {
+		t.Errorf("trailer = %v", trailer)
+	}
+}
+
+func Test_UnaryBinding_Errors(t *testing.T) {
+	var handlerErr error
+	conn := startTestServer(t, Bindings{describeBinding(func(in describeInput) (describeOutput, error) {
+		return describeOutput{Value: in.Name}, handlerErr
+	})})
+
+	tests := []struct {
+		name         string
+		tenant       string
+		handlerErr   error
+		expectedCode codes.Code
+	}{
+		{name: "success", tenant: "acme", expectedCode: codes.OK},
+		{name: "illegal argument", tenant: "acme", handlerErr: serror.IllegalArgumentValue("name", "x"), expectedCode: codes.InvalidArgument},
+		{name: "not found", tenant: "acme", handlerErr: serror.NotFoundError.New("type not found"), expectedCode: codes.NotFound},
+		{name: "status error", tenant: "acme", handlerErr: status.Error(codes.PermissionDenied, "denied"), expectedCode: codes.PermissionDenied},
+		{name: "registered error", tenant: "acme", handlerErr: errTestUnavailable, expectedCode: codes.Unavailable},
+		{name: "unknown error", tenant: "acme", handlerErr: errors.New("boom"), expectedCode: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlerErr = tt.handlerErr
+			ctx := context.Background()
+			if tt.tenant != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant", tt.tenant)
+			}
+			err := conn.Invoke(ctx, "/test.Types/Describe", &typepb.Type{Name: "Order"}, &wrapperspb.StringValue{})
+			if got := status.Code(err); got != tt.expectedCode {
+				t.Errorf("code = %s, want %s, err = %v", got, tt.expectedCode, err)
+			}
+			if tt.expectedCode == codes.Internal && status.Convert(err).Message() != InternalErrorMessage {
+				t.Errorf("message = %q, want %q", status.Convert(err).Message(), InternalErrorMessage)
+			}
+		})
+	}
+}
+
+func Test_ServerStreamingBinding(t *testing.T) {
+	binding := NewServerStreamingBindingBuilder(testService, "ListFields").
+		WithMessages(&typepb.Type{}, &wrapperspb.StringValue{}).
+		WithHandlerFunc(func(in listFieldsInput) (listFieldsOutput, error) {
+			fields := make(chan *wrapperspb.StringValue)
+			go func() {
+				defer close(fields)
+				for _, field := range in.Type.GetFields() {
+					fields <- wrapperspb.String(field.GetName())
+				}
+			}()
+			return listFieldsOutput{Fields: fields}, nil
+		})
+	conn := startTestServer(t, Bindings{binding})
+
+	stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/test.Types/ListFields")
+	if err != nil {
+		t.Fatalf("NewStream() error = %v", err)
+	}
+	request := &typepb.Type{Fields: []*typepb.Field{{Name: "id"}, {Name: "total"}}}
+	if err := stream.SendMsg(request); err != nil {
+		t.Fatalf("SendMsg() error = %v", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend() error = %v", err)
+	}
+
+	var names []string
+	for {
+		message := &wrapperspb.StringValue{}
+		if err := stream.RecvMsg(message); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("RecvMsg() error = %v", err)
+		}
+		names = append(names, message.GetValue())
+	}
+	if len(names) != 2 || names[0] != "id" || names[1] != "total" {
+		t.Errorf("received = %v", names)
+	}
+}
+
+func Test_UnaryBinding_Interceptor(t *testing.T) {
+	var intercepted string
+	server, err := NewServer(Config{
+		Bindings: Bindings{describeBinding(func(in struct {
+			Type *typepb.Type `grpc:""`
+		}) (struct {
+			Message proto.Message `grpc:""`
+		}, error) {
+			return struct {
+				Message proto.Message `grpc:""`
+			}{Message: wrapperspb.String(in.Type.GetName())}, nil
+		})},
+		ServerOptions: []grpc.ServerOption{grpc.UnaryInterceptor(
+			func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				intercepted = info.FullMethod
+				return handler(ctx, req)
+			})},
+	})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	response := &wrapperspb.StringValue{}
+	if err := conn.Invoke(context.Background(), "/test.Types/Describe", &typepb.Type{Name: "Order"}, response); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if response.GetValue() != "Order" || intercepted != "/test.Types/Describe" {
+		t.Errorf("response = %q, intercepted = %q", response.GetValue(), intercepted)
+	}
+}
+
+func Test_NewServiceDescs(t *testing.T) {
+	handler := func(in describeInput) (describeOutput, error) { return describeOutput{}, nil }
+	descs, err := NewServiceDescs(Bindings{
+		describeBinding(handler),
+		NewServerStreamingBindingBuilder(testService, "ListFields").
+			WithMessages(&typepb.Type{}, &wrapperspb.StringValue{}).
+			WithHandlerFunc(func(in listFieldsInput) (listFieldsOutput, error) { return listFieldsOutput{}, nil }),
+		NewUnaryBindingBuilder("test.Other", "Describe").
+			WithMessages(&typepb.Type{}, &wrapperspb.StringValue{}).
+			WithHandlerFunc(handler),
+	})
+	if err != nil {
+		t.Fatalf("NewServiceDescs() error = %v", err)
+	}
+	if len(descs) != 2 || len(descs[0].Methods) != 1 || len(descs[0].Streams) != 1 || len(descs[1].Methods) != 1 {
+		t.Errorf("descs = %+v", descs)
+	}
+
+	if _, err := NewServiceDescs(Bindings{describeBinding(handler), describeBinding(handler)}); err == nil {
+		t.Error("duplicated methods should be rejected")
+	}
+	if _, err := NewServiceDescs(Bindings{{Condition: Condition{Service: testService, Method: "Describe"}}}); err == nil {
+		t.Error("bindings without handler should be rejected")
+	}
+}
+
+func Test_ServerStreamingBinding_Panic(t *testing.T) {
+	binding := NewServerStreamingBindingBuilder(testService, "ListFields").
+		WithMessages(&typepb.Type{}, &wrapperspb.StringValue{}).
+		WithHandlerFunc(func(listFieldsInput) (listFieldsOutput, error) { return listFieldsOutput{}, nil })
+	binding.Handler = MakeHandler(func(_ context.Context, _ Request, output *Response) error {
+		output.Stream = func(context.Context) (proto.Message, bool, error) { panic("secret detail") }
+		return nil
+	})
+	conn := startTestServer(t, Bindings{binding})
+
+	stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/test.Types/ListFields")
+	if err != nil {
+		t.Fatalf("NewStream() error = %v", err)
+	}
+	if err := stream.SendMsg(&typepb.Type{}); err != nil {
+		t.Fatalf("SendMsg() error = %v", err)
+	}
+	_ = stream.CloseSend()
+	err = stream.RecvMsg(&wrapperspb.StringValue{})
+	if status.Code(err) != codes.Internal || status.Convert(err).Message() != InternalErrorMessage {
+		t.Errorf("RecvMsg() error = %v, want an internal error without the panic detail", err)
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/smart-libs/go-adapter/http/impl/awslambda
+
+go 1.25
+
+require (
+	github.com/aws/aws-lambda-go v1.49.0
+	github.com/smart-libs/go-adapter/http/impl/gonethttp v0.0.1
+	github.com/smart-libs/go-adapter/http/lib v0.0.3
+	github.com/smart-libs/go-crosscutting/serror/lib v0.0.2
+)
+
+require (
+	github.com/joomcode/errorx v1.2.0 // indirect
+	github.com/smart-libs/go-adapter/interfaces v0.0.1 // indirect
+	github.com/smart-libs/go-adapter/sdk/lib v0.0.1 // indirect
+	github.com/smart-libs/go-crosscutting/assertions/lib v0.0.6 // indirect
+	github.com/smart-libs/go-crosscutting/converter/lib v0.0.2 // indirect
+	github.com/smart-libs/go-crosscutting/types/lib v0.0.1 // indirect
+)
@@ -0,0 +1,106 @@
+package awslambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	gonethttp "github.com/smart-libs/go-adapter/http/impl/gonethttp/pkg"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	// Handler serves the httpadpt.Config bindings in AWS Lambda. The API Gateway and ALB proxy events are converted
+	// into http.Request and routed like the gonethttp adapter does, so the path templates, methods and middlewares
+	// work unchanged.
+	Handler struct {
+		handler http.Handler
+	}
+
+	// eventProbe has the fields that tell the event types apart
+	eventProbe struct {
+		Version        string `json:"version"`
+		HTTPMethod     string `json:"httpMethod"`
+		RequestContext struct {
+			ELB  json.RawMessage `json:"elb"`
+			HTTP json.RawMessage `json:"http"`
+		} `json:"requestContext"`
+	}
+)
+
+// NewHandler creates the Handler of the config bindings. There is no server to start in Lambda, so the Config.Health
+// is ready once the Handler is created.
+func NewHandler(config httpadpt.Config) (*Handler, error) {
+	handler, err := gonethttp.NewHandler(config)
+	if err != nil {
+		return nil, err
+	}
+	config.Health.SetReady(true)
+	return &Handler{handler: handler}, nil
+}
+
+// HandleAPIGatewayV1 handles the API Gateway REST API events, and the HTTP API ones with payload format 1.0
+func (h *Handler) HandleAPIGatewayV1(ctx context.Context, event events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	request, err := newV1Request(ctx, event)
+	return h.serve(request, err).toV1Response(), nil
+}
+
+// HandleAPIGatewayV2 handles the API Gateway HTTP API events with payload format 2.0, and the Lambda function URL ones
+func (h *Handler) HandleAPIGatewayV2(ctx context.Context, event events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	request, err := newV2Request(ctx, event)
+	return h.serve(request, err).toV2Response(), nil
+}
+
+// HandleALB handles the Application Load Balancer target group events
+func (h *Handler) HandleALB(ctx context.Context, event events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+	request, err := newALBRequest(ctx, event)
+	return h.serve(request, err).toALBResponse(event.MultiValueHeaders != nil), nil
+}
+
+// Invoke handles the raw event payload, it detects the event type so one function can be behind any of the proxy
+// integrations. It implements the lambda.Handler interface, so the Handler can be given to lambda.Start.
+func (h *Handler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	var probe eventProbe
+	if err := json.Unmarshal(payload, &probe); err != nil {
+		return nil, serror.IllegalArgumentValueWithCause("event", "json", err)
+	}
+	var response any
+	var err error
+	switch {
+	case probe.RequestContext.ELB != nil:
+		response, err = invoke(ctx, payload, h.HandleALB)
+	case probe.Version == "2.0" || probe.RequestContext.HTTP != nil:
+		response, err = invoke(ctx, payload, h.HandleAPIGatewayV2)
+	case probe.HTTPMethod != "":
+		response, err = invoke(ctx, payload, h.HandleAPIGatewayV1)
+	default:
+		return nil, serror.IllegalArgumentValue("event", "unsupported type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(response)
+}
+
+func invoke[E any, R any](ctx context.Context, payload []byte, handle func(context.Context, E) (R, error)) (any, error) {
+	var event E
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, serror.IllegalArgumentValueWithCause("event", fmt.Sprintf("%T", event), err)
+	}
+	return handle(ctx, event)
+}
+
+// serve routes the request, the events that cannot be converted get 400 instead of failing the invocation, that
+// would make the proxy return 502
+func (h *Handler) serve(request *http.Request, err error) *responseRecorder {
+	recorder := newResponseRecorder()
+	if err != nil {
+		http.Error(recorder, err.Error(), http.StatusBadRequest)
+		return recorder
+	}
+	h.handler.ServeHTTP(recorder, request)
+	return recorder
+}
@@ -0,0 +1,213 @@
+package awslambda
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+)
+
+type (
+	getOrderInput struct {
+		ID      string `path:"id"`
+		Fields  string `query:"fields"`
+		Tenant  string `header:"X-Tenant"`
+		Session string `cookie:"session"`
+	}
+
+	createOrderInput struct {
+		Name   string `form:"name" assert:"mandatory"`
+		DryRun string `query:"dryRun"`
+	}
+
+	orderOutput struct {
+		StatusCode  int    `statuscode:""`
+		ContentType string `header:"Content-Type"`
+		Visited     string `cookie:"visited,Path=/"`
+		Body        string `body:""`
+	}
+
+	labelOutput struct {
+		ContentType string `header:"Content-Type"`
+		Body        []byte `body:""`
+	}
+)
+
+var label = []byte{0x89, 'P', 'N', 'G', 0xff, 0x00}
+
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	trace := func(next httpadpt.Handler) httpadpt.Handler {
+		return httpadpt.MakeHandler(func(ctx context.Context, input httpadpt.Request, output *httpadpt.Response) error {
+			if output.Header == nil {
+				output.Header = map[string][]string{}
+			}
+			output.Header["X-Trace"] = []string{"global"}
+			return next.Invoke(ctx, input, output)
+		})
+	}
+	handler, err := NewHandler(httpadpt.Config{
+		Bindings: httpadpt.Bindings{
+			httpadpt.NewBindingBuilderUsingPath("/orders/{id}").WithMethods(http.MethodGet).
+				WithHandlerFunc(func(in getOrderInput) (*orderOutput, error) {
+					return &orderOutput{StatusCode: http.StatusOK, ContentType: "application/json", Visited: "yes",
+						Body: `{"id":"` + in.ID + `","fields":"` + in.Fields + `","tenant":"` + in.Tenant +
+							`","session":"` + in.Session + `"}`}, nil
+				}),
+			httpadpt.NewBindingBuilderUsingPath("/orders").WithMethods(http.MethodPost).
+				WithHandlerFunc(func(in createOrderInput) (*orderOutput, error) {
+					return &orderOutput{StatusCode: http.StatusCreated, ContentType: "text/plain", Visited: "yes",
+						Body: "created " + in.Name + " dryRun=" + in.DryRun}, nil
+				}),
+			httpadpt.NewBindingBuilderUsingPath("/orders/{id}/label").WithMethods(http.MethodGet).
+				WithHandlerFunc(func() (*labelOutput, error) {
+					return &labelOutput{ContentType: "image/png", Body: label}, nil
+				}),
+		},
+		Middlewares: httpadpt.Middlewares{trace},
+	})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+	return handler
+}
+
+func readEvent(t *testing.T, name string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func invokeEvent[R any](t *testing.T, handler *Handler, payload []byte) R {
+	t.Helper()
+	output, err := handler.Invoke(context.Background(), payload)
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	var response R
+	if err := json.Unmarshal(output, &response); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	return response
+}
+
+func Test_Handler_APIGatewayV1(t *testing.T) {
+	response := invokeEvent[events.APIGatewayProxyResponse](t, newTestHandler(t), readEvent(t, "apigw-v1-request.json"))
+	if response.StatusCode != http.StatusOK || response.Body != `{"id":"42","fields":"id name","tenant":"acme","session":"s1"}` {
+		t.Errorf("response = %d %q", response.StatusCode, response.Body)
+	}
+	if response.IsBase64Encoded {
+		t.Error("IsBase64Encoded = true, want false for JSON")
+	}
+	if got := response.MultiValueHeaders["X-Trace"]; !reflect.DeepEqual(got, []string{"global"}) {
+		t.Errorf("X-Trace = %v, want the middleware header", got)
+	}
+	if got := response.MultiValueHeaders["Set-Cookie"]; !reflect.DeepEqual(got, []string{"visited=yes; Path=/"}) {
+		t.Errorf("Set-Cookie = %v", got)
+	}
+}
+
+func Test_Handler_APIGatewayV2(t *testing.T) {
+	response := invokeEvent[events.APIGatewayV2HTTPResponse](t, newTestHandler(t), readEvent(t, "apigw-v2-request.json"))
+	if response.StatusCode != http.StatusCreated || response.Body != "created abc dryRun=false" {
+		t.Errorf("response = %d %q", response.StatusCode, response.Body)
+	}
+	if response.Headers["X-Trace"] != "global" || response.Headers["Content-Type"] != "text/plain" {
+		t.Errorf("Headers = %v", response.Headers)
+	}
+	if _, found := response.Headers["Set-Cookie"]; found || !reflect.DeepEqual(response.Cookies, []string{"visited=yes; Path=/"}) {
+		t.Errorf("Cookies = %v, Headers = %v, want the cookies apart", response.Cookies, response.Headers)
+	}
+}
+
+func Test_Handler_ALB(t *testing.T) {
+	response := invokeEvent[events.ALBTargetGroupResponse](t, newTestHandler(t), readEvent(t, "alb-request.json"))
+	if response.StatusCode != http.StatusOK || response.StatusDescription != "200 OK" ||
+		response.Body != `{"id":"42","fields":"id name","tenant":"acme","session":"s3"}` {
+		t.Errorf("response = %d %q %q", response.StatusCode, response.StatusDescription, response.Body)
+	}
+	if response.Headers != nil || !reflect.DeepEqual(response.MultiValueHeaders["X-Trace"], []string{"global"}) {
+		t.Errorf("Headers = %v, MultiValueHeaders = %v, want only the multi-value headers", response.Headers,
+			response.MultiValueHeaders)
+	}
+
+	single, err := newTestHandler(t).HandleALB(context.Background(), events.ALBTargetGroupRequest{
+		HTTPMethod: http.MethodGet,
+		Path:       "/orders/7",
+		Headers:    map[string]string{"x-tenant": "acme"},
+	})
+	if err != nil || single.MultiValueHeaders != nil || single.Headers["X-Trace"] != "global" {
+		t.Errorf("HandleALB() = %+v, %v, want the single value headers", single, err)
+	}
+}
+
+func Test_lastForwardedFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected string
+	}{
+		{name: "none", expected: ""},
+		{name: "client only", values: []string{"203.0.113.9"}, expected: "203.0.113.9"},
+		{name: "forged entry", values: []string{"198.51.100.1, 203.0.113.9"}, expected: "203.0.113.9"},
+		{name: "several headers", values: []string{"198.51.100.1", "10.0.0.1 , 203.0.113.9"}, expected: "203.0.113.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{"X-Forwarded-For": tt.values}
+			if got := lastForwardedFor(header); got != tt.expected {
+				t.Errorf("lastForwardedFor() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_Handler_Routing(t *testing.T) {
+	handler := newTestHandler(t)
+	ctx := context.Background()
+
+	response, _ := handler.HandleAPIGatewayV1(ctx, events.APIGatewayProxyRequest{HTTPMethod: http.MethodDelete, Path: "/orders/42"})
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE /orders/42 = %d, want 405", response.StatusCode)
+	}
+	response, _ = handler.HandleAPIGatewayV1(ctx, events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/customers"})
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /customers = %d, want 404", response.StatusCode)
+	}
+
+	v2, _ := handler.HandleAPIGatewayV2(ctx, events.APIGatewayV2HTTPRequest{
+		RawPath:        "/orders/42/label",
+		RequestContext: events.APIGatewayV2HTTPRequestContext{HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{Method: http.MethodGet}},
+	})
+	if body, _ := base64.StdEncoding.DecodeString(v2.Body); !v2.IsBase64Encoded || !reflect.DeepEqual(body, label) {
+		t.Errorf("binary body = %q base64=%v, want it encoded", v2.Body, v2.IsBase64Encoded)
+	}
+
+	v2, _ = handler.HandleAPIGatewayV2(ctx, events.APIGatewayV2HTTPRequest{
+		RawPath:         "/orders",
+		Body:            "not base64!",
+		IsBase64Encoded: true,
+		RequestContext:  events.APIGatewayV2HTTPRequestContext{HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{Method: http.MethodPost}},
+	})
+	if v2.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid base64 body = %d, want 400", v2.StatusCode)
+	}
+}
+
+func Test_Handler_Invoke_UnsupportedEvent(t *testing.T) {
+	handler := newTestHandler(t)
+	for _, payload := range []string{`{"Records":[]}`, `not json`} {
+		if _, err := handler.Invoke(context.Background(), []byte(payload)); err == nil {
+			t.Errorf("Invoke(%s) error = nil, want an error", payload)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package awslambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+// newV1Request converts the API Gateway REST API event, also used by the HTTP API payload format 1.0. Its path and
+// query values are decoded.
+func newV1Request(ctx context.Context, event events.APIGatewayProxyRequest) (*http.Request, error) {
+	query := url.Values(multiValue(event.MultiValueQueryStringParameters, event.QueryStringParameters)).Encode()
+	return newHTTPRequest(ctx, event.HTTPMethod, &url.URL{Path: event.Path, RawQuery: query},
+		newHeader(multiValue(event.MultiValueHeaders, event.Headers)), event.Body, event.IsBase64Encoded,
+		event.RequestContext.Identity.SourceIP)
+}
+
+// newV2Request converts the API Gateway HTTP API payload format 2.0 event. The repeated headers are joined by commas
+// and the cookies are given apart.
+func newV2Request(ctx context.Context, event events.APIGatewayV2HTTPRequest) (*http.Request, error) {
+	requestURL, err := url.Parse(event.RawPath)
+	if err != nil {
+		return nil, serror.IllegalArgumentValueWithCause("rawPath", event.RawPath, err)
+	}
+	requestURL.RawQuery = event.RawQueryString
+
+	header := newHeader(multiValue(nil, event.Headers))
+	if len(event.Cookies) > 0 {
+		header.Set("Cookie", strings.Join(event.Cookies, "; "))
+	}
+	return newHTTPRequest(ctx, event.RequestContext.HTTP.Method, requestURL, header, event.Body, event.IsBase64Encoded,
+		event.RequestContext.HTTP.SourceIP)
+}
+
+// newALBRequest converts the Application Load Balancer event. The load balancer does not decode the query values,
+// and the client address is only given by X-Forwarded-For, see lastForwardedFor.
+func newALBRequest(ctx context.Context, event events.ALBTargetGroupRequest) (*http.Request, error) {
+	var query []string
+	for name, values := range multiValue(event.MultiValueQueryStringParameters, event.QueryStringParameters) {
+		for _, value := range values {
+			query = append(query, name+"="+value)
+		}
+	}
+	slices.Sort(query)
+
+	header := newHeader(multiValue(event.MultiValueHeaders, event.Headers))
+	return newHTTPRequest(ctx, event.HTTPMethod, &url.URL{Path: event.Path, RawQuery: strings.Join(query, "&")},
+		header, event.Body, event.IsBase64Encoded, lastForwardedFor(header))
+}
+
+// lastForwardedFor returns the last X-Forwarded-For entry, the one appended by the load balancer. The entries before
+// it are sent by the client and they can be forged.
+func lastForwardedFor(header http.Header) string {
+	values := header.Values("X-Forwarded-For")
+	if len(values) == 0 {
+		return ""
+	}
+	entries := strings.Split(values[len(values)-1], ",")
+	return strings.TrimSpace(entries[len(entries)-1])
+}
+
+func newHTTPRequest(ctx context.Context, method string, requestURL *url.URL, header http.Header, body string,
+	base64Encoded bool, sourceIP string) (*http.Request, error) {
+	content := []byte(body)
+	if base64Encoded {
+		var err error
+		if content, err = base64.StdEncoding.DecodeString(body); err != nil {
+			return nil, serror.IllegalArgumentValueWithCause("body", "base64", err)
+		}
+	}
+	request, err := http.NewRequestWithContext(ctx, method, requestURL.RequestURI(), bytes.NewReader(content))
+	if err != nil {
+		return nil, serror.IllegalArgumentValueWithCause("request", requestURL.String(), err)
+	}
+	request.Header = header
+	request.Host = header.Get("Host")
+	request.RequestURI = requestURL.RequestURI()
+	if sourceIP != "" {
+		request.RemoteAddr = net.JoinHostPort(sourceIP, "0")
+	}
+	return request, nil
+}
+
+// multiValue returns the multi-value map when the event has it, otherwise the single value one
+func multiValue(multi map[string][]string, single map[string]string) map[string][]string {
+	if multi != nil {
+		return multi
+	}
+	result := make(map[string][]string, len(single))
+	for name, value := range single {
+		result[name] = []string{value}
+	}
+	return result
+}
+
+func newHeader(values map[string][]string) http.Header {
+	header := make(http.Header, len(values))
+	for name, value := range values {
+		key := http.CanonicalHeaderKey(name)
+		header[key] = append(header[key], value...)
+	}
+	return header
+}
@@ -0,0 +1,131 @@
+package awslambda
+
+import (
+	"bytes"
+	"encoding/base64"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type (
+	// responseRecorder buffers the response written by the bindings, the Lambda proxy integrations do not stream
+	responseRecorder struct {
+		header     http.Header
+		statusCode int
+		body       bytes.Buffer
+	}
+)
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: http.Header{}}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+	}
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(p)
+}
+
+// Flush is a no-op, it makes the streamed responses, like the Server-Sent Events, be written to the buffer
+func (r *responseRecorder) Flush() {}
+
+func (r *responseRecorder) status() int {
+	if r.statusCode == 0 {
+		return http.StatusOK
+	}
+	return r.statusCode
+}
+
+// encodedBody returns the body as is if it is text, otherwise encoded with base64
+func (r *responseRecorder) encodedBody() (string, bool) {
+	if r.body.Len() == 0 {
+		return "", false
+	}
+	if isText(r.header, r.body.Bytes()) {
+		return r.body.String(), false
+	}
+	return base64.StdEncoding.EncodeToString(r.body.Bytes()), true
+}
+
+func (r *responseRecorder) toV1Response() events.APIGatewayProxyResponse {
+	body, base64Encoded := r.encodedBody()
+	return events.APIGatewayProxyResponse{
+		StatusCode:        r.status(),
+		MultiValueHeaders: r.header,
+		Body:              body,
+		IsBase64Encoded:   base64Encoded,
+	}
+}
+
+// toV2Response joins the repeated headers by commas and returns the Set-Cookie headers as cookies
+func (r *responseRecorder) toV2Response() events.APIGatewayV2HTTPResponse {
+	body, base64Encoded := r.encodedBody()
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode:      r.status(),
+		Headers:         make(map[string]string, len(r.header)),
+		Body:            body,
+		IsBase64Encoded: base64Encoded,
+	}
+	for name, values := range r.header {
+		if name == "Set-Cookie" {
+			response.Cookies = values
+			continue
+		}
+		response.Headers[name] = strings.Join(values, ", ")
+	}
+	return response
+}
+
+// toALBResponse uses the multi-value headers only if the target group has them enabled, that is known by the request
+func (r *responseRecorder) toALBResponse(multiValueHeaders bool) events.ALBTargetGroupResponse {
+	body, base64Encoded := r.encodedBody()
+	response := events.ALBTargetGroupResponse{
+		StatusCode:        r.status(),
+		StatusDescription: strconv.Itoa(r.status()) + " " + http.StatusText(r.status()),
+		Body:              body,
+		IsBase64Encoded:   base64Encoded,
+	}
+	if multiValueHeaders {
+		response.MultiValueHeaders = r.header
+		return response
+	}
+	response.Headers = make(map[string]string, len(r.header))
+	for name, values := range r.header {
+		response.Headers[name] = values[len(values)-1]
+	}
+	return response
+}
+
+// isText returns true if the body is not encoded and its content type is textual, or it is not given and the body is
+// valid UTF-8
+func isText(header http.Header, body []byte) bool {
+	if encoding := header.Get("Content-Encoding"); encoding != "" && encoding != "identity" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		return utf8.Valid(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	subtype := mediaType[strings.IndexByte(mediaType, '/')+1:]
+	return strings.HasPrefix(mediaType, "text/") || subtype == "json" || strings.HasSuffix(subtype, "+json") ||
+		subtype == "xml" || strings.HasSuffix(subtype, "+xml") || subtype == "javascript" ||
+		subtype == "x-www-form-urlencoded"
+}
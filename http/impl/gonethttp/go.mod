@@ -3,6 +3,7 @@ module github.com/smart-libs/go-adapter/http/impl/gonethttp
 go 1.25
 
 require (
+	github.com/gorilla/websocket v1.5.3
 	github.com/smart-libs/go-adapter/http/lib v0.0.3
 	github.com/smart-libs/go-crosscutting/assertions/lib v0.0.6
 	github.com/smart-libs/go-crosscutting/serror/lib v0.0.2
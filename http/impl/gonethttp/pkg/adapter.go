@@ -2,9 +2,12 @@ package gonethttp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	"net"
 	"net/http"
+	"sync"
 )
 
 type (
@@ -12,12 +15,19 @@ type (
 		config     httpadpt.Config
 		serveMux   *http.ServeMux
 		server     *http.Server
-		startError error
+		webSockets *webSocketTracker
+
+		locker     sync.Mutex
+		listener   net.Listener
+		started    bool
+		stopped    bool
+		serveDone  chan struct{}
+		serveError error
 	}
 )
 
 func NewAdapter(config httpadpt.Config) (httpadpt.Adapter, error) {
-	adapter := DefaultAdapter{config: config}
+	adapter := DefaultAdapter{config: config, webSockets: newWebSocketTracker()}
 	port := 80
 	if config.Port != nil {
 		port = *config.Port
@@ -31,32 +41,77 @@ func NewAdapter(config httpadpt.Config) (httpadpt.Adapter, error) {
 	adapter.server = &http.Server{
 		Addr:    fmt.Sprintf("%s:%d", host, port),
 		Handler: adapter.serveMux,
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), webSocketTrackerKey{}, adapter.webSockets)
+		},
 	}
+	adapter.server.RegisterOnShutdown(adapter.webSockets.closeAll)
 
-	if err := buildAndAddHandles(adapter.serveMux.Handle, config.Bindings, config.Middlewares); err != nil {
+	if err := buildAndAddHandles(adapter.serveMux.Handle, config); err != nil {
 		return nil, err
 	}
 	return &adapter, nil
 }
 
+// NewHandler returns the http.Handler that routes the requests to the config bindings like the DefaultAdapter, but
+// without a server. It allows the bindings to be served by other servers, or by the adapters that convert other
+// events, like the serverless ones, into http.Request.
+func NewHandler(config httpadpt.Config) (http.Handler, error) {
+	serveMux := http.NewServeMux()
+	if err := buildAndAddHandles(serveMux.Handle, config); err != nil {
+		return nil, err
+	}
+	return serveMux, nil
+}
+
+// Start binds the listener before returning, so the requests sent after it are accepted, and serves in background
 func (d *DefaultAdapter) Start(_ context.Context) error {
-	if d.server == nil {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	if d.stopped {
 		return fmt.Errorf("server already shut down")
 	}
-	go func() { d.startError = d.server.ListenAndServe() }()
+	if d.started {
+		return fmt.Errorf("server already started")
+	}
+	listener, err := net.Listen("tcp", d.server.Addr)
+	if err != nil {
+		return err
+	}
+	d.listener = listener
+	d.started = true
+	d.serveDone = make(chan struct{})
+	d.config.Health.SetReady(true)
+	go func() {
+		defer close(d.serveDone)
+		if err := d.server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			d.serveError = err
+		}
+	}()
 	return nil
 }
 
+// Stop drains the Config.Health, failing the readiness, and then shuts down the server gracefully, closing it if
+// the ctx is done before the active connections finish. The WebSocket connections are closed with the going away
+// code, and they are waited for until the ctx is done too, then their handlers context is canceled.
 func (d *DefaultAdapter) Stop(ctx context.Context) error {
-	if d.server == nil {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	if !d.started || d.stopped {
 		return fmt.Errorf("server not started")
 	}
-	if d.startError != nil {
-		return d.startError
-	}
+	d.stopped = true
+	d.config.Health.Drain(ctx)
 	err := d.server.Shutdown(ctx)
-	if err == nil {
-		err = d.server.Close()
+	if err != nil {
+		_ = d.server.Close()
+	}
+	if waitErr := d.webSockets.wait(ctx); err == nil {
+		err = waitErr
+	}
+	<-d.serveDone
+	if d.serveError != nil {
+		return d.serveError
 	}
 	return err
 }
@@ -0,0 +1,59 @@
+package gonethttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+)
+
+func Test_DefaultAdapter_ReadinessFailsWhileStopping(t *testing.T) {
+	port := 0
+	health := httpadpt.NewHealthChecks(httpadpt.HealthConfig{DrainDelay: 200 * time.Millisecond})
+	adapter, err := NewAdapter(httpadpt.Config{Port: &port, Health: health})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := adapter.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	url := fmt.Sprintf("http://%s%s", adapter.(*DefaultAdapter).listener.Addr(), httpadpt.DefaultReadinessPath)
+
+	// the listener is bound when Start returns
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("readiness status = %d, want 200", resp.StatusCode)
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- adapter.Stop(ctx) }()
+	for health.IsReady() {
+		time.Sleep(time.Millisecond)
+	}
+	resp, err = http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report httpadpt.HealthReport
+	_ = json.NewDecoder(resp.Body).Decode(&report)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || report.Error != httpadpt.ErrNotReady.Error() {
+		t.Errorf("readiness while stopping = %d %+v, want 503", resp.StatusCode, report)
+	}
+
+	if err := <-stopped; err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+	if err := adapter.Start(ctx); err == nil {
+		t.Error("Start() after Stop() should fail")
+	}
+}
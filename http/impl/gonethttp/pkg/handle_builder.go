@@ -14,21 +14,47 @@ func buildPath(method, path string) string {
 	return path
 }
 
-func buildHandler(handler httpadpt.Handler) http.HandlerFunc {
+func buildHandler(handler httpadpt.Handler, formConfig *httpadpt.FormConfig) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		req := NewRequest(r)
+		req := Request{httpReq: r, formConfig: formConfig}
 		resp := httpadpt.Response{}
 		err := handler.Invoke(ctx, req, &resp)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		if resp.WebSocket != nil {
+			serveWebSocket(w, r, resp)
+			return
+		}
 		handleResponse(ctx, w, resp)
 	}
 }
 
-func buildAndAddHandles(addHandle func(path string, handler http.Handler), bindings httpadpt.Bindings, middlewares httpadpt.Middlewares) error {
+// buildAndAddHandles registers the handlers of the bindings. If Config.CORS is set, the bindings are wrapped with the
+// CORS middleware and an OPTIONS handler is registered, after them, for every binding path that does not have one, so
+// that preflight requests reach the middleware. The versioned bindings are merged first, and duplicated method and
+// path pairs are returned as errors instead of making http.ServeMux panic. The Config.Health bindings are added to the
+// Config.Bindings.
+func buildAndAddHandles(addHandle func(path string, handler http.Handler), config httpadpt.Config) error {
+	all := append(append(httpadpt.Bindings(nil), config.Bindings...), config.Health.Bindings()...)
+	bindings, err := httpadpt.MergeVersionedBindings(all)
+	if err != nil {
+		return err
+	}
+	if config.CORS != nil {
+		cors, err := httpadpt.NewCORSMiddleware(*config.CORS)
+		if err != nil {
+			return err
+		}
+		config.Middlewares = append(httpadpt.Middlewares{cors}, config.Middlewares...)
+		bindings = append(bindings, httpadpt.OptionsBindings(bindings)...)
+	}
+	return addBindingHandles(addHandle, bindings, config)
+}
+
+func addBindingHandles(addHandle func(path string, handler http.Handler), bindings httpadpt.Bindings, config httpadpt.Config) error {
 	fName := "httpadpt.buildAndAddHandles"
 	for i, binding := range bindings {
 		if len(binding.Condition.Methods) > 0 {
@@ -37,8 +63,8 @@ func buildAndAddHandles(addHandle func(path string, handler http.Handler), bindi
 					fmt.Sprintf("%s.Config.Bindings[%d].Condition.Path", fName, i), binding.Condition.Path)
 			}
 			for _, method := range binding.Condition.Methods {
-				handler := httpadpt.WrapHandlerWithMiddlewares(binding.Handler, middlewares)
-				addHandle(buildPath(method, *binding.Condition.Path), buildHandler(handler))
+				handler := httpadpt.WrapBindingHandler(binding, config)
+				addHandle(buildPath(method, *binding.Condition.Path), buildHandler(handler, config.Form))
 			}
 		}
 	}
@@ -1,12 +1,21 @@
 package gonethttp
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
+	"fmt"
 	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // mockHandler is a test implementation of httpadpt.Handler
@@ -184,7 +193,7 @@ func Test_buildHandler_Success(t *testing.T) {
 				},
 			}
 
-			httpHandler := buildHandler(handler)
+			httpHandler := buildHandler(handler, nil)
 			req := httptest.NewRequest("GET", "/test", nil)
 			w := httptest.NewRecorder()
 
@@ -223,7 +232,7 @@ func Test_buildHandler_Error(t *testing.T) {
 		},
 	}
 
-	httpHandler := buildHandler(handler)
+	httpHandler := buildHandler(handler, nil)
 	req := httptest.NewRequest("GET", "/test", nil)
 	w := httptest.NewRecorder()
 
@@ -252,7 +261,7 @@ func Test_buildHandler_HeadersSetBeforeWriteHeader(t *testing.T) {
 		},
 	}
 
-	httpHandler := buildHandler(handler)
+	httpHandler := buildHandler(handler, nil)
 	req := httptest.NewRequest("GET", "/test", nil)
 	w := httptest.NewRecorder()
 
@@ -344,7 +353,7 @@ func Test_buildAndAddHandles_Success(t *testing.T) {
 				registeredPaths[path] = true
 			}
 
-			err := buildAndAddHandles(addHandle, tt.bindings, nil)
+			err := buildAndAddHandles(addHandle, httpadpt.Config{Bindings: tt.bindings})
 
 			if (err != nil) != tt.expectedError {
 				t.Errorf("buildAndAddHandles() error = %v, want error = %v", err, tt.expectedError)
@@ -380,7 +389,7 @@ func Test_buildAndAddHandles_Error_NilPath(t *testing.T) {
 		registeredPaths[path] = true
 	}
 
-	err := buildAndAddHandles(addHandle, bindings, nil)
+	err := buildAndAddHandles(addHandle, httpadpt.Config{Bindings: bindings})
 
 	if err == nil {
 		t.Error("buildAndAddHandles() expected error for nil path, got nil")
@@ -399,7 +408,7 @@ func Test_buildAndAddHandles_EmptyBindings(t *testing.T) {
 		registeredPaths[path] = true
 	}
 
-	err := buildAndAddHandles(addHandle, bindings, nil)
+	err := buildAndAddHandles(addHandle, httpadpt.Config{Bindings: bindings})
 
 	if err != nil {
 		t.Errorf("buildAndAddHandles() error = %v, want nil", err)
@@ -429,7 +438,7 @@ func Test_buildAndAddHandles_NilHandler(t *testing.T) {
 		registeredHandler = handler
 	}
 
-	err := buildAndAddHandles(addHandle, bindings, nil)
+	err := buildAndAddHandles(addHandle, httpadpt.Config{Bindings: bindings})
 
 	if err != nil {
 		t.Errorf("buildAndAddHandles() error = %v, want nil", err)
@@ -466,7 +475,7 @@ func Test_buildAndAddHandles_MultipleMethodsSamePath(t *testing.T) {
 		registeredPaths[path] = true
 	}
 
-	err := buildAndAddHandles(addHandle, bindings, nil)
+	err := buildAndAddHandles(addHandle, httpadpt.Config{Bindings: bindings})
 
 	if err != nil {
 		t.Errorf("buildAndAddHandles() error = %v, want nil", err)
@@ -515,7 +524,7 @@ func Test_buildAndAddHandles_ErrorInMiddle(t *testing.T) {
 		registeredPaths[path] = true
 	}
 
-	err := buildAndAddHandles(addHandle, bindings, nil)
+	err := buildAndAddHandles(addHandle, httpadpt.Config{Bindings: bindings})
 
 	if err == nil {
 		t.Error("buildAndAddHandles() expected error for nil path, got nil")
@@ -543,7 +552,7 @@ func Test_buildHandler_ContextPropagation(t *testing.T) {
 		},
 	}
 
-	httpHandler := buildHandler(handler)
+	httpHandler := buildHandler(handler, nil)
 	req := httptest.NewRequest("GET", "/test", nil)
 	w := httptest.NewRecorder()
 
@@ -567,7 +576,7 @@ func Test_buildHandler_EmptyError(t *testing.T) {
 		},
 	}
 
-	httpHandler := buildHandler(handler)
+	httpHandler := buildHandler(handler, nil)
 	req := httptest.NewRequest("GET", "/test", nil)
 	w := httptest.NewRecorder()
 
@@ -598,7 +607,7 @@ func Test_buildHandler_ResponseWithAllFields(t *testing.T) {
 		},
 	}
 
-	httpHandler := buildHandler(handler)
+	httpHandler := buildHandler(handler, nil)
 	req := httptest.NewRequest("GET", "/test", nil)
 	w := httptest.NewRecorder()
 
@@ -646,3 +655,410 @@ func intPtr(i int) *int {
 func stringPtr(s string) *string {
 	return &s
 }
+
+type sseHandlerOutput struct {
+	Events <-chan httpadpt.Event `body:""`
+}
+
+func Test_buildAndAddHandles_ServerSentEventsThroughMiddlewares(t *testing.T) {
+	sseHandler := func() (*sseHandlerOutput, error) {
+		events := make(chan httpadpt.Event, 2)
+		events <- httpadpt.Event{ID: "1", Data: "ping"}
+		events <- httpadpt.Event{ID: "2", Data: "pong"}
+		close(events)
+		return &sseHandlerOutput{Events: events}, nil
+	}
+	bindings := httpadpt.Bindings{
+		httpadpt.NewBindingBuilderUsingPath("/events").
+			WithMethods(http.MethodGet).
+			WithHandlerFunc(sseHandler),
+	}
+	serveMux := http.NewServeMux()
+	if err := buildAndAddHandles(serveMux.Handle, httpadpt.Config{Bindings: bindings, Middlewares: httpadpt.Middlewares{httpadpt.HandlePanic}}); err != nil {
+		t.Fatalf("buildAndAddHandles() error = %v", err)
+	}
+	server := httptest.NewServer(serveMux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatalf("GET /events error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.Header.Get("Content-Type") != httpadpt.ContentTypeEventStream {
+		t.Errorf("Content-Type = %q, want %q", resp.Header.Get("Content-Type"), httpadpt.ContentTypeEventStream)
+	}
+	all, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(all) != "id: 1\ndata: ping\n\nid: 2\ndata: pong\n\n" {
+		t.Errorf("Body = %q", string(all))
+	}
+}
+
+type uploadHandlerInput struct {
+	Name    string    `form:"name" assert:"mandatory"`
+	Content []byte    `file:"upload" assert:"mandatory,contentType=text/csv,maxSize=1KB"`
+	Reader  io.Reader `file:"upload"`
+}
+
+type uploadHandlerOutput struct {
+	Body string `body:""`
+}
+
+func Test_buildAndAddHandles_FileUpload(t *testing.T) {
+	uploadHandler := func(input uploadHandlerInput) (*uploadHandlerOutput, error) {
+		read, err := io.ReadAll(input.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return &uploadHandlerOutput{Body: fmt.Sprintf("%s:%s:%d", input.Name, input.Content, len(read))}, nil
+	}
+	bindings := httpadpt.Bindings{
+		httpadpt.NewBindingBuilderUsingPath("/upload").
+			WithMethods(http.MethodPost).
+			WithHandlerFunc(uploadHandler),
+	}
+	serveMux := http.NewServeMux()
+	config := httpadpt.Config{
+		Bindings:    bindings,
+		Middlewares: httpadpt.Middlewares{httpadpt.HandlePanic},
+		Form:        &httpadpt.FormConfig{MaxSize: 2048},
+	}
+	if err := buildAndAddHandles(serveMux.Handle, config); err != nil {
+		t.Fatalf("buildAndAddHandles() error = %v", err)
+	}
+
+	post := func(contentType, content string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("name", "john")
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="upload"; filename="data.csv"`)
+		header.Set("Content-Type", contentType)
+		part, _ := writer.CreatePart(header)
+		_, _ = part.Write([]byte(content))
+		_ = writer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/upload", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+		serveMux.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("text/csv", "a,b"); w.Code != http.StatusOK || w.Body.String() != "john:a,b:3" {
+		t.Errorf("accepted upload = %d %q, want 200 %q", w.Code, w.Body.String(), "john:a,b:3")
+	}
+	if w := post("image/png", "png"); w.Code != http.StatusBadRequest {
+		t.Errorf("wrong content type status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := post("text/csv", strings.Repeat("x", 1500)); w.Code != http.StatusBadRequest {
+		t.Errorf("file above maxSize status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := post("text/csv", strings.Repeat("x", 4096)); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("body above form MaxSize status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func Test_buildAndAddHandles_CORSPreflight(t *testing.T) {
+	bindings := httpadpt.Bindings{
+		httpadpt.NewBindingBuilderUsingPath("/api/users").
+			WithMethods(http.MethodGet, http.MethodPost).
+			WithHandlerFunc(func() (*uploadHandlerOutput, error) { return &uploadHandlerOutput{Body: "users"}, nil }),
+		httpadpt.NewBindingBuilderUsingPath("/api/panic").
+			WithMethods(http.MethodGet).
+			WithHandlerFunc(func() error { panic("boom") }),
+	}
+	cors := &httpadpt.CORSConfig{
+		AllowedOrigins:   []string{"https://*.example.com"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}
+	serveMux := http.NewServeMux()
+	if err := buildAndAddHandles(serveMux.Handle, httpadpt.Config{Bindings: bindings, CORS: cors, Middlewares: httpadpt.Middlewares{httpadpt.HandlePanic}}); err != nil {
+		t.Fatalf("buildAndAddHandles() error = %v", err)
+	}
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/api/users", nil)
+	preflight.Header.Set("Origin", "https://app.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	preflight.Header.Set("Access-Control-Request-Headers", "Content-Type")
+	w := httptest.NewRecorder()
+	serveMux.ServeHTTP(w, preflight)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("preflight status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "3600" {
+		t.Errorf("Access-Control-Max-Age = %q, want 3600", got)
+	}
+
+	options := httptest.NewRequest(http.MethodOptions, "/api/users", nil)
+	w = httptest.NewRecorder()
+	serveMux.ServeHTTP(w, options)
+	if w.Code != http.StatusNoContent || w.Header().Get("Allow") != "GET, POST, OPTIONS" {
+		t.Errorf("OPTIONS = %d Allow=%q, want 204 Allow=%q", w.Code, w.Header().Get("Allow"), "GET, POST, OPTIONS")
+	}
+
+	get := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	get.Header.Set("Origin", "https://app.example.com")
+	w = httptest.NewRecorder()
+	serveMux.ServeHTTP(w, get)
+	if w.Body.String() != "users" || w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Errorf("GET = %q, headers = %v", w.Body.String(), w.Header())
+	}
+
+	panicking := httptest.NewRequest(http.MethodGet, "/api/panic", nil)
+	panicking.Header.Set("Origin", "https://app.example.com")
+	w = httptest.NewRecorder()
+	serveMux.ServeHTTP(w, panicking)
+	if w.Code != http.StatusInternalServerError || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("GET /api/panic = %d, headers = %v, want 500 with the CORS headers", w.Code, w.Header())
+	}
+}
+
+func Test_buildAndAddHandles_CORSBindingMiddleware(t *testing.T) {
+	cors, err := httpadpt.NewCORSMiddleware(httpadpt.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}})
+	if err != nil {
+		t.Fatalf("NewCORSMiddleware() error = %v", err)
+	}
+	bindings := httpadpt.Bindings{
+		httpadpt.NewBindingBuilderUsingPath("/api/users").
+			WithMethods(http.MethodPost).
+			WithMiddlewares(cors).
+			WithHandlerFunc(func() (*uploadHandlerOutput, error) { return &uploadHandlerOutput{Body: "users"}, nil }),
+	}
+	config := httpadpt.Config{Bindings: append(bindings, httpadpt.OptionsBindings(bindings)...)}
+	serveMux := http.NewServeMux()
+	if err := buildAndAddHandles(serveMux.Handle, config); err != nil {
+		t.Fatalf("buildAndAddHandles() error = %v", err)
+	}
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/api/users", nil)
+	preflight.Header.Set("Origin", "https://app.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := httptest.NewRecorder()
+	serveMux.ServeHTTP(w, preflight)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("preflight = %d, headers = %v, want 204 with the CORS headers", w.Code, w.Header())
+	}
+}
+
+func Test_buildAndAddHandles_CORSConfig(t *testing.T) {
+	binding := httpadpt.Binding{
+		Condition: httpadpt.Condition{Path: stringPtr("/api/users"), Methods: []string{http.MethodGet}},
+		Handler:   &mockHandler{},
+	}
+
+	registeredPaths := map[string]bool{}
+	addHandle := func(path string, _ http.Handler) { registeredPaths[path] = true }
+	if err := buildAndAddHandles(addHandle, httpadpt.Config{Bindings: httpadpt.Bindings{binding}}); err != nil {
+		t.Fatalf("buildAndAddHandles() error = %v", err)
+	}
+	if registeredPaths["OPTIONS /api/users"] {
+		t.Error("Expected no OPTIONS handler without Config.CORS")
+	}
+
+	invalid := &httpadpt.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+	if err := buildAndAddHandles(addHandle, httpadpt.Config{Bindings: httpadpt.Bindings{binding}, CORS: invalid}); err == nil {
+		t.Error("buildAndAddHandles() error = nil, want error for AllowCredentials with the * origin")
+	}
+}
+
+func Test_buildAndAddHandles_MiddlewareGroups(t *testing.T) {
+	addHeader := func(name, value string) httpadpt.Middleware {
+		return func(next httpadpt.Handler) httpadpt.Handler {
+			return httpadpt.MakeHandler(func(ctx context.Context, input httpadpt.Request, output *httpadpt.Response) error {
+				if output.Header == nil {
+					output.Header = map[string][]string{}
+				}
+				output.Header[name] = append(output.Header[name], value)
+				return next.Invoke(ctx, input, output)
+			})
+		}
+	}
+	handlerFunc := func() (*uploadHandlerOutput, error) { return &uploadHandlerOutput{Body: "ok"}, nil }
+	config := httpadpt.Config{
+		Bindings: httpadpt.Bindings{
+			httpadpt.NewBindingBuilderUsingPath("/admin/users").WithMethods(http.MethodGet).
+				WithMiddlewares(addHeader("X-Trace", "binding")).
+				WithHandlerFunc(handlerFunc),
+			httpadpt.NewBindingBuilderUsingPath("/public").WithMethods(http.MethodGet).WithHandlerFunc(handlerFunc),
+		},
+		Middlewares: httpadpt.Middlewares{addHeader("X-Trace", "global")},
+		MiddlewareGroups: httpadpt.MiddlewareGroups{
+			{PathPrefix: "/admin/*", Middlewares: httpadpt.Middlewares{addHeader("X-Trace", "admin")}},
+		},
+	}
+	serveMux := http.NewServeMux()
+	if err := buildAndAddHandles(serveMux.Handle, config); err != nil {
+		t.Fatalf("buildAndAddHandles() error = %v", err)
+	}
+
+	for path, expected := range map[string][]string{
+		"/admin/users": {"global", "admin", "binding"},
+		"/public":      {"global"},
+	} {
+		w := httptest.NewRecorder()
+		serveMux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if got := w.Header().Values("X-Trace"); !reflect.DeepEqual(got, expected) {
+			t.Errorf("GET %s X-Trace = %v, want %v", path, got, expected)
+		}
+	}
+}
+
+func Test_buildAndAddHandles_GroupVersions(t *testing.T) {
+	handlerFunc := func(body string) func() (*uploadHandlerOutput, error) {
+		return func() (*uploadHandlerOutput, error) { return &uploadHandlerOutput{Body: body}, nil }
+	}
+	ordersV1 := httpadpt.NewGroup("/orders")
+	ordersV1.Route("/{id}").WithMethods(http.MethodGet).WithHandlerFunc(handlerFunc("v1"))
+	ordersV2 := httpadpt.NewGroup("/orders")
+	ordersV2.Route("/{id}").WithMethods(http.MethodGet).WithHandlerFunc(handlerFunc("v2"))
+
+	api := httpadpt.NewGroup("/api")
+	api.Mount("", ordersV1)
+	api.Group("").WithVersion(httpadpt.MediaTypeVersion{MediaType: "application/vnd.acme.v2+json"}).Mount("", ordersV2)
+	api.Mount("/v1", ordersV1)
+	bindings, err := api.Bindings()
+	if err != nil {
+		t.Fatalf("Bindings() error = %v", err)
+	}
+
+	serveMux := http.NewServeMux()
+	if err := buildAndAddHandles(serveMux.Handle, httpadpt.Config{Bindings: bindings}); err != nil {
+		t.Fatalf("buildAndAddHandles() error = %v", err)
+	}
+	for _, tt := range []struct{ path, accept, expected string }{
+		{path: "/api/orders/1", expected: "v1"},
+		{path: "/api/orders/1", accept: "application/vnd.acme.v2+json", expected: "v2"},
+		{path: "/api/v1/orders/1", accept: "application/vnd.acme.v2+json", expected: "v1"},
+	} {
+		r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.accept != "" {
+			r.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		serveMux.ServeHTTP(w, r)
+		if w.Body.String() != tt.expected {
+			t.Errorf("GET %s Accept=%q = %q, want %q", tt.path, tt.accept, w.Body.String(), tt.expected)
+		}
+	}
+}
+
+func Test_buildAndAddHandles_DuplicatedBinding(t *testing.T) {
+	handlerFunc := func() error { return nil }
+	config := httpadpt.Config{Bindings: httpadpt.Bindings{
+		httpadpt.NewBindingBuilderUsingPath("/orders/{id}").WithMethods(http.MethodGet).WithHandlerFunc(handlerFunc),
+		httpadpt.NewBindingBuilderUsingPath("/orders/{orderID}").WithMethods(http.MethodGet).WithHandlerFunc(handlerFunc),
+	}}
+	if err := buildAndAddHandles(http.NewServeMux().Handle, config); err == nil {
+		t.Error("buildAndAddHandles() error = nil, want a duplicated binding error")
+	}
+}
+
+type compressionHandlerInput struct {
+	Name string `form:"name" assert:"mandatory"`
+}
+
+type compressionHandlerOutput struct {
+	ContentType string `header:"Content-Type"`
+	Body        string `body:""`
+}
+
+func Test_buildAndAddHandles_Compression(t *testing.T) {
+	handler := func(input compressionHandlerInput) (*compressionHandlerOutput, error) {
+		return &compressionHandlerOutput{ContentType: "text/plain", Body: strings.Repeat(input.Name, 1000)}, nil
+	}
+	bindings := httpadpt.Bindings{
+		httpadpt.NewBindingBuilderUsingPath("/echo").
+			WithMethods(http.MethodPost).
+			WithHandlerFunc(handler),
+	}
+	middlewares := httpadpt.Middlewares{httpadpt.NewCompressionMiddleware(httpadpt.CompressionConfig{DecodeRequests: true})}
+	serveMux := http.NewServeMux()
+	if err := buildAndAddHandles(serveMux.Handle, httpadpt.Config{Bindings: bindings, Middlewares: middlewares}); err != nil {
+		t.Fatalf("buildAndAddHandles() error = %v", err)
+	}
+	server := httptest.NewServer(serveMux)
+	defer server.Close()
+
+	var body bytes.Buffer
+	writer := gzip.NewWriter(&body)
+	_, _ = writer.Write([]byte("name=abc"))
+	_ = writer.Close()
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/echo", &body)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Content-Encoding", "gzip")
+	// setting Accept-Encoding disables the transparent decompression of the client
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /echo error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("status = %d, Content-Encoding = %q", resp.StatusCode, resp.Header.Get("Content-Encoding"))
+	}
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	all, _ := io.ReadAll(reader)
+	if string(all) != strings.Repeat("abc", 1000) {
+		t.Errorf("Body = %q", string(all))
+	}
+}
+
+type conditionalHandlerOutput struct {
+	ETag         string    `etag:""`
+	LastModified time.Time `lastmodified:""`
+	Body         string    `body:""`
+}
+
+func Test_buildAndAddHandles_ConditionalRequests(t *testing.T) {
+	modified := time.Date(2024, 3, 1, 13, 30, 0, 0, time.UTC)
+	handler := func() (*conditionalHandlerOutput, error) {
+		return &conditionalHandlerOutput{ETag: "v1", LastModified: modified, Body: "resource"}, nil
+	}
+	bindings := httpadpt.Bindings{
+		httpadpt.NewBindingBuilderUsingPath("/resource").
+			WithMethods(http.MethodGet).
+			WithMiddlewares(httpadpt.NewCacheControlMiddleware("max-age=60")).
+			WithHandlerFunc(handler),
+	}
+	middlewares := httpadpt.Middlewares{httpadpt.NewConditionalMiddleware(httpadpt.ConditionalConfig{})}
+	serveMux := http.NewServeMux()
+	if err := buildAndAddHandles(serveMux.Handle, httpadpt.Config{Bindings: bindings, Middlewares: middlewares}); err != nil {
+		t.Fatalf("buildAndAddHandles() error = %v", err)
+	}
+	server := httptest.NewServer(serveMux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/resource")
+	if err != nil {
+		t.Fatalf("GET /resource error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.Header.Get("ETag") != `"v1"` || resp.Header.Get("Last-Modified") != "Fri, 01 Mar 2024 13:30:00 GMT" ||
+		resp.Header.Get("Cache-Control") != "max-age=60" {
+		t.Fatalf("headers = %v", resp.Header)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/resource", nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /resource error = %v", err)
+	}
+	all, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified || len(all) != 0 || resp.Header.Get("Cache-Control") != "max-age=60" {
+		t.Errorf("status = %d, body = %q, headers = %v", resp.StatusCode, all, resp.Header)
+	}
+}
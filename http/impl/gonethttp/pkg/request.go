@@ -1,17 +1,29 @@
 package gonethttp
 
 import (
+	"errors"
+	"fmt"
 	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 )
 
 type (
-	Request struct{ httpReq *http.Request }
+	Request struct {
+		httpReq *http.Request
+
+		// formConfig holds the limits used to parse the form, if nil the httpadpt defaults are used
+		formConfig *httpadpt.FormConfig
+	}
 
 	query  struct{ url *url.URL }
 	header struct{ header http.Header }
 	path   struct{ httpReq *http.Request }
+	form   struct{ httpReq *http.Request }
+	cookie struct{ httpReq *http.Request }
 )
 
 func (r Request) URL() *url.URL {
@@ -30,6 +42,38 @@ func (r Request) Method() string {
 	return r.httpReq.Method
 }
 
+func (r Request) RemoteAddr() string {
+	if r.httpReq == nil {
+		return ""
+	}
+
+	return r.httpReq.RemoteAddr
+}
+
+// Body returns the request body, http.NoBody if it has none
+func (r Request) Body() io.Reader {
+	if r.httpReq == nil || r.httpReq.Body == nil {
+		return http.NoBody
+	}
+	return r.httpReq.Body
+}
+
+// WrapBody replaces the request body by the reader returned by wrap, it is used by the middlewares that decode the
+// body before it is read
+func (r Request) WrapBody(wrap func(body io.ReadCloser) (io.ReadCloser, error)) error {
+	if r.httpReq == nil || r.httpReq.Body == nil || r.httpReq.Body == http.NoBody {
+		return nil
+	}
+	body, err := wrap(r.httpReq.Body)
+	if err != nil {
+		return err
+	}
+	r.httpReq.Body = body
+	r.httpReq.ContentLength = -1
+	r.httpReq.Header.Del("Content-Encoding")
+	return nil
+}
+
 func (p path) GetValue(pathParamName string) (string, bool) {
 	if p.httpReq == nil {
 		return "", false
@@ -53,7 +97,7 @@ func (r Request) Path() httpadpt.PathParams {
 	if r.httpReq == nil {
 		return path{}
 	}
-	return path(r)
+	return path{httpReq: r.httpReq}
 }
 
 func (q header) GetValue(name string) ([]string, bool) {
@@ -65,6 +109,36 @@ func (q header) GetValue(name string) ([]string, bool) {
 	return v, len(v) > 0
 }
 
+func (c cookie) GetValue(name string) (string, bool) {
+	if c.httpReq == nil {
+		return "", false
+	}
+
+	v, err := c.httpReq.Cookie(name)
+	if err != nil {
+		return "", false
+	}
+	return v.Value, true
+}
+
+func (f form) GetValue(name string) ([]string, bool) {
+	if f.httpReq == nil || len(f.httpReq.PostForm) == 0 {
+		return nil, false
+	}
+
+	v, found := f.httpReq.PostForm[name]
+	return v, found
+}
+
+func (f form) GetFiles(name string) ([]*multipart.FileHeader, bool) {
+	if f.httpReq == nil || f.httpReq.MultipartForm == nil {
+		return nil, false
+	}
+
+	v := f.httpReq.MultipartForm.File[name]
+	return v, len(v) > 0
+}
+
 func (q query) GetValue(name string) ([]string, bool) {
 	if q.url == nil {
 		return nil, false
@@ -85,6 +159,48 @@ func (r Request) Query() httpadpt.QueryParams {
 	return query{url: r.httpReq.URL}
 }
 
+func (r Request) Cookie() httpadpt.CookieParams {
+	return cookie{httpReq: r.httpReq}
+}
+
+func (r Request) Form() (httpadpt.FormParams, error) {
+	if r.httpReq == nil {
+		return form{}, nil
+	}
+	if err := parseForm(r.httpReq, r.formConfig); err != nil {
+		return nil, err
+	}
+	return form{httpReq: r.httpReq}, nil
+}
+
+// parseForm parses the urlencoded or multipart body only once, the parsed values are kept in the http.Request.
+func parseForm(httpReq *http.Request, formConfig *httpadpt.FormConfig) error {
+	if httpReq.PostForm != nil {
+		return nil
+	}
+	if maxSize := formConfig.GetMaxSize(); maxSize > 0 && httpReq.Body != nil {
+		httpReq.Body = http.MaxBytesReader(nil, httpReq.Body, maxSize)
+	}
+
+	err := httpReq.ParseMultipartForm(formConfig.GetMaxMemory())
+	if err == nil || errors.Is(err, http.ErrNotMultipart) {
+		return nil
+	}
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return fmt.Errorf("gonethttp.parseForm: request body exceeds %d bytes: %w", maxBytesError.Limit, err)
+	}
+	return serror.IllegalArgumentValueWithCause("form", httpReq.Header.Get("Content-Type"), err)
+}
+
+var (
+	_ httpadpt.BodyRequest       = Request{}
+	_ httpadpt.BodyWrapper       = Request{}
+	_ httpadpt.CookieRequest     = Request{}
+	_ httpadpt.FormRequest       = Request{}
+	_ httpadpt.RemoteAddrRequest = Request{}
+)
+
 func NewRequest(httpReq *http.Request) httpadpt.Request {
 	return Request{httpReq: httpReq}
 }
@@ -1,8 +1,14 @@
 package gonethttp
 
 import (
+	"bytes"
+	"errors"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -310,6 +316,11 @@ func TestRequest_AllMethods(t *testing.T) {
 		t.Errorf("Header() GetValue() = %v, %v, want [application/json], true", headerValue, headerFound)
 	}
 
+	// Test RemoteAddr, httptest sets a fixed client address
+	if got := httpadpt.GetRemoteAddr(req); got != "192.0.2.1:1234" {
+		t.Errorf("RemoteAddr() = %q, want 192.0.2.1:1234", got)
+	}
+
 	// Test Path (will be empty for standard request)
 	pathParams := req.Path()
 	pathValue, pathFound := pathParams.GetValue("id")
@@ -393,8 +404,144 @@ func TestPath_GetValue_NilRequest(t *testing.T) {
 	}
 }
 
+func TestRequest_Cookie(t *testing.T) {
+	httpReq := httptest.NewRequest("GET", "/test", nil)
+	httpReq.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	httpReq.AddCookie(&http.Cookie{Name: "empty", Value: ""})
+	cookies := httpadpt.GetCookie(NewRequest(httpReq))
+
+	if value, found := cookies.GetValue("session"); !found || value != "abc" {
+		t.Errorf("GetValue(session) = %q, %v, want %q, true", value, found, "abc")
+	}
+	if value, found := cookies.GetValue("empty"); !found || value != "" {
+		t.Errorf("GetValue(empty) = %q, %v, want empty, true", value, found)
+	}
+	if _, found := cookies.GetValue("missing"); found {
+		t.Error("GetValue(missing) found = true, want false")
+	}
+	if _, found := httpadpt.GetCookie(NewRequest(nil)).GetValue("session"); found {
+		t.Error("GetValue() found = true, want false for nil request")
+	}
+}
+
+func TestRequest_Form_URLEncoded(t *testing.T) {
+	httpReq := httptest.NewRequest("POST", "/test?name=query", strings.NewReader("name=john&role=admin&role=user"))
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req := NewRequest(httpReq)
+
+	form, err := httpadpt.GetForm(req)
+	if err != nil {
+		t.Fatalf("Form() error = %v", err)
+	}
+	if value, found := form.GetValue("name"); !found || len(value) != 1 || value[0] != "john" {
+		t.Errorf("GetValue(name) = %v, %v, want [john], true", value, found)
+	}
+	if value, found := form.GetValue("role"); !found || len(value) != 2 {
+		t.Errorf("GetValue(role) = %v, %v, want [admin user], true", value, found)
+	}
+	if _, found := form.GetValue("missing"); found {
+		t.Error("GetValue(missing) found = true, want false")
+	}
+	if _, found := form.GetFiles("name"); found {
+		t.Error("GetFiles(name) found = true, want false for urlencoded form")
+	}
+}
+
+func TestRequest_Form_Multipart(t *testing.T) {
+	httpReq := createMultipartRequest(t, map[string]string{"name": "john"}, "upload", "data.csv", "a,b\n1,2")
+	req := NewRequest(httpReq)
+
+	form, err := httpadpt.GetForm(req)
+	if err != nil {
+		t.Fatalf("Form() error = %v", err)
+	}
+	if value, found := form.GetValue("name"); !found || value[0] != "john" {
+		t.Errorf("GetValue(name) = %v, %v, want [john], true", value, found)
+	}
+	files, found := form.GetFiles("upload")
+	if !found || len(files) != 1 {
+		t.Fatalf("GetFiles(upload) = %v, %v, want one file", files, found)
+	}
+	if files[0].Filename != "data.csv" {
+		t.Errorf("Filename = %q, want %q", files[0].Filename, "data.csv")
+	}
+	f, _ := files[0].Open()
+	defer func() { _ = f.Close() }()
+	if content, _ := io.ReadAll(f); string(content) != "a,b\n1,2" {
+		t.Errorf("file content = %q", content)
+	}
+
+	// the second call reuses the parsed form
+	if again, err := httpadpt.GetForm(req); err != nil || again == nil {
+		t.Errorf("Form() second call = %v, %v", again, err)
+	}
+}
+
+func TestRequest_Form_MaxSize(t *testing.T) {
+	httpReq := createMultipartRequest(t, nil, "upload", "big.bin", strings.Repeat("x", 1024))
+	req := Request{httpReq: httpReq, formConfig: &httpadpt.FormConfig{MaxSize: 100}}
+
+	_, err := httpadpt.GetForm(req)
+	var maxBytesError *http.MaxBytesError
+	if !errors.As(err, &maxBytesError) {
+		t.Fatalf("Form() error = %v, want *http.MaxBytesError", err)
+	}
+}
+
+func TestRequest_Form_Malformed(t *testing.T) {
+	httpReq := httptest.NewRequest("POST", "/test", strings.NewReader("--broken"))
+	httpReq.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+	req := NewRequest(httpReq)
+
+	if _, err := httpadpt.GetForm(req); err == nil {
+		t.Error("Form() error = nil, want error for malformed multipart body")
+	}
+}
+
+func TestRequest_Form_NilRequest(t *testing.T) {
+	form, err := httpadpt.GetForm(NewRequest(nil))
+	if err != nil {
+		t.Fatalf("Form() error = %v", err)
+	}
+	if _, found := form.GetValue("any"); found {
+		t.Error("GetValue() found = true, want false for nil request")
+	}
+	if _, found := form.GetFiles("any"); found {
+		t.Error("GetFiles() found = true, want false for nil request")
+	}
+}
+
+func TestRequest_Body(t *testing.T) {
+	payload, err := httpadpt.ReadBody(NewRequest(httptest.NewRequest("POST", "/test", strings.NewReader(`{"a":1}`))), 100)
+	if err != nil || string(payload) != `{"a":1}` {
+		t.Errorf("ReadBody() = %q, %v, want the body", payload, err)
+	}
+	if payload, err := httpadpt.ReadBody(NewRequest(nil), 100); err != nil || len(payload) != 0 {
+		t.Errorf("ReadBody() = %q, %v, want empty for nil request", payload, err)
+	}
+}
+
 // Helper functions
 
+func createMultipartRequest(t *testing.T, fields map[string]string, fileField, fileName, content string) *http.Request {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		_ = writer.WriteField(name, value)
+	}
+	part, err := writer.CreateFormFile(fileField, fileName)
+	if err != nil {
+		t.Fatalf("CreateFormFile() error = %v", err)
+	}
+	_, _ = part.Write([]byte(content))
+	_ = writer.Close()
+
+	req := httptest.NewRequest("POST", "/test", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
 func createRequestWithHeader(name, value string) *http.Request {
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set(name, value)
@@ -3,16 +3,65 @@ package gonethttp
 import (
 	"context"
 	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	"io"
 	"net/http"
+	"sync"
 )
 
-func handleResponse(_ context.Context, w http.ResponseWriter, resp httpadpt.Response) {
+type (
+	// contextReader stops reading once the context is done
+	contextReader struct {
+		ctx context.Context
+		r   io.Reader
+	}
+
+	// flushWriter flushes every write so that streamed chunks reach the client as soon as they are produced
+	flushWriter struct {
+		w     io.Writer
+		flush func()
+	}
+)
+
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	f.flush()
+	return n, err
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+func getFlushFunc(w http.ResponseWriter) func() {
+	if flusher, ok := w.(http.Flusher); ok {
+		return flusher.Flush
+	}
+	return func() {}
+}
+
+func setHeaderIfAbsent(w http.ResponseWriter, name, value string) {
+	if w.Header().Get(name) == "" {
+		w.Header().Set(name, value)
+	}
+}
+
+func handleResponse(ctx context.Context, w http.ResponseWriter, resp httpadpt.Response) {
 	// Set headers before WriteHeader (headers must be set before WriteHeader)
 	if len(resp.Header) > 0 {
 		for k, v := range resp.Header {
 			w.Header()[k] = v
 		}
 	}
+	for _, cookie := range resp.Cookies {
+		http.SetCookie(w, cookie)
+	}
+	if resp.Events != nil {
+		setHeaderIfAbsent(w, "Content-Type", httpadpt.ContentTypeEventStream)
+		setHeaderIfAbsent(w, "Cache-Control", "no-cache")
+	}
 	// Set status code
 	if resp.StatusCode != nil {
 		w.WriteHeader(*resp.StatusCode)
@@ -20,7 +69,12 @@ func handleResponse(_ context.Context, w http.ResponseWriter, resp httpadpt.Resp
 		w.WriteHeader(http.StatusOK)
 	}
 	// Write body
-	if resp.Body != nil {
+	switch {
+	case resp.Events != nil:
+		writeEvents(ctx, w, resp.Events)
+	case resp.BodyStream != nil:
+		writeBodyStream(ctx, w, resp.BodyStream)
+	case resp.Body != nil:
 		if _, err := w.Write(resp.Body); err != nil {
 			// Log error if possible, but response may already be committed
 			// In production, consider using a logger here
@@ -28,3 +82,37 @@ func handleResponse(_ context.Context, w http.ResponseWriter, resp httpadpt.Resp
 		}
 	}
 }
+
+// writeBodyStream copies the stream to the client flushing each chunk until the stream ends, a write fails or the
+// context is done, what happens when the client disconnects. The stream is closed, if possible, also when the context
+// is done to unblock the pending Read.
+func writeBodyStream(ctx context.Context, w http.ResponseWriter, stream io.Reader) {
+	if closer, ok := stream.(io.Closer); ok {
+		var closeOnce sync.Once
+		closeStream := func() { closeOnce.Do(func() { _ = closer.Close() }) }
+		defer context.AfterFunc(ctx, closeStream)()
+		defer closeStream()
+	}
+	_, _ = io.Copy(flushWriter{w: w, flush: getFlushFunc(w)}, contextReader{ctx: ctx, r: stream})
+}
+
+// writeEvents writes the events received until the channel is closed or the context is done, what happens when the
+// client disconnects.
+func writeEvents(ctx context.Context, w http.ResponseWriter, events <-chan httpadpt.Event) {
+	flush := getFlushFunc(w)
+	flush()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := httpadpt.WriteEvent(w, event); err != nil {
+				return
+			}
+			flush()
+		}
+	}
+}
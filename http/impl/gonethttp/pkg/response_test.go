@@ -3,9 +3,12 @@ package gonethttp
 import (
 	"context"
 	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func Test_handleResponse_WithStatusCode(t *testing.T) {
@@ -143,6 +146,25 @@ func Test_handleResponse_WithHeaders(t *testing.T) {
 	}
 }
 
+func Test_handleResponse_WithCookies(t *testing.T) {
+	resp := httpadpt.Response{
+		Header: map[string][]string{"Set-Cookie": {"raw=1"}},
+		Cookies: []*http.Cookie{
+			{Name: "session", Value: "abc", Path: "/", HttpOnly: true},
+			{Name: "theme", Value: "dark"},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	handleResponse(context.Background(), w, resp)
+
+	expected := []string{"raw=1", "session=abc; Path=/; HttpOnly", "theme=dark"}
+	got := w.Header().Values("Set-Cookie")
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("Set-Cookie = %v, want %v", got, expected)
+	}
+}
+
 func Test_handleResponse_WithBody(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -412,3 +434,122 @@ func Test_handleResponse_HeaderOverwrite(t *testing.T) {
 		t.Errorf("Header X-Custom[1] = %q, want %q", values[1], "value2")
 	}
 }
+
+type closeTrackingReader struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTrackingReader) Close() error {
+	c.closed = true
+	return nil
+}
+
+func Test_handleResponse_WithBodyStream(t *testing.T) {
+	stream := &closeTrackingReader{Reader: strings.NewReader("streamed body")}
+	resp := httpadpt.Response{
+		StatusCode: intPtr(200),
+		Body:       []byte("ignored"),
+		BodyStream: stream,
+	}
+
+	w := httptest.NewRecorder()
+	handleResponse(context.Background(), w, resp)
+
+	if w.Body.String() != "streamed body" {
+		t.Errorf("Body = %q, want %q", w.Body.String(), "streamed body")
+	}
+	if !w.Flushed {
+		t.Error("Expected the stream to be flushed")
+	}
+	if !stream.closed {
+		t.Error("Expected the stream to be closed")
+	}
+}
+
+func Test_handleResponse_WithBodyStream_StopsWhenContextIsDone(t *testing.T) {
+	chunks := make(chan []byte)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	w := httptest.NewRecorder()
+	go func() {
+		defer close(done)
+		handleResponse(ctx, w, httpadpt.Response{BodyStream: httpadpt.NewChanReader(chunks)})
+	}()
+
+	chunks <- []byte("first")
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handleResponse did not return after the context was cancelled")
+	}
+	if w.Body.String() != "first" {
+		t.Errorf("Body = %q, want %q", w.Body.String(), "first")
+	}
+}
+
+func Test_handleResponse_WithEvents(t *testing.T) {
+	events := make(chan httpadpt.Event, 2)
+	events <- httpadpt.Event{ID: "1", Data: "first"}
+	events <- httpadpt.Event{Event: "done", Data: "second"}
+	close(events)
+
+	w := httptest.NewRecorder()
+	handleResponse(context.Background(), w, httpadpt.Response{Events: events})
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Status code = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("Content-Type"); got != httpadpt.ContentTypeEventStream {
+		t.Errorf("Content-Type = %q, want %q", got, httpadpt.ContentTypeEventStream)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q, want %q", got, "no-cache")
+	}
+	expected := "id: 1\ndata: first\n\nevent: done\ndata: second\n\n"
+	if w.Body.String() != expected {
+		t.Errorf("Body = %q, want %q", w.Body.String(), expected)
+	}
+}
+
+func Test_handleResponse_WithEvents_StopsWhenContextIsDone(t *testing.T) {
+	events := make(chan httpadpt.Event)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	w := httptest.NewRecorder()
+	go func() {
+		defer close(done)
+		handleResponse(ctx, w, httpadpt.Response{Events: events})
+	}()
+
+	events <- httpadpt.Event{Data: "first"}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handleResponse did not return after the context was cancelled")
+	}
+	if w.Body.String() != "data: first\n\n" {
+		t.Errorf("Body = %q, want %q", w.Body.String(), "data: first\n\n")
+	}
+}
+
+func Test_handleResponse_WithEvents_KeepsGivenContentType(t *testing.T) {
+	events := make(chan httpadpt.Event)
+	close(events)
+
+	w := httptest.NewRecorder()
+	handleResponse(context.Background(), w, httpadpt.Response{
+		Header: map[string][]string{"Content-Type": {"text/event-stream; charset=utf-8"}},
+		Events: events,
+	})
+
+	if got := w.Header().Get("Content-Type"); got != "text/event-stream; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want %q", got, "text/event-stream; charset=utf-8")
+	}
+}
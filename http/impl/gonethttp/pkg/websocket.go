@@ -0,0 +1,246 @@
+package gonethttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+)
+
+const (
+	// webSocketWriteTimeout limits the time to write a frame to a slow client
+	webSocketWriteTimeout = 10 * time.Second
+
+	// webSocketCloseTimeout is how long the client has to reply the close frame sent by the server
+	webSocketCloseTimeout = 5 * time.Second
+)
+
+type (
+	// webSocketTracker keeps the connections upgraded by an adapter, so Stop can close them, the http.Server
+	// Shutdown ignores the hijacked connections. The handlers context is canceled by Stop, see handlerContext.
+	webSocketTracker struct {
+		mutex   sync.Mutex
+		closing bool
+		conns   map[*httpadpt.WebSocketConn]*websocket.Conn
+		done    sync.WaitGroup
+		ctx     context.Context
+		cancel  context.CancelFunc
+	}
+
+	webSocketTrackerKey struct{}
+)
+
+func newWebSocketTracker() *webSocketTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webSocketTracker{conns: map[*httpadpt.WebSocketConn]*websocket.Conn{}, ctx: ctx, cancel: cancel}
+}
+
+// handlerContext returns the context of the session handlers. It has the values of the upgrade request context, but
+// it is canceled by Stop instead of by the end of the request. Without a tracker, like with NewHandler, it is derived
+// from the request context.
+func (t *webSocketTracker) handlerContext(r *http.Request) (context.Context, context.CancelFunc) {
+	if t == nil {
+		return context.WithCancel(r.Context())
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	stop := context.AfterFunc(t.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+func (t *webSocketTracker) add(session *httpadpt.WebSocketConn, conn *websocket.Conn) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.closing {
+		return false
+	}
+	t.conns[session] = conn
+	t.done.Add(1)
+	return true
+}
+
+func (t *webSocketTracker) remove(session *httpadpt.WebSocketConn) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.conns, session)
+	t.done.Done()
+}
+
+// closeAll sends the close frame to the clients, the connections end when they reply
+func (t *webSocketTracker) closeAll() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.closing = true
+	for session := range t.conns {
+		session.Close(httpadpt.WebSocketCloseGoingAway, "server shutting down")
+	}
+}
+
+// wait closes the connections and waits for them to end until the context is done, then the remaining ones are
+// closed abruptly and their handlers context is canceled, without waiting for the handlers to return
+func (t *webSocketTracker) wait(ctx context.Context) error {
+	t.closeAll()
+	done := make(chan struct{})
+	go func() {
+		t.done.Wait()
+		close(done)
+	}()
+	defer t.cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+	t.mutex.Lock()
+	for _, conn := range t.conns {
+		_ = conn.Close()
+	}
+	t.mutex.Unlock()
+	return ctx.Err()
+}
+
+// serveWebSocket upgrades the connection and serves the session until the connection is closed. The upgrader replies
+// the invalid handshakes with an error status.
+func serveWebSocket(w http.ResponseWriter, r *http.Request, resp httpadpt.Response) {
+	session := resp.WebSocket
+	header := http.Header{}
+	for name, values := range resp.Header {
+		header[name] = values
+	}
+	for _, cookie := range resp.Cookies {
+		if value := cookie.String(); value != "" {
+			header.Add("Set-Cookie", value)
+		}
+	}
+	upgrader := websocket.Upgrader{}
+	if session.Config.CheckOrigin != nil {
+		upgrader.CheckOrigin = func(*http.Request) bool { return session.Config.CheckOrigin(session.Conn.Request()) }
+	}
+	conn, err := upgrader.Upgrade(w, r, header)
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	tracker, _ := r.Context().Value(webSocketTrackerKey{}).(*webSocketTracker)
+	ctx, cancel := tracker.handlerContext(r)
+	defer cancel()
+	if tracker != nil {
+		if !tracker.add(session.Conn, conn) {
+			session.Conn.Close(httpadpt.WebSocketCloseGoingAway, "server shutting down")
+		} else {
+			defer tracker.remove(session.Conn)
+		}
+	}
+	serveWebSocketConn(ctx, conn, session)
+}
+
+// serveWebSocketConn reads the messages and handles them in order, while another goroutine writes the frames and the
+// pings. The session is closed by the client, the handlers or the server, and the connection ends when both sides
+// have sent the close frame.
+func serveWebSocketConn(ctx context.Context, conn *websocket.Conn, session *httpadpt.WebSocketSession) {
+	config := session.Config
+	conn.SetReadLimit(config.MaxMessageSize)
+	if config.PingInterval > 0 {
+		readTimeout := config.PingInterval + config.PongTimeout
+		_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
+		conn.SetPongHandler(func(string) error { return conn.SetReadDeadline(time.Now().Add(readTimeout)) })
+	}
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		writeWebSocketFrames(conn, session.Conn, config.PingInterval)
+	}()
+
+	if err := session.Open(ctx); err != nil {
+		session.Conn.Close(httpadpt.WebSocketClosePolicyViolation, err.Error())
+	}
+	// the reading continues after the session is closed, until the client replies the close frame
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			session.Conn.Close(webSocketCloseStatus(err))
+			break
+		}
+		select {
+		case <-session.Conn.Done():
+		default:
+			_ = session.HandleMessage(ctx, data)
+		}
+	}
+	<-writerDone
+	session.Close(ctx)
+}
+
+// writeWebSocketFrames writes the queued frames and the pings. Once the session is closed, it writes the frames still
+// queued and the close frame, and gives the client webSocketCloseTimeout to reply it.
+func writeWebSocketFrames(conn *websocket.Conn, session *httpadpt.WebSocketConn, pingInterval time.Duration) {
+	var pings <-chan time.Time
+	if pingInterval > 0 {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		pings = ticker.C
+	}
+	write := func(frame []byte) bool {
+		_ = conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+		if err := conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+			session.Close(httpadpt.WebSocketCloseGoingAway, "")
+			_ = conn.Close()
+			return false
+		}
+		return true
+	}
+
+	for {
+		select {
+		case frame := <-session.Outbound():
+			if !write(frame) {
+				return
+			}
+		case <-pings:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketWriteTimeout)); err != nil {
+				session.Close(httpadpt.WebSocketCloseGoingAway, "")
+				_ = conn.Close()
+				return
+			}
+		case <-session.Done():
+			for {
+				select {
+				case frame := <-session.Outbound():
+					if !write(frame) {
+						return
+					}
+					continue
+				default:
+				}
+				break
+			}
+			code, reason := session.CloseStatus()
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+				time.Now().Add(webSocketWriteTimeout))
+			_ = conn.SetReadDeadline(time.Now().Add(webSocketCloseTimeout))
+			return
+		}
+	}
+}
+
+// webSocketCloseStatus returns the close code replied to the read error: the client close code, or the one of the
+// size limit, otherwise the connection is gone
+func webSocketCloseStatus(err error) (int, string) {
+	var closeErr *websocket.CloseError
+	switch {
+	case errors.As(err, &closeErr):
+		return closeErr.Code, ""
+	case errors.Is(err, websocket.ErrReadLimit):
+		return httpadpt.WebSocketCloseMessageTooBig, "message too big"
+	default:
+		return httpadpt.WebSocketCloseGoingAway, ""
+	}
+}
@@ -0,0 +1,265 @@
+package gonethttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+)
+
+type (
+	echoInput struct {
+		Room string `path:"room"`
+		User string `wsvalue:"user"`
+		Text string `ws:"text"`
+	}
+
+	echoOutput struct {
+		Reply map[string]string `ws:""`
+	}
+)
+
+func newWebSocketBinding(config httpadpt.WebSocketConfig, closed chan<- string) httpadpt.Binding {
+	config.OnConnect = func(ctx context.Context, conn *httpadpt.WebSocketConn) error {
+		values, _ := conn.Request().Header().GetValue("X-User")
+		if len(values) == 0 {
+			return errors.New("anonymous")
+		}
+		conn.SetValue("user", values[0])
+		return nil
+	}
+	config.OnClose = func(ctx context.Context, conn *httpadpt.WebSocketConn) {
+		user, _ := conn.Value("user")
+		closed <- fmt.Sprint(user)
+	}
+	return httpadpt.NewWebSocketBindingBuilder("/rooms/{room}").
+		WithConfig(config).
+		WithMessageHandlerFunc("echo", func(in echoInput) (*echoOutput, error) {
+			return &echoOutput{Reply: map[string]string{"room": in.Room, "user": in.User, "text": in.Text}}, nil
+		}).
+		Build()
+}
+
+func dialWebSocket(t *testing.T, url, user string) *websocket.Conn {
+	t.Helper()
+	header := http.Header{}
+	if user != "" {
+		header.Set("X-User", user)
+	}
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http")+"/rooms/lobby", header)
+	if err != nil {
+		t.Fatalf("Dial() error = %v, response = %v", err, resp)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	return conn
+}
+
+func Test_WebSocket_Messages(t *testing.T) {
+	closed := make(chan string, 1)
+	handler, err := NewHandler(httpadpt.Config{Bindings: httpadpt.Bindings{newWebSocketBinding(httpadpt.WebSocketConfig{}, closed)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conn := dialWebSocket(t, server.URL, "ana")
+	for i, text := range []string{"hello", "world"} {
+		if err := conn.WriteJSON(map[string]any{"type": "echo", "id": i, "text": text}); err != nil {
+			t.Fatal(err)
+		}
+		var reply map[string]any
+		if err := conn.ReadJSON(&reply); err != nil {
+			t.Fatalf("ReadJSON() error = %v", err)
+		}
+		expected := map[string]any{"type": "echo", "id": float64(i), "room": "lobby", "user": "ana", "text": text}
+		if fmt.Sprint(reply) != fmt.Sprint(expected) {
+			t.Errorf("reply = %v, want %v", reply, expected)
+		}
+	}
+
+	_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("ReadMessage() after close error = %v, want the close reply", err)
+	}
+	select {
+	case user := <-closed:
+		if user != "ana" {
+			t.Errorf("OnClose user = %q", user)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("OnClose not invoked")
+	}
+
+	resp, err := http.Get(server.URL + "/rooms/lobby")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Errorf("GET without upgrade = %d, want 426", resp.StatusCode)
+	}
+}
+
+func Test_WebSocket_ConnectionLimits(t *testing.T) {
+	closed := make(chan string, 3)
+	handler, err := NewHandler(httpadpt.Config{Bindings: httpadpt.Bindings{newWebSocketBinding(httpadpt.WebSocketConfig{
+		MaxMessageSize: 64,
+		PingInterval:   20 * time.Millisecond,
+		PongTimeout:    20 * time.Millisecond,
+	}, closed)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	t.Run("rejected by OnConnect", func(t *testing.T) {
+		conn := dialWebSocket(t, server.URL, "")
+		if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+			t.Errorf("ReadMessage() error = %v, want policy violation", err)
+		}
+	})
+
+	t.Run("max message size", func(t *testing.T) {
+		conn := dialWebSocket(t, server.URL, "ana")
+		_ = conn.WriteJSON(map[string]any{"type": "echo", "text": strings.Repeat("x", 100)})
+		if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+			t.Errorf("ReadMessage() error = %v, want message too big", err)
+		}
+	})
+
+	t.Run("keepalive", func(t *testing.T) {
+		conn := dialWebSocket(t, server.URL, "ana")
+		pings := make(chan struct{}, 10)
+		conn.SetPingHandler(func(data string) error {
+			pings <- struct{}{}
+			return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		})
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		// the connection stays open after the ping and pong timeouts, because the client replies the pings
+		for range 4 {
+			select {
+			case <-pings:
+			case <-time.After(2 * time.Second):
+				t.Fatal("no ping received")
+			}
+		}
+		if err := conn.WriteJSON(map[string]any{"type": "echo", "text": "alive"}); err != nil {
+			t.Errorf("WriteJSON() after pings error = %v", err)
+		}
+	})
+
+	t.Run("no pong", func(t *testing.T) {
+		// the client does not read, so it does not reply the pings
+		dialWebSocket(t, server.URL, "bob")
+		select {
+		case user := <-closed:
+			for user != "bob" {
+				user = <-closed
+			}
+		case <-time.After(2 * time.Second):
+			t.Error("connection without pongs not closed")
+		}
+	})
+}
+
+func Test_WebSocket_StopClosesConnections(t *testing.T) {
+	port := 0
+	closed := make(chan string, 1)
+	adapter, err := NewAdapter(httpadpt.Config{Port: &port, Bindings: httpadpt.Bindings{
+		newWebSocketBinding(httpadpt.WebSocketConfig{}, closed),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	conn := dialWebSocket(t, fmt.Sprintf("http://%s", adapter.(*DefaultAdapter).listener.Addr()), "ana")
+
+	read := make(chan error, 1)
+	go func() {
+		_, _, err := conn.ReadMessage()
+		read <- err
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := adapter.Stop(ctx); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+	if err := <-read; !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("ReadMessage() error = %v, want going away", err)
+	}
+	select {
+	case <-closed:
+	default:
+		t.Error("Stop() returned before the connection was closed")
+	}
+}
+
+func Test_WebSocket_StopCancelsHandlers(t *testing.T) {
+	port := 0
+	started := make(chan struct{}, 1)
+	canceled := make(chan error, 1)
+	adapter, err := NewAdapter(httpadpt.Config{Port: &port, Bindings: httpadpt.Bindings{
+		httpadpt.NewWebSocketBindingBuilder("/rooms/{room}").
+			WithMessageHandlerFunc("wait", func(ctx context.Context) error {
+				started <- struct{}{}
+				<-ctx.Done()
+				canceled <- ctx.Err()
+				return nil
+			}).
+			Build(),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	conn := dialWebSocket(t, fmt.Sprintf("http://%s", adapter.(*DefaultAdapter).listener.Addr()), "")
+	if err := conn.WriteJSON(map[string]any{"type": "wait"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler not invoked")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- adapter.Stop(ctx) }()
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Stop() error = %v, want the context error", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop() blocked after its context was done")
+	}
+	select {
+	case err := <-canceled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("handler ctx error = %v, want canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("the handler context was not canceled by Stop")
+	}
+}
@@ -0,0 +1,116 @@
+package httpadpt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type (
+	// Principal is the authenticated identity
+	Principal struct {
+		// Subject identifies the principal, like the username, the JWT "sub" claim or the API key owner
+		Subject string
+		// Scheme is the authentication scheme used, like "Basic", "Bearer" or "APIKey"
+		Scheme string
+		// Claims are the attributes of the principal, like the JWT claims
+		Claims map[string]any
+	}
+
+	// Authenticator authenticates a request using one scheme
+	Authenticator interface {
+		// Challenge returns the WWW-Authenticate value sent when the authentication fails
+		Challenge() string
+
+		// Authenticate returns the Principal identified by the request credentials. If the request does not have
+		// credentials for this scheme, it returns an error wrapping ErrNoCredentials, so the next Authenticator is
+		// tried. Any other error must wrap ErrUnauthorized.
+		Authenticate(ctx context.Context, input Request) (Principal, error)
+	}
+
+	// PrincipalKey is used to add the Principal to the context and to the Request
+	PrincipalKey struct{}
+)
+
+var (
+	// ErrUnauthorized is wrapped by the authentication errors, it is mapped to 401
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden is wrapped by the authorization errors, it is mapped to 403
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrNoCredentials means the request does not have credentials for the Authenticator scheme
+	ErrNoCredentials = fmt.Errorf("%w: no credentials", ErrUnauthorized)
+)
+
+// asUnauthorized wraps the error with ErrUnauthorized, if it is not wrapped yet
+func asUnauthorized(err error) error {
+	if err == nil || errors.Is(err, ErrUnauthorized) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrUnauthorized, err)
+}
+
+// asForbidden wraps the error with ErrForbidden, if it is not wrapped yet
+func asForbidden(err error) error {
+	if err == nil || errors.Is(err, ErrForbidden) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrForbidden, err)
+}
+
+// PrincipalFromContext returns the Principal added by the authentication middleware
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(PrincipalKey{}).(Principal)
+	return principal, ok
+}
+
+// PrincipalToContext returns a new context with the given Principal
+func PrincipalToContext(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, PrincipalKey{}, principal)
+}
+
+// PrincipalFromRequest returns the Principal added by the authentication middleware to the Request
+func PrincipalFromRequest(input Request) (Principal, bool) {
+	value, found := GetRequestValue(input, PrincipalKey{})
+	if !found {
+		return Principal{}, false
+	}
+	principal, ok := value.(Principal)
+	return principal, ok
+}
+
+// HasScopes returns true if the principal has all the given scopes in the "scope" claim, a space separated list as
+// defined by RFC 8693, or in the "scp" claim, a list of strings.
+func (p Principal) HasScopes(scopes ...string) bool {
+	var granted []string
+	if scope, ok := p.Claims["scope"].(string); ok {
+		granted = strings.Fields(scope)
+	}
+	switch scp := p.Claims["scp"].(type) {
+	case []string:
+		granted = append(granted, scp...)
+	case []any:
+		for _, s := range scp {
+			granted = append(granted, fmt.Sprint(s))
+		}
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// RequireScopes creates an AuthConfig.Authorize function that requires all the given scopes
+func RequireScopes(scopes ...string) func(ctx context.Context, principal Principal, input Request) error {
+	return func(_ context.Context, principal Principal, _ Request) error {
+		if !principal.HasScopes(scopes...) {
+			return fmt.Errorf("%w: the scopes %v are required", ErrForbidden, scopes)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+package httpadpt
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+)
+
+const (
+	AuthSchemeAPIKey = "APIKey"
+
+	DefaultAPIKeyHeader = "X-Api-Key"
+)
+
+type (
+	// APIKeyLookup returns the Principal that owns the key, otherwise an error
+	APIKeyLookup = func(ctx context.Context, key string) (Principal, error)
+
+	// APIKeyConfig configures the Authenticator created by NewAPIKeyAuthenticator
+	APIKeyConfig struct {
+		// Header is the request header that carries the key, if both Header and QueryParam are empty
+		// DefaultAPIKeyHeader is used
+		Header string
+
+		// QueryParam is the query parameter that carries the key, it is used when the header is not sent
+		QueryParam string
+
+		// Lookup finds the key owner, see APIKeys
+		Lookup APIKeyLookup
+	}
+
+	apiKeyAuthenticator struct {
+		config APIKeyConfig
+	}
+)
+
+// NewAPIKeyAuthenticator creates an Authenticator for API keys sent in a header or in a query parameter
+func NewAPIKeyAuthenticator(config APIKeyConfig) Authenticator {
+	if config.Header == "" && config.QueryParam == "" {
+		config.Header = DefaultAPIKeyHeader
+	}
+	return apiKeyAuthenticator{config: config}
+}
+
+// APIKeys creates an APIKeyLookup from a fixed set of keys, the keys are compared in constant time
+func APIKeys(keys map[string]Principal) APIKeyLookup {
+	return func(_ context.Context, key string) (Principal, error) {
+		for knownKey, principal := range keys {
+			if subtle.ConstantTimeCompare([]byte(knownKey), []byte(key)) == 1 {
+				return principal, nil
+			}
+		}
+		return Principal{}, fmt.Errorf("%w: unknown API key", ErrUnauthorized)
+	}
+}
+
+func (a apiKeyAuthenticator) Challenge() string {
+	return AuthSchemeAPIKey
+}
+
+func (a apiKeyAuthenticator) Authenticate(ctx context.Context, input Request) (Principal, error) {
+	key := ""
+	if a.config.Header != "" {
+		key = getFirstHeaderValue(input, a.config.Header)
+	}
+	if key == "" && a.config.QueryParam != "" && input.Query() != nil {
+		if values, found := input.Query().GetValue(a.config.QueryParam); found && len(values) > 0 {
+			key = values[0]
+		}
+	}
+	if key == "" {
+		return Principal{}, ErrNoCredentials
+	}
+
+	principal, err := a.config.Lookup(ctx, key)
+	if err != nil {
+		return Principal{}, asUnauthorized(err)
+	}
+	principal.Scheme = AuthSchemeAPIKey
+	return principal, nil
+}
@@ -0,0 +1,58 @@
+package httpadpt
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_apiKeyAuthenticator_Authenticate(t *testing.T) {
+	authenticator := NewAPIKeyAuthenticator(APIKeyConfig{
+		Header:     DefaultAPIKeyHeader,
+		QueryParam: "api_key",
+		Lookup:     APIKeys(map[string]Principal{"key-1": {Subject: "service-a"}}),
+	})
+
+	tests := []struct {
+		name            string
+		input           Request
+		expectedSubject string
+		expectedErr     error
+	}{
+		{
+			name:            "key in header",
+			input:           &mockRequest{header: &mockHeaderParams{values: map[string][]string{DefaultAPIKeyHeader: {"key-1"}}}, query: &mockQueryParams{}},
+			expectedSubject: "service-a",
+		},
+		{
+			name:            "key in query",
+			input:           &mockRequest{header: &mockHeaderParams{}, query: &mockQueryParams{values: map[string][]string{"api_key": {"key-1"}}}},
+			expectedSubject: "service-a",
+		},
+		{
+			name:        "unknown key",
+			input:       &mockRequest{header: &mockHeaderParams{values: map[string][]string{DefaultAPIKeyHeader: {"key-2"}}}, query: &mockQueryParams{}},
+			expectedErr: ErrUnauthorized,
+		},
+		{
+			name:        "no key",
+			input:       &mockRequest{header: &mockHeaderParams{}, query: &mockQueryParams{}},
+			expectedErr: ErrNoCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(context.Background(), tt.input)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Authenticate() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil || principal.Subject != tt.expectedSubject || principal.Scheme != AuthSchemeAPIKey {
+				t.Errorf("Authenticate() = %+v, %v", principal, err)
+			}
+		})
+	}
+}
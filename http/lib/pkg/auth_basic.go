@@ -0,0 +1,65 @@
+package httpadpt
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const (
+	AuthSchemeBasic = "Basic"
+)
+
+type (
+	// BasicValidator returns the Principal if the username and password are valid, otherwise an error
+	BasicValidator = func(ctx context.Context, username, password string) (Principal, error)
+
+	basicAuthenticator struct {
+		realm    string
+		validate BasicValidator
+	}
+)
+
+// NewBasicAuthenticator creates an Authenticator for the HTTP Basic scheme (RFC 7617)
+func NewBasicAuthenticator(realm string, validate BasicValidator) Authenticator {
+	return basicAuthenticator{realm: realm, validate: validate}
+}
+
+func (b basicAuthenticator) Challenge() string {
+	return fmt.Sprintf(`%s realm=%q, charset="UTF-8"`, AuthSchemeBasic, b.realm)
+}
+
+func (b basicAuthenticator) Authenticate(ctx context.Context, input Request) (Principal, error) {
+	credentials, found := getAuthorizationCredentials(input, AuthSchemeBasic)
+	if !found {
+		return Principal{}, ErrNoCredentials
+	}
+	decoded, err := base64.StdEncoding.DecodeString(credentials)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: malformed basic credentials", ErrUnauthorized)
+	}
+	username, password, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return Principal{}, fmt.Errorf("%w: malformed basic credentials", ErrUnauthorized)
+	}
+	principal, err := b.validate(ctx, username, password)
+	if err != nil {
+		return Principal{}, asUnauthorized(err)
+	}
+	if principal.Subject == "" {
+		principal.Subject = username
+	}
+	principal.Scheme = AuthSchemeBasic
+	return principal, nil
+}
+
+// getAuthorizationCredentials returns the credentials of the Authorization header if it uses the given scheme
+func getAuthorizationCredentials(input Request, scheme string) (string, bool) {
+	authorization := getFirstHeaderValue(input, HeaderAuthorization)
+	givenScheme, credentials, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(givenScheme, scheme) {
+		return "", false
+	}
+	return strings.TrimSpace(credentials), true
+}
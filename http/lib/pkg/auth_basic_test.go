@@ -0,0 +1,39 @@
+package httpadpt
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func Test_basicAuthenticator_Authenticate(t *testing.T) {
+	authenticator := NewBasicAuthenticator("api", func(_ context.Context, username, password string) (Principal, error) {
+		if username == "john" && password == "s3cr3t:x" {
+			return Principal{Claims: map[string]any{"role": "admin"}}, nil
+		}
+		return Principal{}, errors.New("invalid credentials")
+	})
+	basic := func(credentials string) Request {
+		return &mockRequest{header: &mockHeaderParams{values: map[string][]string{
+			HeaderAuthorization: {"Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))},
+		}}}
+	}
+
+	principal, err := authenticator.Authenticate(context.Background(), basic("john:s3cr3t:x"))
+	if err != nil || principal.Subject != "john" || principal.Scheme != AuthSchemeBasic || principal.Claims["role"] != "admin" {
+		t.Errorf("Authenticate() = %+v, %v", principal, err)
+	}
+	if _, err := authenticator.Authenticate(context.Background(), basic("john:wrong")); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Authenticate() wrong password error = %v, want ErrUnauthorized", err)
+	}
+	if _, err := authenticator.Authenticate(context.Background(), basic("no-colon")); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Authenticate() malformed error = %v, want ErrUnauthorized", err)
+	}
+	if _, err := authenticator.Authenticate(context.Background(), newBearerRequest("token")); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Authenticate() other scheme error = %v, want ErrNoCredentials", err)
+	}
+	if authenticator.Challenge() != `Basic realm="api", charset="UTF-8"` {
+		t.Errorf("Challenge() = %q", authenticator.Challenge())
+	}
+}
@@ -0,0 +1,165 @@
+package httpadpt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+const (
+	// MinJWTHMACKeySize is the minimum number of bytes of the oct keys, the size of the HS256 hash output
+	MinJWTHMACKeySize = 32
+)
+
+type (
+	// JWK is a key used to verify the JWT signatures
+	JWK struct {
+		// KeyID is matched against the "kid" JWT header, when both are set
+		KeyID string
+		// Algorithm restricts the key to one algorithm, like "RS256", if empty the key type defines the algorithms
+		Algorithm string
+		// Key is a []byte for the HS algorithms, an *rsa.PublicKey for the RS algorithms or an *ecdsa.PublicKey for
+		// the ES algorithms
+		Key any
+	}
+
+	// JWKS is the set of keys used to verify the JWT signatures
+	JWKS struct {
+		Keys []JWK
+	}
+
+	// jsonWebKey is the JSON representation of a key defined by RFC 7517
+	jsonWebKey struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		Use string `json:"use"`
+		K   string `json:"k"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+)
+
+// LoadJWKSFile reads a JSON Web Key Set file
+func LoadJWKSFile(path string) (JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return JWKS{}, fmt.Errorf("httpadpt.LoadJWKSFile: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses a JSON Web Key Set, the keys that are not used to verify signatures are ignored
+func ParseJWKS(data []byte) (JWKS, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return JWKS{}, fmt.Errorf("httpadpt.ParseJWKS: %w", err)
+	}
+	var jwks JWKS
+	for i, jsonKey := range set.Keys {
+		if jsonKey.Use != "" && jsonKey.Use != "sig" {
+			continue
+		}
+		key, err := jsonKey.toKey()
+		if err != nil {
+			return JWKS{}, fmt.Errorf("httpadpt.ParseJWKS: keys[%d] kid=[%s]: %w", i, jsonKey.Kid, err)
+		}
+		jwks.Keys = append(jwks.Keys, JWK{KeyID: jsonKey.Kid, Algorithm: jsonKey.Alg, Key: key})
+	}
+	return jwks, nil
+}
+
+func (k jsonWebKey) toKey() (any, error) {
+	switch k.Kty {
+	case "oct":
+		secret, err := decodeBase64URL(k.K)
+		if err != nil {
+			return nil, err
+		}
+		if len(secret) < MinJWTHMACKeySize {
+			return nil, fmt.Errorf("the oct key has %d bytes, the minimum is %d", len(secret), MinJWTHMACKeySize)
+		}
+		return secret, nil
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, err := getCurve(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("the point is not on the curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported kty=[%s]", k.Kty)
+}
+
+func getCurve(crv string) (elliptic.Curve, error) {
+	switch crv {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	}
+	return nil, fmt.Errorf("unsupported crv=[%s]", crv)
+}
+
+func decodeBase64URL(value string) ([]byte, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64url value: %w", err)
+	}
+	return decoded, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	decoded, err := decodeBase64URL(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(decoded), nil
+}
+
+// candidates returns the keys that can verify a signature made with the algorithm by the key identified by kid
+func (s JWKS) candidates(kid, alg string) []JWK {
+	var keys []JWK
+	for _, key := range s.Keys {
+		if kid != "" && key.KeyID != "" && key.KeyID != kid {
+			continue
+		}
+		if key.Algorithm != "" && key.Algorithm != alg {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,246 @@
+package httpadpt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers SHA-256 used by HS256, RS256 and ES256
+	_ "crypto/sha512" // registers SHA-384 and SHA-512
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	AuthSchemeBearer = "Bearer"
+)
+
+type (
+	// JWTConfig configures the Authenticator created by NewJWTAuthenticator
+	JWTConfig struct {
+		// KeySet has the keys used to verify the signatures, see LoadJWKSFile
+		KeySet JWKS
+
+		// Algorithms are the accepted algorithms, if empty all the supported ones are accepted:
+		// HS256, HS384, HS512, RS256, RS384, RS512, ES256, ES384 and ES512
+		Algorithms []string
+
+		// Issuer when set must be equal to the "iss" claim
+		Issuer string
+
+		// Audience when set must be one of the "aud" claim values
+		Audience string
+
+		// Leeway is the clock skew tolerated when the "exp" and "nbf" claims are validated
+		Leeway time.Duration
+
+		// RequireExp when nil or true rejects the tokens without the "exp" claim, so a leaked token cannot be used
+		// forever
+		RequireExp *bool
+
+		// Realm is sent in the WWW-Authenticate challenge
+		Realm string
+
+		// Now returns the current time, if nil time.Now is used
+		Now func() time.Time
+	}
+
+	jwtAuthenticator struct {
+		config JWTConfig
+	}
+
+	jwtHeader struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	jwtAlgorithm struct {
+		hash      crypto.Hash
+		verify    func(hash crypto.Hash, key any, signingInput, signature []byte) bool
+		curveSize int
+	}
+)
+
+var (
+	jwtAlgorithms = map[string]jwtAlgorithm{
+		"HS256": {hash: crypto.SHA256, verify: verifyHMAC},
+		"HS384": {hash: crypto.SHA384, verify: verifyHMAC},
+		"HS512": {hash: crypto.SHA512, verify: verifyHMAC},
+		"RS256": {hash: crypto.SHA256, verify: verifyRSA},
+		"RS384": {hash: crypto.SHA384, verify: verifyRSA},
+		"RS512": {hash: crypto.SHA512, verify: verifyRSA},
+		"ES256": {hash: crypto.SHA256, verify: verifyECDSA, curveSize: 256},
+		"ES384": {hash: crypto.SHA384, verify: verifyECDSA, curveSize: 384},
+		"ES512": {hash: crypto.SHA512, verify: verifyECDSA, curveSize: 521},
+	}
+
+	errInvalidToken = fmt.Errorf("%w: invalid token", ErrUnauthorized)
+)
+
+// NewJWTAuthenticator creates an Authenticator for Bearer JSON Web Tokens (RFC 7519) signed with HMAC, RSA or ECDSA
+func NewJWTAuthenticator(config JWTConfig) Authenticator {
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return jwtAuthenticator{config: config}
+}
+
+func (j jwtAuthenticator) Challenge() string {
+	if j.config.Realm == "" {
+		return AuthSchemeBearer
+	}
+	return fmt.Sprintf(`%s realm=%q`, AuthSchemeBearer, j.config.Realm)
+}
+
+func (j jwtAuthenticator) Authenticate(_ context.Context, input Request) (Principal, error) {
+	token, found := getAuthorizationCredentials(input, AuthSchemeBearer)
+	if !found {
+		return Principal{}, ErrNoCredentials
+	}
+	claims, err := j.verify(token)
+	if err != nil {
+		return Principal{}, err
+	}
+	subject, _ := claims["sub"].(string)
+	return Principal{Subject: subject, Scheme: AuthSchemeBearer, Claims: claims}, nil
+}
+
+// verify checks the token signature and the registered claims, it returns the token claims
+func (j jwtAuthenticator) verify(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", errInvalidToken)
+	}
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", errInvalidToken)
+	}
+	algorithm, supported := jwtAlgorithms[header.Alg]
+	if !supported || (len(j.config.Algorithms) > 0 && !slices.Contains(j.config.Algorithms, header.Alg)) {
+		return nil, fmt.Errorf("%w: algorithm=[%s] is not accepted", errInvalidToken, header.Alg)
+	}
+	signature, err := decodeBase64URL(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", errInvalidToken)
+	}
+
+	signingInput := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range j.config.KeySet.candidates(header.Kid, header.Alg) {
+		if algorithm.verifyWithCurve(key.Key, signingInput, signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("%w: signature verification failed", errInvalidToken)
+	}
+
+	var claims map[string]any
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", errInvalidToken)
+	}
+	if err := j.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (a jwtAlgorithm) verifyWithCurve(key any, signingInput, signature []byte) bool {
+	if a.curveSize > 0 {
+		publicKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || publicKey.Curve.Params().BitSize != a.curveSize {
+			return false
+		}
+	}
+	return a.verify(a.hash, key, signingInput, signature)
+}
+
+func (j jwtAuthenticator) validateClaims(claims map[string]any) error {
+	now := j.config.Now()
+	exp, found := claims["exp"]
+	if !found && (j.config.RequireExp == nil || *j.config.RequireExp) {
+		return fmt.Errorf("%w: the token has no expiration", errInvalidToken)
+	}
+	if found {
+		expiresAt, ok := exp.(float64)
+		if !ok || now.After(time.Unix(int64(expiresAt), 0).Add(j.config.Leeway)) {
+			return fmt.Errorf("%w: the token is expired", errInvalidToken)
+		}
+	}
+	if nbf, found := claims["nbf"]; found {
+		notBefore, ok := nbf.(float64)
+		if !ok || now.Add(j.config.Leeway).Before(time.Unix(int64(notBefore), 0)) {
+			return fmt.Errorf("%w: the token is not valid yet", errInvalidToken)
+		}
+	}
+	if j.config.Issuer != "" && claims["iss"] != j.config.Issuer {
+		return fmt.Errorf("%w: unexpected issuer", errInvalidToken)
+	}
+	if j.config.Audience != "" && !hasAudience(claims["aud"], j.config.Audience) {
+		return fmt.Errorf("%w: unexpected audience", errInvalidToken)
+	}
+	return nil
+}
+
+func hasAudience(aud any, audience string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == audience
+	case []any:
+		return slices.Contains(v, any(audience))
+	}
+	return false
+}
+
+func decodeJWTPart(part string, target any) error {
+	decoded, err := decodeBase64URL(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(decoded, target)
+}
+
+func digest(hash crypto.Hash, data []byte) []byte {
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func verifyHMAC(hash crypto.Hash, key any, signingInput, signature []byte) bool {
+	secret, ok := key.([]byte)
+	if !ok || len(secret) < hash.Size() {
+		return false // RFC 7518 requires a key at least as large as the hash output
+	}
+	mac := hmac.New(hash.New, secret)
+	mac.Write(signingInput)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+func verifyRSA(hash crypto.Hash, key any, signingInput, signature []byte) bool {
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return false
+	}
+	return rsa.VerifyPKCS1v15(publicKey, hash, digest(hash, signingInput), signature) == nil
+}
+
+// verifyECDSA verifies the JWS signature, that is the concatenation of the R and S values (RFC 7518, section 3.4)
+func verifyECDSA(hash crypto.Hash, key any, signingInput, signature []byte) bool {
+	publicKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return false
+	}
+	size := (publicKey.Curve.Params().BitSize + 7) / 8
+	if len(signature) != 2*size {
+		return false
+	}
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+	return ecdsa.Verify(publicKey, digest(hash, signingInput), r, s)
+}
@@ -0,0 +1,239 @@
+package httpadpt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testJWTNow = time.Unix(1_700_000_000, 0)
+
+// signTestJWT creates a compact JWS with the given header and claims
+func signTestJWT(t *testing.T, header map[string]any, claims map[string]any, key any) string {
+	t.Helper()
+	encode := func(v any) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signingInput := encode(header) + "." + encode(claims)
+	alg, _ := header["alg"].(string)
+	algorithm, found := jwtAlgorithms[alg]
+	if !found {
+		return signingInput + "."
+	}
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(algorithm.hash.New, k)
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, algorithm.hash, digest(algorithm.hash, []byte(signingInput)))
+		if err != nil {
+			t.Fatalf("SignPKCS1v15() error = %v", err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest(algorithm.hash, []byte(signingInput)))
+		if err != nil {
+			t.Fatalf("ecdsa.Sign() error = %v", err)
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newBearerRequest(token string) Request {
+	return &mockRequest{header: &mockHeaderParams{values: map[string][]string{HeaderAuthorization: {"Bearer " + token}}}}
+}
+
+func Test_jwtAuthenticator_Algorithms(t *testing.T) {
+	secret := []byte(strings.Repeat("0123456789abcdef", 4))
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ec384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	keySet := JWKS{Keys: []JWK{
+		{KeyID: "hmac", Key: secret},
+		{KeyID: "hmac256", Key: secret[:32]},
+		{KeyID: "rsa", Key: &rsaKey.PublicKey},
+		{KeyID: "ec", Key: &ecKey.PublicKey},
+		{KeyID: "ec384", Key: &ec384Key.PublicKey},
+	}}
+	authenticator := NewJWTAuthenticator(JWTConfig{KeySet: keySet, Now: func() time.Time { return testJWTNow }})
+	claims := map[string]any{"sub": "user-1", "exp": testJWTNow.Add(time.Minute).Unix()}
+
+	tests := []struct {
+		name      string
+		header    map[string]any
+		key       any
+		expectErr bool
+	}{
+		{name: "HS256", header: map[string]any{"alg": "HS256", "kid": "hmac"}, key: secret},
+		{name: "HS512 without kid", header: map[string]any{"alg": "HS512"}, key: secret},
+		{name: "HS512 with a key shorter than the hash", header: map[string]any{"alg": "HS512", "kid": "hmac256"}, key: secret[:32], expectErr: true},
+		{name: "RS256", header: map[string]any{"alg": "RS256", "kid": "rsa"}, key: rsaKey},
+		{name: "RS384", header: map[string]any{"alg": "RS384", "kid": "rsa"}, key: rsaKey},
+		{name: "ES256", header: map[string]any{"alg": "ES256", "kid": "ec"}, key: ecKey},
+		{name: "ES384", header: map[string]any{"alg": "ES384", "kid": "ec384"}, key: ec384Key},
+		{name: "ES384 with P-256 key", header: map[string]any{"alg": "ES384", "kid": "ec"}, key: ecKey, expectErr: true},
+		{name: "none", header: map[string]any{"alg": "none"}, expectErr: true},
+		{name: "wrong secret", header: map[string]any{"alg": "HS256"}, key: []byte("another secret key with 32 bytes"), expectErr: true},
+		{name: "unknown kid", header: map[string]any{"alg": "RS256", "kid": "other"}, key: rsaKey, expectErr: true},
+		{name: "HS256 signed with the RSA public key", header: map[string]any{"alg": "HS256", "kid": "rsa"}, key: rsaKey.PublicKey.N.Bytes(), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := signTestJWT(t, tt.header, claims, tt.key)
+			principal, err := authenticator.Authenticate(context.Background(), newBearerRequest(token))
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Authenticate() error = %v, expectErr = %v", err, tt.expectErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrUnauthorized) {
+					t.Errorf("Authenticate() error = %v, want ErrUnauthorized", err)
+				}
+				return
+			}
+			if principal.Subject != "user-1" || principal.Scheme != AuthSchemeBearer {
+				t.Errorf("Authenticate() principal = %+v", principal)
+			}
+		})
+	}
+}
+
+func Test_jwtAuthenticator_Claims(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	authenticator := NewJWTAuthenticator(JWTConfig{
+		KeySet:     JWKS{Keys: []JWK{{Key: secret}}},
+		Algorithms: []string{"HS256"},
+		Issuer:     "https://issuer",
+		Audience:   "api",
+		Leeway:     30 * time.Second,
+		Now:        func() time.Time { return testJWTNow },
+	})
+	valid := func() map[string]any {
+		return map[string]any{"sub": "user-1", "iss": "https://issuer", "aud": []string{"web", "api"}, "exp": testJWTNow.Unix()}
+	}
+
+	tests := []struct {
+		name      string
+		alg       string
+		change    func(claims map[string]any)
+		expectErr bool
+	}{
+		{name: "valid", change: func(map[string]any) {}},
+		{name: "expired within leeway", change: func(c map[string]any) { c["exp"] = testJWTNow.Add(-20 * time.Second).Unix() }},
+		{name: "expired", change: func(c map[string]any) { c["exp"] = testJWTNow.Add(-time.Minute).Unix() }, expectErr: true},
+		{name: "not valid yet", change: func(c map[string]any) { c["nbf"] = testJWTNow.Add(time.Minute).Unix() }, expectErr: true},
+		{name: "wrong issuer", change: func(c map[string]any) { c["iss"] = "https://other" }, expectErr: true},
+		{name: "audience as string", change: func(c map[string]any) { c["aud"] = "api" }},
+		{name: "wrong audience", change: func(c map[string]any) { c["aud"] = "web" }, expectErr: true},
+		{name: "algorithm not accepted", alg: "HS384", change: func(map[string]any) {}, expectErr: true},
+		{name: "no expiration", change: func(c map[string]any) { delete(c, "exp") }, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.change(claims)
+			alg := tt.alg
+			if alg == "" {
+				alg = "HS256"
+			}
+			token := signTestJWT(t, map[string]any{"alg": alg}, claims, secret)
+			_, err := authenticator.Authenticate(context.Background(), newBearerRequest(token))
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Authenticate() error = %v, expectErr = %v", err, tt.expectErr)
+			}
+		})
+	}
+
+	requireExp := false
+	withoutExp := NewJWTAuthenticator(JWTConfig{KeySet: JWKS{Keys: []JWK{{Key: secret}}}, RequireExp: &requireExp})
+	token := signTestJWT(t, map[string]any{"alg": "HS256"}, map[string]any{"sub": "user-1"}, secret)
+	if _, err := withoutExp.Authenticate(context.Background(), newBearerRequest(token)); err != nil {
+		t.Errorf("Authenticate() without exp and RequireExp=false error = %v", err)
+	}
+}
+
+func Test_jwtAuthenticator_NoCredentials(t *testing.T) {
+	authenticator := NewJWTAuthenticator(JWTConfig{Realm: "api"})
+	_, err := authenticator.Authenticate(context.Background(), &mockRequest{header: &mockHeaderParams{}})
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Authenticate() error = %v, want ErrNoCredentials", err)
+	}
+	if _, err := authenticator.Authenticate(context.Background(), newBearerRequest("a.b")); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Authenticate() malformed token error = %v, want ErrUnauthorized", err)
+	}
+	if authenticator.Challenge() != `Bearer realm="api"` {
+		t.Errorf("Challenge() = %q", authenticator.Challenge())
+	}
+}
+
+func TestLoadJWKSFile(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "alg": "RS256", "use": "sig", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "oct", "kid": "hmac", "k": %q},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"}
+	]}`, b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		b64(ecKey.X.Bytes()), b64(ecKey.Y.Bytes()), b64(secret))
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(jwks), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	keySet, err := LoadJWKSFile(path)
+	if err != nil {
+		t.Fatalf("LoadJWKSFile() error = %v", err)
+	}
+	if len(keySet.Keys) != 3 {
+		t.Fatalf("LoadJWKSFile() = %d keys, want 3", len(keySet.Keys))
+	}
+
+	authenticator := NewJWTAuthenticator(JWTConfig{KeySet: keySet})
+	for _, signer := range []struct {
+		header map[string]any
+		key    any
+	}{
+		{header: map[string]any{"alg": "RS256", "kid": "rsa"}, key: rsaKey},
+		{header: map[string]any{"alg": "ES256", "kid": "ec"}, key: ecKey},
+		{header: map[string]any{"alg": "HS256", "kid": "hmac"}, key: secret},
+	} {
+		token := signTestJWT(t, signer.header, map[string]any{"sub": "x", "exp": time.Now().Add(time.Minute).Unix()}, signer.key)
+		if _, err := authenticator.Authenticate(context.Background(), newBearerRequest(token)); err != nil {
+			t.Errorf("Authenticate(%v) error = %v", signer.header, err)
+		}
+	}
+
+	if _, err := ParseJWKS([]byte(`{"keys": [{"kty": "EC", "crv": "P-999"}]}`)); err == nil {
+		t.Error("ParseJWKS() with unsupported curve error = nil, want error")
+	}
+	for _, k := range []string{"", b64([]byte("short secret"))} {
+		if _, err := ParseJWKS([]byte(fmt.Sprintf(`{"keys": [{"kty": "oct", "k": %q}]}`, k))); err == nil {
+			t.Errorf("ParseJWKS() with oct key=[%s] error = nil, want error", k)
+		}
+	}
+	if _, err := LoadJWKSFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadJWKSFile() with missing file error = nil, want error")
+	}
+}
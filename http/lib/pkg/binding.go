@@ -14,6 +14,9 @@ type (
 	Binding struct {
 		Condition
 		Handler
+
+		// Middlewares wrap only this binding handler, inside the Config.Middlewares and Config.MiddlewareGroups
+		Middlewares Middlewares
 	}
 
 	// Bindings represents the bindings the HTTP handler should handle, the binding order in the list
@@ -2,6 +2,7 @@ package httpadpt
 
 import (
 	tagbasedhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler/tagbased"
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
 )
 
 type (
@@ -11,6 +12,8 @@ type (
 	}
 
 	HandlerBuildingStep interface {
+		// WithMiddlewares adds middlewares that wrap only this binding handler, the first one is the outermost
+		WithMiddlewares(middlewares ...Middleware) HandlerBuildingStep
 		WithHandlerFunc(handler any) Binding
 	}
 
@@ -25,11 +28,16 @@ type (
 
 	BaseBuilder struct {
 		Binding
+
+		// outErrorSpec replaces the default OutErrorParamSpec when it is not nil
+		outErrorSpec sdkparam.OutputParamSpec[*Response]
+		// onBuild is invoked with the binding created by WithHandlerFunc, it is used by Group to collect it
+		onBuild func(Binding)
 	}
 )
 
 func NewBindingBuilderUsingOtherCondition(other any) StandardConditionBuildingStep {
-	return &BaseBuilder{Binding{Condition: Condition{Other: other}}}
+	return &BaseBuilder{Binding: Binding{Condition: Condition{Other: other}}}
 }
 
 func NewBindingBuilderUsingPath(path string) MethodsConditionBuildingStep {
@@ -54,11 +62,23 @@ func (b *BaseBuilder) WithMethods(method string, methods ...string) HandlerBuild
 	return b
 }
 
+func (b *BaseBuilder) WithMiddlewares(middlewares ...Middleware) HandlerBuildingStep {
+	b.Binding.Middlewares = append(b.Binding.Middlewares, middlewares...)
+	return b
+}
+
 func (b *BaseBuilder) WithHandlerFunc(handler any) Binding {
+	outErrorSpec := b.outErrorSpec
+	if outErrorSpec == nil {
+		outErrorSpec = NewOutErrorParamSpec()
+	}
 	b.Handler = tagbasedhandler.NewBuilderForFunc[Request, *Response](handler).
 		WithInTagBasedFactory(createInParamSpecFactory()).
 		WithOutTagBasedFactory(createOutParamSpecFactory()).
-		WithOutErrorParamSpec(NewOutErrorParamSpec()).
+		WithOutErrorParamSpec(outErrorSpec).
 		Build()
+	if b.onBuild != nil {
+		b.onBuild(b.Binding)
+	}
 	return b.Binding
 }
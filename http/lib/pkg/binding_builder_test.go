@@ -1,6 +1,7 @@
 package httpadpt
 
 import (
+	"context"
 	"testing"
 )
 
@@ -201,3 +202,22 @@ func TestBaseBuilder_Chaining(t *testing.T) {
 		t.Fatalf("Expected 2 methods, got %d", len(baseBuilder.Condition.Methods))
 	}
 }
+
+func TestBaseBuilder_WithMiddlewares(t *testing.T) {
+	var calls []string
+	binding := NewBindingBuilderUsingPath("/api/users").
+		WithMethods("GET").
+		WithMiddlewares(newRecordingMiddleware("first", &calls)).
+		WithMiddlewares(newRecordingMiddleware("second", &calls)).
+		WithHandlerFunc(func() error { return nil })
+
+	if len(binding.Middlewares) != 2 {
+		t.Fatalf("Expected 2 middlewares, got %d", len(binding.Middlewares))
+	}
+	if err := WrapBindingHandler(binding, Config{}).Invoke(context.Background(), &mockRequest{}, &Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Errorf("Expected calls [first second], got %v", calls)
+	}
+}
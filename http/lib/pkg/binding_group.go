@@ -0,0 +1,130 @@
+package httpadpt
+
+import (
+	"strings"
+
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+)
+
+type (
+	// Group builds Bindings that share a path prefix, middlewares, the output error spec and a VersionCondition.
+	// A group can be mounted under other groups, even several times, like the same resources under /v1 and /v2.
+	Group struct {
+		prefix       string
+		parent       *Group
+		middlewares  Middlewares
+		outErrorSpec sdkparam.OutputParamSpec[*Response]
+		version      VersionCondition
+		bindings     Bindings
+		mounts       []groupMount
+	}
+
+	groupMount struct {
+		prefix string
+		group  *Group
+	}
+)
+
+// NewGroup creates a group whose bindings have the path prefix, like "/api"
+func NewGroup(prefix string) *Group {
+	return &Group{prefix: prefix}
+}
+
+// Use adds middlewares to the bindings of the group and of its subgroups, the first one is the outermost. They wrap
+// the handler inside the middlewares of the parent groups and outside the Binding.Middlewares.
+func (g *Group) Use(middlewares ...Middleware) *Group {
+	g.middlewares = append(g.middlewares, middlewares...)
+	return g
+}
+
+// WithOutErrorParamSpec sets the spec used to write the errors returned by the handlers created by Route, in this
+// group and in the subgroups created by Group. Mounted groups keep their own spec.
+func (g *Group) WithOutErrorParamSpec(spec sdkparam.OutputParamSpec[*Response]) *Group {
+	g.outErrorSpec = spec
+	return g
+}
+
+// WithVersion sets the Condition.Other of the bindings of the group, and of the groups under it, that have no
+// condition yet. See MergeVersionedBindings.
+func (g *Group) WithVersion(version VersionCondition) *Group {
+	g.version = version
+	return g
+}
+
+// Group creates a subgroup under the path prefix that inherits the output error spec
+func (g *Group) Group(prefix string) *Group {
+	child := &Group{prefix: prefix, parent: g}
+	g.mounts = append(g.mounts, groupMount{group: child})
+	return child
+}
+
+// Mount adds the bindings of the group under the path prefix, the same group can be mounted several times
+func (g *Group) Mount(prefix string, group *Group) *Group {
+	g.mounts = append(g.mounts, groupMount{prefix: prefix, group: group})
+	return g
+}
+
+// Add adds bindings to the group, their paths are relative to the group prefix
+func (g *Group) Add(bindings ...Binding) *Group {
+	g.bindings = append(g.bindings, bindings...)
+	return g
+}
+
+// Route starts building a binding for the path relative to the group prefix, the binding is added to the group by
+// WithHandlerFunc.
+func (g *Group) Route(path string) MethodsConditionBuildingStep {
+	builder := &BaseBuilder{outErrorSpec: g.getOutErrorParamSpec(), onBuild: func(binding Binding) { g.Add(binding) }}
+	builder.WithPath(path)
+	return builder
+}
+
+// Bindings returns the bindings of the group and the mounted groups with the full paths and middlewares. Versioned
+// bindings are merged and the duplicated method and path pairs are reported as errors, see MergeVersionedBindings.
+func (g *Group) Bindings() (Bindings, error) {
+	return MergeVersionedBindings(g.collect("", nil, nil))
+}
+
+func (g *Group) getOutErrorParamSpec() sdkparam.OutputParamSpec[*Response] {
+	for group := g; group != nil; group = group.parent {
+		if group.outErrorSpec != nil {
+			return group.outErrorSpec
+		}
+	}
+	return nil
+}
+
+func (g *Group) collect(prefix string, middlewares Middlewares, version VersionCondition) Bindings {
+	prefix = joinPaths(prefix, g.prefix)
+	middlewares = append(append(Middlewares{}, middlewares...), g.middlewares...)
+	if g.version != nil {
+		version = g.version
+	}
+
+	var result Bindings
+	for _, binding := range g.bindings {
+		if binding.Condition.Path != nil {
+			path := joinPaths(prefix, *binding.Condition.Path)
+			binding.Condition.Path = &path
+		}
+		if binding.Condition.Other == nil && version != nil {
+			binding.Condition.Other = version
+		}
+		binding.Middlewares = append(append(Middlewares{}, middlewares...), binding.Middlewares...)
+		result = append(result, binding)
+	}
+	for _, mount := range g.mounts {
+		result = append(result, mount.group.collect(joinPaths(prefix, mount.prefix), middlewares, version)...)
+	}
+	return result
+}
+
+// joinPaths concatenates the path prefix and the path, making sure there is one "/" between them
+func joinPaths(prefix, path string) string {
+	if path == "" {
+		return prefix
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return strings.TrimSuffix(prefix, "/") + path
+}
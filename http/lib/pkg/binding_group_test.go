@@ -0,0 +1,103 @@
+package httpadpt
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type recordingErrorSpec struct {
+	OutErrorParamSpec
+	errors *[]error
+}
+
+func (r recordingErrorSpec) SetValue(output *Response, value any) error {
+	if err, ok := value.(error); ok && err != nil {
+		*r.errors = append(*r.errors, err)
+	}
+	return r.OutErrorParamSpec.SetValue(output, value)
+}
+
+func bindingPaths(bindings Bindings) []string {
+	var paths []string
+	for _, binding := range bindings {
+		for _, method := range binding.Condition.Methods {
+			paths = append(paths, method+" "+*binding.Condition.Path)
+		}
+	}
+	return paths
+}
+
+func TestGroup_Bindings(t *testing.T) {
+	var calls []string
+	orders := NewGroup("/orders").Use(newRecordingMiddleware("orders", &calls))
+	orders.Route("/{id}").WithMethods("GET").WithHandlerFunc(func() error {
+		calls = append(calls, "handler")
+		return nil
+	})
+	orders.Route("").WithMethods("POST").WithHandlerFunc(func() error { return nil })
+
+	api := NewGroup("/api/").Use(newRecordingMiddleware("api", &calls))
+	api.Mount("/v1", orders)
+	api.Group("/v2").Use(newRecordingMiddleware("v2", &calls)).Mount("", orders)
+
+	bindings, err := api.Bindings()
+	if err != nil {
+		t.Fatalf("Bindings() error = %v", err)
+	}
+	expected := []string{"GET /api/v1/orders/{id}", "POST /api/v1/orders", "GET /api/v2/orders/{id}", "POST /api/v2/orders"}
+	if got := bindingPaths(bindings); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Bindings() paths = %v, want %v", got, expected)
+	}
+
+	if err := WrapBindingHandler(bindings[2], Config{}).Invoke(context.Background(), &mockRequest{}, &Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if expected := []string{"api", "v2", "orders", "handler"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v, want %v", calls, expected)
+	}
+}
+
+func TestGroup_WithOutErrorParamSpec(t *testing.T) {
+	var errs []error
+	api := NewGroup("/api").WithOutErrorParamSpec(recordingErrorSpec{errors: &errs})
+	binding := api.Group("/v1").Route("/fail").WithMethods("GET").WithHandlerFunc(func() error {
+		return serror.NotFoundError.New("not found")
+	})
+
+	output := &Response{}
+	if err := binding.Handler.Invoke(context.Background(), &mockRequest{}, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if len(errs) != 1 || output.StatusCode == nil || *output.StatusCode != 404 {
+		t.Errorf("errors = %v, status = %v, want the group error spec to handle the error", errs, output.StatusCode)
+	}
+}
+
+func TestGroup_Bindings_Duplicated(t *testing.T) {
+	handler := func() error { return nil }
+	api := NewGroup("/api")
+	api.Route("/orders/{id}").WithMethods("GET", "PUT").WithHandlerFunc(handler)
+	api.Group("/orders").Route("/{orderID}").WithMethods("GET").WithHandlerFunc(handler)
+
+	if _, err := api.Bindings(); !serror.IsDuplicateError(err) {
+		t.Errorf("Bindings() error = %v, want DuplicateError", err)
+	}
+}
+
+func Test_joinPaths(t *testing.T) {
+	tests := []struct{ prefix, path, expected string }{
+		{prefix: "", path: "/orders", expected: "/orders"},
+		{prefix: "/api/", path: "/orders", expected: "/api/orders"},
+		{prefix: "/api", path: "orders", expected: "/api/orders"},
+		{prefix: "/api", path: "", expected: "/api"},
+		{prefix: "/api", path: "/", expected: "/api/"},
+	}
+	for _, tt := range tests {
+		if got := joinPaths(tt.prefix, tt.path); got != tt.expected {
+			t.Errorf("joinPaths(%q, %q) = %q, want %q", tt.prefix, tt.path, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package httpadpt
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	HeaderAllow = "Allow"
+)
+
+// OptionsBindings returns an OPTIONS binding for every path of the given bindings that does not handle OPTIONS
+// already. The implementations add them to the registered bindings when Config.CORS is set, so the preflight requests
+// reach the middleware created by NewCORSMiddleware. The OPTIONS handler answers 204 with the Allow header, and the
+// OPTIONS binding has the Binding.Middlewares of the first binding of its path.
+func OptionsBindings(bindings Bindings) Bindings {
+	var (
+		paths       []string
+		methods     = map[string][]string{}
+		middlewares = map[string]Middlewares{}
+	)
+	for _, binding := range bindings {
+		if binding.Condition.Path == nil || len(binding.Condition.Methods) == 0 {
+			continue
+		}
+		path := *binding.Condition.Path
+		if _, found := methods[path]; !found {
+			paths = append(paths, path)
+			middlewares[path] = binding.Middlewares
+		}
+		for _, method := range binding.Condition.Methods {
+			if !slices.Contains(methods[path], method) {
+				methods[path] = append(methods[path], method)
+			}
+		}
+	}
+
+	var optionsBindings Bindings
+	for _, path := range paths {
+		if slices.Contains(methods[path], http.MethodOptions) {
+			continue
+		}
+		optionsBindings = append(optionsBindings, Binding{
+			Condition:   Condition{Path: &path, Methods: []string{http.MethodOptions}},
+			Handler:     newOptionsHandler(append(methods[path], http.MethodOptions)),
+			Middlewares: middlewares[path],
+		})
+	}
+	return optionsBindings
+}
+
+func newOptionsHandler(methods []string) Handler {
+	allow := strings.Join(methods, ", ")
+	return MakeHandler(func(_ context.Context, _ Request, output *Response) error {
+		statusCode := http.StatusNoContent
+		output.StatusCode = &statusCode
+		setHeaderValue(output, HeaderAllow, allow)
+		return nil
+	})
+}
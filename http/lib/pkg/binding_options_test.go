@@ -0,0 +1,58 @@
+package httpadpt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOptionsBindings(t *testing.T) {
+	bindings := Bindings{
+		{Condition: Condition{Path: stringPtr("/users"), Methods: []string{"GET"}}, Handler: MakeHandler(nil)},
+		{Condition: Condition{Path: stringPtr("/users"), Methods: []string{"POST", "GET"}}, Handler: MakeHandler(nil)},
+		{Condition: Condition{Path: stringPtr("/custom"), Methods: []string{"GET", "OPTIONS"}}, Handler: MakeHandler(nil)},
+		{Condition: Condition{Path: stringPtr("/orders"), Methods: []string{"DELETE"}}, Handler: MakeHandler(nil)},
+		{Condition: Condition{Methods: []string{"GET"}}, Handler: MakeHandler(nil)},
+	}
+
+	optionsBindings := OptionsBindings(bindings)
+	if len(optionsBindings) != 2 {
+		t.Fatalf("OptionsBindings() = %d bindings, want 2", len(optionsBindings))
+	}
+
+	expected := map[string]string{"/users": "GET, POST, OPTIONS", "/orders": "DELETE, OPTIONS"}
+	for _, binding := range optionsBindings {
+		if len(binding.Methods) != 1 || binding.Methods[0] != "OPTIONS" {
+			t.Errorf("Methods = %v, want [OPTIONS]", binding.Methods)
+		}
+		output := &Response{}
+		if err := binding.Handler.Invoke(context.Background(), &mockRequest{method: "OPTIONS"}, output); err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+		if *output.StatusCode != 204 || output.Header[HeaderAllow][0] != expected[*binding.Path] {
+			t.Errorf("%s: status = %d, Allow = %v, want 204 %q", *binding.Path, *output.StatusCode, output.Header[HeaderAllow], expected[*binding.Path])
+		}
+	}
+}
+
+func TestOptionsBindings_Middlewares(t *testing.T) {
+	var calls []string
+	bindings := Bindings{
+		{
+			Condition:   Condition{Path: stringPtr("/users"), Methods: []string{"GET"}},
+			Handler:     MakeHandler(nil),
+			Middlewares: Middlewares{newRecordingMiddleware("users", &calls)},
+		},
+		{Condition: Condition{Path: stringPtr("/users"), Methods: []string{"POST"}}, Handler: MakeHandler(nil)},
+	}
+
+	optionsBindings := OptionsBindings(bindings)
+	if len(optionsBindings) != 1 || len(optionsBindings[0].Middlewares) != 1 {
+		t.Fatalf("OptionsBindings() = %+v, want one binding with the /users middlewares", optionsBindings)
+	}
+	if err := WrapBindingHandler(optionsBindings[0], Config{}).Invoke(context.Background(), &mockRequest{method: "OPTIONS"}, &Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if len(calls) != 1 || calls[0] != "users" {
+		t.Errorf("calls = %v, want [users]", calls)
+	}
+}
@@ -0,0 +1,182 @@
+package httpadpt
+
+import (
+	"context"
+	"mime"
+	"regexp"
+	"strings"
+
+	"github.com/smart-libs/go-crosscutting/assertions/lib/pkg/check"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	HeaderAccept = "Accept"
+)
+
+type (
+	// VersionCondition can be set in Condition.Other to have several bindings with the same method and path, each one
+	// handling a version of the API. MergeVersionedBindings replaces them by a single binding that invokes the handler
+	// whose condition matches the request.
+	VersionCondition interface {
+		MatchesVersion(input Request) bool
+	}
+
+	// HeaderVersion matches the requests where the Header value is Version, like "Api-Version: 2"
+	HeaderVersion struct {
+		Header  string
+		Version string
+	}
+
+	// MediaTypeVersion matches the requests that accept the MediaType, like "Accept: application/vnd.acme.v2+json"
+	MediaTypeVersion struct {
+		MediaType string
+	}
+
+	versionedHandler struct {
+		conditions []VersionCondition
+		handlers   []Handler
+		// fallback is the handler of the binding without VersionCondition, it can be nil
+		fallback Handler
+	}
+)
+
+var pathParamRegex = regexp.MustCompile(`\{[^}]*}`)
+
+func (h HeaderVersion) MatchesVersion(input Request) bool {
+	values := getHeaderValues(input, h.Header)
+	for _, value := range values {
+		if strings.TrimSpace(value) == h.Version {
+			return true
+		}
+	}
+	return false
+}
+
+func (h HeaderVersion) String() string { return h.Header + ": " + h.Version }
+
+func (m MediaTypeVersion) MatchesVersion(input Request) bool {
+	values := getHeaderValues(input, HeaderAccept)
+	for _, value := range values {
+		for _, accepted := range strings.Split(value, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+			if err == nil && strings.EqualFold(mediaType, m.MediaType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m MediaTypeVersion) String() string { return HeaderAccept + ": " + m.MediaType }
+
+func getHeaderValues(input Request, headerName string) []string {
+	if check.IsNil(input) || check.IsNil(input.Header()) {
+		return nil
+	}
+	values, _ := input.Header().GetValue(headerName)
+	return values
+}
+
+func (v versionedHandler) Invoke(ctx context.Context, input Request, output *Response) error {
+	for i, condition := range v.conditions {
+		if condition.MatchesVersion(input) {
+			return v.handlers[i].Invoke(ctx, input, output)
+		}
+	}
+	if v.fallback != nil {
+		return v.fallback.Invoke(ctx, input, output)
+	}
+	err := serror.NotFoundError.New("httpadpt.versionedHandler: no version of the resource matches the request")
+	if output == nil {
+		return err
+	}
+	return NewOutErrorParamSpec().SetValue(output, err)
+}
+
+// bindingKey identifies the method and path of a binding the way the HTTP routers compare them, so the name of the
+// path parameters is not relevant, but a wildcard like {path...} and the end anchor {$} are kept apart from {id}.
+func bindingKey(method, path string) string {
+	return method + " " + pathParamRegex.ReplaceAllStringFunc(path, func(param string) string {
+		switch {
+		case param == "{$}":
+			return param
+		case strings.HasSuffix(param, "...}"):
+			return "{...}"
+		}
+		return "{}"
+	})
+}
+
+// MergeVersionedBindings returns the bindings where the ones with the same method and path, and a VersionCondition
+// in Condition.Other, are replaced by a single binding that selects the version by the request. One of them can have
+// no Condition.Other to handle the requests that match no version. A binding with a VersionCondition is replaced even
+// when it is alone, so the requests that do not match its version get 404. Each version handler is wrapped with its
+// Binding.Middlewares before the merge. It returns a DuplicateError if the same method and path are used by bindings
+// that cannot be merged, instead of letting the HTTP router fail when the handlers are registered.
+func MergeVersionedBindings(bindings Bindings) (Bindings, error) {
+	counts := map[string]int{}
+	for _, binding := range bindings {
+		if binding.Condition.Path == nil {
+			continue
+		}
+		for _, method := range binding.Condition.Methods {
+			counts[bindingKey(method, *binding.Condition.Path)]++
+		}
+	}
+
+	var (
+		result  Bindings
+		merged  = map[string]int{}
+		handler = func(binding Binding) Handler {
+			return WrapHandlerWithMiddlewares(binding.Handler, binding.Middlewares)
+		}
+	)
+	for _, binding := range bindings {
+		_, isVersion := binding.Condition.Other.(VersionCondition)
+		if binding.Condition.Path == nil || (!isVersion && !hasRepeatedKey(binding, counts)) {
+			result = append(result, binding)
+			continue
+		}
+		for _, method := range binding.Condition.Methods {
+			key := bindingKey(method, *binding.Condition.Path)
+			single := binding
+			single.Condition.Methods = []string{method}
+			if counts[key] == 1 && !isVersion {
+				result = append(result, single)
+				continue
+			}
+
+			index, found := merged[key]
+			if !found {
+				index = len(result)
+				merged[key] = index
+				result = append(result, Binding{
+					Condition: Condition{Path: binding.Condition.Path, Methods: []string{method}},
+					Handler:   versionedHandler{},
+				})
+			}
+			versioned := result[index].Handler.(versionedHandler)
+			if condition, ok := binding.Condition.Other.(VersionCondition); ok {
+				versioned.conditions = append(versioned.conditions, condition)
+				versioned.handlers = append(versioned.handlers, handler(binding))
+			} else if binding.Condition.Other == nil && versioned.fallback == nil {
+				versioned.fallback = handler(binding)
+			} else {
+				return nil, serror.DuplicateError.New(
+					"httpadpt.MergeVersionedBindings: duplicated binding %s %s", method, *binding.Condition.Path)
+			}
+			result[index].Handler = versioned
+		}
+	}
+	return result, nil
+}
+
+func hasRepeatedKey(binding Binding, counts map[string]int) bool {
+	for _, method := range binding.Condition.Methods {
+		if counts[bindingKey(method, *binding.Condition.Path)] > 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package httpadpt
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func newVersionTestBinding(path string, version any, body string, methods ...string) Binding {
+	return Binding{
+		Condition: Condition{Path: &path, Methods: methods, Other: version},
+		Handler: MakeHandler(func(_ context.Context, _ Request, output *Response) error {
+			output.Body = []byte(body)
+			return nil
+		}),
+	}
+}
+
+func TestHeaderVersion_MatchesVersion(t *testing.T) {
+	version := HeaderVersion{Header: "Api-Version", Version: "2"}
+	if !version.MatchesVersion(&mockRequest{header: &mockHeaderParams{values: map[string][]string{"Api-Version": {" 2"}}}}) {
+		t.Error("MatchesVersion() = false, want true")
+	}
+	if version.MatchesVersion(&mockRequest{header: &mockHeaderParams{values: map[string][]string{"Api-Version": {"1"}}}}) {
+		t.Error("MatchesVersion() = true, want false")
+	}
+	if version.MatchesVersion(&mockRequest{}) {
+		t.Error("MatchesVersion() without headers = true, want false")
+	}
+}
+
+func TestMediaTypeVersion_MatchesVersion(t *testing.T) {
+	version := MediaTypeVersion{MediaType: "application/vnd.acme.v2+json"}
+	accept := func(value string) Request {
+		return &mockRequest{header: &mockHeaderParams{values: map[string][]string{HeaderAccept: {value}}}}
+	}
+	if !version.MatchesVersion(accept("text/html, application/vnd.acme.v2+JSON; q=0.9")) {
+		t.Error("MatchesVersion() = false, want true")
+	}
+	if version.MatchesVersion(accept("application/vnd.acme.v1+json")) {
+		t.Error("MatchesVersion() = true, want false")
+	}
+}
+
+func TestMergeVersionedBindings(t *testing.T) {
+	v2 := HeaderVersion{Header: "Api-Version", Version: "2"}
+	bindings, err := MergeVersionedBindings(Bindings{
+		newVersionTestBinding("/orders/{id}", nil, "v1", http.MethodGet, http.MethodDelete),
+		newVersionTestBinding("/orders", nil, "list", http.MethodGet),
+		newVersionTestBinding("/orders/{orderID}", v2, "v2", http.MethodGet),
+	})
+	if err != nil {
+		t.Fatalf("MergeVersionedBindings() error = %v", err)
+	}
+	expected := []string{"GET /orders/{id}", "DELETE /orders/{id}", "GET /orders"}
+	if got := bindingPaths(bindings); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("MergeVersionedBindings() = %v, want %v", got, expected)
+	}
+
+	for version, body := range map[string]string{"2": "v2", "1": "v1", "": "v1"} {
+		input := &mockRequest{header: &mockHeaderParams{values: map[string][]string{"Api-Version": {version}}}}
+		output := &Response{}
+		if err := bindings[0].Handler.Invoke(context.Background(), input, output); err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+		if string(output.Body) != body {
+			t.Errorf("Api-Version %q body = %q, want %q", version, output.Body, body)
+		}
+	}
+}
+
+func TestMergeVersionedBindings_NoMatchingVersion(t *testing.T) {
+	bindings, err := MergeVersionedBindings(Bindings{
+		newVersionTestBinding("/orders", HeaderVersion{Header: "Api-Version", Version: "1"}, "v1", http.MethodGet),
+		newVersionTestBinding("/orders", HeaderVersion{Header: "Api-Version", Version: "2"}, "v2", http.MethodGet),
+	})
+	if err != nil {
+		t.Fatalf("MergeVersionedBindings() error = %v", err)
+	}
+	output := &Response{}
+	if err := bindings[0].Handler.Invoke(context.Background(), &mockRequest{header: &mockHeaderParams{}}, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if output.StatusCode == nil || *output.StatusCode != http.StatusNotFound {
+		t.Errorf("StatusCode = %v, want 404", output.StatusCode)
+	}
+}
+
+func TestMergeVersionedBindings_SingleVersion(t *testing.T) {
+	bindings, err := MergeVersionedBindings(Bindings{
+		newVersionTestBinding("/orders", HeaderVersion{Header: "Api-Version", Version: "2"}, "v2", http.MethodGet),
+	})
+	if err != nil {
+		t.Fatalf("MergeVersionedBindings() error = %v", err)
+	}
+	for version, notFound := range map[string]bool{"2": false, "1": true} {
+		input := &mockRequest{header: &mockHeaderParams{values: map[string][]string{"Api-Version": {version}}}}
+		output := &Response{}
+		if err := bindings[0].Handler.Invoke(context.Background(), input, output); err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+		if got := output.StatusCode != nil && *output.StatusCode == http.StatusNotFound; got != notFound {
+			t.Errorf("Api-Version %q StatusCode = %v, want 404 = %v", version, output.StatusCode, notFound)
+		}
+	}
+}
+
+func Test_bindingKey(t *testing.T) {
+	tests := []struct {
+		path1, path2 string
+		same         bool
+	}{
+		{path1: "/orders/{id}", path2: "/orders/{orderID}", same: true},
+		{path1: "/files/{path...}", path2: "/files/{name...}", same: true},
+		{path1: "/files/{path...}", path2: "/files/{path}", same: false},
+		{path1: "/files/{$}", path2: "/files/{id}", same: false},
+	}
+	for _, tt := range tests {
+		if got := bindingKey(http.MethodGet, tt.path1) == bindingKey(http.MethodGet, tt.path2); got != tt.same {
+			t.Errorf("bindingKey(%s) == bindingKey(%s) = %v, want %v", tt.path1, tt.path2, got, tt.same)
+		}
+	}
+}
+
+func TestMergeVersionedBindings_Duplicated(t *testing.T) {
+	tests := map[string]Bindings{
+		"without versions": {
+			newVersionTestBinding("/orders/{id}", nil, "a", http.MethodGet),
+			newVersionTestBinding("/orders/{orderID}", nil, "b", http.MethodGet),
+		},
+		"with another condition": {
+			newVersionTestBinding("/orders", HeaderVersion{Header: "Api-Version", Version: "1"}, "a", http.MethodGet),
+			newVersionTestBinding("/orders", "other", "b", http.MethodGet),
+		},
+	}
+	for name, bindings := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := MergeVersionedBindings(bindings); err == nil {
+				t.Error("MergeVersionedBindings() error = nil, want DuplicateError")
+			}
+		})
+	}
+}
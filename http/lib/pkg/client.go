@@ -0,0 +1,658 @@
+package httpadpt
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	// TagEndpoint declares the method and path of a client func field, for instance `endpoint:"GET /users/{id}"`
+	TagEndpoint = "endpoint"
+
+	ContentTypeJSON = "application/json"
+
+	// DefaultClientTimeout is the ClientConfig.Timeout used when it is zero
+	DefaultClientTimeout = 30 * time.Second
+	// DefaultClientMaxResponseSize is the ClientConfig.MaxResponseSize used when it is zero
+	DefaultClientMaxResponseSize = 10 << 20
+	// DefaultClientBackoff is the ClientRetry.Backoff used when it is zero
+	DefaultClientBackoff = 100 * time.Millisecond
+	// DefaultClientMaxBackoff is the ClientRetry.MaxBackoff used when it is zero
+	DefaultClientMaxBackoff = 5 * time.Second
+)
+
+type (
+	// ClientConfig configures the functions created by MakeClient, MakeClientFunc and NewClientFunc
+	ClientConfig struct {
+		// BaseURL is prepended to the endpoint paths, for instance "https://api.example.com/v1"
+		BaseURL string
+		// HTTPClient sends the requests, http.DefaultClient is used if nil
+		HTTPClient *http.Client
+		// Header is sent with every request, the headers of the request struct replace it
+		Header http.Header
+		// Timeout limits each attempt, including the response body read. DefaultClientTimeout is used if zero, a
+		// negative value disables it.
+		Timeout time.Duration
+		// Retry configures the retries, the zero value sends each request only once
+		Retry ClientRetry
+		// MaxResponseSize limits the response body, DefaultClientMaxResponseSize is used if zero
+		MaxResponseSize int64
+	}
+
+	// ClientRetry configures how the failed attempts are retried
+	ClientRetry struct {
+		// MaxAttempts is the number of attempts including the first one
+		MaxAttempts int
+		// Backoff is the delay before the first retry, it doubles for each retry up to MaxBackoff.
+		// DefaultClientBackoff is used if zero.
+		Backoff time.Duration
+		// MaxBackoff limits the delay between attempts, including the one required by a Retry-After header.
+		// DefaultClientMaxBackoff is used if zero.
+		MaxBackoff time.Duration
+		// Retryable decides if the attempt can be retried, the response body was already read. DefaultClientRetryable
+		// is used if nil.
+		Retryable func(request *http.Request, response *http.Response, err error) bool
+	}
+
+	// ClientError is the error returned when the server replies with a status code 4xx or 5xx. The client returns it
+	// wrapped in the serror type matching the status code, use AsClientError to get it.
+	ClientError struct {
+		Method string
+		// URL is the request URL, Error omits its query because it can have secrets like API keys
+		URL        string
+		StatusCode int
+		Header     http.Header
+		Body       []byte
+		// Problem is the ProblemDetail sent by the server, if any
+		Problem *ProblemDetail
+		cause   error
+	}
+
+	clientEndpoint struct {
+		config       ClientConfig
+		method       string
+		path         string
+		pathParams   []string
+		requestSpec  *clientStructSpec[sdkparam.OutputParamSpec[*clientRequest]]
+		responseSpec *clientStructSpec[sdkparam.InputParamSpec[*clientResponse]]
+	}
+
+	// clientStructSpec maps the fields of the request or response struct. A struct without tagged fields is sent or
+	// received as a JSON body.
+	clientStructSpec[S any] struct {
+		structType reflect.Type
+		isPointer  bool
+		fields     []clientFieldSpec[S]
+		jsonBody   bool
+		mimeType   string
+	}
+
+	clientFieldSpec[S any] struct {
+		index []int
+		spec  S
+	}
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+
+	clientRequestTags  = []string{TagPath, TagQuery, TagRequestHeader, TagBody}
+	clientResponseTags = []string{TagStatusCode, TagResponseHeader, TagBody}
+)
+
+// NewClientFunc returns a function that sends the request described by endpoint, "METHOD /path/{param}". Req is the
+// request struct and Resp is the response struct, see MakeClientFunc.
+func NewClientFunc[Req any, Resp any](config ClientConfig, endpoint string) (func(context.Context, Req) (Resp, error), error) {
+	var clientFunc func(context.Context, Req) (Resp, error)
+	if err := MakeClientFunc(config, endpoint, &clientFunc); err != nil {
+		return nil, err
+	}
+	return clientFunc, nil
+}
+
+// MakeClientFunc sets the function pointed by funcPtr with one that sends the request described by endpoint,
+// "METHOD /path/{param}". The function type must be func(context.Context[, Req]) ([Resp, ]error) where:
+//   - Req is a struct, or a pointer to it, whose fields are tagged with path, query, header and body. The path tag
+//     values must match the endpoint path params.
+//   - Resp is a struct, or a pointer to it, whose fields are tagged with statuscode, header and body.
+//
+// The body fields use the mime-type option, for instance `body:"" mime-type:"application/json"`. A Req or Resp
+// without tagged fields is sent or received as a JSON body. The status codes 4xx and 5xx are returned as errors,
+// see ClientError.
+func MakeClientFunc(config ClientConfig, endpoint string, funcPtr any) error {
+	const fName = "httpadpt.MakeClientFunc"
+	funcValue := reflect.ValueOf(funcPtr)
+	if funcValue.Kind() != reflect.Pointer || funcValue.IsNil() || funcValue.Elem().Kind() != reflect.Func {
+		return fmt.Errorf("%s: %w", fName, serror.IllegalArgumentValue("funcPtr", funcPtr))
+	}
+	clientFunc, err := newClientFunc(config, endpoint, funcValue.Elem().Type())
+	if err != nil {
+		return fmt.Errorf("%s: %w", fName, err)
+	}
+	funcValue.Elem().Set(clientFunc)
+	return nil
+}
+
+// MakeClient sets the func fields of the struct pointed by clientPtr that are tagged with endpoint, for instance:
+//
+//	type UsersClient struct {
+//		Get    func(context.Context, GetUserRequest) (*GetUserResponse, error) `endpoint:"GET /users/{id}"`
+//		Delete func(context.Context, DeleteUserRequest) error                   `endpoint:"DELETE /users/{id}"`
+//	}
+//
+// Go does not allow to implement an interface using reflection, so the struct of functions plays its role. See
+// MakeClientFunc for the supported function types.
+func MakeClient(config ClientConfig, clientPtr any) error {
+	const fName = "httpadpt.MakeClient"
+	clientValue := reflect.ValueOf(clientPtr)
+	if clientValue.Kind() != reflect.Pointer || clientValue.IsNil() || clientValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%s: %w", fName, serror.IllegalArgumentValue("clientPtr", clientPtr))
+	}
+	clientStruct := clientValue.Elem()
+	for i := 0; i < clientStruct.NumField(); i++ {
+		field := clientStruct.Type().Field(i)
+		endpoint, found := field.Tag.Lookup(TagEndpoint)
+		if !found {
+			continue
+		}
+		if !field.IsExported() || field.Type.Kind() != reflect.Func {
+			return fmt.Errorf("%s: field=[%s] must be an exported func: %w", fName, field.Name,
+				serror.IllegalArgumentValue(field.Name, field.Type))
+		}
+		clientFunc, err := newClientFunc(config, endpoint, field.Type)
+		if err != nil {
+			return fmt.Errorf("%s: field=[%s]: %w", fName, field.Name, err)
+		}
+		clientStruct.Field(i).Set(clientFunc)
+	}
+	return nil
+}
+
+// AsClientError returns the ClientError found in the chain of err
+func AsClientError(err error) (*ClientError, bool) {
+	for err != nil {
+		if clientErr, ok := err.(*ClientError); ok {
+			return clientErr, true
+		}
+		if causer, ok := err.(interface{ Cause() error }); ok && causer.Cause() != nil {
+			err = causer.Cause()
+			continue
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil, false
+}
+
+// DefaultClientRetryable retries the idempotent requests that failed without response or with the status codes 429,
+// 502, 503 and 504. The requests with the Idempotency-Key header are idempotent.
+func DefaultClientRetryable(request *http.Request, response *http.Response, err error) bool {
+	if !isIdempotentRequest(request) {
+		return false
+	}
+	if err != nil || response == nil {
+		return true
+	}
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isIdempotentRequest(request *http.Request) bool {
+	if request == nil {
+		return false
+	}
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return request.Header.Get(HeaderIdempotencyKey) != ""
+}
+
+func (e *ClientError) Error() string {
+	message := fmt.Sprintf("%s %s: %d %s", e.Method, e.redactedURL(), e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Problem != nil && cmp.Or(e.Problem.Detail, e.Problem.Title) != "" {
+		message += ": " + cmp.Or(e.Problem.Detail, e.Problem.Title)
+	}
+	return message
+}
+
+// redactedURL returns the URL without the query and the fragment, they can hold secrets like API keys
+func (e *ClientError) redactedURL() string {
+	requestURL, _, _ := strings.Cut(e.URL, "?")
+	requestURL, _, _ = strings.Cut(requestURL, "#")
+	return requestURL
+}
+
+// Unwrap returns the sentinel error of the status code, for instance ErrUnauthorized for 401
+func (e *ClientError) Unwrap() error {
+	return e.cause
+}
+
+// newClientStatusError maps the status code to the error that errorToStatusCode maps back to it
+func newClientStatusError(clientErr *ClientError) error {
+	switch status := clientErr.StatusCode; {
+	case status == http.StatusUnauthorized:
+		clientErr.cause = ErrUnauthorized
+	case status == http.StatusForbidden:
+		clientErr.cause = ErrForbidden
+	case status == http.StatusTooManyRequests:
+		clientErr.cause = ErrTooManyRequests
+	case status == http.StatusPreconditionFailed:
+		clientErr.cause = ErrPreconditionFailed
+	case status == http.StatusNotFound:
+		return serror.NotFoundError.WrapWithNoMessage(clientErr)
+	case status == http.StatusConflict:
+		return serror.DuplicateError.WrapWithNoMessage(clientErr)
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return serror.WrapAsTimeout(clientErr)
+	case status == http.StatusBadGateway || status == http.StatusServiceUnavailable:
+		return serror.WrapAsTemporary(clientErr)
+	case status < http.StatusInternalServerError:
+		return serror.IllegalArgumentValueWithCause("request", clientErr.redactedURL(), clientErr)
+	default:
+		return serror.WrapAsInternalError(clientErr)
+	}
+	return clientErr
+}
+
+func newClientFunc(config ClientConfig, endpoint string, funcType reflect.Type) (reflect.Value, error) {
+	if err := checkClientFuncType(funcType); err != nil {
+		return reflect.Value{}, err
+	}
+	clientEndpoint, err := newClientEndpoint(config, endpoint, funcType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.MakeFunc(funcType, func(args []reflect.Value) []reflect.Value {
+		ctx, _ := args[0].Interface().(context.Context)
+		var request reflect.Value
+		if len(args) > 1 {
+			request = args[1]
+		}
+		response, err := clientEndpoint.call(cmp.Or(ctx, context.Background()), request)
+		errValue := reflect.Zero(errorType)
+		if err != nil {
+			errValue = reflect.ValueOf(&err).Elem()
+		}
+		if funcType.NumOut() == 1 {
+			return []reflect.Value{errValue}
+		}
+		if err != nil || !response.IsValid() {
+			response = reflect.Zero(funcType.Out(0))
+		}
+		return []reflect.Value{response, errValue}
+	}), nil
+}
+
+func checkClientFuncType(funcType reflect.Type) error {
+	valid := funcType.NumIn() >= 1 && funcType.NumIn() <= 2 && funcType.In(0) == contextType && !funcType.IsVariadic() &&
+		funcType.NumOut() >= 1 && funcType.NumOut() <= 2 && funcType.Out(funcType.NumOut()-1) == errorType
+	if !valid {
+		return serror.IllegalArgumentValue("func", funcType)
+	}
+	return nil
+}
+
+func newClientEndpoint(config ClientConfig, endpoint string, funcType reflect.Type) (*clientEndpoint, error) {
+	method, path, found := strings.Cut(strings.TrimSpace(endpoint), " ")
+	path = strings.TrimSpace(path)
+	if !found || method == "" || !strings.HasPrefix(path, "/") {
+		return nil, serror.IllegalArgumentValue(TagEndpoint, endpoint)
+	}
+	result := &clientEndpoint{config: config, method: strings.ToUpper(method), path: path, pathParams: parsePathParams(path)}
+	if funcType.NumIn() > 1 {
+		requestSpec, err := newClientStructSpec(funcType.In(1), clientRequestTags, func(field reflect.StructField) (sdkparam.OutputParamSpec[*clientRequest], error) {
+			return tagbased.NewOutputParamSpecFactory(getClientRequestParamSpecFactoryRegistry()).CreateOutputParamSpec(field)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err = checkClientPathParams(result.pathParams, requestSpec); err != nil {
+			return nil, err
+		}
+		result.requestSpec = requestSpec
+	} else if len(result.pathParams) > 0 {
+		return nil, serror.IllegalArgumentValue(TagEndpoint, endpoint)
+	}
+	if funcType.NumOut() > 1 {
+		responseSpec, err := newClientStructSpec(funcType.Out(0), clientResponseTags, func(field reflect.StructField) (sdkparam.InputParamSpec[*clientResponse], error) {
+			return tagbased.NewsInputParamSpecFactory(getClientResponseParamSpecFactoryRegistry()).CreateInputParamSpec(field)
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.responseSpec = responseSpec
+	}
+	return result, nil
+}
+
+// parsePathParams returns the names of the {param} segments of the path
+func parsePathParams(path string) []string {
+	var params []string
+	for rest := path; ; {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			return params
+		}
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			return params
+		}
+		params = append(params, rest[start+1:start+end])
+		rest = rest[start+end+1:]
+	}
+}
+
+func checkClientPathParams(pathParams []string, requestSpec *clientStructSpec[sdkparam.OutputParamSpec[*clientRequest]]) error {
+	declared := map[string]bool{}
+	for _, field := range reflect.VisibleFields(requestSpec.structType) {
+		if name, found := field.Tag.Lookup(TagPath); found {
+			declared[name] = true
+		}
+	}
+	for _, param := range pathParams {
+		if !declared[param] {
+			return serror.IllegalArgumentValue(TagPath, param)
+		}
+		delete(declared, param)
+	}
+	for name := range declared {
+		return serror.IllegalArgumentValue(TagPath, name)
+	}
+	return nil
+}
+
+func newClientStructSpec[S any](valueType reflect.Type, tags []string, createSpec func(reflect.StructField) (S, error)) (*clientStructSpec[S], error) {
+	result := &clientStructSpec[S]{structType: valueType}
+	if valueType.Kind() == reflect.Pointer && valueType.Elem().Kind() == reflect.Struct {
+		result.structType, result.isPointer = valueType.Elem(), true
+	}
+	if result.structType.Kind() != reflect.Struct {
+		result.jsonBody, result.mimeType = true, ContentTypeJSON
+		return result, nil
+	}
+	for _, field := range reflect.VisibleFields(result.structType) {
+		if field.Anonymous || !field.IsExported() || !hasAnyTag(field, tags) {
+			continue
+		}
+		spec, err := createSpec(field)
+		if err != nil {
+			return nil, err
+		}
+		if _, isBody := field.Tag.Lookup(TagBody); isBody {
+			result.mimeType = field.Tag.Get(tagMimeType)
+		}
+		result.fields = append(result.fields, clientFieldSpec[S]{index: field.Index, spec: spec})
+	}
+	if len(result.fields) == 0 && result.structType.NumField() > 0 {
+		result.jsonBody, result.mimeType = true, ContentTypeJSON
+	}
+	return result, nil
+}
+
+func hasAnyTag(field reflect.StructField, tags []string) bool {
+	for _, tag := range tags {
+		if _, found := field.Tag.Lookup(tag); found {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *clientEndpoint) call(ctx context.Context, requestValue reflect.Value) (reflect.Value, error) {
+	request, err := e.newRequest(requestValue)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	response, err := e.send(ctx, request)
+	if err != nil || e.responseSpec == nil {
+		return reflect.Value{}, err
+	}
+	return e.newResponse(response)
+}
+
+func (e *clientEndpoint) newRequest(requestValue reflect.Value) (*clientRequest, error) {
+	const fName = "httpadpt.clientEndpoint.newRequest"
+	request := newClientRequest()
+	if e.requestSpec == nil {
+		return request, nil
+	}
+	if e.requestSpec.isPointer {
+		if requestValue.IsNil() {
+			return nil, serror.IllegalArgumentValue[any]("request", nil)
+		}
+		requestValue = requestValue.Elem()
+	}
+	if e.requestSpec.jsonBody {
+		body, err := json.Marshal(requestValue.Interface())
+		if err != nil {
+			return nil, serror.CmpError.Wrap(err, "%s: failed to marshal the request", fName)
+		}
+		request.body, request.hasBody = body, true
+		return request, nil
+	}
+	for _, field := range e.requestSpec.fields {
+		fieldValue, err := requestValue.FieldByIndexErr(field.index)
+		if err != nil {
+			// nil embedded struct pointer, its fields are not sent
+			continue
+		}
+		if err = field.spec.SetValue(request, fieldValue.Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
+
+func (e *clientEndpoint) newResponse(response *clientResponse) (reflect.Value, error) {
+	const fName = "httpadpt.clientEndpoint.newResponse"
+	responseValue := reflect.New(e.responseSpec.structType)
+	if e.responseSpec.jsonBody {
+		if len(response.body) > 0 {
+			if err := json.Unmarshal(response.body, responseValue.Interface()); err != nil {
+				return reflect.Value{}, serror.CmpError.Wrap(err, "%s: failed to unmarshal the response", fName)
+			}
+		}
+	} else {
+		for _, field := range e.responseSpec.fields {
+			if err := field.spec.CopyValue(response, allocFieldByIndex(responseValue.Elem(), field.index).Addr().Interface()); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+	}
+	if e.responseSpec.isPointer {
+		return responseValue, nil
+	}
+	return responseValue.Elem(), nil
+}
+
+// allocFieldByIndex returns the field allocating the embedded struct pointers in the path
+func allocFieldByIndex(structValue reflect.Value, index []int) reflect.Value {
+	for i, fieldIndex := range index {
+		if i > 0 && structValue.Kind() == reflect.Pointer {
+			if structValue.IsNil() {
+				structValue.Set(reflect.New(structValue.Type().Elem()))
+			}
+			structValue = structValue.Elem()
+		}
+		structValue = structValue.Field(fieldIndex)
+	}
+	return structValue
+}
+
+func (e *clientEndpoint) url(request *clientRequest) string {
+	path := e.path
+	for _, param := range e.pathParams {
+		path = strings.Replace(path, "{"+param+"}", url.PathEscape(request.pathParams[param]), 1)
+	}
+	result := strings.TrimSuffix(e.config.BaseURL, "/") + path
+	if len(request.query) > 0 {
+		separator := "?"
+		if strings.Contains(result, "?") {
+			separator = "&"
+		}
+		result += separator + request.query.Encode()
+	}
+	return result
+}
+
+func (e *clientEndpoint) header(request *clientRequest) http.Header {
+	header := e.config.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	for name, values := range request.header {
+		header[name] = values
+	}
+	if request.hasBody && e.requestSpec != nil && e.requestSpec.mimeType != "" && header.Get("Content-Type") == "" {
+		header.Set("Content-Type", e.requestSpec.mimeType)
+	}
+	if e.responseSpec != nil && e.responseSpec.mimeType != "" && header.Get("Accept") == "" {
+		header.Set("Accept", e.responseSpec.mimeType)
+	}
+	return header
+}
+
+// send sends the request retrying the failed attempts as configured by ClientRetry
+func (e *clientEndpoint) send(ctx context.Context, request *clientRequest) (*clientResponse, error) {
+	retry := e.config.Retry
+	retryable := retry.Retryable
+	if retryable == nil {
+		retryable = DefaultClientRetryable
+	}
+	requestURL, header := e.url(request), e.header(request)
+	for attempt := 1; ; attempt++ {
+		httpRequest, httpResponse, response, err := e.sendAttempt(ctx, requestURL, header, request)
+		if err == nil && response.statusCode >= http.StatusBadRequest {
+			err = newClientStatusError(newClientError(httpRequest, response))
+		}
+		if err == nil || attempt >= retry.MaxAttempts || ctx.Err() != nil || !retryable(httpRequest, httpResponse, err) {
+			return response, err
+		}
+		timer := time.NewTimer(retryDelay(retry, attempt, httpResponse))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (e *clientEndpoint) sendAttempt(ctx context.Context, requestURL string, header http.Header, request *clientRequest) (*http.Request, *http.Response, *clientResponse, error) {
+	const fName = "httpadpt.clientEndpoint.sendAttempt"
+	timeout := cmp.Or(e.config.Timeout, DefaultClientTimeout)
+	attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	var body io.Reader
+	if request.hasBody {
+		body = bytes.NewReader(request.body)
+	}
+	httpRequest, err := http.NewRequestWithContext(attemptCtx, e.method, requestURL, body)
+	if err != nil {
+		return nil, nil, nil, serror.CmpError.Wrap(err, "%s: failed to create the request %s %s", fName, e.method, requestURL)
+	}
+	httpRequest.Header = header.Clone()
+	httpClient := e.config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	httpResponse, err := httpClient.Do(httpRequest)
+	if err != nil {
+		return httpRequest, nil, nil, clientTransportError(ctx, err)
+	}
+	defer func() { _ = httpResponse.Body.Close() }()
+	maxResponseSize := cmp.Or(e.config.MaxResponseSize, DefaultClientMaxResponseSize)
+	responseBody, err := io.ReadAll(http.MaxBytesReader(nil, httpResponse.Body, maxResponseSize))
+	if err != nil {
+		return httpRequest, httpResponse, nil, clientTransportError(ctx, err)
+	}
+	return httpRequest, httpResponse, &clientResponse{
+		statusCode: httpResponse.StatusCode,
+		header:     httpResponse.Header,
+		body:       responseBody,
+	}, nil
+}
+
+// clientTransportError classifies the errors of the attempts that did not get a response, the attempt timeout is a
+// serror timeout while the cancellation of ctx is returned as is
+func clientTransportError(ctx context.Context, err error) error {
+	switch {
+	case ctx.Err() != nil:
+		return err
+	case errors.Is(err, context.DeadlineExceeded):
+		return serror.WrapAsTimeout(err)
+	case isRequestTooLargeError(err):
+		return err
+	default:
+		return serror.WrapAsTemporary(err)
+	}
+}
+
+func newClientError(httpRequest *http.Request, response *clientResponse) *ClientError {
+	clientErr := &ClientError{
+		Method:     httpRequest.Method,
+		URL:        httpRequest.URL.String(),
+		StatusCode: response.statusCode,
+		Header:     response.header,
+		Body:       response.body,
+	}
+	var problem ProblemDetail
+	if len(response.body) > 0 && json.Unmarshal(response.body, &problem) == nil &&
+		cmp.Or(problem.Type, problem.Title, problem.Detail) != "" {
+		clientErr.Problem = &problem
+	}
+	return clientErr
+}
+
+// retryDelay returns the exponential backoff of the attempt, or the delay required by the Retry-After header if it
+// is longer, up to MaxBackoff
+func retryDelay(retry ClientRetry, attempt int, response *http.Response) time.Duration {
+	maxBackoff := cmp.Or(retry.MaxBackoff, DefaultClientMaxBackoff)
+	delay := cmp.Or(retry.Backoff, DefaultClientBackoff)
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if response != nil {
+		if retryAfter := parseRetryAfter(response.Header.Get(HeaderRetryAfter), time.Now()); retryAfter > delay {
+			delay = retryAfter
+		}
+	}
+	return min(delay, maxBackoff)
+}
+
+// parseRetryAfter returns the delay of the Retry-After header, it can be a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
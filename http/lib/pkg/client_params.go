@@ -0,0 +1,194 @@
+package httpadpt
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"reflect"
+
+	sdkconverter "github.com/smart-libs/go-adapter/sdk/lib/pkg/converter"
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	// tagMimeType is the sdk option tag, the client uses it also as Content-Type of the request and Accept of the
+	// response
+	tagMimeType = "mime-type"
+)
+
+type (
+	// clientRequest is the Output of the request struct fields, the client sends it
+	clientRequest struct {
+		pathParams map[string]string
+		query      url.Values
+		header     http.Header
+		body       []byte
+		hasBody    bool
+	}
+
+	// clientResponse is the Input of the response struct fields, the client creates it from the received response
+	clientResponse struct {
+		statusCode int
+		header     http.Header
+		body       []byte
+	}
+)
+
+var (
+	// ClientConverters is the list of converter.Converters used by the client, it unmarshals the bodies with the
+	// mime-type application/json and then it tries the Converters
+	ClientConverters = converter.NewConvertersList(sdkconverter.JSONConverters{Name: "json"}, Converters)
+
+	clientRequestParamSpecFactoryRegistry  tagbased.OutputParamSpecFactoryRegistry[*clientRequest]
+	clientResponseParamSpecFactoryRegistry tagbased.InputParamSpecFactoryRegistry[*clientResponse]
+)
+
+func init() {
+	getClientRequestParamSpecFactoryRegistry().
+		AddOption2(TagPath, setClientPathParam).
+		AddOption2(TagQuery, setClientQueryParam).
+		AddOption2(TagRequestHeader, setClientHeader).
+		AddOption1(TagBody, "", setClientBody)
+
+	getClientResponseParamSpecFactoryRegistry().
+		AddOption1(TagStatusCode, "", func(input *clientResponse) (any, error) { return input.statusCode, nil }).
+		AddOption2(TagResponseHeader, getClientHeader).
+		AddOption1(TagBody, "", getClientBody)
+}
+
+func getClientRequestParamSpecFactoryRegistry() tagbased.OutputParamSpecFactoryRegistry[*clientRequest] {
+	if clientRequestParamSpecFactoryRegistry == nil {
+		clientRequestParamSpecFactoryRegistry = tagbased.NewOutputParamSpecFactoryRegistry[*clientRequest](ClientConverters)
+	}
+
+	return clientRequestParamSpecFactoryRegistry
+}
+
+func getClientResponseParamSpecFactoryRegistry() tagbased.InputParamSpecFactoryRegistry[*clientResponse] {
+	if clientResponseParamSpecFactoryRegistry == nil {
+		clientResponseParamSpecFactoryRegistry = tagbased.NewInputParamSpecFactoryRegistry[*clientResponse](ClientConverters)
+	}
+
+	return clientResponseParamSpecFactoryRegistry
+}
+
+func newClientRequest() *clientRequest {
+	return &clientRequest{pathParams: map[string]string{}, query: url.Values{}, header: http.Header{}}
+}
+
+// clientParamValue returns the value pointed by value, or false if it is nil so that the parameter is not sent
+func clientParamValue(value any) (any, bool) {
+	valueOf := reflect.ValueOf(value)
+	for valueOf.IsValid() {
+		switch valueOf.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			if valueOf.IsNil() {
+				return nil, false
+			}
+			valueOf = valueOf.Elem()
+			continue
+		case reflect.Map, reflect.Slice:
+			if valueOf.IsNil() {
+				return nil, false
+			}
+		}
+		return valueOf.Interface(), true
+	}
+	return nil, false
+}
+
+func setClientPathParam(output *clientRequest, name string, value any) error {
+	const fName = "httpadpt.setClientPathParam"
+	paramValue, ok := clientParamValue(value)
+	if !ok {
+		return serror.IllegalArgumentValue(name, value)
+	}
+	pathValue, err := converter.To[string](ClientConverters, paramValue)
+	if err != nil {
+		return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+	}
+	output.pathParams[name] = pathValue
+	return nil
+}
+
+func setClientQueryParam(output *clientRequest, name string, value any) error {
+	const fName = "httpadpt.setClientQueryParam"
+	paramValue, ok := clientParamValue(value)
+	if !ok {
+		return nil
+	}
+	queryValues, err := converter.To[[]string](ClientConverters, paramValue)
+	if err != nil {
+		return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+	}
+	output.query[name] = append(output.query[name], queryValues...)
+	return nil
+}
+
+func setClientHeader(output *clientRequest, name string, value any) error {
+	const fName = "httpadpt.setClientHeader"
+	paramValue, ok := clientParamValue(value)
+	if !ok {
+		return nil
+	}
+	headerValues, err := converter.To[[]string](ClientConverters, paramValue)
+	if err != nil {
+		return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+	}
+	output.header[textproto.CanonicalMIMEHeaderKey(name)] = headerValues
+	return nil
+}
+
+// setClientBody sets the request body, the JSON bodies are the json.RawMessage produced by the mime-type option
+func setClientBody(output *clientRequest, value any) error {
+	const fName = "httpadpt.setClientBody"
+	if reader, ok := value.(io.Reader); ok && !reflect.ValueOf(reader).IsZero() {
+		read, err := io.ReadAll(reader)
+		if err != nil {
+			return serror.CmpError.Wrap(err, "%s: failed to read the body", fName)
+		}
+		output.body, output.hasBody = read, true
+		return nil
+	}
+	paramValue, ok := clientParamValue(value)
+	if !ok {
+		return nil
+	}
+	var body []byte
+	switch typed := paramValue.(type) {
+	case json.RawMessage:
+		body = typed
+	case []byte:
+		body = typed
+	case string:
+		body = []byte(typed)
+	default:
+		converted, err := converter.To[[]byte](ClientConverters, paramValue)
+		if err != nil {
+			return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+		}
+		body = converted
+	}
+	output.body = body
+	output.hasBody = true
+	return nil
+}
+
+func getClientHeader(input *clientResponse, name string) (any, error) {
+	values := input.header.Values(name)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return values, nil
+}
+
+func getClientBody(input *clientResponse) (any, error) {
+	if len(input.body) == 0 {
+		return nil, nil
+	}
+	return input.body, nil
+}
@@ -0,0 +1,295 @@
+package httpadpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	clientUser struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	clientTenant struct {
+		Tenant string `header:"X-Tenant"`
+	}
+
+	getClientUserRequest struct {
+		clientTenant
+		ID     string   `path:"id"`
+		Fields []string `query:"fields"`
+		Limit  *int     `query:"limit"`
+	}
+
+	getClientUserResponse struct {
+		StatusCode int        `statuscode:""`
+		ETag       string     `header:"ETag"`
+		User       clientUser `body:"" mime-type:"application/json"`
+	}
+
+	createClientUserRequest struct {
+		IdempotencyKey string     `header:"Idempotency-Key"`
+		User           clientUser `body:"" mime-type:"application/json"`
+	}
+
+	usersClient struct {
+		Get    func(context.Context, getClientUserRequest) (*getClientUserResponse, error) `endpoint:"GET /users/{id}"`
+		Create func(context.Context, createClientUserRequest) (clientUser, error)          `endpoint:"POST /users"`
+		Delete func(context.Context, struct {
+			ID string `path:"id"`
+		}) error `endpoint:"DELETE /users/{id}"`
+		List func(context.Context) ([]clientUser, error) `endpoint:"GET /users"`
+	}
+)
+
+func newTestUsersClient(t *testing.T, handler http.HandlerFunc, config ClientConfig) usersClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	config.BaseURL = server.URL
+	var client usersClient
+	if err := MakeClient(config, &client); err != nil {
+		t.Fatalf("MakeClient() error = %v", err)
+	}
+	return client
+}
+
+func TestMakeClient(t *testing.T) {
+	client := newTestUsersClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /users/a b":
+			if r.URL.EscapedPath() != "/users/a%20b" || r.URL.Query()["fields"][1] != "email" || r.URL.Query().Get("limit") != "" {
+				http.Error(w, "unexpected request "+r.URL.String(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Type", r.Header.Get("Accept"))
+			_, _ = io.WriteString(w, `{"id":"a b","name":"`+r.Header.Get("X-Tenant")+`"}`)
+		case "POST /users":
+			var user clientUser
+			if r.Header.Get("Content-Type") != ContentTypeJSON || json.NewDecoder(r.Body).Decode(&user) != nil {
+				http.Error(w, "unexpected body", http.StatusBadRequest)
+				return
+			}
+			user.ID = r.Header.Get(HeaderIdempotencyKey)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(user)
+		case "DELETE /users/1":
+			w.WriteHeader(http.StatusNoContent)
+		case "GET /users":
+			_, _ = io.WriteString(w, `[{"id":"1"},{"id":"2"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}, ClientConfig{Header: http.Header{"X-Tenant": {"default"}}})
+
+	ctx := context.Background()
+	got, err := client.Get(ctx, getClientUserRequest{clientTenant: clientTenant{Tenant: "acme"}, ID: "a b", Fields: []string{"name", "email"}})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.StatusCode != http.StatusOK || got.ETag != `"v1"` || got.User != (clientUser{ID: "a b", Name: "acme"}) {
+		t.Errorf("Get() = %+v", got)
+	}
+
+	created, err := client.Create(ctx, createClientUserRequest{IdempotencyKey: "key-1", User: clientUser{Name: "John"}})
+	if err != nil || created != (clientUser{ID: "key-1", Name: "John"}) {
+		t.Errorf("Create() = %+v, %v", created, err)
+	}
+
+	if err = client.Delete(ctx, struct {
+		ID string `path:"id"`
+	}{ID: "1"}); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+
+	users, err := client.List(ctx)
+	if err != nil || len(users) != 2 || users[1].ID != "2" {
+		t.Errorf("List() = %+v, %v", users, err)
+	}
+}
+
+func TestNewClientFunc_StatusErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		check      func(error) bool
+	}{
+		{name: "400", statusCode: http.StatusBadRequest, check: serror.IsIllegalArgumentError},
+		{name: "401", statusCode: http.StatusUnauthorized, check: func(err error) bool { return errors.Is(err, ErrUnauthorized) }},
+		{name: "403", statusCode: http.StatusForbidden, check: func(err error) bool { return errors.Is(err, ErrForbidden) }},
+		{name: "404", statusCode: http.StatusNotFound, check: serror.IsNotFoundError},
+		{name: "409", statusCode: http.StatusConflict, check: serror.IsDuplicateError},
+		{name: "429", statusCode: http.StatusTooManyRequests, check: func(err error) bool { return errors.Is(err, ErrTooManyRequests) }},
+		{name: "504", statusCode: http.StatusGatewayTimeout, check: serror.IsTimeoutError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", ContentTypeProblemDetail)
+				w.WriteHeader(tt.statusCode)
+				_, _ = io.WriteString(w, `{"type":"x","detail":"failed"}`)
+			}))
+			defer server.Close()
+			getUser, err := NewClientFunc[getClientUserRequest, clientUser](ClientConfig{BaseURL: server.URL}, "GET /users/{id}")
+			if err != nil {
+				t.Fatalf("NewClientFunc() error = %v", err)
+			}
+
+			_, err = getUser(context.Background(), getClientUserRequest{ID: "1"})
+			if !tt.check(err) {
+				t.Errorf("error = %v, it does not match the status code %d", err, tt.statusCode)
+			}
+			var statusCode int
+			_ = errorToStatusCode(err, &statusCode)
+			if statusCode != tt.statusCode {
+				t.Errorf("errorToStatusCode() = %d, want %d", statusCode, tt.statusCode)
+			}
+			clientErr, ok := AsClientError(err)
+			if !ok || clientErr.StatusCode != tt.statusCode || clientErr.Problem == nil || clientErr.Problem.Detail != "failed" {
+				t.Errorf("AsClientError() = %+v, %v", clientErr, ok)
+			}
+		})
+	}
+}
+
+func TestClientError_Error(t *testing.T) {
+	clientErr := &ClientError{Method: http.MethodGet, URL: "https://api.example.com/users/1?api_key=secret#top",
+		StatusCode: http.StatusNotFound, Problem: &ProblemDetail{Title: "Not Found", Detail: "no user"}}
+	if got, expected := clientErr.Error(), "GET https://api.example.com/users/1: 404 Not Found: no user"; got != expected {
+		t.Errorf("Error() = %q, want %q", got, expected)
+	}
+
+	for _, statusCode := range []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity,
+		http.StatusInternalServerError} {
+		err := newClientStatusError(&ClientError{Method: http.MethodPost,
+			URL: "https://api.example.com/users?api_key=secret", StatusCode: statusCode})
+		if message := err.Error(); strings.Contains(message, "secret") || !strings.Contains(message, "https://api.example.com/users") {
+			t.Errorf("newClientStatusError(%d).Error() = %q, want the URL without the query", statusCode, message)
+		}
+	}
+}
+
+func TestNewClientFunc_Retry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, `{"id":"1"}`)
+	}))
+	defer server.Close()
+	config := ClientConfig{BaseURL: server.URL, Retry: ClientRetry{MaxAttempts: 3, Backoff: time.Millisecond}}
+
+	getUser, err := NewClientFunc[getClientUserRequest, *clientUser](config, "GET /users/{id}")
+	if err != nil {
+		t.Fatalf("NewClientFunc() error = %v", err)
+	}
+	user, err := getUser(context.Background(), getClientUserRequest{ID: "1"})
+	if err != nil || user.ID != "1" || calls.Load() != 3 {
+		t.Errorf("getUser() = %+v, %v after %d calls", user, err, calls.Load())
+	}
+
+	calls.Store(0)
+	createUser, err := NewClientFunc[createClientUserRequest, clientUser](config, "POST /users")
+	if err != nil {
+		t.Fatalf("NewClientFunc() error = %v", err)
+	}
+	if _, err = createUser(context.Background(), createClientUserRequest{}); !isClientErrorStatus(err, http.StatusServiceUnavailable) || calls.Load() != 1 {
+		t.Errorf("createUser() without Idempotency-Key error = %v after %d calls, want no retry", err, calls.Load())
+	}
+	calls.Store(0)
+	if _, err = createUser(context.Background(), createClientUserRequest{IdempotencyKey: "k"}); err != nil || calls.Load() != 3 {
+		t.Errorf("createUser() with Idempotency-Key error = %v after %d calls", err, calls.Load())
+	}
+}
+
+func isClientErrorStatus(err error, statusCode int) bool {
+	clientErr, ok := AsClientError(err)
+	return ok && clientErr.StatusCode == statusCode
+}
+
+func TestNewClientFunc_Timeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+	config := ClientConfig{BaseURL: server.URL, Timeout: 20 * time.Millisecond, Retry: ClientRetry{MaxAttempts: 2, Backoff: time.Millisecond}}
+	getUser, err := NewClientFunc[getClientUserRequest, clientUser](config, "GET /users/{id}")
+	if err != nil {
+		t.Fatalf("NewClientFunc() error = %v", err)
+	}
+
+	if _, err = getUser(context.Background(), getClientUserRequest{ID: "1"}); !serror.IsTimeoutError(err) || calls.Load() != 2 {
+		t.Errorf("getUser() error = %v after %d calls, want timeout after 2 calls", err, calls.Load())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = getUser(ctx, getClientUserRequest{ID: "1"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("getUser() canceled error = %v, want context.Canceled", err)
+	}
+}
+
+func TestMakeClientFunc_InvalidDeclarations(t *testing.T) {
+	var noContext func(getClientUserRequest) (clientUser, error)
+	var noError func(context.Context, getClientUserRequest) clientUser
+	var getUser func(context.Context, getClientUserRequest) (clientUser, error)
+	var noRequest func(context.Context) (clientUser, error)
+	tests := []struct {
+		name     string
+		endpoint string
+		funcPtr  any
+	}{
+		{name: "not a pointer", endpoint: "GET /users/{id}", funcPtr: getUser},
+		{name: "no context", endpoint: "GET /users/{id}", funcPtr: &noContext},
+		{name: "no error", endpoint: "GET /users/{id}", funcPtr: &noError},
+		{name: "no method", endpoint: "/users/{id}", funcPtr: &getUser},
+		{name: "undeclared path param", endpoint: "GET /users/{userId}", funcPtr: &getUser},
+		{name: "missing path param", endpoint: "GET /users", funcPtr: &getUser},
+		{name: "path param without request", endpoint: "GET /users/{id}", funcPtr: &noRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := MakeClientFunc(ClientConfig{}, tt.endpoint, tt.funcPtr); !serror.IsIllegalArgumentError(err) {
+				t.Errorf("MakeClientFunc() error = %v, want illegal argument", err)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "3", want: 3 * time.Second},
+		{value: now.Add(time.Minute).Format(http.TimeFormat), want: time.Minute},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		{value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
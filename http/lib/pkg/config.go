@@ -1,16 +1,69 @@
 package httpadpt
 
+const (
+	// DefaultFormMaxMemory is the default number of bytes of a multipart form kept in memory
+	DefaultFormMaxMemory int64 = 32 << 20
+
+	// DefaultFormMaxSize is the default maximum number of bytes read from the request body when parsing a form
+	DefaultFormMaxSize int64 = 64 << 20
+)
+
 type (
 	Config struct {
 		// Bindings are rules used to identify which function/use case to be invoked based on the FlagSet and the Args
 		Bindings
 
-		// Middlewares that shall be created by the implementation
+		// Middlewares that shall be created by the implementation for every binding, the first one is the outermost
 		Middlewares
+
+		// MiddlewareGroups are the middlewares applied only to the bindings under a path prefix, inside Middlewares
+		MiddlewareGroups
 		Host *string
 		Port *int
 
+		// Health is the registry of the health checks, if set its bindings are added to the Bindings and the
+		// adapter flips the readiness to failing when Stop begins
+		Health *HealthChecks
+
+		// CORS when set makes the implementation wrap every binding with the middleware created by NewCORSMiddleware,
+		// outside the Middlewares, and register an OPTIONS handler for every binding path, see OptionsBindings
+		CORS *CORSConfig
+
+		// Form limits how the request forms and uploaded files are read, if nil the defaults are used
+		Form *FormConfig
+
+		// CookieKeyRing signs and encrypts the values of the cookie tags with the signed or encrypted options, these
+		// cookies are rejected if it is nil. See NewCookieKeyRing.
+		CookieKeyRing *CookieKeyRing
+
 		// Other is used to provide additional implementation specific configuration
 		Other any
 	}
+
+	// FormConfig holds the limits applied when the request body is parsed as a urlencoded or multipart form
+	FormConfig struct {
+		// MaxMemory is the number of bytes of the multipart form kept in memory, the remaining file parts are
+		// stored in temporary files. Zero means DefaultFormMaxMemory.
+		MaxMemory int64
+
+		// MaxSize is the maximum number of bytes read from the request body. Zero means DefaultFormMaxSize and
+		// a negative value means no limit.
+		MaxSize int64
+	}
 )
+
+// GetMaxMemory returns the MaxMemory or DefaultFormMaxMemory if it is not set
+func (f *FormConfig) GetMaxMemory() int64 {
+	if f == nil || f.MaxMemory <= 0 {
+		return DefaultFormMaxMemory
+	}
+	return f.MaxMemory
+}
+
+// GetMaxSize returns the MaxSize or DefaultFormMaxSize if it is not set
+func (f *FormConfig) GetMaxSize() int64 {
+	if f == nil || f.MaxSize == 0 {
+		return DefaultFormMaxSize
+	}
+	return f.MaxSize
+}
@@ -1,6 +1,7 @@
 package httpadpt
 
 import (
+	"errors"
 	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
 	converterdefault "github.com/smart-libs/go-crosscutting/converter/lib/pkg/default"
 	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
@@ -16,6 +17,7 @@ var (
 	// The main idea it to used first converter functions specialized for the HTTP Adapter.
 	Converters = converter.NewConvertersList(
 		converterdefault.NewConverters(ConverterRegistry), // This is the local converters for the HTTP Adapter
+		fileReaderConverters{},                            // uploaded files to io.Reader
 		converterdefault.Converters,                       // default as fallback
 	)
 )
@@ -46,6 +48,34 @@ func errorToStatusCode(err error, to *int) error {
 	found := serror.IdentifyRootCause(
 		err,
 		func(err error) { *to = http.StatusInternalServerError }, // fallback
+		serror.CallbackCondition{
+			Condition: func(err error) bool { return errors.Is(err, ErrUnauthorized) },
+			Callback:  func(err error) { *to = http.StatusUnauthorized },
+		},
+		serror.CallbackCondition{
+			Condition: func(err error) bool { return errors.Is(err, ErrForbidden) },
+			Callback:  func(err error) { *to = http.StatusForbidden },
+		},
+		serror.CallbackCondition{
+			Condition: func(err error) bool { return errors.Is(err, ErrTooManyRequests) },
+			Callback:  func(err error) { *to = http.StatusTooManyRequests },
+		},
+		serror.CallbackCondition{
+			Condition: func(err error) bool { return errors.Is(err, ErrPreconditionFailed) },
+			Callback:  func(err error) { *to = http.StatusPreconditionFailed },
+		},
+		serror.CallbackCondition{
+			Condition: func(err error) bool { return errors.Is(err, ErrIdempotencyKeyInUse) },
+			Callback:  func(err error) { *to = http.StatusConflict },
+		},
+		serror.CallbackCondition{
+			Condition: func(err error) bool { return errors.Is(err, ErrIdempotencyKeyMismatch) },
+			Callback:  func(err error) { *to = http.StatusUnprocessableEntity },
+		},
+		serror.CallbackCondition{
+			Condition: isRequestTooLargeError,
+			Callback:  func(err error) { *to = http.StatusRequestEntityTooLarge },
+		},
 		serror.CallbackCondition{
 			Condition: serror.IsIllegalArgumentError,
 			Callback:  func(err error) { *to = http.StatusBadRequest },
@@ -72,3 +102,9 @@ func errorToStatusCode(err error, to *int) error {
 	}
 	return nil
 }
+
+// isRequestTooLargeError returns true if the request body was rejected because it exceeded the configured limit
+func isRequestTooLargeError(err error) bool {
+	var maxBytesError *http.MaxBytesError
+	return errors.As(err, &maxBytesError)
+}
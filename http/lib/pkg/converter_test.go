@@ -2,6 +2,7 @@ package httpadpt
 
 import (
 	"errors"
+	"fmt"
 	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
 	"net/http"
 	"testing"
@@ -86,6 +87,31 @@ func Test_errorToStatusCode(t *testing.T) {
 			err:            serror.IllegalConfigParamValue("param", "value"),
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "request too large error",
+			err:            fmt.Errorf("failed to parse form: %w", &http.MaxBytesError{Limit: 10}),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "too many requests error",
+			err:            fmt.Errorf("%w: retry after 1s", ErrTooManyRequests),
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:           "precondition failed error",
+			err:            fmt.Errorf("%w: If-Match does not match", ErrPreconditionFailed),
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "idempotency key in use error",
+			err:            ErrIdempotencyKeyInUse,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "idempotency key mismatch error",
+			err:            ErrIdempotencyKeyMismatch,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
 		{
 			name:           "generic error",
 			err:            errors.New("generic error"),
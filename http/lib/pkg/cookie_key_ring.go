@@ -0,0 +1,155 @@
+package httpadpt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// MinCookieKeySize is the minimum number of bytes of each CookieKeyRing key
+	MinCookieKeySize = 16
+)
+
+type (
+	// CookieKeyRing holds the keys used to sign and encrypt cookie values. The first key signs and encrypts new
+	// values and every key is tried to verify and decrypt them, so keys are rotated by adding a new first key and
+	// removing the oldest one once the cookies it produced expired.
+	CookieKeyRing struct {
+		keys []cookieKey
+	}
+
+	cookieKey struct {
+		signingKey []byte
+		aead       cipher.AEAD
+	}
+
+	// cookieKeyRingKey is used to add the CookieKeyRing to the Request
+	cookieKeyRingKey struct{}
+)
+
+var (
+	// ErrInvalidCookieValue is returned when a cookie value was tampered with or it was produced by an unknown key
+	ErrInvalidCookieValue = errors.New("invalid cookie value")
+)
+
+// NewCookieKeyRing creates a CookieKeyRing with the given keys, the first one being the current key
+func NewCookieKeyRing(keys ...[]byte) (*CookieKeyRing, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("httpadpt.NewCookieKeyRing: at least one key is required")
+	}
+	ring := &CookieKeyRing{}
+	for i, key := range keys {
+		if len(key) < MinCookieKeySize {
+			return nil, fmt.Errorf("httpadpt.NewCookieKeyRing: key[%d] has %d bytes, the minimum is %d", i, len(key), MinCookieKeySize)
+		}
+		// the signing and encryption keys are derived from the given key, so the same key is never used twice
+		block, err := aes.NewCipher(deriveCookieKey(key, "encryption"))
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		ring.keys = append(ring.keys, cookieKey{signingKey: deriveCookieKey(key, "signing"), aead: aead})
+	}
+	return ring, nil
+}
+
+func deriveCookieKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+func (k cookieKey) sign(name, payload string) []byte {
+	mac := hmac.New(sha256.New, k.signingKey)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// Sign returns the value and its HMAC, the cookie name is part of the HMAC, so a value cannot be moved to another cookie
+func (r *CookieKeyRing) Sign(name, value string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(value))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(r.keys[0].sign(name, payload))
+}
+
+// Verify returns the original value if the signed value was produced by Sign with any key of the ring
+func (r *CookieKeyRing) Verify(name, signedValue string) (string, error) {
+	payload, signature, found := strings.Cut(signedValue, ".")
+	if !found {
+		return "", ErrInvalidCookieValue
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return "", ErrInvalidCookieValue
+	}
+	for _, key := range r.keys {
+		if hmac.Equal(mac, key.sign(name, payload)) {
+			value, err := base64.RawURLEncoding.DecodeString(payload)
+			if err != nil {
+				return "", ErrInvalidCookieValue
+			}
+			return string(value), nil
+		}
+	}
+	return "", ErrInvalidCookieValue
+}
+
+// Encrypt returns the value encrypted with AES-GCM, the cookie name is authenticated with the value
+func (r *CookieKeyRing) Encrypt(name, value string) (string, error) {
+	aead := r.keys[0].aead
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("httpadpt.CookieKeyRing.Encrypt: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the original value if the encrypted value was produced by Encrypt with any key of the ring
+func (r *CookieKeyRing) Decrypt(name, encryptedValue string) (string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(encryptedValue)
+	if err != nil {
+		return "", ErrInvalidCookieValue
+	}
+	for _, key := range r.keys {
+		nonceSize := key.aead.NonceSize()
+		if len(sealed) < nonceSize {
+			return "", ErrInvalidCookieValue
+		}
+		if value, err := key.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(name)); err == nil {
+			return string(value), nil
+		}
+	}
+	return "", ErrInvalidCookieValue
+}
+
+// NewCookieKeyRingMiddleware gives the ring to the cookie tags with the signed or encrypted options of the handler it
+// wraps. WrapBindingHandler adds it as the innermost middleware when Config.CookieKeyRing is set.
+func NewCookieKeyRingMiddleware(ring *CookieKeyRing) Middleware {
+	return func(next Handler) Handler {
+		return MakeHandler(func(ctx context.Context, input Request, output *Response) error {
+			if output != nil {
+				output.cookieKeyRing = ring
+			}
+			return next.Invoke(ctx, WithRequestValue(input, cookieKeyRingKey{}, ring), output)
+		})
+	}
+}
+
+func cookieKeyRingFromRequest(input Request) *CookieKeyRing {
+	value, _ := GetRequestValue(input, cookieKeyRingKey{})
+	ring, _ := value.(*CookieKeyRing)
+	return ring
+}
@@ -0,0 +1,87 @@
+package httpadpt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func newTestCookieKeyRing(t *testing.T, seeds ...byte) *CookieKeyRing {
+	t.Helper()
+	var keys [][]byte
+	for _, seed := range seeds {
+		keys = append(keys, bytes.Repeat([]byte{seed}, 32))
+	}
+	ring, err := NewCookieKeyRing(keys...)
+	if err != nil {
+		t.Fatalf("NewCookieKeyRing() error = %v", err)
+	}
+	return ring
+}
+
+func TestNewCookieKeyRing_InvalidKeys(t *testing.T) {
+	if _, err := NewCookieKeyRing(); err == nil {
+		t.Error("NewCookieKeyRing() without keys error = nil, want error")
+	}
+	if _, err := NewCookieKeyRing([]byte("short")); err == nil {
+		t.Error("NewCookieKeyRing() with short key error = nil, want error")
+	}
+}
+
+func TestCookieKeyRing_SignAndVerify(t *testing.T) {
+	ring := newTestCookieKeyRing(t, 1)
+	signed := ring.Sign("session", "user=42; admin")
+
+	value, err := ring.Verify("session", signed)
+	if err != nil || value != "user=42; admin" {
+		t.Errorf("Verify() = %q, %v, want %q", value, err, "user=42; admin")
+	}
+	if _, err := ring.Verify("other", signed); !errors.Is(err, ErrInvalidCookieValue) {
+		t.Errorf("Verify() with another cookie name error = %v, want ErrInvalidCookieValue", err)
+	}
+	if _, err := ring.Verify("session", "x"+signed); !errors.Is(err, ErrInvalidCookieValue) {
+		t.Errorf("Verify() of tampered value error = %v, want ErrInvalidCookieValue", err)
+	}
+	if _, err := newTestCookieKeyRing(t, 2).Verify("session", signed); !errors.Is(err, ErrInvalidCookieValue) {
+		t.Errorf("Verify() with unknown key error = %v, want ErrInvalidCookieValue", err)
+	}
+}
+
+func TestCookieKeyRing_EncryptAndDecrypt(t *testing.T) {
+	ring := newTestCookieKeyRing(t, 1)
+	encrypted, err := ring.Encrypt("session", "secret")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if bytes.Contains([]byte(encrypted), []byte("secret")) {
+		t.Errorf("Encrypt() = %q exposes the value", encrypted)
+	}
+
+	value, err := ring.Decrypt("session", encrypted)
+	if err != nil || value != "secret" {
+		t.Errorf("Decrypt() = %q, %v, want %q", value, err, "secret")
+	}
+	if _, err := ring.Decrypt("other", encrypted); !errors.Is(err, ErrInvalidCookieValue) {
+		t.Errorf("Decrypt() with another cookie name error = %v, want ErrInvalidCookieValue", err)
+	}
+	if _, err := ring.Decrypt("session", "abc"); !errors.Is(err, ErrInvalidCookieValue) {
+		t.Errorf("Decrypt() of short value error = %v, want ErrInvalidCookieValue", err)
+	}
+}
+
+func TestCookieKeyRing_Rotation(t *testing.T) {
+	oldRing := newTestCookieKeyRing(t, 1)
+	signed := oldRing.Sign("session", "value")
+	encrypted, _ := oldRing.Encrypt("session", "value")
+
+	rotated := newTestCookieKeyRing(t, 2, 1)
+	if value, err := rotated.Verify("session", signed); err != nil || value != "value" {
+		t.Errorf("Verify() with old key = %q, %v, want %q", value, err, "value")
+	}
+	if value, err := rotated.Decrypt("session", encrypted); err != nil || value != "value" {
+		t.Errorf("Decrypt() with old key = %q, %v, want %q", value, err, "value")
+	}
+	if _, err := oldRing.Verify("session", rotated.Sign("session", "value")); err == nil {
+		t.Error("Verify() of a value signed by the new key succeeded with the old ring")
+	}
+}
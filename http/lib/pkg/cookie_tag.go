@@ -0,0 +1,115 @@
+package httpadpt
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	TagCookie = "cookie"
+
+	// CookieOptionSigned makes the cookie value signed with the Config.CookieKeyRing
+	CookieOptionSigned = "signed"
+
+	// CookieOptionEncrypted makes the cookie value encrypted with the Config.CookieKeyRing
+	CookieOptionEncrypted = "encrypted"
+)
+
+type (
+	// cookieTag is the parsed value of the cookie tag, for instance `cookie:"session,Path=/,HttpOnly,signed"`.
+	// The first element is the cookie name, the attributes are only used by the output tag.
+	cookieTag struct {
+		template  http.Cookie
+		signed    bool
+		encrypted bool
+	}
+)
+
+func parseCookieTag(tagValue string) (cookieTag, error) {
+	elements := strings.Split(tagValue, ",")
+	tag := cookieTag{template: http.Cookie{Name: strings.TrimSpace(elements[0])}}
+	if tag.template.Name == "" {
+		return tag, fmt.Errorf("httpadpt.parseCookieTag: the cookie name is missing in tag=[%s]", tagValue)
+	}
+	for _, element := range elements[1:] {
+		name, value, _ := strings.Cut(strings.TrimSpace(element), "=")
+		switch strings.ToLower(name) {
+		case CookieOptionSigned:
+			tag.signed = true
+		case CookieOptionEncrypted:
+			tag.encrypted = true
+		case "path":
+			tag.template.Path = value
+		case "domain":
+			tag.template.Domain = value
+		case "httponly":
+			tag.template.HttpOnly = true
+		case "secure":
+			tag.template.Secure = true
+		case "max-age":
+			maxAge, err := strconv.Atoi(value)
+			if err != nil {
+				return tag, fmt.Errorf("httpadpt.parseCookieTag: invalid Max-Age=[%s] in tag=[%s]", value, tagValue)
+			}
+			tag.template.MaxAge = maxAge
+		case "samesite":
+			sameSite, err := parseSameSite(value)
+			if err != nil {
+				return tag, fmt.Errorf("httpadpt.parseCookieTag: %w in tag=[%s]", err, tagValue)
+			}
+			tag.template.SameSite = sameSite
+		default:
+			return tag, fmt.Errorf("httpadpt.parseCookieTag: unknown attribute=[%s] in tag=[%s]", element, tagValue)
+		}
+	}
+	return tag, nil
+}
+
+func parseSameSite(value string) (http.SameSite, error) {
+	switch strings.ToLower(value) {
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	}
+	return http.SameSiteDefaultMode, fmt.Errorf("invalid SameSite=[%s]", value)
+}
+
+func (t cookieTag) checkKeyRing(ring *CookieKeyRing) error {
+	if ring == nil {
+		return fmt.Errorf("httpadpt: cookie=[%s] is signed or encrypted, but Config.CookieKeyRing is not set", t.template.Name)
+	}
+	return nil
+}
+
+// encode signs or encrypts the value according to the tag options
+func (t cookieTag) encode(ring *CookieKeyRing, name, value string) (string, error) {
+	if !t.signed && !t.encrypted {
+		return value, nil
+	}
+	if err := t.checkKeyRing(ring); err != nil {
+		return "", err
+	}
+	if t.encrypted {
+		return ring.Encrypt(name, value)
+	}
+	return ring.Sign(name, value), nil
+}
+
+// decode verifies or decrypts the value according to the tag options
+func (t cookieTag) decode(ring *CookieKeyRing, value string) (string, error) {
+	if !t.signed && !t.encrypted {
+		return value, nil
+	}
+	if err := t.checkKeyRing(ring); err != nil {
+		return "", err
+	}
+	if t.encrypted {
+		return ring.Decrypt(t.template.Name, value)
+	}
+	return ring.Verify(t.template.Name, value)
+}
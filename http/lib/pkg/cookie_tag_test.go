@@ -0,0 +1,34 @@
+package httpadpt
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_parseCookieTag(t *testing.T) {
+	tag, err := parseCookieTag("session, Path=/api, Domain=example.com, HttpOnly, Secure, SameSite=Strict, Max-Age=3600, signed")
+	if err != nil {
+		t.Fatalf("parseCookieTag() error = %v", err)
+	}
+	expected := http.Cookie{
+		Name:     "session",
+		Path:     "/api",
+		Domain:   "example.com",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   3600,
+	}
+	if tag.template.String() != expected.String() {
+		t.Errorf("parseCookieTag() template = %v, want %v", tag.template.String(), expected.String())
+	}
+	if !tag.signed || tag.encrypted {
+		t.Errorf("parseCookieTag() signed = %v, encrypted = %v, want true, false", tag.signed, tag.encrypted)
+	}
+
+	for _, invalid := range []string{"", ",Path=/", "session,Max-Age=abc", "session,SameSite=Sometimes", "session,Unknown"} {
+		if _, err := parseCookieTag(invalid); err == nil {
+			t.Errorf("parseCookieTag(%q) error = nil, want error", invalid)
+		}
+	}
+}
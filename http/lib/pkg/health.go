@@ -0,0 +1,309 @@
+package httpadpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultLivenessPath       = "/healthz"
+	DefaultReadinessPath      = "/readyz"
+	DefaultHealthCheckTimeout = 5 * time.Second
+
+	HealthStatusPass = "pass"
+	HealthStatusFail = "fail"
+)
+
+// ErrNotReady is the error reported by the readiness when the adapter is stopping
+var ErrNotReady = errors.New("not ready")
+
+type (
+	// HealthCheckFunc returns nil if the checked resource is healthy, it shall give up when the ctx is done
+	HealthCheckFunc = func(ctx context.Context) error
+
+	// HealthCheck is a named check registered in the HealthChecks
+	HealthCheck struct {
+		Name  string
+		Check HealthCheckFunc
+
+		// Timeout limits the check duration, zero means DefaultHealthCheckTimeout
+		Timeout time.Duration
+
+		// CacheTTL is how long the last result is reused before running the check again, zero means no cache
+		CacheTTL time.Duration
+	}
+
+	// HealthCheckResult is the result of one check in the HealthReport
+	HealthCheckResult struct {
+		Status    string    `json:"status"`
+		Error     string    `json:"error,omitempty"`
+		Duration  string    `json:"duration"`
+		CheckedAt time.Time `json:"checkedAt"`
+	}
+
+	// HealthReport is the JSON body returned by the liveness and readiness bindings
+	HealthReport struct {
+		Status string                       `json:"status"`
+		Error  string                       `json:"error,omitempty"`
+		Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+	}
+
+	// HealthConfig configures the HealthChecks created by NewHealthChecks
+	HealthConfig struct {
+		// LivenessPath is DefaultLivenessPath if empty
+		LivenessPath string
+
+		// ReadinessPath is DefaultReadinessPath if empty
+		ReadinessPath string
+
+		// DrainDelay is how long the adapter Stop waits, after failing the readiness, before shutting down the
+		// server, giving time to the load balancers to notice it and stop sending new requests
+		DrainDelay time.Duration
+
+		// Now is used by the tests, it is time.Now if nil
+		Now func() time.Time
+	}
+
+	// HealthChecks is the registry of the liveness and readiness checks. Set it in the Config.Health so the adapter
+	// flips the readiness to failing as soon as Stop begins, letting the load balancers drain the instance.
+	HealthChecks struct {
+		config    HealthConfig
+		notReady  atomic.Bool
+		locker    sync.RWMutex
+		liveness  []*cachedHealthCheck
+		readiness []*cachedHealthCheck
+	}
+
+	// RunningTasks is implemented by the task managers that can tell how many tasks are running. The sdk async
+	// manager is commented out, so it does not implement it; RunningTasksFunc adapts the counters of the others.
+	RunningTasks interface {
+		CountRunning() int
+	}
+
+	// RunningTasksFunc adapts a function that counts the running tasks, like the size of a worker pool, to RunningTasks
+	RunningTasksFunc func() int
+
+	cachedHealthCheck struct {
+		HealthCheck
+		locker    sync.Mutex
+		result    HealthCheckResult
+		expiresAt time.Time
+	}
+)
+
+// NewHealthChecks creates an empty registry, it is ready until SetReady(false) is called
+func NewHealthChecks(config HealthConfig) *HealthChecks {
+	if config.LivenessPath == "" {
+		config.LivenessPath = DefaultLivenessPath
+	}
+	if config.ReadinessPath == "" {
+		config.ReadinessPath = DefaultReadinessPath
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &HealthChecks{config: config}
+}
+
+// CountRunning returns f()
+func (f RunningTasksFunc) CountRunning() int { return f() }
+
+// NewRunningTasksCheck creates a check that fails when fewer than minRunning tasks are running
+func NewRunningTasksCheck(name string, tasks RunningTasks, minRunning int) HealthCheck {
+	if tasks == nil {
+		panic(fmt.Errorf("httpadpt.NewRunningTasksCheck: %s: nil tasks", name))
+	}
+	return HealthCheck{
+		Name: name,
+		Check: func(_ context.Context) error {
+			if running := tasks.CountRunning(); running < minRunning {
+				return fmt.Errorf("%d tasks running, expected at least %d", running, minRunning)
+			}
+			return nil
+		},
+	}
+}
+
+// AddLiveness registers checks run by the liveness binding and also by the readiness one
+func (h *HealthChecks) AddLiveness(checks ...HealthCheck) *HealthChecks {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+	h.liveness = appendHealthChecks(h.liveness, checks)
+	return h
+}
+
+// AddReadiness registers checks run only by the readiness binding
+func (h *HealthChecks) AddReadiness(checks ...HealthCheck) *HealthChecks {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+	h.readiness = appendHealthChecks(h.readiness, checks)
+	return h
+}
+
+func appendHealthChecks(list []*cachedHealthCheck, checks []HealthCheck) []*cachedHealthCheck {
+	for _, check := range checks {
+		if check.Name == "" || check.Check == nil {
+			panic(fmt.Errorf("httpadpt.HealthChecks: invalid check %q", check.Name))
+		}
+		list = append(list, &cachedHealthCheck{HealthCheck: check})
+	}
+	return list
+}
+
+// SetReady changes the readiness, the adapters call it with false when Stop begins. It does nothing if h is nil.
+func (h *HealthChecks) SetReady(ready bool) {
+	if h != nil {
+		h.notReady.Store(!ready)
+	}
+}
+
+// Drain fails the readiness and waits the DrainDelay or until the ctx is done. It does nothing if h is nil.
+func (h *HealthChecks) Drain(ctx context.Context) {
+	if h == nil {
+		return
+	}
+	h.SetReady(false)
+	if h.config.DrainDelay <= 0 {
+		return
+	}
+	timer := time.NewTimer(h.config.DrainDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// IsReady returns false after SetReady(false)
+func (h *HealthChecks) IsReady() bool {
+	return !h.notReady.Load()
+}
+
+// Liveness runs the liveness checks
+func (h *HealthChecks) Liveness(ctx context.Context) HealthReport {
+	h.locker.RLock()
+	checks := h.liveness
+	h.locker.RUnlock()
+	return h.run(ctx, checks)
+}
+
+// Readiness runs the liveness and the readiness checks, it fails without running them if the adapter is stopping
+func (h *HealthChecks) Readiness(ctx context.Context) HealthReport {
+	if !h.IsReady() {
+		return HealthReport{Status: HealthStatusFail, Error: ErrNotReady.Error()}
+	}
+	h.locker.RLock()
+	checks := append(append([]*cachedHealthCheck(nil), h.liveness...), h.readiness...)
+	h.locker.RUnlock()
+	return h.run(ctx, checks)
+}
+
+// Bindings returns the GET bindings of the liveness and readiness paths, they answer 200 if all the checks pass
+// and 503 otherwise. It returns nil if h is nil.
+func (h *HealthChecks) Bindings() Bindings {
+	if h == nil {
+		return nil
+	}
+	return Bindings{
+		newHealthBinding(h.config.LivenessPath, h.Liveness),
+		newHealthBinding(h.config.ReadinessPath, h.Readiness),
+	}
+}
+
+func (h *HealthChecks) run(ctx context.Context, checks []*cachedHealthCheck) HealthReport {
+	report := HealthReport{Status: HealthStatusPass}
+	if len(checks) == 0 {
+		return report
+	}
+	results := make([]HealthCheckResult, len(checks))
+	var waitGroup sync.WaitGroup
+	for i, check := range checks {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			results[i] = check.run(ctx, h.config.Now)
+		}()
+	}
+	waitGroup.Wait()
+
+	report.Checks = make(map[string]HealthCheckResult, len(checks))
+	for i, check := range checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != HealthStatusPass {
+			report.Status = HealthStatusFail
+		}
+	}
+	return report
+}
+
+// run returns the cached result if it did not expire, the lock makes the concurrent requests share the same run
+func (c *cachedHealthCheck) run(ctx context.Context, now func() time.Time) HealthCheckResult {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	if c.CacheTTL > 0 && now().Before(c.expiresAt) {
+		return c.result
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- c.Check(checkCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		err = fmt.Errorf("timed out after %s: %w", timeout, checkCtx.Err())
+	}
+
+	c.result = HealthCheckResult{Status: HealthStatusPass, Duration: now().Sub(start).String(), CheckedAt: start}
+	if err != nil {
+		c.result.Status = HealthStatusFail
+		c.result.Error = err.Error()
+	}
+	c.expiresAt = start.Add(c.CacheTTL)
+	return c.result
+}
+
+func newHealthBinding(path string, report func(ctx context.Context) HealthReport) Binding {
+	return Binding{
+		Condition: Condition{Path: &path, Methods: []string{http.MethodGet}},
+		Handler: MakeHandler(func(ctx context.Context, _ Request, output *Response) error {
+			result := report(ctx)
+			body, err := json.Marshal(result)
+			if err != nil {
+				return fmt.Errorf("httpadpt.HealthChecks: %w", err)
+			}
+			statusCode := http.StatusOK
+			if result.Status != HealthStatusPass {
+				statusCode = http.StatusServiceUnavailable
+			}
+			output.StatusCode = &statusCode
+			output.Header = map[string][]string{
+				"Content-Type":  {"application/json"},
+				"Cache-Control": {"no-store"},
+			}
+			output.Body = body
+			return nil
+		}),
+	}
+}
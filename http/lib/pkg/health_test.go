@@ -0,0 +1,151 @@
+package httpadpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testRunningTasks int
+
+func (t testRunningTasks) CountRunning() int { return int(t) }
+
+func invokeHealthBinding(t *testing.T, binding Binding) (int, HealthReport) {
+	t.Helper()
+	var output Response
+	if err := binding.Handler.Invoke(context.Background(), &mockRequest{}, &output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	var report HealthReport
+	if err := json.Unmarshal(output.Body, &report); err != nil {
+		t.Fatalf("invalid body %s: %v", output.Body, err)
+	}
+	return *output.StatusCode, report
+}
+
+func TestHealthChecks_Bindings(t *testing.T) {
+	health := NewHealthChecks(HealthConfig{}).
+		AddLiveness(HealthCheck{Name: "self", Check: func(context.Context) error { return nil }}).
+		AddReadiness(HealthCheck{Name: "db", Check: func(context.Context) error { return errors.New("db down") }})
+	bindings := health.Bindings()
+	if len(bindings) != 2 || *bindings[0].Path != DefaultLivenessPath || *bindings[1].Path != DefaultReadinessPath {
+		t.Fatalf("Bindings() = %+v", bindings)
+	}
+
+	status, report := invokeHealthBinding(t, bindings[0])
+	if status != http.StatusOK || report.Status != HealthStatusPass || len(report.Checks) != 1 {
+		t.Errorf("liveness = %d %+v, want 200 with the self check", status, report)
+	}
+
+	status, report = invokeHealthBinding(t, bindings[1])
+	if status != http.StatusServiceUnavailable || report.Status != HealthStatusFail {
+		t.Fatalf("readiness = %d %+v, want 503", status, report)
+	}
+	if report.Checks["self"].Status != HealthStatusPass || report.Checks["db"].Error != "db down" {
+		t.Errorf("readiness checks = %+v", report.Checks)
+	}
+}
+
+func TestHealthChecks_SetReady(t *testing.T) {
+	health := NewHealthChecks(HealthConfig{})
+	if report := health.Readiness(context.Background()); report.Status != HealthStatusPass {
+		t.Fatalf("Readiness() = %+v, want pass", report)
+	}
+	health.SetReady(false)
+	report := health.Readiness(context.Background())
+	if report.Status != HealthStatusFail || report.Error != ErrNotReady.Error() {
+		t.Errorf("Readiness() after SetReady(false) = %+v", report)
+	}
+	if report := health.Liveness(context.Background()); report.Status != HealthStatusPass {
+		t.Errorf("Liveness() after SetReady(false) = %+v, want pass", report)
+	}
+	var nilHealth *HealthChecks
+	nilHealth.SetReady(false)
+	nilHealth.Drain(context.Background())
+	if nilHealth.Bindings() != nil {
+		t.Error("nil Bindings() should be nil")
+	}
+}
+
+func TestHealthChecks_CacheTTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var calls atomic.Int32
+	health := NewHealthChecks(HealthConfig{Now: func() time.Time { return now }}).AddLiveness(HealthCheck{
+		Name:     "counted",
+		CacheTTL: time.Minute,
+		Check:    func(context.Context) error { calls.Add(1); return nil },
+	})
+
+	health.Liveness(context.Background())
+	now = now.Add(30 * time.Second)
+	health.Liveness(context.Background())
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want the cached result reused", calls.Load())
+	}
+	now = now.Add(31 * time.Second)
+	health.Liveness(context.Background())
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want the check run again after the CacheTTL", calls.Load())
+	}
+}
+
+func TestHealthChecks_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	health := NewHealthChecks(HealthConfig{}).AddLiveness(
+		HealthCheck{Name: "stuck", Timeout: 10 * time.Millisecond, Check: func(context.Context) error {
+			<-release // ignores the ctx
+			return nil
+		}},
+		HealthCheck{Name: "panic", Check: func(context.Context) error { panic("boom") }},
+	)
+
+	report := health.Liveness(context.Background())
+	if report.Status != HealthStatusFail {
+		t.Fatalf("Liveness() = %+v, want fail", report)
+	}
+	if !strings.Contains(report.Checks["stuck"].Error, "timed out") {
+		t.Errorf("stuck error = %q", report.Checks["stuck"].Error)
+	}
+	if report.Checks["panic"].Error != "panic: boom" {
+		t.Errorf("panic error = %q", report.Checks["panic"].Error)
+	}
+}
+
+func TestHealthChecks_Drain(t *testing.T) {
+	health := NewHealthChecks(HealthConfig{DrainDelay: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	health.Drain(ctx)
+	if health.IsReady() {
+		t.Error("IsReady() after Drain() should be false")
+	}
+}
+
+func TestNewRunningTasksCheck(t *testing.T) {
+	check := NewRunningTasksCheck("workers", testRunningTasks(1), 2)
+	if err := check.Check(context.Background()); err == nil || err.Error() != "1 tasks running, expected at least 2" {
+		t.Errorf("Check() = %v", err)
+	}
+	check = NewRunningTasksCheck("workers", testRunningTasks(2), 2)
+	if err := check.Check(context.Background()); err != nil {
+		t.Errorf("Check() = %v", err)
+	}
+}
+
+func TestRunningTasksFunc(t *testing.T) {
+	var running atomic.Int32
+	check := NewRunningTasksCheck("workers", RunningTasksFunc(func() int { return int(running.Load()) }), 1)
+	if err := check.Check(context.Background()); err == nil {
+		t.Error("Check() without running tasks = nil, want error")
+	}
+	running.Add(1)
+	if err := check.Check(context.Background()); err != nil {
+		t.Errorf("Check() = %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package httpadpt
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	// IdempotencyRecord is what the IdempotencyStore keeps for an Idempotency-Key. The record is in flight until the
+	// first request completes, then it holds the Response replayed to the retries.
+	IdempotencyRecord struct {
+		Fingerprint string
+		Completed   bool
+		Response    Response
+	}
+
+	// IdempotencyStore keeps the IdempotencyRecord of the keys, it can be shared by several instances of the
+	// application
+	IdempotencyStore interface {
+		// Begin atomically creates an in-flight record with the fingerprint if the key has none, or if it expired, and
+		// returns found false. Otherwise, it returns the existing record and found true.
+		Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (record IdempotencyRecord, found bool, err error)
+
+		// Extend keeps the in-flight record of the key for the ttl from now, it is called while the handler runs
+		Extend(ctx context.Context, key string, ttl time.Duration) error
+
+		// Complete stores the response of the key, keeping it at least for the ttl
+		Complete(ctx context.Context, key string, response Response, ttl time.Duration) error
+
+		// Abort removes the in-flight record of the key, so the request can be retried
+		Abort(ctx context.Context, key string) error
+	}
+
+	// MemoryIdempotencyStore is an IdempotencyStore for a single instance, the expired keys are removed periodically
+	MemoryIdempotencyStore struct {
+		mutex     sync.Mutex
+		entries   map[string]memoryIdempotencyEntry
+		nextSweep time.Time
+	}
+
+	memoryIdempotencyEntry struct {
+		record    IdempotencyRecord
+		expiresAt time.Time
+	}
+)
+
+const memoryIdempotencySweepInterval = time.Minute
+
+// NewMemoryIdempotencyStore creates an in-memory IdempotencyStore
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: map[string]memoryIdempotencyEntry{}}
+}
+
+func (m *MemoryIdempotencyStore) Begin(_ context.Context, key, fingerprint string, ttl time.Duration) (IdempotencyRecord, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+	if entry, found := m.entries[key]; found && now.Before(entry.expiresAt) {
+		return entry.record, true, nil
+	}
+	m.entries[key] = memoryIdempotencyEntry{record: IdempotencyRecord{Fingerprint: fingerprint}, expiresAt: now.Add(ttl)}
+	return IdempotencyRecord{}, false, nil
+}
+
+func (m *MemoryIdempotencyStore) Extend(_ context.Context, key string, ttl time.Duration) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if entry, found := m.entries[key]; found && !entry.record.Completed {
+		entry.expiresAt = time.Now().Add(ttl)
+		m.entries[key] = entry
+	}
+	return nil
+}
+
+func (m *MemoryIdempotencyStore) Complete(_ context.Context, key string, response Response, ttl time.Duration) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry := m.entries[key]
+	entry.record.Completed = true
+	entry.record.Response = cloneResponse(response)
+	entry.expiresAt = time.Now().Add(ttl)
+	m.entries[key] = entry
+	return nil
+}
+
+func (m *MemoryIdempotencyStore) Abort(_ context.Context, key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *MemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Before(m.nextSweep) {
+		return
+	}
+	for key, entry := range m.entries {
+		if now.After(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	m.nextSweep = now.Add(memoryIdempotencySweepInterval)
+}
+
+// cloneResponse copies the status, headers, cookies and body, so the stored response is not changed by the
+// middlewares that run after the store
+func cloneResponse(response Response) Response {
+	clone := Response{Body: slices.Clone(response.Body)}
+	if response.StatusCode != nil {
+		statusCode := *response.StatusCode
+		clone.StatusCode = &statusCode
+	}
+	if response.Header != nil {
+		clone.Header = make(map[string][]string, len(response.Header))
+		for name, values := range response.Header {
+			clone.Header[name] = slices.Clone(values)
+		}
+	}
+	for _, cookie := range response.Cookies {
+		copied := *cookie
+		clone.Cookies = append(clone.Cookies, &copied)
+	}
+	return clone
+}
+
+var _ IdempotencyStore = (*MemoryIdempotencyStore)(nil)
@@ -14,6 +14,9 @@ type (
 	// LoggerToContext is used by the adapter to add to the context a new logger with labels to be
 	// printed in all log lines, like XID
 	LoggerToContext = func(ctx context.Context, current *slog.Logger) context.Context
+
+	// handleLogKey marks the context given to the provider by the log middleware, which adds the request ID itself
+	handleLogKey struct{}
 )
 
 var (
@@ -21,11 +24,16 @@ var (
 	DefaultLoggerToContext = defaultLoggerToContext
 )
 
+// defaultLoggerProvider returns the logger in the context or, if there is none, the default logger with the request
+// ID added by the middleware created by NewRequestIDMiddleware
 func defaultLoggerProvider(ctx context.Context) *slog.Logger {
 	ctxLogger, ok := ctx.Value(LoggerKey{}).(*slog.Logger)
 	if ok {
 		return ctxLogger
 	}
+	if requestID, found := RequestIDFromContext(ctx); found && ctx.Value(handleLogKey{}) == nil {
+		return slog.Default().With(slog.String("xid", requestID))
+	}
 	return slog.Default()
 }
 
@@ -1,14 +1,80 @@
 package httpadpt
 
+import (
+	"context"
+	"strings"
+)
+
 type (
 	Middleware = func(next Handler) Handler
 
+	// Middlewares is an ordered list of middlewares, the first one is the outermost, so it is the first to see the
+	// request and the last to see the response.
 	Middlewares = []Middleware
+
+	// MiddlewareGroup applies the Middlewares to the bindings whose path starts with PathPrefix. The prefix can
+	// end with "/*", so "/admin/*" and "/admin" both match "/admin" and "/admin/users" but not "/administrator".
+	MiddlewareGroup struct {
+		PathPrefix string
+		Middlewares
+	}
+
+	MiddlewareGroups []MiddlewareGroup
+
+	// RouteKey is used to add the binding path template to the Request
+	RouteKey struct{}
 )
 
+// WrapHandlerWithMiddlewares wraps the handler with the middlewares, the first middleware is the outermost one.
 func WrapHandlerWithMiddlewares(handler Handler, middlewares Middlewares) Handler {
-	for _, middleware := range middlewares {
-		handler = middleware(handler)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
 	}
 	return handler
 }
+
+// Matches returns true if the path is the group prefix or is below it
+func (g MiddlewareGroup) Matches(path string) bool {
+	prefix := strings.TrimSuffix(strings.TrimSuffix(g.PathPrefix, "*"), "/")
+	if prefix == "" {
+		return true
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// WrapBindingHandler returns the binding handler wrapped with the middlewares that apply to it, from the outermost
+// to the innermost: Config.Middlewares, the Config.MiddlewareGroups matching the binding path in the configured
+// order, and then Binding.Middlewares. The binding path is added to the Request, see RouteFromRequest, and the
+// Config.CookieKeyRing, if set, is given to the binding handler by NewCookieKeyRingMiddleware.
+func WrapBindingHandler(binding Binding, config Config) Handler {
+	middlewares := append(Middlewares{}, config.Middlewares...)
+	if binding.Condition.Path != nil {
+		for _, group := range config.MiddlewareGroups {
+			if group.Matches(*binding.Condition.Path) {
+				middlewares = append(middlewares, group.Middlewares...)
+			}
+		}
+	}
+	middlewares = append(middlewares, binding.Middlewares...)
+	if config.CookieKeyRing != nil {
+		middlewares = append(middlewares, NewCookieKeyRingMiddleware(config.CookieKeyRing))
+	}
+	handler := WrapHandlerWithMiddlewares(binding.Handler, middlewares)
+	if binding.Condition.Path == nil {
+		return handler
+	}
+	route := *binding.Condition.Path
+	return MakeHandler(func(ctx context.Context, input Request, output *Response) error {
+		return handler.Invoke(ctx, WithRequestValue(input, RouteKey{}, route), output)
+	})
+}
+
+// RouteFromRequest returns the path template of the binding that handles the request, like "/orders/{id}"
+func RouteFromRequest(input Request) (string, bool) {
+	value, found := GetRequestValue(input, RouteKey{})
+	if !found {
+		return "", false
+	}
+	route, found := value.(string)
+	return route, found
+}
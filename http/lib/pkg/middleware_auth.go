@@ -0,0 +1,70 @@
+package httpadpt
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	HeaderAuthorization   = "Authorization"
+	HeaderWWWAuthenticate = "Www-Authenticate"
+)
+
+type (
+	// AuthConfig configures the middleware created by NewAuthMiddleware
+	AuthConfig struct {
+		// Authenticators are tried in order, the first one that finds credentials in the request authenticates it
+		Authenticators []Authenticator
+
+		// Authorize is optional, it is invoked after the authentication and the error it returns should wrap
+		// ErrForbidden, see RequireScopes.
+		Authorize func(ctx context.Context, principal Principal, input Request) error
+	}
+
+	authMiddleware struct {
+		config    AuthConfig
+		decorated Handler
+	}
+)
+
+// NewAuthMiddleware creates a middleware that authenticates the requests using the configured schemes. The
+// authenticated Principal is added to the context and to the Request, so the handlers can receive it using the
+// principal and claim input tags. The failures are written by OutErrorParamSpec as 401 or 403 problem responses.
+// To choose the schemes per binding, decorate the binding Handler with the middleware.
+func NewAuthMiddleware(config AuthConfig) Middleware {
+	return func(next Handler) Handler {
+		return authMiddleware{config: config, decorated: next}
+	}
+}
+
+func (a authMiddleware) Invoke(ctx context.Context, input Request, output *Response) error {
+	principal, err := a.authenticate(ctx, input)
+	if err == nil && a.config.Authorize != nil {
+		err = asForbidden(a.config.Authorize(ctx, principal, input))
+	}
+	if err != nil {
+		if output == nil {
+			return err
+		}
+		if errors.Is(err, ErrUnauthorized) {
+			for _, authenticator := range a.config.Authenticators {
+				addHeaderValue(output, HeaderWWWAuthenticate, authenticator.Challenge())
+			}
+		}
+		return NewOutErrorParamSpec().SetValue(output, err)
+	}
+
+	ctx = PrincipalToContext(ctx, principal)
+	return a.decorated.Invoke(ctx, WithRequestValue(input, PrincipalKey{}, principal), output)
+}
+
+func (a authMiddleware) authenticate(ctx context.Context, input Request) (Principal, error) {
+	for _, authenticator := range a.config.Authenticators {
+		principal, err := authenticator.Authenticate(ctx, input)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return principal, asUnauthorized(err)
+	}
+	return Principal{}, ErrNoCredentials
+}
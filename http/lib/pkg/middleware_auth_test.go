@@ -0,0 +1,115 @@
+package httpadpt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestAuthMiddleware(authorize func(ctx context.Context, principal Principal, input Request) error) (Middleware, []byte) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	return NewAuthMiddleware(AuthConfig{
+		Authenticators: []Authenticator{
+			NewAPIKeyAuthenticator(APIKeyConfig{Lookup: APIKeys(map[string]Principal{"key-1": {Subject: "service-a"}})}),
+			NewJWTAuthenticator(JWTConfig{KeySet: JWKS{Keys: []JWK{{Key: secret}}}, Realm: "api"}),
+		},
+		Authorize: authorize,
+	}), secret
+}
+
+func Test_authMiddleware_Unauthorized(t *testing.T) {
+	middleware, _ := newTestAuthMiddleware(nil)
+	invoked := false
+	handler := middleware(MakeHandler(func(context.Context, Request, *Response) error {
+		invoked = true
+		return nil
+	}))
+
+	for name, input := range map[string]Request{
+		"no credentials": &mockRequest{header: &mockHeaderParams{}, query: &mockQueryParams{}},
+		"invalid token":  newBearerRequest("a.b.c"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			output := &Response{}
+			if err := handler.Invoke(context.Background(), input, output); err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if invoked {
+				t.Error("the decorated handler was invoked")
+			}
+			if *output.StatusCode != http.StatusUnauthorized {
+				t.Errorf("StatusCode = %d, want 401", *output.StatusCode)
+			}
+			if got := output.Header[HeaderWWWAuthenticate]; !reflect.DeepEqual(got, []string{"APIKey", `Bearer realm="api"`}) {
+				t.Errorf("WWW-Authenticate = %v", got)
+			}
+			var problem ProblemDetail
+			if err := json.Unmarshal(output.Body, &problem); err != nil || problem.Status != "401" {
+				t.Errorf("Body = %s, want a 401 problem detail", output.Body)
+			}
+			if output.Header["Content-Type"][0] != ContentTypeProblemDetail {
+				t.Errorf("Content-Type = %v", output.Header["Content-Type"])
+			}
+		})
+	}
+}
+
+func Test_authMiddleware_Forbidden(t *testing.T) {
+	middleware, secret := newTestAuthMiddleware(RequireScopes("orders:write"))
+	handler := middleware(MakeHandler(func(context.Context, Request, *Response) error { return nil }))
+
+	token := signTestJWT(t, map[string]any{"alg": "HS256"}, map[string]any{"sub": "user-1", "scope": "orders:read", "exp": time.Now().Add(time.Minute).Unix()}, secret)
+	output := &Response{}
+	if err := handler.Invoke(context.Background(), newBearerRequest(token), output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if *output.StatusCode != http.StatusForbidden {
+		t.Errorf("StatusCode = %d, want 403", *output.StatusCode)
+	}
+	if _, found := output.Header[HeaderWWWAuthenticate]; found {
+		t.Error("WWW-Authenticate must not be sent with 403")
+	}
+}
+
+func Test_authMiddleware_PrincipalInjection(t *testing.T) {
+	middleware, secret := newTestAuthMiddleware(RequireScopes("orders:read"))
+	type handlerInput struct {
+		Principal Principal `principal:""`
+		Subject   string    `claim:"sub"`
+		Roles     []string  `claim:"roles"`
+	}
+	type handlerOutput struct {
+		Body string `body:""`
+	}
+	var (
+		got        handlerInput
+		ctxSubject string
+	)
+	binding := NewBindingBuilderUsingPath("/orders").WithMethods("GET").
+		WithHandlerFunc(func(ctx context.Context, input handlerInput) (*handlerOutput, error) {
+			got = input
+			principal, _ := PrincipalFromContext(ctx)
+			ctxSubject = principal.Subject
+			return &handlerOutput{Body: "ok"}, nil
+		})
+	handler := middleware(binding.Handler)
+
+	claims := map[string]any{"sub": "user-1", "scope": "orders:read", "roles": []string{"admin", "user"}, "exp": time.Now().Add(time.Minute).Unix()}
+	token := signTestJWT(t, map[string]any{"alg": "HS256"}, claims, secret)
+	output := &Response{}
+	if err := handler.Invoke(context.Background(), newBearerRequest(token), output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if string(output.Body) != "ok" {
+		t.Fatalf("Body = %s", output.Body)
+	}
+	if got.Principal.Subject != "user-1" || got.Subject != "user-1" || ctxSubject != "user-1" {
+		t.Errorf("principal = %+v, subject = %q, context subject = %q", got.Principal, got.Subject, ctxSubject)
+	}
+	if !reflect.DeepEqual(got.Roles, []string{"admin", "user"}) {
+		t.Errorf("roles = %v, want [admin user]", got.Roles)
+	}
+}
@@ -0,0 +1,282 @@
+package httpadpt
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	HeaderAcceptEncoding  = "Accept-Encoding"
+	HeaderContentEncoding = "Content-Encoding"
+	HeaderContentLength   = "Content-Length"
+	HeaderContentType     = "Content-Type"
+
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+
+	// DefaultCompressionMinSize is the minimum body size compressed when CompressionConfig.MinSize is zero
+	DefaultCompressionMinSize = 1024
+)
+
+// DefaultCompressibleContentTypes is used when CompressionConfig.ContentTypes is empty
+var DefaultCompressibleContentTypes = []string{
+	"text/*",
+	"application/json",
+	"application/*+json",
+	"application/xml",
+	"application/*+xml",
+	"application/javascript",
+	"image/svg+xml",
+}
+
+type (
+	// CompressionEncoder compresses the response bodies with the content coding named by Encoding
+	CompressionEncoder struct {
+		Encoding  string
+		NewWriter func(w io.Writer) (io.WriteCloser, error)
+	}
+
+	// CompressionConfig configures the middleware created by NewCompressionMiddleware
+	CompressionConfig struct {
+		// Encoders in the server preference order, used when the client accepts several with the same quality.
+		// It is gzip and deflate if empty, other encoders like zstd can be provided by other modules.
+		Encoders []CompressionEncoder
+
+		// MinSize is the minimum body size compressed, zero means DefaultCompressionMinSize
+		MinSize int
+
+		// ContentTypes are the media types compressed, "text/*" matches any subtype and "application/*+json" any
+		// subtype with the suffix. It is DefaultCompressibleContentTypes if empty.
+		ContentTypes []string
+
+		// DecodeRequests makes the gzip and deflate request bodies be decompressed before the form is parsed
+		DecodeRequests bool
+	}
+
+	// BodyWrapper is implemented by the Request implementations that allow the middlewares to replace the body reader
+	BodyWrapper interface {
+		WrapBody(wrap func(body io.ReadCloser) (io.ReadCloser, error)) error
+	}
+
+	compressionMiddleware struct {
+		config    CompressionConfig
+		decorated Handler
+	}
+
+	// decodedBody closes both the decoder and the original body
+	decodedBody struct {
+		io.ReadCloser
+		body io.Closer
+	}
+)
+
+// GzipEncoder compresses with gzip using the given compress/gzip level
+func GzipEncoder(level int) CompressionEncoder {
+	return CompressionEncoder{Encoding: EncodingGzip, NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(w, level)
+	}}
+}
+
+// DeflateEncoder compresses with the zlib format, which is what the deflate content coding means, using the given
+// compress/zlib level
+func DeflateEncoder(level int) CompressionEncoder {
+	return CompressionEncoder{Encoding: EncodingDeflate, NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return zlib.NewWriterLevel(w, level)
+	}}
+}
+
+// NewCompressionMiddleware creates a middleware that compresses the response body with the best encoding accepted
+// by the client in the Accept-Encoding header. The responses with a compressible content type get the
+// Vary: Accept-Encoding header, and they are not compressed if they are smaller than the MinSize, if they already
+// have a Content-Encoding or if they are streamed. The strong ETag of the compressed responses becomes weak.
+func NewCompressionMiddleware(config CompressionConfig) Middleware {
+	if len(config.Encoders) == 0 {
+		config.Encoders = []CompressionEncoder{GzipEncoder(gzip.DefaultCompression), DeflateEncoder(zlib.DefaultCompression)}
+	}
+	for _, encoder := range config.Encoders {
+		if encoder.Encoding == "" || encoder.NewWriter == nil {
+			panic(fmt.Errorf("httpadpt.NewCompressionMiddleware: invalid encoder %q", encoder.Encoding))
+		}
+	}
+	if config.MinSize <= 0 {
+		config.MinSize = DefaultCompressionMinSize
+	}
+	if len(config.ContentTypes) == 0 {
+		config.ContentTypes = DefaultCompressibleContentTypes
+	}
+	return func(next Handler) Handler {
+		return compressionMiddleware{config: config, decorated: next}
+	}
+}
+
+func (c compressionMiddleware) Invoke(ctx context.Context, input Request, output *Response) error {
+	if c.config.DecodeRequests {
+		if err := decodeRequestBody(input); err != nil {
+			if output == nil {
+				return err
+			}
+			return NewOutErrorParamSpec().SetValue(output, err)
+		}
+	}
+	if err := c.decorated.Invoke(ctx, input, output); err != nil || output == nil {
+		return err
+	}
+	if output.IsStreaming() || !c.isCompressible(getResponseHeader(output, HeaderContentType)) {
+		return nil
+	}
+	addHeaderValue(output, HeaderVary, HeaderAcceptEncoding)
+	if len(output.Body) < c.config.MinSize || getResponseHeader(output, HeaderContentEncoding) != "" {
+		return nil
+	}
+	encoder, found := c.selectEncoder(getHeaderValues(input, HeaderAcceptEncoding))
+	if !found {
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	writer, err := encoder.NewWriter(&buffer)
+	if err == nil {
+		if _, err = writer.Write(output.Body); err == nil {
+			err = writer.Close()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("httpadpt.compressionMiddleware: %s: %w", encoder.Encoding, err)
+	}
+	output.Body = buffer.Bytes()
+	deleteResponseHeader(output, HeaderContentLength)
+	setHeaderValue(output, HeaderContentEncoding, encoder.Encoding)
+	weakenETag(output)
+	return nil
+}
+
+// weakenETag turns the strong ETag into a weak one, because the compressed body is not byte equal to the one the
+// ETag was computed for
+func weakenETag(output *Response) {
+	if etag := getResponseHeader(output, HeaderETag); strings.HasPrefix(etag, `"`) {
+		deleteResponseHeader(output, HeaderETag)
+		setHeaderValue(output, HeaderETag, "W/"+etag)
+	}
+}
+
+func (c compressionMiddleware) isCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(c.config.ContentTypes, func(pattern string) bool {
+		return matchesMediaType(pattern, mediaType)
+	})
+}
+
+// matchesMediaType accepts the exact media type, "type/*" and "type/*+suffix" patterns
+func matchesMediaType(pattern, mediaType string) bool {
+	pattern = strings.ToLower(pattern)
+	prefix, suffix, wildcard := strings.Cut(pattern, "*")
+	if !wildcard {
+		return pattern == mediaType
+	}
+	return len(mediaType) > len(prefix)+len(suffix) && strings.HasPrefix(mediaType, prefix) &&
+		strings.HasSuffix(mediaType, suffix)
+}
+
+// selectEncoder returns the encoder with the highest quality in the Accept-Encoding values, the ties are solved by
+// the Encoders order. The encodings not listed get the quality of "*", if present.
+func (c compressionMiddleware) selectEncoder(acceptEncoding []string) (CompressionEncoder, bool) {
+	qualities := parseAcceptEncoding(acceptEncoding)
+	var (
+		best        CompressionEncoder
+		bestQuality float64
+	)
+	for _, encoder := range c.config.Encoders {
+		quality, found := qualities[strings.ToLower(encoder.Encoding)]
+		if !found {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoder, quality
+		}
+	}
+	return best, bestQuality > 0
+}
+
+func parseAcceptEncoding(values []string) map[string]float64 {
+	qualities := map[string]float64{}
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			coding, params, _ := strings.Cut(item, ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding == "" {
+				continue
+			}
+			quality := 1.0
+			if name, q, found := strings.Cut(strings.TrimSpace(params), "="); found && strings.TrimSpace(name) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(q), 64); err == nil {
+					quality = parsed
+				}
+			}
+			qualities[coding] = quality
+		}
+	}
+	return qualities
+}
+
+// decodeRequestBody replaces the gzip and deflate request bodies by their decompressed content
+func decodeRequestBody(input Request) error {
+	encoding := strings.ToLower(strings.TrimSpace(getFirstHeaderValue(input, HeaderContentEncoding)))
+	var newReader func(io.Reader) (io.ReadCloser, error)
+	switch encoding {
+	case EncodingGzip:
+		newReader = func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+	case EncodingDeflate:
+		newReader = zlib.NewReader
+	default:
+		return nil
+	}
+	wrapper, ok := input.(BodyWrapper)
+	if !ok {
+		return nil
+	}
+	return wrapper.WrapBody(func(body io.ReadCloser) (io.ReadCloser, error) {
+		reader, err := newReader(body)
+		if err != nil {
+			return nil, serror.IllegalArgumentValueWithCause(HeaderContentEncoding, encoding, err)
+		}
+		return decodedBody{ReadCloser: reader, body: body}, nil
+	})
+}
+
+func (d decodedBody) Close() error {
+	err := d.ReadCloser.Close()
+	if bodyErr := d.body.Close(); err == nil {
+		err = bodyErr
+	}
+	return err
+}
+
+func getResponseHeader(output *Response, headerName string) string {
+	for name, values := range output.Header {
+		if strings.EqualFold(name, headerName) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+func deleteResponseHeader(output *Response, headerName string) {
+	for name := range output.Header {
+		if strings.EqualFold(name, headerName) {
+			delete(output.Header, name)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package httpadpt
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+var testCompressionBody = []byte(strings.Repeat(`{"name":"value"},`, 100))
+
+type bodyWrapperRequest struct {
+	mockRequest
+	body io.ReadCloser
+}
+
+func (b *bodyWrapperRequest) WrapBody(wrap func(body io.ReadCloser) (io.ReadCloser, error)) error {
+	body, err := wrap(b.body)
+	if err == nil {
+		b.body = body
+	}
+	return err
+}
+
+func (b *bodyWrapperRequest) Body() io.Reader {
+	return b.body
+}
+
+func invokeCompression(t *testing.T, config CompressionConfig, acceptEncoding string, response Response) *Response {
+	t.Helper()
+	handler := NewCompressionMiddleware(config)(MakeHandler(func(_ context.Context, _ Request, output *Response) error {
+		*output = response
+		return nil
+	}))
+	headers := map[string][]string{}
+	if acceptEncoding != "" {
+		headers[HeaderAcceptEncoding] = []string{acceptEncoding}
+	}
+	output := &Response{}
+	if err := handler.Invoke(context.Background(), &mockRequest{header: &mockHeaderParams{values: headers}}, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	return output
+}
+
+func jsonResponse(body []byte) Response {
+	return Response{Header: map[string][]string{"content-type": {"application/json; charset=utf-8"}}, Body: body}
+}
+
+func Test_compressionMiddleware_Negotiation(t *testing.T) {
+	tests := []struct {
+		name             string
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{name: "gzip", acceptEncoding: "gzip", expectedEncoding: EncodingGzip},
+		{name: "server preference on ties", acceptEncoding: "deflate, gzip", expectedEncoding: EncodingGzip},
+		{name: "client quality", acceptEncoding: "gzip;q=0.5, deflate", expectedEncoding: EncodingDeflate},
+		{name: "wildcard", acceptEncoding: "*", expectedEncoding: EncodingGzip},
+		{name: "excluded by q=0", acceptEncoding: "gzip;q=0, *;q=0.1", expectedEncoding: EncodingDeflate},
+		{name: "unsupported", acceptEncoding: "br", expectedEncoding: ""},
+		{name: "no header", acceptEncoding: "", expectedEncoding: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := invokeCompression(t, CompressionConfig{}, tt.acceptEncoding, jsonResponse(testCompressionBody))
+			if got := getResponseHeader(output, HeaderContentEncoding); got != tt.expectedEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.expectedEncoding)
+			}
+			if got := output.Header[HeaderVary]; len(got) != 1 || got[0] != HeaderAcceptEncoding {
+				t.Errorf("Vary = %v", got)
+			}
+
+			var reader io.Reader = bytes.NewReader(output.Body)
+			switch tt.expectedEncoding {
+			case EncodingGzip:
+				reader, _ = gzip.NewReader(reader)
+			case EncodingDeflate:
+				reader, _ = zlib.NewReader(reader)
+			}
+			if body, err := io.ReadAll(reader); err != nil || !bytes.Equal(body, testCompressionBody) {
+				t.Errorf("decompressed body differs, err = %v", err)
+			}
+		})
+	}
+}
+
+func Test_compressionMiddleware_Skips(t *testing.T) {
+	precompressed := jsonResponse(testCompressionBody)
+	precompressed.Header[HeaderContentEncoding] = []string{"br"}
+
+	tests := []struct {
+		name       string
+		config     CompressionConfig
+		response   Response
+		expectVary bool
+	}{
+		{name: "small body", response: jsonResponse([]byte(`{}`)), expectVary: true},
+		{name: "already encoded", response: precompressed, expectVary: true},
+		{
+			name:     "content type not allowed",
+			response: Response{Header: map[string][]string{HeaderContentType: {"image/png"}}, Body: testCompressionBody},
+		},
+		{
+			name:     "content type not in the custom list",
+			config:   CompressionConfig{ContentTypes: []string{"text/*"}},
+			response: jsonResponse(testCompressionBody),
+		},
+		{
+			name:     "streaming",
+			response: Response{Header: map[string][]string{HeaderContentType: {"text/plain"}}, BodyStream: strings.NewReader("x")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := invokeCompression(t, tt.config, "gzip", tt.response)
+			if got := getResponseHeader(output, HeaderContentEncoding); got == EncodingGzip {
+				t.Errorf("the response should not be compressed")
+			}
+			if _, found := output.Header[HeaderVary]; found != tt.expectVary {
+				t.Errorf("Vary found = %v, want %v", found, tt.expectVary)
+			}
+		})
+	}
+}
+
+func Test_compressionMiddleware_DecodeRequests(t *testing.T) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, _ = writer.Write([]byte("a=1&b=2"))
+	_ = writer.Close()
+
+	handler := NewCompressionMiddleware(CompressionConfig{DecodeRequests: true})(MakeHandler(
+		func(_ context.Context, _ Request, _ *Response) error { return nil }))
+
+	input := &bodyWrapperRequest{
+		mockRequest: mockRequest{header: &mockHeaderParams{values: map[string][]string{HeaderContentEncoding: {"gzip"}}}},
+		body:        io.NopCloser(&compressed),
+	}
+	if err := handler.Invoke(context.Background(), input, &Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if body, _ := io.ReadAll(input.body); string(body) != "a=1&b=2" {
+		t.Errorf("decoded body = %q", body)
+	}
+
+	input.body = io.NopCloser(strings.NewReader("not gzip"))
+	output := &Response{}
+	if err := handler.Invoke(context.Background(), input, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if output.StatusCode == nil || *output.StatusCode != 400 {
+		t.Errorf("status code = %v, want 400", output.StatusCode)
+	}
+}
+
+func Test_compressionMiddleware_EncoderError(t *testing.T) {
+	failing := CompressionEncoder{Encoding: "fail", NewWriter: func(io.Writer) (io.WriteCloser, error) {
+		return nil, errors.New("boom")
+	}}
+	handler := NewCompressionMiddleware(CompressionConfig{Encoders: []CompressionEncoder{failing}})(MakeHandler(
+		func(_ context.Context, _ Request, output *Response) error {
+			*output = jsonResponse(testCompressionBody)
+			return nil
+		}))
+	input := &mockRequest{header: &mockHeaderParams{values: map[string][]string{HeaderAcceptEncoding: {"fail"}}}}
+	if err := handler.Invoke(context.Background(), input, &Response{}); err == nil {
+		t.Error("Invoke() should return the encoder error")
+	}
+}
+
+func Test_compressionMiddleware_NilOutput(t *testing.T) {
+	handler := NewCompressionMiddleware(CompressionConfig{DecodeRequests: true})(MakeHandler(
+		func(context.Context, Request, *Response) error { return nil }))
+	input := &mockRequest{header: &mockHeaderParams{values: map[string][]string{HeaderAcceptEncoding: {"gzip"}}}}
+	if err := handler.Invoke(context.Background(), input, nil); err != nil {
+		t.Errorf("Invoke() error = %v", err)
+	}
+
+	invalid := &bodyWrapperRequest{
+		mockRequest: mockRequest{header: &mockHeaderParams{values: map[string][]string{HeaderContentEncoding: {"gzip"}}}},
+		body:        io.NopCloser(strings.NewReader("not gzip")),
+	}
+	if err := handler.Invoke(context.Background(), invalid, nil); err == nil {
+		t.Error("Invoke() should return the decoding error")
+	}
+}
+
+func Test_compressionMiddleware_WeakensETag(t *testing.T) {
+	response := jsonResponse(testCompressionBody)
+	response.Header[HeaderETag] = []string{`"v1"`}
+	output := invokeCompression(t, CompressionConfig{}, "gzip", response)
+	if got := getResponseHeader(output, HeaderETag); got != `W/"v1"` {
+		t.Errorf("ETag = %q, want the weak one", got)
+	}
+}
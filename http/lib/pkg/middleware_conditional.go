@@ -0,0 +1,238 @@
+package httpadpt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderCacheControl      = "Cache-Control"
+	HeaderIfMatch           = "If-Match"
+	HeaderIfNoneMatch       = "If-None-Match"
+	HeaderIfModifiedSince   = "If-Modified-Since"
+	HeaderIfUnmodifiedSince = "If-Unmodified-Since"
+)
+
+// ErrPreconditionFailed is wrapped by the error of the requests whose preconditions do not hold, it is mapped to 412
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+type (
+	// Validators are the entity tag and the modification time of the resource targeted by a request
+	Validators struct {
+		ETag         string
+		LastModified time.Time
+	}
+
+	// ConditionalConfig configures the middleware created by NewConditionalMiddleware
+	ConditionalConfig struct {
+		// GenerateETag makes the 200 responses of GET and HEAD without an ETag get a strong one computed from the body
+		GenerateETag bool
+
+		// Current returns the validators of the resource before an unsafe method, like PUT or DELETE, is invoked, so
+		// If-Match, If-None-Match and If-Unmodified-Since are evaluated before the resource is changed. Found is false
+		// if the resource does not exist. If Current is nil, the preconditions of the unsafe methods are ignored.
+		Current func(ctx context.Context, input Request) (validators Validators, found bool, err error)
+	}
+
+	conditionalMiddleware struct {
+		config    ConditionalConfig
+		decorated Handler
+	}
+
+	cacheControlMiddleware struct {
+		value     string
+		decorated Handler
+	}
+
+	entityTag struct {
+		weak   bool
+		opaque string
+	}
+)
+
+// NewConditionalMiddleware creates a middleware that evaluates the conditional request headers. The GET and HEAD
+// requests get 304 Not Modified when If-None-Match or If-Modified-Since match the ETag and Last-Modified of the
+// response, set with the etag and lastmodified output tags, and 412 Precondition Failed when If-Match or
+// If-Unmodified-Since do not. The other methods get 412 before the handler is invoked, see ConditionalConfig.Current.
+func NewConditionalMiddleware(config ConditionalConfig) Middleware {
+	return func(next Handler) Handler {
+		return conditionalMiddleware{config: config, decorated: next}
+	}
+}
+
+// NewCacheControlMiddleware creates a middleware that sets the Cache-Control header of the 2xx and 304 responses that
+// do not have one. Add it to the Binding.Middlewares to configure the caching of each binding.
+func NewCacheControlMiddleware(value string) Middleware {
+	return func(next Handler) Handler {
+		return cacheControlMiddleware{value: value, decorated: next}
+	}
+}
+
+func (c cacheControlMiddleware) Invoke(ctx context.Context, input Request, output *Response) error {
+	if err := c.decorated.Invoke(ctx, input, output); err != nil || output == nil {
+		return err
+	}
+	statusCode := getStatusCode(output)
+	if (statusCode/100 == 2 || statusCode == http.StatusNotModified) && getResponseHeader(output, HeaderCacheControl) == "" {
+		setHeaderValue(output, HeaderCacheControl, c.value)
+	}
+	return nil
+}
+
+func (c conditionalMiddleware) Invoke(ctx context.Context, input Request, output *Response) error {
+	if method := GetMethod(input); method != http.MethodGet && method != http.MethodHead {
+		if err := c.checkBeforeChange(ctx, input); err != nil {
+			if output == nil {
+				return err
+			}
+			return NewOutErrorParamSpec().SetValue(output, err)
+		}
+		return c.decorated.Invoke(ctx, input, output)
+	}
+
+	if err := c.decorated.Invoke(ctx, input, output); err != nil || output == nil {
+		return err
+	}
+	if getStatusCode(output) != http.StatusOK || output.IsStreaming() {
+		return nil
+	}
+	if c.config.GenerateETag && getResponseHeader(output, HeaderETag) == "" && output.Body != nil {
+		setHeaderValue(output, HeaderETag, GenerateETag(output.Body))
+	}
+
+	current := Validators{ETag: getResponseHeader(output, HeaderETag)}
+	current.LastModified, _ = http.ParseTime(getResponseHeader(output, HeaderLastModified))
+	if err := checkIfMatch(input, current, true); err != nil {
+		*output = Response{}
+		return NewOutErrorParamSpec().SetValue(output, err)
+	}
+	if isNotModified(input, current) {
+		setNotModified(output)
+	}
+	return nil
+}
+
+// checkBeforeChange evaluates the preconditions of the unsafe methods against the current validators
+func (c conditionalMiddleware) checkBeforeChange(ctx context.Context, input Request) error {
+	ifNoneMatch := getFirstHeaderValue(input, HeaderIfNoneMatch)
+	if c.config.Current == nil || (ifNoneMatch == "" && getFirstHeaderValue(input, HeaderIfMatch) == "" &&
+		getFirstHeaderValue(input, HeaderIfUnmodifiedSince) == "") {
+		return nil
+	}
+	current, found, err := c.config.Current(ctx, input)
+	if err != nil {
+		return err
+	}
+	if err := checkIfMatch(input, current, found); err != nil {
+		return err
+	}
+	if ifNoneMatch != "" && found && matchesETags(ifNoneMatch, current.ETag, false) {
+		return errPreconditionFailed(HeaderIfNoneMatch)
+	}
+	return nil
+}
+
+// checkIfMatch evaluates If-Match or, if it is absent, If-Unmodified-Since
+func checkIfMatch(input Request, current Validators, found bool) error {
+	if ifMatch := getFirstHeaderValue(input, HeaderIfMatch); ifMatch != "" {
+		if !found || !matchesETags(ifMatch, current.ETag, true) {
+			return errPreconditionFailed(HeaderIfMatch)
+		}
+		return nil
+	}
+	since, err := http.ParseTime(getFirstHeaderValue(input, HeaderIfUnmodifiedSince))
+	if err == nil && found && !current.LastModified.IsZero() && current.LastModified.Truncate(time.Second).After(since) {
+		return errPreconditionFailed(HeaderIfUnmodifiedSince)
+	}
+	return nil
+}
+
+// isNotModified evaluates If-None-Match or, if it is absent, If-Modified-Since
+func isNotModified(input Request, current Validators) bool {
+	if ifNoneMatch := getFirstHeaderValue(input, HeaderIfNoneMatch); ifNoneMatch != "" {
+		return matchesETags(ifNoneMatch, current.ETag, false)
+	}
+	since, err := http.ParseTime(getFirstHeaderValue(input, HeaderIfModifiedSince))
+	return err == nil && !current.LastModified.IsZero() && !current.LastModified.Truncate(time.Second).After(since)
+}
+
+func errPreconditionFailed(headerName string) error {
+	return fmt.Errorf("%w: %s does not match the current resource", ErrPreconditionFailed, headerName)
+}
+
+// setNotModified replaces the response by a 304 keeping only the headers that describe the cached response
+func setNotModified(output *Response) {
+	statusCode := http.StatusNotModified
+	output.StatusCode = &statusCode
+	output.Body = nil
+	deleteResponseHeader(output, HeaderContentType)
+	deleteResponseHeader(output, HeaderContentLength)
+}
+
+func getStatusCode(output *Response) int {
+	if output.StatusCode == nil {
+		return http.StatusOK
+	}
+	return *output.StatusCode
+}
+
+// GenerateETag returns a strong entity tag computed from the SHA-256 of the body
+func GenerateETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// matchesETags returns true if the header is "*", the callers check the resource exists, or if one of the header entity
+// tags matches the current one using the strong or the weak comparison
+func matchesETags(header, current string, strong bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	if current == "" {
+		return false
+	}
+	currentTag, ok := parseETag(current)
+	if !ok {
+		return false
+	}
+	for _, tag := range parseETags(header) {
+		if tag.opaque == currentTag.opaque && (!strong || (!tag.weak && !currentTag.weak)) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseETags(header string) []entityTag {
+	var tags []entityTag
+	for rest := strings.TrimSpace(header); rest != ""; {
+		weak := strings.HasPrefix(rest, "W/")
+		if weak {
+			rest = rest[2:]
+		}
+		if !strings.HasPrefix(rest, `"`) {
+			return tags
+		}
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return tags
+		}
+		tags = append(tags, entityTag{weak: weak, opaque: rest[1 : end+1]})
+		rest = strings.TrimLeft(rest[end+2:], " \t,")
+	}
+	return tags
+}
+
+func parseETag(value string) (entityTag, bool) {
+	tags := parseETags(value)
+	if len(tags) != 1 {
+		return entityTag{}, false
+	}
+	return tags[0], true
+}
@@ -0,0 +1,177 @@
+package httpadpt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const testLastModified = "Fri, 01 Mar 2024 13:30:00 GMT"
+
+func newConditionalRequest(method string, headers map[string][]string) *mockRequest {
+	return &mockRequest{method: method, header: &mockHeaderParams{values: headers}}
+}
+
+func invokeConditional(t *testing.T, config ConditionalConfig, input Request, response Response) (*Response, bool) {
+	t.Helper()
+	invoked := false
+	handler := NewConditionalMiddleware(config)(MakeHandler(func(_ context.Context, _ Request, output *Response) error {
+		invoked = true
+		*output = response
+		return nil
+	}))
+	output := &Response{}
+	if err := handler.Invoke(context.Background(), input, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	return output, invoked
+}
+
+func Test_conditionalMiddleware_Get(t *testing.T) {
+	response := Response{
+		Header: map[string][]string{
+			HeaderETag:         {`"v2"`},
+			HeaderLastModified: {testLastModified},
+			HeaderContentType:  {"application/json"},
+		},
+		Body: []byte(`{"id":1}`),
+	}
+	tests := []struct {
+		name           string
+		headers        map[string][]string
+		expectedStatus int
+	}{
+		{name: "no conditions", expectedStatus: http.StatusOK},
+		{name: "If-None-Match matches", headers: map[string][]string{HeaderIfNoneMatch: {`"v1", W/"v2"`}}, expectedStatus: http.StatusNotModified},
+		{name: "If-None-Match star", headers: map[string][]string{HeaderIfNoneMatch: {"*"}}, expectedStatus: http.StatusNotModified},
+		{name: "If-None-Match differs", headers: map[string][]string{HeaderIfNoneMatch: {`"v1"`}}, expectedStatus: http.StatusOK},
+		{
+			name:           "If-None-Match takes precedence over If-Modified-Since",
+			headers:        map[string][]string{HeaderIfNoneMatch: {`"v1"`}, HeaderIfModifiedSince: {testLastModified}},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "not modified since", headers: map[string][]string{HeaderIfModifiedSince: {testLastModified}}, expectedStatus: http.StatusNotModified},
+		{name: "modified since", headers: map[string][]string{HeaderIfModifiedSince: {"Thu, 29 Feb 2024 00:00:00 GMT"}}, expectedStatus: http.StatusOK},
+		{name: "If-Match matches", headers: map[string][]string{HeaderIfMatch: {`"v2"`}}, expectedStatus: http.StatusOK},
+		{name: "If-Match weak never matches", headers: map[string][]string{HeaderIfMatch: {`W/"v2"`}}, expectedStatus: http.StatusPreconditionFailed},
+		{name: "modified after If-Unmodified-Since", headers: map[string][]string{HeaderIfUnmodifiedSince: {"Thu, 29 Feb 2024 00:00:00 GMT"}}, expectedStatus: http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, _ := invokeConditional(t, ConditionalConfig{}, newConditionalRequest(http.MethodGet, tt.headers), response)
+			if got := getStatusCode(output); got != tt.expectedStatus {
+				t.Fatalf("status = %d, want %d", got, tt.expectedStatus)
+			}
+			if tt.expectedStatus == http.StatusNotModified {
+				if output.Body != nil || getResponseHeader(output, HeaderContentType) != "" || getResponseHeader(output, HeaderETag) != `"v2"` {
+					t.Errorf("304 response = %+v", output)
+				}
+			}
+		})
+	}
+}
+
+func Test_conditionalMiddleware_GenerateETag(t *testing.T) {
+	response := Response{Body: []byte("hello")}
+	output, _ := invokeConditional(t, ConditionalConfig{GenerateETag: true}, newConditionalRequest(http.MethodGet, nil), response)
+	etag := getResponseHeader(output, HeaderETag)
+	if etag != GenerateETag([]byte("hello")) || etag == GenerateETag([]byte("world")) {
+		t.Fatalf("ETag = %q", etag)
+	}
+
+	input := newConditionalRequest(http.MethodGet, map[string][]string{HeaderIfNoneMatch: {etag}})
+	if output, _ = invokeConditional(t, ConditionalConfig{GenerateETag: true}, input, response); getStatusCode(output) != http.StatusNotModified {
+		t.Errorf("status = %d, want 304", getStatusCode(output))
+	}
+
+	// the errors are not changed
+	notFound := 404
+	output, _ = invokeConditional(t, ConditionalConfig{GenerateETag: true}, input, Response{StatusCode: &notFound, Body: []byte("hello")})
+	if getStatusCode(output) != http.StatusNotFound || getResponseHeader(output, HeaderETag) != "" {
+		t.Errorf("404 response = %+v", output)
+	}
+}
+
+func Test_conditionalMiddleware_Write(t *testing.T) {
+	current := func(found bool, err error) ConditionalConfig {
+		return ConditionalConfig{Current: func(context.Context, Request) (Validators, bool, error) {
+			return Validators{ETag: `"v2"`}, found, err
+		}}
+	}
+	tests := []struct {
+		name           string
+		config         ConditionalConfig
+		headers        map[string][]string
+		expectedStatus int
+	}{
+		{name: "If-Match matches", config: current(true, nil), headers: map[string][]string{HeaderIfMatch: {`"v2"`}}, expectedStatus: http.StatusNoContent},
+		{name: "If-Match differs", config: current(true, nil), headers: map[string][]string{HeaderIfMatch: {`"v1"`}}, expectedStatus: http.StatusPreconditionFailed},
+		{name: "If-Match star not found", config: current(false, nil), headers: map[string][]string{HeaderIfMatch: {"*"}}, expectedStatus: http.StatusPreconditionFailed},
+		{name: "If-None-Match star found", config: current(true, nil), headers: map[string][]string{HeaderIfNoneMatch: {"*"}}, expectedStatus: http.StatusPreconditionFailed},
+		{name: "If-None-Match star not found", config: current(false, nil), headers: map[string][]string{HeaderIfNoneMatch: {"*"}}, expectedStatus: http.StatusNoContent},
+		{name: "no Current", headers: map[string][]string{HeaderIfMatch: {`"v1"`}}, expectedStatus: http.StatusNoContent},
+		{name: "Current fails", config: current(true, errors.New("db down")), headers: map[string][]string{HeaderIfMatch: {`"v2"`}}, expectedStatus: http.StatusInternalServerError},
+	}
+	noContent := http.StatusNoContent
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, invoked := invokeConditional(t, tt.config, newConditionalRequest(http.MethodPut, tt.headers), Response{StatusCode: &noContent})
+			if got := getStatusCode(output); got != tt.expectedStatus {
+				t.Fatalf("status = %d, want %d", got, tt.expectedStatus)
+			}
+			if invoked != (tt.expectedStatus == http.StatusNoContent) {
+				t.Errorf("handler invoked = %v", invoked)
+			}
+		})
+	}
+}
+
+func Test_conditionalMiddleware_NilOutput(t *testing.T) {
+	next := MakeHandler(func(context.Context, Request, *Response) error { return nil })
+	handler := NewConditionalMiddleware(ConditionalConfig{GenerateETag: true})(next)
+	get := newConditionalRequest(http.MethodGet, map[string][]string{HeaderIfNoneMatch: {`"v1"`}})
+	if err := handler.Invoke(context.Background(), get, nil); err != nil {
+		t.Errorf("Invoke(GET) error = %v", err)
+	}
+
+	config := ConditionalConfig{Current: func(context.Context, Request) (Validators, bool, error) {
+		return Validators{ETag: `"v2"`}, true, nil
+	}}
+	put := newConditionalRequest(http.MethodPut, map[string][]string{HeaderIfMatch: {`"v1"`}})
+	if err := NewConditionalMiddleware(config)(next).Invoke(context.Background(), put, nil); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Invoke(PUT) error = %v, want ErrPreconditionFailed", err)
+	}
+
+	if err := NewCacheControlMiddleware("no-store")(next).Invoke(context.Background(), get, nil); err != nil {
+		t.Errorf("cache control Invoke() error = %v", err)
+	}
+}
+
+func Test_cacheControlMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		response Response
+		expected string
+	}{
+		{name: "success", response: Response{}, expected: "public, max-age=60"},
+		{name: "not modified", response: Response{StatusCode: intPtr(http.StatusNotModified)}, expected: "public, max-age=60"},
+		{name: "error", response: Response{StatusCode: intPtr(http.StatusInternalServerError)}, expected: ""},
+		{name: "set by the handler", response: Response{Header: map[string][]string{"cache-control": {"no-store"}}}, expected: "no-store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewCacheControlMiddleware("public, max-age=60")(MakeHandler(func(_ context.Context, _ Request, output *Response) error {
+				*output = tt.response
+				return nil
+			}))
+			output := &Response{}
+			if err := handler.Invoke(context.Background(), &mockRequest{}, output); err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if got := getResponseHeader(output, HeaderCacheControl); got != tt.expected {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package httpadpt
+
+import (
+	"context"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderOrigin                        = "Origin"
+	HeaderVary                          = "Vary"
+	HeaderAccessControlRequestMethod    = "Access-Control-Request-Method"
+	HeaderAccessControlRequestHeaders   = "Access-Control-Request-Headers"
+	HeaderAccessControlAllowOrigin      = "Access-Control-Allow-Origin"
+	HeaderAccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	HeaderAccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	HeaderAccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	HeaderAccessControlExposeHeaders    = "Access-Control-Expose-Headers"
+	HeaderAccessControlMaxAge           = "Access-Control-Max-Age"
+)
+
+type (
+	// CORSConfig configures the middleware created by NewCORSMiddleware
+	CORSConfig struct {
+		// AllowedOrigins are the origins allowed to access the resources. An origin can be exact, like
+		// "https://app.example.com", or it can have one "*" wildcard, like "https://*.example.com". The value "*"
+		// allows any origin.
+		AllowedOrigins []string
+
+		// AllowedOriginPatterns are regular expressions matched against the origin, they should be anchored with ^ and $
+		AllowedOriginPatterns []*regexp.Regexp
+
+		// AllowedMethods are the methods allowed in the preflight requests, if empty DefaultCORSAllowedMethods is used
+		AllowedMethods []string
+
+		// AllowedHeaders are the request headers allowed in the preflight requests. If empty, the headers requested
+		// by the client are allowed.
+		AllowedHeaders []string
+
+		// ExposedHeaders are the response headers the browser exposes to the client code
+		ExposedHeaders []string
+
+		// AllowCredentials allows the browser to send cookies and credentials. In this case the request origin is
+		// returned and the AllowedOrigins cannot have "*".
+		AllowCredentials bool
+
+		// MaxAge is how long the browser can cache the preflight response, zero means the header is not sent
+		MaxAge time.Duration
+	}
+
+	corsMiddleware struct {
+		config    CORSConfig
+		decorated Handler
+	}
+)
+
+var (
+	DefaultCORSAllowedMethods = []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+	}
+)
+
+// NewCORSMiddleware creates a middleware that implements Cross-Origin Resource Sharing. It answers the preflight
+// requests without invoking the decorated handler and adds the CORS headers to the other responses. When it is set
+// as Config.CORS, the implementations register an OPTIONS handler for every binding path (see OptionsBindings), so
+// the preflight requests reach this middleware. It returns an error if AllowCredentials is used with the "*" origin.
+func NewCORSMiddleware(config CORSConfig) (Middleware, error) {
+	if config.AllowCredentials && slices.Contains(config.AllowedOrigins, "*") {
+		return nil, serror.IllegalConfigParamValue("CORSConfig.AllowedOrigins", "* with AllowCredentials")
+	}
+	if len(config.AllowedMethods) == 0 {
+		config.AllowedMethods = DefaultCORSAllowedMethods
+	}
+	return func(next Handler) Handler {
+		return corsMiddleware{config: config, decorated: next}
+	}, nil
+}
+
+func (c corsMiddleware) Invoke(ctx context.Context, input Request, output *Response) error {
+	origin := getFirstHeaderValue(input, HeaderOrigin)
+	if output == nil {
+		return c.decorated.Invoke(ctx, input, output)
+	}
+	if origin == "" {
+		// the response depends on the Origin, so the caches must not give it to the requests with an Origin
+		err := c.decorated.Invoke(ctx, input, output)
+		addHeaderValue(output, HeaderVary, HeaderOrigin)
+		return err
+	}
+
+	if GetMethod(input) == http.MethodOptions && getFirstHeaderValue(input, HeaderAccessControlRequestMethod) != "" {
+		c.handlePreflight(input, output, origin)
+		return nil
+	}
+
+	err := c.decorated.Invoke(ctx, input, output)
+	addHeaderValue(output, HeaderVary, HeaderOrigin)
+	if c.isOriginAllowed(origin) {
+		c.setAllowOrigin(output, origin)
+		if len(c.config.ExposedHeaders) > 0 {
+			setHeaderValue(output, HeaderAccessControlExposeHeaders, strings.Join(c.config.ExposedHeaders, ", "))
+		}
+	}
+	return err
+}
+
+func (c corsMiddleware) handlePreflight(input Request, output *Response, origin string) {
+	statusCode := http.StatusNoContent
+	output.StatusCode = &statusCode
+	addHeaderValue(output, HeaderVary, HeaderOrigin)
+	addHeaderValue(output, HeaderVary, HeaderAccessControlRequestMethod)
+	addHeaderValue(output, HeaderVary, HeaderAccessControlRequestHeaders)
+
+	requestedMethod := getFirstHeaderValue(input, HeaderAccessControlRequestMethod)
+	if !c.isOriginAllowed(origin) || !slices.Contains(c.config.AllowedMethods, strings.ToUpper(requestedMethod)) {
+		return // without the CORS headers the browser rejects the request
+	}
+	requestedHeaders := getRequestedHeaders(input)
+	if !c.areHeadersAllowed(requestedHeaders) {
+		return
+	}
+
+	c.setAllowOrigin(output, origin)
+	setHeaderValue(output, HeaderAccessControlAllowMethods, strings.Join(c.config.AllowedMethods, ", "))
+	if len(requestedHeaders) > 0 {
+		allowedHeaders := requestedHeaders
+		if len(c.config.AllowedHeaders) > 0 && !slices.Contains(c.config.AllowedHeaders, "*") {
+			allowedHeaders = c.config.AllowedHeaders
+		}
+		setHeaderValue(output, HeaderAccessControlAllowHeaders, strings.Join(allowedHeaders, ", "))
+	}
+	if c.config.MaxAge > 0 {
+		setHeaderValue(output, HeaderAccessControlMaxAge, strconv.Itoa(int(c.config.MaxAge.Seconds())))
+	}
+}
+
+func (c corsMiddleware) setAllowOrigin(output *Response, origin string) {
+	if c.config.AllowCredentials {
+		setHeaderValue(output, HeaderAccessControlAllowCredentials, "true")
+		setHeaderValue(output, HeaderAccessControlAllowOrigin, origin)
+		return
+	}
+	if slices.Contains(c.config.AllowedOrigins, "*") {
+		setHeaderValue(output, HeaderAccessControlAllowOrigin, "*")
+		return
+	}
+	setHeaderValue(output, HeaderAccessControlAllowOrigin, origin)
+}
+
+func (c corsMiddleware) isOriginAllowed(origin string) bool {
+	for _, allowed := range c.config.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		if prefix, suffix, found := strings.Cut(strings.ToLower(allowed), "*"); found {
+			lowerOrigin := strings.ToLower(origin)
+			if len(lowerOrigin) >= len(prefix)+len(suffix) && strings.HasPrefix(lowerOrigin, prefix) &&
+				strings.HasSuffix(lowerOrigin, suffix) {
+				return true
+			}
+		}
+	}
+	for _, pattern := range c.config.AllowedOriginPatterns {
+		if pattern != nil && pattern.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c corsMiddleware) areHeadersAllowed(requestedHeaders []string) bool {
+	if len(c.config.AllowedHeaders) == 0 || slices.Contains(c.config.AllowedHeaders, "*") {
+		return true
+	}
+	for _, requested := range requestedHeaders {
+		if !slices.ContainsFunc(c.config.AllowedHeaders, func(allowed string) bool { return strings.EqualFold(allowed, requested) }) {
+			return false
+		}
+	}
+	return true
+}
+
+func getRequestedHeaders(input Request) []string {
+	var headers []string
+	values, _ := input.Header().GetValue(HeaderAccessControlRequestHeaders)
+	for _, value := range values {
+		for _, header := range strings.Split(value, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, header)
+			}
+		}
+	}
+	return headers
+}
+
+func getFirstHeaderValue(input Request, headerName string) string {
+	if input == nil || input.Header() == nil {
+		return ""
+	}
+	values, found := input.Header().GetValue(headerName)
+	if !found || len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func setHeaderValue(output *Response, headerName string, value string) {
+	if output.Header == nil {
+		output.Header = make(map[string][]string)
+	}
+	output.Header[headerName] = []string{value}
+}
+
+func addHeaderValue(output *Response, headerName string, value string) {
+	if output.Header == nil {
+		output.Header = make(map[string][]string)
+	}
+	if !slices.Contains(output.Header[headerName], value) {
+		output.Header[headerName] = append(output.Header[headerName], value)
+	}
+}
@@ -0,0 +1,194 @@
+package httpadpt
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func newCORSRequest(method string, headers map[string][]string) *mockRequest {
+	return &mockRequest{method: method, header: &mockHeaderParams{values: headers}}
+}
+
+func invokeCORS(t *testing.T, config CORSConfig, input Request) (*Response, bool) {
+	t.Helper()
+	invoked := false
+	cors, err := NewCORSMiddleware(config)
+	if err != nil {
+		t.Fatalf("NewCORSMiddleware() error = %v", err)
+	}
+	handler := cors(MakeHandler(func(_ context.Context, _ Request, output *Response) error {
+		invoked = true
+		statusCode := 200
+		output.StatusCode = &statusCode
+		return nil
+	}))
+	output := &Response{}
+	if err := handler.Invoke(context.Background(), input, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	return output, invoked
+}
+
+func Test_corsMiddleware_Preflight(t *testing.T) {
+	config := CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		MaxAge:         10 * time.Minute,
+	}
+	tests := []struct {
+		name          string
+		headers       map[string][]string
+		expectAllowed bool
+	}{
+		{
+			name: "allowed origin, method and headers",
+			headers: map[string][]string{
+				HeaderOrigin:                      {"https://app.example.com"},
+				HeaderAccessControlRequestMethod:  {"PUT"},
+				HeaderAccessControlRequestHeaders: {"content-type, authorization"},
+			},
+			expectAllowed: true,
+		},
+		{
+			name: "origin not allowed",
+			headers: map[string][]string{
+				HeaderOrigin:                     {"https://evil.com"},
+				HeaderAccessControlRequestMethod: {"PUT"},
+			},
+		},
+		{
+			name: "method not allowed",
+			headers: map[string][]string{
+				HeaderOrigin:                     {"https://app.example.com"},
+				HeaderAccessControlRequestMethod: {"TRACE"},
+			},
+		},
+		{
+			name: "header not allowed",
+			headers: map[string][]string{
+				HeaderOrigin:                      {"https://app.example.com"},
+				HeaderAccessControlRequestMethod:  {"GET"},
+				HeaderAccessControlRequestHeaders: {"X-Other"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, invoked := invokeCORS(t, config, newCORSRequest("OPTIONS", tt.headers))
+			if invoked {
+				t.Error("preflight invoked the decorated handler")
+			}
+			if output.StatusCode == nil || *output.StatusCode != 204 {
+				t.Errorf("StatusCode = %v, want 204", output.StatusCode)
+			}
+			_, allowed := output.Header[HeaderAccessControlAllowOrigin]
+			if allowed != tt.expectAllowed {
+				t.Fatalf("allowed = %v, want %v, headers = %v", allowed, tt.expectAllowed, output.Header)
+			}
+			if !allowed {
+				return
+			}
+			if got := output.Header[HeaderAccessControlAllowMethods][0]; got != "GET, HEAD, POST, PUT, PATCH, DELETE" {
+				t.Errorf("Allow-Methods = %q", got)
+			}
+			if got := output.Header[HeaderAccessControlAllowHeaders][0]; got != "Content-Type, Authorization" {
+				t.Errorf("Allow-Headers = %q", got)
+			}
+			if got := output.Header[HeaderAccessControlMaxAge][0]; got != "600" {
+				t.Errorf("Max-Age = %q, want 600", got)
+			}
+		})
+	}
+}
+
+func Test_corsMiddleware_ActualRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         CORSConfig
+		origin         string
+		expectedOrigin string
+	}{
+		{
+			name:           "any origin",
+			config:         CORSConfig{AllowedOrigins: []string{"*"}},
+			origin:         "https://a.com",
+			expectedOrigin: "*",
+		},
+		{
+			name:           "credentials echo the origin",
+			config:         CORSConfig{AllowedOrigins: []string{"https://a.com"}, AllowCredentials: true},
+			origin:         "https://a.com",
+			expectedOrigin: "https://a.com",
+		},
+		{
+			name:           "wildcard subdomain",
+			config:         CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			origin:         "https://api.example.com",
+			expectedOrigin: "https://api.example.com",
+		},
+		{
+			name:           "wildcard ignores case",
+			config:         CORSConfig{AllowedOrigins: []string{"https://*.Example.com"}},
+			origin:         "HTTPS://api.EXAMPLE.com",
+			expectedOrigin: "HTTPS://api.EXAMPLE.com",
+		},
+		{
+			name:   "wildcard does not match other domain",
+			config: CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			origin: "https://example.org",
+		},
+		{
+			name:           "regex",
+			config:         CORSConfig{AllowedOriginPatterns: []*regexp.Regexp{regexp.MustCompile(`^http://localhost:\d+$`)}},
+			origin:         "http://localhost:3000",
+			expectedOrigin: "http://localhost:3000",
+		},
+		{
+			name:   "regex does not match",
+			config: CORSConfig{AllowedOriginPatterns: []*regexp.Regexp{regexp.MustCompile(`^http://localhost:\d+$`)}},
+			origin: "http://localhost.evil.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.ExposedHeaders = []string{"X-Total"}
+			output, invoked := invokeCORS(t, tt.config, newCORSRequest("GET", map[string][]string{HeaderOrigin: {tt.origin}}))
+			if !invoked {
+				t.Fatal("the decorated handler was not invoked")
+			}
+			if output.Header[HeaderVary][0] != HeaderOrigin {
+				t.Errorf("Vary = %v, want Origin", output.Header[HeaderVary])
+			}
+			got := ""
+			if values := output.Header[HeaderAccessControlAllowOrigin]; len(values) > 0 {
+				got = values[0]
+			}
+			if got != tt.expectedOrigin {
+				t.Errorf("Allow-Origin = %q, want %q", got, tt.expectedOrigin)
+			}
+			if tt.expectedOrigin != "" && output.Header[HeaderAccessControlExposeHeaders][0] != "X-Total" {
+				t.Errorf("Expose-Headers = %v", output.Header[HeaderAccessControlExposeHeaders])
+			}
+		})
+	}
+}
+
+func Test_corsMiddleware_WithoutOrigin(t *testing.T) {
+	output, invoked := invokeCORS(t, CORSConfig{AllowedOrigins: []string{"*"}}, newCORSRequest("OPTIONS", nil))
+	if !invoked {
+		t.Error("request without Origin did not reach the decorated handler")
+	}
+	if len(output.Header) != 1 || len(output.Header[HeaderVary]) != 1 || output.Header[HeaderVary][0] != HeaderOrigin {
+		t.Errorf("Header = %v, want only Vary: Origin", output.Header)
+	}
+}
+
+func Test_NewCORSMiddleware_CredentialsWithAnyOrigin(t *testing.T) {
+	if _, err := NewCORSMiddleware(CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}); err == nil {
+		t.Error("NewCORSMiddleware() error = nil, want error for AllowCredentials with the * origin")
+	}
+}
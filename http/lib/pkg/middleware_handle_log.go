@@ -46,20 +46,15 @@ func getDuration(start time.Time) string {
 	return time.Since(start).String()
 }
 
-func getMethod(input Request) string {
-	if input == nil {
-		return "GET"
-	}
-	if input.Method() == "" {
-		return "GET"
-	}
-	return input.Method()
-}
-
 func (h handleLogMiddleware) Invoke(ctx context.Context, input Request, output *Response) error {
 	start := time.Now()
-	xid := slog.String("xid", getRequestID(start))
-	logger := h.provider(ctx)
+	requestID, found := RequestIDFromContext(ctx)
+	if !found {
+		requestID = getRequestID(start)
+	}
+	xid := slog.String("xid", requestID)
+	// the provider sees the request ID, the mark keeps DefaultLoggerProvider from adding the xid twice
+	logger := h.provider(context.WithValue(ctx, handleLogKey{}, true))
 	if logger != nil {
 		ctx = DefaultLoggerToContext(ctx, logger.With(xid))
 	}
@@ -68,7 +63,7 @@ func (h handleLogMiddleware) Invoke(ctx context.Context, input Request, output *
 			pathLabel := slog.String("path", getPath(input))
 			statusCodeLabel := slog.Int("status", getStatus(output))
 			durationLabel := slog.String("duration", getDuration(start))
-			methodLabel := slog.String("method", getMethod(input))
+			methodLabel := slog.String("method", GetMethod(input))
 			logger.Info("HTTP.Request", pathLabel, methodLabel, statusCodeLabel, xid, durationLabel)
 		}
 	}()
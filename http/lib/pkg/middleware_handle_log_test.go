@@ -113,7 +113,7 @@ func Test_getStatus(t *testing.T) {
 	}
 }
 
-func Test_getMethod(t *testing.T) {
+func TestGetMethod(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    Request
@@ -170,9 +170,9 @@ func Test_getMethod(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getMethod(tt.input)
+			result := GetMethod(tt.input)
 			if result != tt.expected {
-				t.Errorf("getMethod() = %q, want %q", result, tt.expected)
+				t.Errorf("GetMethod() = %q, want %q", result, tt.expected)
 			}
 		})
 	}
@@ -25,12 +25,19 @@ func (h handlePanicMiddleware) Invoke(ctx context.Context, input Request, output
 				return
 			}
 			statusCode := 500
-			output.StatusCode = &statusCode
-			output.Header = map[string][]string{"Content-Type": {ContentTypeProblemDetail}}
 			err, ok := panicArg.(error)
-			if !ok {
+			if ok {
+				// input params that fail the assertions panic with a classified error, e.g. 400 or 413
+				_ = errorToStatusCode(err, &statusCode)
+			} else {
 				err = serror.WrapAsInternalError(fmt.Errorf("%v", panicArg))
 			}
+			output.StatusCode = &statusCode
+			output.Header = map[string][]string{"Content-Type": {ContentTypeProblemDetail}}
+			output.Cookies = nil
+			output.BodyStream = nil
+			output.Events = nil
+			output.WebSocket = nil
 			output.Body, _ = JSONProblemDetailFromError(err)
 		}
 	}()
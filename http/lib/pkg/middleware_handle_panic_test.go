@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"net/http"
 	"strings"
 	"testing"
 )
@@ -451,3 +453,39 @@ func (h *testPanicHandler) Invoke(_ context.Context, _ Request, _ *Response) err
 	}
 	return h.err
 }
+
+func Test_handlePanicMiddleware_Invoke_PanicDiscardsStreams(t *testing.T) {
+	mockHandler := &testPanicHandler{shouldPanic: true, panicValue: "stream panic"}
+	middleware := handlePanicMiddleware{decorated: mockHandler}
+
+	output := &Response{
+		BodyStream: strings.NewReader("partial"),
+		Events:     make(chan Event),
+	}
+
+	if err := middleware.Invoke(context.Background(), &mockRequest{method: "GET"}, output); err != nil {
+		t.Errorf("Invoke() error = %v, want nil", err)
+	}
+
+	if output.IsStreaming() {
+		t.Error("Expected streams to be discarded after panic")
+	}
+	if len(output.Body) == 0 {
+		t.Error("Expected Body to be set after panic")
+	}
+}
+
+func Test_handlePanicMiddleware_Invoke_ClassifiedError(t *testing.T) {
+	panicErr := serror.IllegalArgumentValue("param", "value")
+	mockHandler := &testPanicHandler{shouldPanic: true, panicValue: panicErr}
+	middleware := handlePanicMiddleware{decorated: mockHandler}
+
+	output := &Response{}
+	if err := middleware.Invoke(context.Background(), &mockRequest{method: "POST"}, output); err != nil {
+		t.Errorf("Invoke() error = %v, want nil", err)
+	}
+
+	if output.StatusCode == nil || *output.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected StatusCode = 400, got %v", output.StatusCode)
+	}
+}
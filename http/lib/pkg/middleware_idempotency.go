@@ -0,0 +1,242 @@
+package httpadpt
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	DefaultIdempotencyTTL     = 24 * time.Hour
+	DefaultIdempotencyLockTTL = time.Minute
+
+	// DefaultIdempotencyMaxBodySize is the body size limit of the requests with an Idempotency-Key when
+	// IdempotencyConfig.MaxBodySize is zero
+	DefaultIdempotencyMaxBodySize int64 = 10 << 20
+
+	// MaxIdempotencyKeyLength is the length limit of the Idempotency-Key header value
+	MaxIdempotencyKeyLength = 255
+)
+
+var (
+	// ErrIdempotencyKeyInUse is wrapped by the error of the requests whose key is in flight, it is mapped to 409
+	ErrIdempotencyKeyInUse = errors.New("idempotency key in use")
+
+	// ErrIdempotencyKeyMismatch is wrapped by the error of the requests that reuse a key with a different request,
+	// it is mapped to 422
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key reused with a different request")
+)
+
+type (
+	// IdempotencyConfig configures the middleware created by NewIdempotencyMiddleware
+	IdempotencyConfig struct {
+		// Store is a new MemoryIdempotencyStore if nil
+		Store IdempotencyStore
+
+		// Methods are the methods handled, it is POST and PATCH if empty
+		Methods []string
+
+		// TTL is how long the responses are replayed, zero means DefaultIdempotencyTTL
+		TTL time.Duration
+
+		// LockTTL is how long an in-flight key is kept if the instance handling it dies, zero means
+		// DefaultIdempotencyLockTTL. The lock is extended every half LockTTL while the handler runs.
+		LockTTL time.Duration
+
+		// Required makes the requests without the Idempotency-Key header be rejected with 400
+		Required bool
+
+		// Client identifies the client, so the clients cannot replay the responses of each other. It is the
+		// Principal subject, or the client IP if there is none, when nil. The requests with an Idempotency-Key
+		// whose client is empty are rejected.
+		Client RateLimitKeyFunc
+
+		// Scope is prepended to the keys, so several bindings can share the same Store
+		Scope string
+
+		// MaxBodySize is the body size limit of the requests with an Idempotency-Key, the larger ones get 413, zero
+		// means DefaultIdempotencyMaxBodySize
+		MaxBodySize int64
+	}
+
+	idempotencyMiddleware struct {
+		config    IdempotencyConfig
+		decorated Handler
+	}
+
+	// replayableBody gives back the body bytes read for the fingerprint
+	replayableBody struct {
+		io.Reader
+		io.Closer
+	}
+)
+
+// NewIdempotencyMiddleware creates a middleware that makes the retries of the requests with the same Idempotency-Key
+// header replay the response of the first one. The request is fingerprinted by its method, path, query, content type
+// and body: a retry with a different fingerprint gets 422, and a retry sent while the first request is in flight
+// gets 409. The responses are replayed with the Idempotent-Replayed: true header. The requests whose handler returns
+// an error, a 5xx or a streamed response are not stored, so they can be retried. The requests with a key are
+// rejected when the Request is not a BodyWrapper, since their body cannot be fingerprinted, or when the client is
+// not identified.
+func NewIdempotencyMiddleware(config IdempotencyConfig) Middleware {
+	if config.Store == nil {
+		config.Store = NewMemoryIdempotencyStore()
+	}
+	if len(config.Methods) == 0 {
+		config.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+	if config.TTL <= 0 {
+		config.TTL = DefaultIdempotencyTTL
+	}
+	if config.LockTTL <= 0 {
+		config.LockTTL = DefaultIdempotencyLockTTL
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultIdempotencyMaxBodySize
+	}
+	if config.Client == nil {
+		config.Client = FirstRateLimitKey(RateLimitKeyByPrincipal(), RateLimitKeyByIP(0))
+	}
+	return func(next Handler) Handler {
+		return idempotencyMiddleware{config: config, decorated: next}
+	}
+}
+
+func (m idempotencyMiddleware) Invoke(ctx context.Context, input Request, output *Response) error {
+	if input == nil || !slices.Contains(m.config.Methods, input.Method()) {
+		return m.decorated.Invoke(ctx, input, output)
+	}
+	idempotencyKey := getFirstHeaderValue(input, HeaderIdempotencyKey)
+	if idempotencyKey == "" {
+		if m.config.Required {
+			return NewOutErrorParamSpec().SetValue(output, serror.IllegalArgumentValue(HeaderIdempotencyKey, idempotencyKey))
+		}
+		return m.decorated.Invoke(ctx, input, output)
+	}
+	if len(idempotencyKey) > MaxIdempotencyKeyLength {
+		return NewOutErrorParamSpec().SetValue(output, serror.IllegalArgumentValue(HeaderIdempotencyKey, idempotencyKey))
+	}
+
+	client := m.config.Client(ctx, input)
+	if client == "" {
+		return NewOutErrorParamSpec().SetValue(output,
+			serror.CmpError.New("httpadpt.idempotencyMiddleware: the client of the request is not identified"))
+	}
+	fingerprint, err := m.fingerprint(input)
+	if err != nil {
+		return NewOutErrorParamSpec().SetValue(output, err)
+	}
+	key := m.config.Scope + ":" + client + ":" + idempotencyKey
+	record, found, err := m.config.Store.Begin(ctx, key, fingerprint, m.config.LockTTL)
+	if err != nil {
+		return fmt.Errorf("httpadpt.idempotencyMiddleware: %w", err)
+	}
+	if found {
+		return m.replay(record, fingerprint, output)
+	}
+
+	completed := false
+	defer func() {
+		if !completed {
+			_ = m.config.Store.Abort(context.WithoutCancel(ctx), key)
+		}
+	}()
+	// deferred after the Abort, so a panicking handler stops the extension before the record is aborted
+	stopExtending := m.extendLock(ctx, key)
+	defer stopExtending()
+	err = m.decorated.Invoke(ctx, input, output)
+	stopExtending()
+	if err != nil || output.IsStreaming() || getStatusCode(output) >= 500 {
+		return err
+	}
+	if err := m.config.Store.Complete(ctx, key, *output, m.config.TTL); err != nil {
+		return fmt.Errorf("httpadpt.idempotencyMiddleware: %w", err)
+	}
+	completed = true
+	return nil
+}
+
+func (m idempotencyMiddleware) replay(record IdempotencyRecord, fingerprint string, output *Response) error {
+	switch {
+	case record.Fingerprint != fingerprint:
+		return NewOutErrorParamSpec().SetValue(output, ErrIdempotencyKeyMismatch)
+	case !record.Completed:
+		return NewOutErrorParamSpec().SetValue(output, ErrIdempotencyKeyInUse)
+	}
+	*output = cloneResponse(record.Response)
+	setHeaderValue(output, HeaderIdempotentReplayed, "true")
+	return nil
+}
+
+// extendLock extends the in-flight record every half LockTTL until the returned function is called, so a handler
+// that runs longer than LockTTL is not invoked again by a retry. The returned function can be called more than once.
+func (m idempotencyMiddleware) extendLock(ctx context.Context, key string) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(m.config.LockTTL / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = m.config.Store.Extend(context.WithoutCancel(ctx), key, m.config.LockTTL)
+			}
+		}
+	}()
+	return sync.OnceFunc(func() {
+		close(done)
+		<-stopped
+	})
+}
+
+// fingerprint hashes the method, path, query, content type and the whole body. The body is read through the
+// BodyWrapper and given back to the handler, the bodies larger than MaxBodySize are rejected.
+func (m idempotencyMiddleware) fingerprint(input Request) (string, error) {
+	wrapper, ok := input.(BodyWrapper)
+	if !ok {
+		return "", serror.CmpError.New(
+			"httpadpt.idempotencyMiddleware: the request=[%T] does not give access to the body", input)
+	}
+	hash := sha256.New()
+	hash.Write([]byte(input.Method()))
+	if requestURL := input.URL(); requestURL != nil {
+		hash.Write([]byte{0})
+		hash.Write([]byte(requestURL.EscapedPath()))
+		hash.Write([]byte{0})
+		hash.Write([]byte(requestURL.RawQuery))
+	}
+	hash.Write([]byte{0})
+	hash.Write([]byte(getFirstHeaderValue(input, HeaderContentType)))
+	hash.Write([]byte{0})
+
+	err := wrapper.WrapBody(func(body io.ReadCloser) (io.ReadCloser, error) {
+		content, err := io.ReadAll(io.LimitReader(body, m.config.MaxBodySize+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(content)) > m.config.MaxBodySize {
+			return nil, &http.MaxBytesError{Limit: m.config.MaxBodySize}
+		}
+		hash.Write(content)
+		return replayableBody{Reader: bytes.NewReader(content), Closer: body}, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("httpadpt.idempotencyMiddleware: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
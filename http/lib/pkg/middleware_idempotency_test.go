@@ -0,0 +1,238 @@
+package httpadpt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newIdempotencyRequest(key, body string) *bodyWrapperRequest {
+	headers := map[string][]string{HeaderContentType: {"application/json"}}
+	if key != "" {
+		headers[HeaderIdempotencyKey] = []string{key}
+	}
+	return &bodyWrapperRequest{
+		mockRequest: mockRequest{
+			method:     http.MethodPost,
+			url:        &url.URL{Path: "/orders"},
+			header:     &mockHeaderParams{values: headers},
+			remoteAddr: "192.0.2.1:1234",
+		},
+		body: io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func newIdempotencyHandler(config IdempotencyConfig, calls *atomic.Int32, statusCode int) Handler {
+	return NewIdempotencyMiddleware(config)(MakeHandler(func(_ context.Context, input Request, output *Response) error {
+		calls.Add(1)
+		body, _ := io.ReadAll(input.(*bodyWrapperRequest).body)
+		output.StatusCode = &statusCode
+		output.Header = map[string][]string{HeaderContentType: {"application/json"}}
+		output.Body = append([]byte("created "), body...)
+		return nil
+	}))
+}
+
+func invokeIdempotency(t *testing.T, handler Handler, input Request) *Response {
+	t.Helper()
+	output := &Response{}
+	if err := handler.Invoke(context.Background(), input, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	return output
+}
+
+func Test_idempotencyMiddleware_Replay(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotencyHandler(IdempotencyConfig{}, &calls, http.StatusCreated)
+
+	first := invokeIdempotency(t, handler, newIdempotencyRequest("k1", `{"item":1}`))
+	if *first.StatusCode != http.StatusCreated || string(first.Body) != `created {"item":1}` {
+		t.Fatalf("first response = %d %s, the handler should read the whole body", *first.StatusCode, first.Body)
+	}
+	retry := invokeIdempotency(t, handler, newIdempotencyRequest("k1", `{"item":1}`))
+	if calls.Load() != 1 || *retry.StatusCode != http.StatusCreated || string(retry.Body) != string(first.Body) {
+		t.Errorf("retry = %d %s, calls = %d, want the first response replayed", *retry.StatusCode, retry.Body, calls.Load())
+	}
+	if getResponseHeader(retry, HeaderIdempotentReplayed) != "true" || getResponseHeader(first, HeaderIdempotentReplayed) != "" {
+		t.Errorf("Idempotent-Replayed header is wrong, first = %v, retry = %v", first.Header, retry.Header)
+	}
+
+	mismatch := invokeIdempotency(t, handler, newIdempotencyRequest("k1", `{"item":2}`))
+	if *mismatch.StatusCode != http.StatusUnprocessableEntity || calls.Load() != 1 {
+		t.Errorf("mismatch status = %d, want 422", *mismatch.StatusCode)
+	}
+
+	invokeIdempotency(t, handler, newIdempotencyRequest("", `{"item":1}`))
+	invokeIdempotency(t, handler, newIdempotencyRequest("k2", `{"item":1}`))
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, the requests without key or with a new key should be handled", calls.Load())
+	}
+}
+
+func Test_idempotencyMiddleware_InFlight(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	request := newIdempotencyRequest("k1", "{}")
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := NewIdempotencyMiddleware(IdempotencyConfig{Store: store})(MakeHandler(func(context.Context, Request, *Response) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = handler.Invoke(context.Background(), request, &Response{})
+	}()
+	<-started
+
+	duplicate := invokeIdempotency(t, handler, newIdempotencyRequest("k1", "{}"))
+	close(release)
+	<-done
+	if *duplicate.StatusCode != http.StatusConflict {
+		t.Errorf("in-flight duplicate status = %d, want 409", *duplicate.StatusCode)
+	}
+}
+
+func Test_idempotencyMiddleware_NotStored(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotencyHandler(IdempotencyConfig{}, &calls, http.StatusServiceUnavailable)
+	invokeIdempotency(t, handler, newIdempotencyRequest("k1", "{}"))
+	invokeIdempotency(t, handler, newIdempotencyRequest("k1", "{}"))
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, the 5xx responses should not be replayed", calls.Load())
+	}
+
+	required := newIdempotencyHandler(IdempotencyConfig{Required: true}, &calls, http.StatusCreated)
+	if output := invokeIdempotency(t, required, newIdempotencyRequest("", "{}")); *output.StatusCode != http.StatusBadRequest {
+		t.Errorf("missing key status = %d, want 400", *output.StatusCode)
+	}
+	if output := invokeIdempotency(t, required, newIdempotencyRequest(strings.Repeat("k", 256), "{}")); *output.StatusCode != http.StatusBadRequest {
+		t.Errorf("long key status = %d, want 400", *output.StatusCode)
+	}
+
+	get := newIdempotencyRequest("k3", "")
+	get.method = http.MethodGet
+	before := calls.Load()
+	invokeIdempotency(t, required, get)
+	invokeIdempotency(t, required, get)
+	if calls.Load() != before+2 {
+		t.Error("the GET requests should not be handled by the middleware")
+	}
+}
+
+func Test_idempotencyMiddleware_Client(t *testing.T) {
+	var calls atomic.Int32
+	client := "alice"
+	config := IdempotencyConfig{Client: func(context.Context, Request) string { return client }}
+	handler := newIdempotencyHandler(config, &calls, http.StatusCreated)
+	invokeIdempotency(t, handler, newIdempotencyRequest("k1", "{}"))
+	client = "bob"
+	if output := invokeIdempotency(t, handler, newIdempotencyRequest("k1", "{}")); getResponseHeader(output, HeaderIdempotentReplayed) != "" {
+		t.Error("the response of a client should not be replayed to another")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+}
+
+func Test_idempotencyMiddleware_FailClosed(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotencyHandler(IdempotencyConfig{}, &calls, http.StatusCreated)
+
+	anonymous := newIdempotencyRequest("k1", "{}")
+	anonymous.remoteAddr = ""
+	if output := invokeIdempotency(t, handler, anonymous); *output.StatusCode != http.StatusInternalServerError {
+		t.Errorf("anonymous client status = %d, want 500", *output.StatusCode)
+	}
+	noBody := struct{ Request }{Request: &newIdempotencyRequest("k1", "{}").mockRequest}
+	if output := invokeIdempotency(t, handler, noBody); *output.StatusCode != http.StatusInternalServerError {
+		t.Errorf("request without BodyWrapper status = %d, want 500", *output.StatusCode)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("calls = %d, the rejected requests should not be handled", calls.Load())
+	}
+}
+
+func Test_idempotencyMiddleware_WholeBody(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotencyHandler(IdempotencyConfig{MaxBodySize: 8}, &calls, http.StatusCreated)
+
+	invokeIdempotency(t, handler, newIdempotencyRequest("k1", `{"a":1}`))
+	if output := invokeIdempotency(t, handler, newIdempotencyRequest("k1", `{"a":2}`)); *output.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("body changed at the end status = %d, want 422", *output.StatusCode)
+	}
+	if output := invokeIdempotency(t, handler, newIdempotencyRequest("k2", `{"a":1234}`)); *output.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("large body status = %d, want 413", *output.StatusCode)
+	}
+}
+
+func Test_idempotencyMiddleware_ExtendsLock(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := NewIdempotencyMiddleware(IdempotencyConfig{LockTTL: 20 * time.Millisecond})(MakeHandler(
+		func(context.Context, Request, *Response) error {
+			close(started)
+			<-release
+			return nil
+		}))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = handler.Invoke(context.Background(), newIdempotencyRequest("k1", "{}"), &Response{})
+	}()
+	<-started
+	time.Sleep(60 * time.Millisecond)
+
+	duplicate := invokeIdempotency(t, handler, newIdempotencyRequest("k1", "{}"))
+	close(release)
+	<-done
+	if duplicate.StatusCode == nil || *duplicate.StatusCode != http.StatusConflict {
+		t.Errorf("duplicate after LockTTL status = %v, want 409", duplicate.StatusCode)
+	}
+}
+
+type extendCountingStore struct {
+	IdempotencyStore
+	extended atomic.Int32
+}
+
+func (s *extendCountingStore) Extend(ctx context.Context, key string, ttl time.Duration) error {
+	s.extended.Add(1)
+	return s.IdempotencyStore.Extend(ctx, key, ttl)
+}
+
+func Test_idempotencyMiddleware_PanicStopsExtending(t *testing.T) {
+	store := &extendCountingStore{IdempotencyStore: NewMemoryIdempotencyStore()}
+	handler := NewIdempotencyMiddleware(IdempotencyConfig{Store: store, LockTTL: 10 * time.Millisecond})(MakeHandler(
+		func(context.Context, Request, *Response) error {
+			time.Sleep(20 * time.Millisecond)
+			panic("boom")
+		}))
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Invoke() should panic")
+			}
+		}()
+		_ = handler.Invoke(context.Background(), newIdempotencyRequest("k1", "{}"), &Response{})
+	}()
+
+	extended := store.extended.Load()
+	time.Sleep(50 * time.Millisecond)
+	if got := store.extended.Load(); got != extended {
+		t.Errorf("Extend() called %d times after the panic, want 0", got-extended)
+	}
+	var calls atomic.Int32
+	retry := invokeIdempotency(t, newIdempotencyHandler(IdempotencyConfig{Store: store}, &calls, http.StatusCreated),
+		newIdempotencyRequest("k1", "{}"))
+	if calls.Load() != 1 || retry.StatusCode == nil || *retry.StatusCode != http.StatusCreated {
+		t.Errorf("retry after the panic = %v, calls = %d, want the handler invoked again", retry.StatusCode, calls.Load())
+	}
+}
@@ -0,0 +1,138 @@
+package httpadpt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	HeaderRateLimitLimit     = "Ratelimit-Limit"
+	HeaderRateLimitRemaining = "Ratelimit-Remaining"
+	HeaderRateLimitReset     = "Ratelimit-Reset"
+	HeaderRateLimitPolicy    = "Ratelimit-Policy"
+	HeaderRetryAfter         = "Retry-After"
+	HeaderXForwardedFor      = "X-Forwarded-For"
+)
+
+// ErrTooManyRequests is wrapped by the error of the requests rejected by the rate limit, it is mapped to 429
+var ErrTooManyRequests = errors.New("too many requests")
+
+type (
+	// RateLimitConfig configures the middleware created by NewRateLimitMiddleware
+	RateLimitConfig struct {
+		RateLimit
+
+		// Algorithm is TokenBucket if nil
+		Algorithm RateLimitAlgorithm
+
+		// Store is a new MemoryRateLimitStore if nil
+		Store RateLimitStore
+
+		// Key identifies the client, it is RateLimitKeyByIP(0) if nil
+		Key RateLimitKeyFunc
+
+		// Scope is prepended to the keys, so several limits can share the same Store, like one per binding
+		Scope string
+
+		// Now is used by the tests, it is time.Now if nil
+		Now func() time.Time
+	}
+
+	rateLimitMiddleware struct {
+		config    RateLimitConfig
+		ttl       time.Duration
+		decorated Handler
+	}
+)
+
+// NewRateLimitMiddleware creates a middleware that limits the number of requests of each client. Every response gets
+// the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers, and the rejected requests
+// get a 429 problem response with the Retry-After header. To limit a binding, add the middleware to its Middlewares.
+// It returns an error if the RateLimit has no Requests or no Period.
+func NewRateLimitMiddleware(config RateLimitConfig) (Middleware, error) {
+	if config.Requests <= 0 || config.Period <= 0 {
+		return nil, serror.IllegalConfigParamValue("RateLimitConfig.RateLimit",
+			fmt.Sprintf("%d/%s", config.Requests, config.Period))
+	}
+	if config.Algorithm == nil {
+		config.Algorithm = TokenBucket{}
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryRateLimitStore()
+	}
+	if config.Key == nil {
+		config.Key = RateLimitKeyByIP(0)
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	ttl := rateLimitStateTTL(config.RateLimit)
+	return func(next Handler) Handler {
+		return rateLimitMiddleware{config: config, ttl: ttl, decorated: next}
+	}, nil
+}
+
+// rateLimitStateTTL returns how long the state of a key is kept: two periods, enough for the sliding window, or the
+// time an empty token bucket takes to refill when Burst is larger, so an idle key cannot expire with fewer tokens than
+// a new one gets
+func rateLimitStateTTL(limit RateLimit) time.Duration {
+	refill := limit.Period * time.Duration(limit.Burst) / time.Duration(limit.Requests)
+	return max(2*limit.Period, refill)
+}
+
+func (r rateLimitMiddleware) Invoke(ctx context.Context, input Request, output *Response) error {
+	key := r.config.Key(ctx, input)
+	if key == "" {
+		return r.decorated.Invoke(ctx, input, output)
+	}
+
+	var (
+		result RateLimitResult
+		now    = r.config.Now()
+	)
+	err := r.config.Store.Update(ctx, r.config.Scope+"|"+key, r.ttl,
+		func(state RateLimitState, found bool) RateLimitState {
+			state, result = r.config.Algorithm.Allow(state, found, r.config.RateLimit, now)
+			return state
+		})
+	if err != nil {
+		return fmt.Errorf("httpadpt.rateLimitMiddleware: %w", err)
+	}
+
+	if !result.Allowed {
+		err := fmt.Errorf("%w: retry after %s", ErrTooManyRequests, result.RetryAfter.Round(time.Second))
+		if output == nil {
+			return err
+		}
+		if setErr := NewOutErrorParamSpec().SetValue(output, err); setErr != nil {
+			return setErr
+		}
+		r.setHeaders(output, result)
+		setHeaderValue(output, HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		return nil
+	}
+
+	err = r.decorated.Invoke(ctx, input, output)
+	if output != nil {
+		r.setHeaders(output, result)
+	}
+	return err
+}
+
+func (r rateLimitMiddleware) setHeaders(output *Response, result RateLimitResult) {
+	setHeaderValue(output, HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+	setHeaderValue(output, HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+	setHeaderValue(output, HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+	setHeaderValue(output, HeaderRateLimitPolicy,
+		fmt.Sprintf("%d;w=%d", r.config.Requests, ceilSeconds(r.config.Period)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
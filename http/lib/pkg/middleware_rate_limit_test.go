@@ -0,0 +1,151 @@
+package httpadpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	now := testRateLimitNow
+	middleware, err := NewRateLimitMiddleware(RateLimitConfig{
+		RateLimit: RateLimit{Requests: 2, Period: time.Minute},
+		Now:       func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("NewRateLimitMiddleware() error = %v", err)
+	}
+	invoked := 0
+	handler := middleware(MakeHandler(func(context.Context, Request, *Response) error {
+		invoked++
+		return nil
+	}))
+	invoke := func(remoteAddr string) *Response {
+		output := &Response{}
+		if err := handler.Invoke(context.Background(), &mockRequest{remoteAddr: remoteAddr}, output); err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+		return output
+	}
+
+	output := invoke("10.0.0.1:1000")
+	if got := output.Header[HeaderRateLimitRemaining]; len(got) != 1 || got[0] != "1" {
+		t.Errorf("RateLimit-Remaining = %v, want 1", got)
+	}
+	if got := output.Header[HeaderRateLimitPolicy]; len(got) != 1 || got[0] != "2;w=60" {
+		t.Errorf("RateLimit-Policy = %v, want 2;w=60", got)
+	}
+	invoke("10.0.0.1:1001")
+
+	output = invoke("10.0.0.1:1002")
+	if invoked != 2 {
+		t.Errorf("handler invoked %d times, want 2", invoked)
+	}
+	if output.StatusCode == nil || *output.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("StatusCode = %v, want 429", output.StatusCode)
+	}
+	if got := output.Header[HeaderRetryAfter]; len(got) != 1 || got[0] != "30" {
+		t.Errorf("Retry-After = %v, want 30", got)
+	}
+	if got := output.Header["Content-Type"]; len(got) != 1 || got[0] != ContentTypeProblemDetail {
+		t.Errorf("Content-Type = %v", got)
+	}
+	var problem ProblemDetail
+	if err := json.Unmarshal(output.Body, &problem); err != nil || problem.Status != "429" {
+		t.Errorf("Body = %s, want a 429 problem detail", output.Body)
+	}
+
+	// another client has its own quota
+	if output = invoke("10.0.0.2:1000"); output.StatusCode != nil {
+		t.Errorf("StatusCode of another client = %d, want the handler response", *output.StatusCode)
+	}
+
+	now = now.Add(30 * time.Second)
+	if output = invoke("10.0.0.1:1003"); output.StatusCode != nil {
+		t.Errorf("StatusCode after the refill = %d, want the handler response", *output.StatusCode)
+	}
+}
+
+type ttlRecordingStore struct {
+	RateLimitStore
+	ttl time.Duration
+}
+
+func (s *ttlRecordingStore) Update(ctx context.Context, key string, ttl time.Duration,
+	update func(state RateLimitState, found bool) RateLimitState) error {
+	s.ttl = ttl
+	return s.RateLimitStore.Update(ctx, key, ttl, update)
+}
+
+func TestRateLimitMiddleware_StateTTL(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    RateLimit
+		expected time.Duration
+	}{
+		{name: "no burst", limit: RateLimit{Requests: 2, Period: time.Minute}, expected: 2 * time.Minute},
+		{name: "small burst", limit: RateLimit{Requests: 2, Period: time.Minute, Burst: 3}, expected: 2 * time.Minute},
+		{name: "burst larger than two periods", limit: RateLimit{Requests: 2, Period: time.Minute, Burst: 10},
+			expected: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &ttlRecordingStore{RateLimitStore: NewMemoryRateLimitStore()}
+			middleware, err := NewRateLimitMiddleware(RateLimitConfig{RateLimit: tt.limit, Store: store})
+			if err != nil {
+				t.Fatalf("NewRateLimitMiddleware() error = %v", err)
+			}
+			handler := middleware(MakeHandler(func(context.Context, Request, *Response) error { return nil }))
+			if err := handler.Invoke(context.Background(), &mockRequest{remoteAddr: "10.0.0.1:1000"}, &Response{}); err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if store.ttl != tt.expected {
+				t.Errorf("Update() ttl = %s, want %s", store.ttl, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware_NotLimited(t *testing.T) {
+	middleware, err := NewRateLimitMiddleware(RateLimitConfig{
+		RateLimit: RateLimit{Requests: 1, Period: time.Minute},
+		Key:       RateLimitKeyByPrincipal(),
+	})
+	if err != nil {
+		t.Fatalf("NewRateLimitMiddleware() error = %v", err)
+	}
+	handler := middleware(MakeHandler(func(context.Context, Request, *Response) error { return nil }))
+	for i := 0; i < 3; i++ {
+		output := &Response{}
+		if err := handler.Invoke(context.Background(), &mockRequest{}, output); err != nil || output.StatusCode != nil {
+			t.Fatalf("Invoke() = %v, %v, want the requests without key not limited", output.StatusCode, err)
+		}
+	}
+}
+
+func TestRateLimitMiddleware_NilOutput(t *testing.T) {
+	middleware, err := NewRateLimitMiddleware(RateLimitConfig{
+		RateLimit: RateLimit{Requests: 1, Period: time.Minute},
+		Algorithm: SlidingWindow{},
+		Key:       func(context.Context, Request) string { return "fixed" },
+	})
+	if err != nil {
+		t.Fatalf("NewRateLimitMiddleware() error = %v", err)
+	}
+	handler := middleware(MakeHandler(func(context.Context, Request, *Response) error { return nil }))
+	_ = handler.Invoke(context.Background(), &mockRequest{}, nil)
+	if err := handler.Invoke(context.Background(), &mockRequest{}, nil); !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("Invoke() error = %v, want ErrTooManyRequests", err)
+	}
+}
+
+func TestNewRateLimitMiddleware_InvalidLimit(t *testing.T) {
+	for _, limit := range []RateLimit{{}, {Requests: 1}, {Period: time.Minute}, {Requests: -1, Period: time.Minute}} {
+		if _, err := NewRateLimitMiddleware(RateLimitConfig{RateLimit: limit}); err == nil {
+			t.Errorf("NewRateLimitMiddleware(%+v) error = nil, want error", limit)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package httpadpt
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	HeaderXRequestID  = "X-Request-Id"
+	HeaderTraceparent = "Traceparent"
+
+	// MaxRequestIDLength is the maximum length of a request ID accepted from the client
+	MaxRequestIDLength = 128
+)
+
+type (
+	// RequestIDConfig configures the middleware created by NewRequestIDMiddleware
+	RequestIDConfig struct {
+		// Header is the request header read and the response header written, it is X-Request-Id if empty
+		Header string
+
+		// Generator creates the IDs of the requests that do not have one, it is NewUUIDv7 if nil
+		Generator RequestIDGenerator
+
+		// IgnoreIncoming makes the middleware generate the IDs even if the client sends one
+		IgnoreIncoming bool
+
+		// UseTraceparent uses the trace ID of the W3C traceparent header when the request has no ID header
+		UseTraceparent bool
+	}
+
+	requestIDMiddleware struct {
+		config    RequestIDConfig
+		decorated Handler
+	}
+)
+
+// NewRequestIDMiddleware creates a middleware that identifies every request. It accepts the ID sent by the client,
+// if it is valid, or generates a new one, adds it to the context and to the Request, so the handlers can receive
+// it with the requestid input tag, and echoes it in the response header. The log middleware adds it as "xid" to its
+// log line and to the logger it gives to the handlers through DefaultLoggerProvider, and DefaultLoggerProvider adds it
+// to the default logger outside the log middleware, so add this middleware before the log one.
+func NewRequestIDMiddleware(config RequestIDConfig) Middleware {
+	if config.Header == "" {
+		config.Header = HeaderXRequestID
+	}
+	if config.Generator == nil {
+		config.Generator = NewUUIDv7
+	}
+	return func(next Handler) Handler {
+		return requestIDMiddleware{config: config, decorated: next}
+	}
+}
+
+func (r requestIDMiddleware) Invoke(ctx context.Context, input Request, output *Response) error {
+	requestID := r.incomingRequestID(input)
+	if requestID == "" {
+		requestID = r.config.Generator()
+	}
+	if output != nil {
+		defer setHeaderValue(output, r.config.Header, requestID)
+	}
+
+	ctx = RequestIDToContext(ctx, requestID)
+	return r.decorated.Invoke(ctx, WithRequestValue(input, RequestIDKey{}, requestID), output)
+}
+
+func (r requestIDMiddleware) incomingRequestID(input Request) string {
+	if r.config.IgnoreIncoming {
+		return ""
+	}
+	if requestID := strings.TrimSpace(getFirstHeaderValue(input, r.config.Header)); isValidRequestID(requestID) {
+		return requestID
+	}
+	if r.config.UseTraceparent {
+		return getTraceID(getFirstHeaderValue(input, HeaderTraceparent))
+	}
+	return ""
+}
+
+// isValidRequestID accepts the IDs with up to MaxRequestIDLength printable ASCII characters that are safe to log
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > MaxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		if c <= ' ' || c > '~' || c == '"' || c == '\\' {
+			return false
+		}
+	}
+	return true
+}
+
+// getTraceID returns the trace ID of a traceparent header like "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func getTraceID(traceparent string) string {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || parts[1] == strings.Repeat("0", 32) {
+		return ""
+	}
+	for _, c := range parts[1] {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return ""
+		}
+	}
+	return parts[1]
+}
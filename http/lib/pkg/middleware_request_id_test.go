@@ -0,0 +1,163 @@
+package httpadpt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   RequestIDConfig
+		headers  map[string][]string
+		expected string
+	}{
+		{
+			name:     "incoming ID",
+			headers:  map[string][]string{HeaderXRequestID: {"client-id-1"}},
+			expected: "client-id-1",
+		},
+		{
+			name:     "invalid incoming ID",
+			headers:  map[string][]string{HeaderXRequestID: {"bad id"}},
+			expected: "generated",
+		},
+		{
+			name:     "ignored incoming ID",
+			config:   RequestIDConfig{IgnoreIncoming: true},
+			headers:  map[string][]string{HeaderXRequestID: {"client-id-1"}},
+			expected: "generated",
+		},
+		{
+			name:     "traceparent",
+			config:   RequestIDConfig{UseTraceparent: true},
+			headers:  map[string][]string{HeaderTraceparent: {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}},
+			expected: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:     "invalid traceparent",
+			config:   RequestIDConfig{UseTraceparent: true},
+			headers:  map[string][]string{HeaderTraceparent: {"00-00000000000000000000000000000000-00f067aa0ba902b7-01"}},
+			expected: "generated",
+		},
+		{
+			name:     "custom header",
+			config:   RequestIDConfig{Header: "X-Correlation-Id"},
+			headers:  map[string][]string{"X-Correlation-Id": {"corr-1"}},
+			expected: "corr-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Generator = func() string { return "generated" }
+			var ctxID, inputID string
+			handler := NewRequestIDMiddleware(tt.config)(MakeHandler(func(ctx context.Context, input Request, _ *Response) error {
+				ctxID, _ = RequestIDFromContext(ctx)
+				inputID, _ = RequestIDFromRequest(input)
+				return nil
+			}))
+			output := &Response{}
+			if err := handler.Invoke(context.Background(), &mockRequest{header: &mockHeaderParams{values: tt.headers}}, output); err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if ctxID != tt.expected || inputID != tt.expected {
+				t.Errorf("context ID = %q, request ID = %q, want %q", ctxID, inputID, tt.expected)
+			}
+			header := tt.config.Header
+			if header == "" {
+				header = HeaderXRequestID
+			}
+			if got := output.Header[header]; len(got) != 1 || got[0] != tt.expected {
+				t.Errorf("response %s = %v, want %q", header, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddleware_Tag(t *testing.T) {
+	type handlerInput struct {
+		RequestID string `requestid:""`
+	}
+	var got string
+	binding := NewBindingBuilderUsingPath("/").WithMethods("GET").
+		WithHandlerFunc(func(input handlerInput) error {
+			got = input.RequestID
+			return nil
+		})
+	handler := NewRequestIDMiddleware(RequestIDConfig{})(binding.Handler)
+	output := &Response{}
+	input := &mockRequest{header: &mockHeaderParams{values: map[string][]string{HeaderXRequestID: {"tag-id"}}}}
+	if err := handler.Invoke(context.Background(), input, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if got != "tag-id" {
+		t.Errorf("requestid tag = %q, want tag-id", got)
+	}
+}
+
+func TestRequestIDMiddleware_Logs(t *testing.T) {
+	var buf bytes.Buffer
+	original := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(original)
+
+	handler := WrapHandlerWithMiddlewares(
+		MakeHandler(func(ctx context.Context, _ Request, _ *Response) error {
+			DefaultLoggerProvider(ctx).Info("handler")
+			return nil
+		}),
+		Middlewares{NewRequestIDMiddleware(RequestIDConfig{}), NewHandleWithLoggerProviderMiddleware(DefaultLoggerProvider)},
+	)
+	input := &mockRequest{
+		url:    &url.URL{Path: "/"},
+		header: &mockHeaderParams{values: map[string][]string{HeaderXRequestID: {"log-id"}}},
+	}
+	if err := handler.Invoke(context.Background(), input, &Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log lines = %q, want 2", lines)
+	}
+	for _, line := range lines {
+		if strings.Count(line, `"xid"`) != 1 || !strings.Contains(line, `"xid":"log-id"`) {
+			t.Errorf("log line = %s, want one xid log-id", line)
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Errorf("log line is not JSON: %v", err)
+		}
+	}
+
+	buf.Reset()
+	DefaultLoggerProvider(RequestIDToContext(context.Background(), "ctx-id")).Info("outside")
+	if !strings.Contains(buf.String(), `"xid":"ctx-id"`) {
+		t.Errorf("DefaultLoggerProvider() log = %s, want xid ctx-id", buf.String())
+	}
+}
+
+func TestRequestIDMiddleware_LoggerProvider(t *testing.T) {
+	var providedID string
+	provider := func(ctx context.Context) *slog.Logger {
+		providedID, _ = RequestIDFromContext(ctx)
+		return slog.New(slog.DiscardHandler)
+	}
+	handler := WrapHandlerWithMiddlewares(
+		MakeHandler(func(context.Context, Request, *Response) error { return nil }),
+		Middlewares{NewRequestIDMiddleware(RequestIDConfig{}), NewHandleWithLoggerProviderMiddleware(provider)},
+	)
+	input := &mockRequest{header: &mockHeaderParams{values: map[string][]string{HeaderXRequestID: {"provider-id"}}}}
+	if err := handler.Invoke(context.Background(), input, &Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if providedID != "provider-id" {
+		t.Errorf("RequestIDFromContext() in the provider = %q, want provider-id", providedID)
+	}
+}
@@ -0,0 +1,104 @@
+package httpadpt
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func newRecordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next Handler) Handler {
+		return MakeHandler(func(ctx context.Context, input Request, output *Response) error {
+			*calls = append(*calls, name)
+			return next.Invoke(ctx, input, output)
+		})
+	}
+}
+
+func newRecordingHandler(calls *[]string) Handler {
+	return MakeHandler(func(context.Context, Request, *Response) error {
+		*calls = append(*calls, "handler")
+		return nil
+	})
+}
+
+func TestWrapHandlerWithMiddlewares_OutermostFirst(t *testing.T) {
+	var calls []string
+	handler := WrapHandlerWithMiddlewares(newRecordingHandler(&calls), Middlewares{
+		newRecordingMiddleware("first", &calls),
+		newRecordingMiddleware("second", &calls),
+	})
+	if err := handler.Invoke(context.Background(), &mockRequest{}, &Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if expected := []string{"first", "second", "handler"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v, want %v", calls, expected)
+	}
+}
+
+func TestMiddlewareGroup_Matches(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		path     string
+		expected bool
+	}{
+		{prefix: "/admin/*", path: "/admin", expected: true},
+		{prefix: "/admin/*", path: "/admin/users/{id}", expected: true},
+		{prefix: "/admin", path: "/admin/users", expected: true},
+		{prefix: "/admin/", path: "/administrator", expected: false},
+		{prefix: "/admin/*", path: "/api/admin", expected: false},
+		{prefix: "/*", path: "/anything", expected: true},
+		{prefix: "", path: "/anything", expected: true},
+	}
+	for _, tt := range tests {
+		if got := (MiddlewareGroup{PathPrefix: tt.prefix}).Matches(tt.path); got != tt.expected {
+			t.Errorf("MiddlewareGroup{%q}.Matches(%q) = %v, want %v", tt.prefix, tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestWrapBindingHandler(t *testing.T) {
+	var calls []string
+	config := Config{
+		Middlewares: Middlewares{newRecordingMiddleware("global", &calls), newRecordingMiddleware("global-2", &calls)},
+		MiddlewareGroups: MiddlewareGroups{
+			{PathPrefix: "/admin/*", Middlewares: Middlewares{
+				newRecordingMiddleware("admin", &calls),
+				newRecordingMiddleware("admin-2", &calls),
+			}},
+			{PathPrefix: "/api/*", Middlewares: Middlewares{newRecordingMiddleware("api", &calls)}},
+			{PathPrefix: "/admin/users/*", Middlewares: Middlewares{newRecordingMiddleware("admin-users", &calls)}},
+		},
+	}
+	path := "/admin/users"
+	binding := Binding{
+		Condition:   Condition{Path: &path, Methods: []string{"GET"}},
+		Handler:     newRecordingHandler(&calls),
+		Middlewares: Middlewares{newRecordingMiddleware("binding", &calls)},
+	}
+
+	if err := WrapBindingHandler(binding, config).Invoke(context.Background(), &mockRequest{}, &Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if expected := []string{"global", "global-2", "admin", "admin-2", "admin-users", "binding", "handler"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v, want %v", calls, expected)
+	}
+}
+
+func TestWrapBindingHandler_Route(t *testing.T) {
+	var route string
+	path := "/orders/{id}"
+	binding := Binding{
+		Condition: Condition{Path: &path, Methods: []string{"GET"}},
+		Handler: MakeHandler(func(_ context.Context, input Request, _ *Response) error {
+			route, _ = RouteFromRequest(input)
+			return nil
+		}),
+	}
+	if err := WrapBindingHandler(binding, Config{}).Invoke(context.Background(), &mockRequest{}, &Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if route != path {
+		t.Errorf("RouteFromRequest() = %q, want %q", route, path)
+	}
+}
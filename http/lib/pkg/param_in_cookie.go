@@ -0,0 +1,40 @@
+package httpadpt
+
+import (
+	"errors"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().AddOption3(TagCookie, createCookieInParamGetter)
+}
+
+func createCookieInParamGetter(tagValue string) (func(Request) (any, error), error) {
+	tag, err := parseCookieTag(tagValue)
+	if err != nil {
+		return nil, err
+	}
+	return func(input Request) (any, error) {
+		return getCookieInParamValue(input, tag)
+	}, nil
+}
+
+// getCookieInParamValue returns the cookie value. A signed or encrypted cookie that fails the verification is
+// handled as if it was not sent, so a stale or forged cookie is caught by the mandatory assertion.
+func getCookieInParamValue(input Request, tag cookieTag) (any, error) {
+	var err error
+	if IsRequestCookieNil(input, &err) {
+		return nil, err
+	}
+	value, found := GetCookie(input).GetValue(tag.template.Name)
+	if !found {
+		return nil, nil
+	}
+	decoded, err := tag.decode(cookieKeyRingFromRequest(input), value)
+	if errors.Is(err, ErrInvalidCookieValue) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
@@ -0,0 +1,102 @@
+package httpadpt
+
+import (
+	"testing"
+)
+
+func Test_getCookieInParamValue(t *testing.T) {
+	ring := newTestCookieKeyRing(t, 1)
+	encrypted, _ := ring.Encrypt("session", "secret")
+
+	tests := []struct {
+		name     string
+		tagValue string
+		cookies  map[string]string
+		expected any
+	}{
+		{
+			name:     "plain cookie found",
+			tagValue: "session",
+			cookies:  map[string]string{"session": "abc"},
+			expected: "abc",
+		},
+		{
+			name:     "cookie not found",
+			tagValue: "session",
+			cookies:  map[string]string{"other": "abc"},
+			expected: nil,
+		},
+		{
+			name:     "signed cookie verified",
+			tagValue: "session,signed",
+			cookies:  map[string]string{"session": ring.Sign("session", "user-42")},
+			expected: "user-42",
+		},
+		{
+			name:     "forged signed cookie is ignored",
+			tagValue: "session,signed",
+			cookies:  map[string]string{"session": "user-42"},
+			expected: nil,
+		},
+		{
+			name:     "encrypted cookie decrypted",
+			tagValue: "session,encrypted",
+			cookies:  map[string]string{"session": encrypted},
+			expected: "secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter, err := createCookieInParamGetter(tt.tagValue)
+			if err != nil {
+				t.Fatalf("createCookieInParamGetter() error = %v", err)
+			}
+			input := WithRequestValue(&mockRequest{cookie: &mockCookieParams{values: tt.cookies}}, cookieKeyRingKey{}, ring)
+			result, err := getter(input)
+			if err != nil {
+				t.Fatalf("getter() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("getter() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_getCookieInParamValue_WithoutKeyRing(t *testing.T) {
+	getter, _ := createCookieInParamGetter("session,signed")
+	if _, err := getter(&mockRequest{cookie: &mockCookieParams{values: map[string]string{"session": "abc"}}}); err == nil {
+		t.Error("getter() error = nil, want error when Config.CookieKeyRing is not set")
+	}
+}
+
+func Test_getCookieInParamValue_WithoutCookies(t *testing.T) {
+	getter, _ := createCookieInParamGetter("session")
+	if _, err := getter(struct{ Request }{Request: &mockRequest{}}); err == nil {
+		t.Error("getter() error = nil, want error when the request is not a CookieRequest")
+	}
+}
+
+func Test_getCookieInParamValue_ThroughHandler(t *testing.T) {
+	type handlerInput struct {
+		Session string `cookie:"session" assert:"mandatory"`
+	}
+	type handlerOutput struct {
+		Body string `body:""`
+	}
+	var got handlerInput
+	handler := NewBindingBuilderUsingPath("/me").WithMethods("GET").
+		WithHandlerFunc(func(input handlerInput) (*handlerOutput, error) {
+			got = input
+			return &handlerOutput{Body: "ok"}, nil
+		}).Handler
+
+	req := &mockRequest{cookie: &mockCookieParams{values: map[string]string{"session": "abc"}}}
+	if err := handler.Invoke(t.Context(), req, &Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if got.Session != "abc" {
+		t.Errorf("Invoke() session = %q, want %q", got.Session, "abc")
+	}
+}
@@ -0,0 +1,278 @@
+package httpadpt
+
+import (
+	"bytes"
+	"fmt"
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	convertererror "github.com/smart-libs/go-crosscutting/converter/lib/pkg/error"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"io"
+	"math"
+	"mime"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	TagFile = "file"
+
+	// AssertFileContentType is the assertion that checks the uploaded file content type against a list of media
+	// types separated by "|", a subtype "*" matches any subtype, for instance `assert:"contentType=image/*|text/csv"`
+	AssertFileContentType = "contentType"
+
+	// AssertFileMaxSize is the assertion that checks the uploaded file size, the value is given in bytes or using
+	// the suffixes KB, MB and GB, for instance `assert:"maxSize=10MB"`
+	AssertFileMaxSize = "maxSize"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().AddOption5(TagFile, createFileInParamSpec)
+
+	converter.AddHandler[[]*multipart.FileHeader, *multipart.FileHeader](ConverterRegistry, firstFileHeaderFromArray)
+	converter.AddHandler[[]*multipart.FileHeader, []byte](ConverterRegistry, fileBytesFromArray)
+	converter.AddHandler[*multipart.FileHeader, []byte](ConverterRegistry, fileBytesFromFileHeader)
+}
+
+var (
+	fileAssertionFactories = map[string]func(arg string) (sdkparam.Option, error){
+		AssertFileContentType: createFileContentTypeAssertion,
+		AssertFileMaxSize:     createFileMaxSizeAssertion,
+	}
+)
+
+// createFileInParamSpec adds to the options of the assert tag the file assertions, they are declared as name=argument,
+// for instance `assert:"mandatory,contentType=image/*,maxSize=10MB"`
+func createFileInParamSpec(fieldName string, field reflect.StructField) ([]sdkparam.Option, func(Request) (any, error), error) {
+	options, err := tagbased.CreateParamSpecOptionsFromTag(field, Converters)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, assertion := range strings.Split(field.Tag.Get("assert"), ",") {
+		name, arg, hasArg := strings.Cut(assertion, "=")
+		if !hasArg {
+			continue
+		}
+		factory, found := fileAssertionFactories[name]
+		if !found {
+			return nil, nil, fmt.Errorf("unknown file assert named=[%s] in the field=[%s]", name, field.Name)
+		}
+		option, err := factory(arg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create file assert named=[%s] with arg=[%s] in the field=[%s]: %w",
+				name, arg, field.Name, err)
+		}
+		options = append(options, option)
+	}
+	return options, func(input Request) (any, error) {
+		return getFileInParamValue(input, fieldName)
+	}, nil
+}
+
+func getFileInParamValue(input Request, fieldName string) (any, error) {
+	var err error
+	if IsRequestFormNil(input, &err) {
+		return nil, err
+	}
+	form, _ := GetForm(input)
+	value, found := form.GetFiles(fieldName)
+	if !found {
+		return nil, nil
+	}
+	return value, nil
+}
+
+// firstFileHeaderFromArray is used to return the first file of a multipart field. Like query parameters, a multipart
+// field may carry many files, but most of the time it has only one.
+func firstFileHeaderFromArray(files []*multipart.FileHeader, first **multipart.FileHeader) error {
+	if len(files) > 0 {
+		*first = files[0]
+	} else {
+		*first = nil
+	}
+	return nil
+}
+
+type (
+	// fileReaderConverters converts the uploaded files into io.Reader. It is needed because the ConverterRegistry
+	// cannot hold conversion functions whose target type is an interface.
+	fileReaderConverters struct{}
+)
+
+var readerType = reflect.TypeFor[io.Reader]()
+
+func (fileReaderConverters) Convert(from any, to any) error {
+	if reader, ok := to.(*io.Reader); ok && reader != nil {
+		switch files := from.(type) {
+		case []*multipart.FileHeader:
+			return fileReaderFromArray(files, reader)
+		case *multipart.FileHeader:
+			return fileReaderFromFileHeader(files, reader)
+		}
+	}
+	return convertererror.NewConversionNotFoundError(from, to)
+}
+
+func (c fileReaderConverters) ConvertToType(from any, toType reflect.Type) (any, error) {
+	if toType == readerType {
+		var reader io.Reader
+		if err := c.Convert(from, &reader); err != nil {
+			return nil, err
+		}
+		return reader, nil
+	}
+	return nil, convertererror.NewConversionNotFoundError(from, toType)
+}
+
+func fileBytesFromArray(files []*multipart.FileHeader, content *[]byte) error {
+	if len(files) == 0 {
+		*content = nil
+		return nil
+	}
+	return fileBytesFromFileHeader(files[0], content)
+}
+
+func fileReaderFromArray(files []*multipart.FileHeader, reader *io.Reader) error {
+	if len(files) == 0 {
+		*reader = nil
+		return nil
+	}
+	return fileReaderFromFileHeader(files[0], reader)
+}
+
+func fileBytesFromFileHeader(file *multipart.FileHeader, content *[]byte) error {
+	if file == nil {
+		*content = nil
+		return nil
+	}
+	f, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open file=[%s]: %w", file.Filename, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	buffer := bytes.NewBuffer(make([]byte, 0, file.Size))
+	if _, err := io.Copy(buffer, f); err != nil {
+		return fmt.Errorf("failed to read file=[%s]: %w", file.Filename, err)
+	}
+	*content = buffer.Bytes()
+	return nil
+}
+
+// fileReaderFromFileHeader opens the uploaded file, the reader is a multipart.File that the handler may close.
+func fileReaderFromFileHeader(file *multipart.FileHeader, reader *io.Reader) error {
+	if file == nil {
+		*reader = nil
+		return nil
+	}
+	f, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open file=[%s]: %w", file.Filename, err)
+	}
+	*reader = f
+	return nil
+}
+
+// fileAssertion applies the check to every file header in the value. A nil value is accepted because the presence
+// of the file is checked by the mandatory assertion.
+func fileAssertion(check func(spec sdkparam.Spec, file *multipart.FileHeader) error) sdkparam.Option {
+	return func(spec sdkparam.Spec, value any) (any, error) {
+		var files []*multipart.FileHeader
+		switch v := value.(type) {
+		case nil:
+			return nil, nil
+		case []*multipart.FileHeader:
+			files = v
+		case *multipart.FileHeader:
+			files = []*multipart.FileHeader{v}
+		default:
+			return nil, fmt.Errorf("%s=[%v] is not a file, it is [%T]", spec.Name(), value, value)
+		}
+		for _, file := range files {
+			if file == nil {
+				continue
+			}
+			if err := check(spec, file); err != nil {
+				return nil, err
+			}
+		}
+		return value, nil
+	}
+}
+
+func createFileContentTypeAssertion(arg string) (sdkparam.Option, error) {
+	accepted := strings.Split(arg, "|")
+	for i := range accepted {
+		accepted[i] = strings.ToLower(strings.TrimSpace(accepted[i]))
+		if !strings.Contains(accepted[i], "/") {
+			return nil, fmt.Errorf("invalid media type=[%s]", accepted[i])
+		}
+	}
+
+	return fileAssertion(func(spec sdkparam.Spec, file *multipart.FileHeader) error {
+		contentType := file.Header.Get("Content-Type")
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err == nil {
+			for _, pattern := range accepted {
+				if mediaTypeMatches(pattern, mediaType) {
+					return nil
+				}
+			}
+		}
+		return serror.IllegalArgumentValueWithCause(spec.Name(), contentType,
+			fmt.Errorf("%s: file=[%s] content type=[%s] is not one of [%s]", spec.Name(), file.Filename, contentType, arg))
+	}), nil
+}
+
+func mediaTypeMatches(pattern, mediaType string) bool {
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+	if prefix, found := strings.CutSuffix(pattern, "/*"); found {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+	return false
+}
+
+func createFileMaxSizeAssertion(arg string) (sdkparam.Option, error) {
+	maxSize, err := parseByteSize(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return fileAssertion(func(spec sdkparam.Spec, file *multipart.FileHeader) error {
+		if file.Size > maxSize {
+			return serror.IllegalArgumentValueWithCause(spec.Name(), file.Size,
+				fmt.Errorf("%s: file=[%s] size=[%d] exceeds the maximum size=[%d]", spec.Name(), file.Filename, file.Size, maxSize))
+		}
+		return nil
+	}), nil
+}
+
+// parseByteSize parses sizes like 1024, 512KB, 10MB or 1GB, the sizes that overflow int64 are invalid
+func parseByteSize(value string) (int64, error) {
+	multipliers := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{suffix: "GB", multiplier: 1 << 30},
+		{suffix: "MB", multiplier: 1 << 20},
+		{suffix: "KB", multiplier: 1 << 10},
+		{suffix: "B", multiplier: 1},
+	}
+	number, multiplier := strings.ToUpper(strings.TrimSpace(value)), int64(1)
+	for _, m := range multipliers {
+		if trimmed, found := strings.CutSuffix(number, m.suffix); found {
+			number, multiplier = strings.TrimSpace(trimmed), m.multiplier
+			break
+		}
+	}
+	size, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || size < 0 || size > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("invalid size=[%s]", value)
+	}
+	return size * multiplier, nil
+}
@@ -0,0 +1,222 @@
+package httpadpt
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"testing"
+)
+
+// newTestFileHeaders creates the file headers of a multipart form with one file per given content
+func newTestFileHeaders(t *testing.T, fieldName, contentType string, contents ...string) []*multipart.FileHeader {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for i, content := range contents {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="`+fieldName+`"; filename="file`+string(rune('0'+i))+`"`)
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			t.Fatalf("CreatePart() error = %v", err)
+		}
+		_, _ = part.Write([]byte(content))
+	}
+	_ = writer.Close()
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("ReadForm() error = %v", err)
+	}
+	t.Cleanup(func() { _ = form.RemoveAll() })
+	return form.File[fieldName]
+}
+
+func Test_getFileInParamValue(t *testing.T) {
+	files := newTestFileHeaders(t, "upload", "text/csv", "a,b")
+	tests := []struct {
+		name        string
+		input       Request
+		expectError bool
+		expected    []*multipart.FileHeader
+	}{
+		{
+			name:     "file found",
+			input:    &mockRequest{form: &mockFormParams{files: map[string][]*multipart.FileHeader{"upload": files}}},
+			expected: files,
+		},
+		{
+			name:     "file not found",
+			input:    &mockRequest{form: &mockFormParams{files: map[string][]*multipart.FileHeader{"other": files}}},
+			expected: nil,
+		},
+		{
+			name:        "form parsing error",
+			input:       &mockRequest{formErr: errors.New("malformed body")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := getFileInParamValue(tt.input, "upload")
+			if (err != nil) != tt.expectError {
+				t.Fatalf("getFileInParamValue() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if tt.expectError {
+				return
+			}
+			if tt.expected == nil {
+				if result != nil {
+					t.Errorf("getFileInParamValue() = %v, want nil", result)
+				}
+				return
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("getFileInParamValue() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_fileConverters(t *testing.T) {
+	files := newTestFileHeaders(t, "upload", "text/plain", "first", "second")
+
+	var header *multipart.FileHeader
+	if err := Converters.Convert(files, &header); err != nil || header != files[0] {
+		t.Errorf("Convert() to *multipart.FileHeader = %v, %v, want first file", header, err)
+	}
+
+	var content []byte
+	if err := Converters.Convert(files, &content); err != nil || string(content) != "first" {
+		t.Errorf("Convert() to []byte = %q, %v, want %q", content, err, "first")
+	}
+
+	var reader io.Reader
+	if err := Converters.Convert(files, &reader); err != nil {
+		t.Fatalf("Convert() to io.Reader error = %v", err)
+	}
+	read, _ := io.ReadAll(reader)
+	if string(read) != "first" {
+		t.Errorf("Convert() to io.Reader read = %q, want %q", read, "first")
+	}
+
+	var all []*multipart.FileHeader
+	if err := Converters.Convert(files, &all); err != nil || len(all) != 2 {
+		t.Errorf("Convert() to []*multipart.FileHeader = %v, %v, want 2 files", all, err)
+	}
+}
+
+func Test_fileAssertions_ThroughHandler(t *testing.T) {
+	type handlerInput struct {
+		Content []byte `file:"upload" assert:"mandatory,contentType=image/*|text/csv,maxSize=8B"`
+	}
+	type handlerOutput struct {
+		StatusCode int `statuscode:""`
+	}
+	var got handlerInput
+	handler := HandlePanic(NewBindingBuilderUsingPath("/upload").WithMethods("POST").
+		WithHandlerFunc(func(input handlerInput) (*handlerOutput, error) {
+			got = input
+			return &handlerOutput{StatusCode: http.StatusCreated}, nil
+		}).Handler)
+
+	tests := []struct {
+		name           string
+		files          []*multipart.FileHeader
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "accepted csv",
+			files:          newTestFileHeaders(t, "upload", "text/csv", "a,b"),
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "a,b",
+		},
+		{
+			name:           "accepted image with parameters",
+			files:          newTestFileHeaders(t, "upload", "image/png; q=1", "png"),
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "png",
+		},
+		{
+			name:           "rejected content type",
+			files:          newTestFileHeaders(t, "upload", "application/pdf", "pdf"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "rejected size",
+			files:          newTestFileHeaders(t, "upload", "text/csv", "a,b,c,d,e"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = handlerInput{}
+			req := &mockRequest{form: &mockFormParams{files: map[string][]*multipart.FileHeader{"upload": tt.files}}}
+			resp := Response{}
+			if err := handler.Invoke(t.Context(), req, &resp); err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if resp.StatusCode == nil || *resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Invoke() status = %v, want %d, body = %s", resp.StatusCode, tt.expectedStatus, resp.Body)
+			}
+			if string(got.Content) != tt.expectedBody {
+				t.Errorf("Invoke() content = %q, want %q", got.Content, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func Test_parseByteSize(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    int64
+		expectError bool
+	}{
+		{value: "1024", expected: 1024},
+		{value: "8B", expected: 8},
+		{value: "512KB", expected: 512 << 10},
+		{value: "10mb", expected: 10 << 20},
+		{value: "1 GB", expected: 1 << 30},
+		{value: "abc", expectError: true},
+		{value: "-1", expectError: true},
+		{value: "9999999999GB", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			size, err := parseByteSize(tt.value)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("parseByteSize() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if size != tt.expected {
+				t.Errorf("parseByteSize() = %d, want %d", size, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_mediaTypeMatches(t *testing.T) {
+	tests := []struct {
+		pattern, mediaType string
+		expected           bool
+	}{
+		{pattern: "image/*", mediaType: "image/png", expected: true},
+		{pattern: "text/csv", mediaType: "text/csv", expected: true},
+		{pattern: "*/*", mediaType: "application/json", expected: true},
+		{pattern: "image/*", mediaType: "imagex/png", expected: false},
+		{pattern: "text/csv", mediaType: "text/plain", expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := mediaTypeMatches(tt.pattern, tt.mediaType); got != tt.expected {
+			t.Errorf("mediaTypeMatches(%q, %q) = %v, want %v", tt.pattern, tt.mediaType, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package httpadpt
+
+const (
+	TagForm = "form"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().AddOption2(TagForm, getFormInParamValue)
+}
+
+func getFormInParamValue(input Request, fieldName string) (any, error) {
+	var err error
+	if IsRequestFormNil(input, &err) {
+		return nil, err
+	}
+	form, _ := GetForm(input)
+	value, found := form.GetValue(fieldName)
+	if !found {
+		return nil, nil
+	}
+	return value, nil
+}
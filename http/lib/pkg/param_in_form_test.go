@@ -0,0 +1,106 @@
+package httpadpt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_getFormInParamValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       Request
+		fieldName   string
+		expectError bool
+		expected    []string
+	}{
+		{
+			name:      "form field found",
+			input:     &mockRequest{form: &mockFormParams{values: map[string][]string{"name": {"value1", "value2"}}}},
+			fieldName: "name",
+			expected:  []string{"value1", "value2"},
+		},
+		{
+			name:      "form field not found",
+			input:     &mockRequest{form: &mockFormParams{values: map[string][]string{"other": {"value"}}}},
+			fieldName: "name",
+			expected:  nil,
+		},
+		{
+			name:      "nil form values map",
+			input:     &mockRequest{form: &mockFormParams{}},
+			fieldName: "name",
+			expected:  nil,
+		},
+		{
+			name:        "nil form",
+			input:       &mockRequest{},
+			fieldName:   "name",
+			expectError: true,
+		},
+		{
+			name:        "form parsing error",
+			input:       &mockRequest{formErr: errors.New("malformed body")},
+			fieldName:   "name",
+			expectError: true,
+		},
+		{
+			name:        "request without form",
+			input:       struct{ Request }{Request: &mockRequest{form: &mockFormParams{}}},
+			fieldName:   "name",
+			expectError: true,
+		},
+		{
+			name:      "form of a request with values",
+			input:     WithRequestValue(&mockRequest{form: &mockFormParams{values: map[string][]string{"name": {"v"}}}}, "k", "v"),
+			fieldName: "name",
+			expected:  []string{"v"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := getFormInParamValue(tt.input, tt.fieldName)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("getFormInParamValue() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if tt.expectError {
+				return
+			}
+			if tt.expected == nil {
+				if result != nil {
+					t.Errorf("getFormInParamValue() = %v, want nil", result)
+				}
+				return
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("getFormInParamValue() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_getFormInParamValue_ThroughHandler(t *testing.T) {
+	type handlerInput struct {
+		Name  string   `form:"name" assert:"mandatory"`
+		Roles []string `form:"role"`
+	}
+	type handlerOutput struct {
+		Body string `body:""`
+	}
+	var got handlerInput
+	handler := NewBindingBuilderUsingPath("/form").WithMethods("POST").
+		WithHandlerFunc(func(input handlerInput) (*handlerOutput, error) {
+			got = input
+			return &handlerOutput{Body: "ok"}, nil
+		}).Handler
+
+	req := &mockRequest{form: &mockFormParams{values: map[string][]string{"name": {"john"}, "role": {"admin", "user"}}}}
+	resp := Response{}
+	if err := handler.Invoke(t.Context(), req, &resp); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if got.Name != "john" || !reflect.DeepEqual(got.Roles, []string{"admin", "user"}) {
+		t.Errorf("Invoke() input = %+v, want name=john roles=[admin user]", got)
+	}
+}
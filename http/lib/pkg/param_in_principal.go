@@ -0,0 +1,50 @@
+package httpadpt
+
+import (
+	"fmt"
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+)
+
+const (
+	// TagPrincipal binds the Principal authenticated by the middleware created by NewAuthMiddleware
+	TagPrincipal = "principal"
+
+	// TagClaim binds one claim of the authenticated Principal, like `claim:"sub"`
+	TagClaim = "claim"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().AddOption2(TagPrincipal, getPrincipalInParamValue)
+	getInputParamSpecFactoryRegistry().AddOption2(TagClaim, getClaimInParamValue)
+	converter.AddHandler[[]any, []string](ConverterRegistry, stringArrayFromAnyArray)
+}
+
+func getPrincipalInParamValue(input Request, _ string) (any, error) {
+	principal, found := PrincipalFromRequest(input)
+	if !found {
+		return nil, nil
+	}
+	return principal, nil
+}
+
+func getClaimInParamValue(input Request, claimName string) (any, error) {
+	principal, found := PrincipalFromRequest(input)
+	if !found {
+		return nil, nil
+	}
+	value, found := principal.Claims[claimName]
+	if !found {
+		return nil, nil
+	}
+	return value, nil
+}
+
+// stringArrayFromAnyArray is used to bind JSON arrays, like the "roles" claim, to []string
+func stringArrayFromAnyArray(values []any, target *[]string) error {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, fmt.Sprint(value))
+	}
+	*target = result
+	return nil
+}
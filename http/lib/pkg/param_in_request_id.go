@@ -0,0 +1,18 @@
+package httpadpt
+
+const (
+	// TagRequestID binds the request ID added by the middleware created by NewRequestIDMiddleware
+	TagRequestID = "requestid"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().AddOption2(TagRequestID, getRequestIDInParamValue)
+}
+
+func getRequestIDInParamValue(input Request, _ string) (any, error) {
+	requestID, found := RequestIDFromRequest(input)
+	if !found {
+		return nil, nil
+	}
+	return requestID, nil
+}
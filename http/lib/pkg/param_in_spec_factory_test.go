@@ -6,6 +6,8 @@ import (
 
 func Test_getInputParamSpecFactoryRegistry(t *testing.T) {
 	// Reset the global registry for testing
+	saved := inParamSpecFactoryRegistry
+	t.Cleanup(func() { inParamSpecFactoryRegistry = saved })
 	inParamSpecFactoryRegistry = nil
 
 	// First call should initialize
@@ -23,6 +25,8 @@ func Test_getInputParamSpecFactoryRegistry(t *testing.T) {
 
 func Test_createInParamSpecFactory(t *testing.T) {
 	// Reset the global registry for testing
+	saved := inParamSpecFactoryRegistry
+	t.Cleanup(func() { inParamSpecFactoryRegistry = saved })
 	inParamSpecFactoryRegistry = nil
 
 	// This should not panic even if registry is nil (it should initialize it)
@@ -39,6 +43,8 @@ func Test_createInParamSpecFactory(t *testing.T) {
 
 func Test_createInParamSpecFactory_InitializesRegistry(t *testing.T) {
 	// Reset the global registry
+	saved := inParamSpecFactoryRegistry
+	t.Cleanup(func() { inParamSpecFactoryRegistry = saved })
 	inParamSpecFactoryRegistry = nil
 
 	// Call createInParamSpecFactory which should initialize the registry
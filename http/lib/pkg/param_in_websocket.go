@@ -0,0 +1,87 @@
+package httpadpt
+
+import (
+	"bytes"
+
+	sdkconverter "github.com/smart-libs/go-adapter/sdk/lib/pkg/converter"
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+)
+
+const (
+	// TagWebSocket gets the whole message with `ws:""`, or one of its fields with `ws:"name"`, unmarshalled into the
+	// field type
+	TagWebSocket = "ws"
+	// TagWebSocketType gets the message type, or sets the reply type
+	TagWebSocketType = "wstype"
+	// TagWebSocketValue gets a connection value set by WebSocketConn.SetValue
+	TagWebSocketValue = "wsvalue"
+)
+
+var (
+	// webSocketJSONConverters unmarshals the json.RawMessage of the message and its fields into any target type
+	webSocketJSONConverters = sdkconverter.JSONConverters{Name: "message"}
+
+	// WebSocketConverters is the list of converter.Converters used by the WebSocket handlers, it unmarshals the
+	// message values and then it tries the Converters
+	WebSocketConverters = converter.NewConvertersList(webSocketJSONConverters, Converters)
+
+	webSocketInParamSpecFactoryRegistry tagbased.InputParamSpecFactoryRegistry[WebSocketMessage]
+)
+
+func init() {
+	getWebSocketInputParamSpecFactoryRegistry().
+		AddOption2(TagWebSocket, getWebSocketInParamValue).
+		AddOption1(TagWebSocketType, "", func(input WebSocketMessage) (any, error) { return input.Type, nil }).
+		AddOption2(TagWebSocketValue, getWebSocketValueInParamValue).
+		// the upgrade request values
+		AddOption2(TagPath, fromUpgradeRequest(getPathInParamValue)).
+		AddOption2(TagQuery, fromUpgradeRequest(getQueryInParamValue)).
+		AddOption2(TagRequestHeader, fromUpgradeRequest(getHeaderInParamValue)).
+		AddOption2(TagPrincipal, fromUpgradeRequest(getPrincipalInParamValue)).
+		AddOption2(TagClaim, fromUpgradeRequest(getClaimInParamValue)).
+		AddOption2(TagRequestID, fromUpgradeRequest(getRequestIDInParamValue)).
+		AddOption3(TagCookie, func(tagValue string) (func(WebSocketMessage) (any, error), error) {
+			getter, err := createCookieInParamGetter(tagValue)
+			if err != nil {
+				return nil, err
+			}
+			return func(input WebSocketMessage) (any, error) { return getter(input.Conn.Request()) }, nil
+		})
+}
+
+func getWebSocketInputParamSpecFactoryRegistry() tagbased.InputParamSpecFactoryRegistry[WebSocketMessage] {
+	if webSocketInParamSpecFactoryRegistry == nil {
+		webSocketInParamSpecFactoryRegistry = tagbased.NewInputParamSpecFactoryRegistry[WebSocketMessage](WebSocketConverters)
+	}
+
+	return webSocketInParamSpecFactoryRegistry
+}
+
+func createWebSocketInParamSpecFactory() tagbased.InputParamSpecFactory[WebSocketMessage] {
+	return tagbased.NewsInputParamSpecFactory(getWebSocketInputParamSpecFactoryRegistry())
+}
+
+// getWebSocketInParamValue returns the message or its field, the absent and null fields are nil so the default and
+// mandatory options apply
+func getWebSocketInParamValue(input WebSocketMessage, fieldName string) (any, error) {
+	if fieldName == "" {
+		return input.Data, nil
+	}
+	value, found := input.fields[fieldName]
+	if !found || bytes.Equal(value, []byte("null")) {
+		return nil, nil
+	}
+	return value, nil
+}
+
+func getWebSocketValueInParamValue(input WebSocketMessage, key string) (any, error) {
+	value, _ := input.Conn.Value(key)
+	return value, nil
+}
+
+func fromUpgradeRequest(getter func(Request, string) (any, error)) func(WebSocketMessage, string) (any, error) {
+	return func(input WebSocketMessage, tagValue string) (any, error) {
+		return getter(input.Conn.Request(), tagValue)
+	}
+}
@@ -3,6 +3,7 @@ package httpadpt
 import (
 	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
 	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"io"
 )
 
 const (
@@ -13,12 +14,52 @@ func init() {
 	getOutParamSpecFactoryRegistry().AddOption1(TagBody, "", setBodyBytes)
 }
 
+// setStreamBody sets the Response stream fields if the value is one of the supported stream types: the Event and
+// chunk channels and Stream. It returns false if the value is not a stream, so that it must be converted to []byte.
+func setStreamBody(output *Response, value any) bool {
+	switch stream := value.(type) {
+	case <-chan Event:
+		output.Events = stream
+	case chan Event:
+		output.Events = stream
+	case <-chan []byte:
+		if stream != nil {
+			output.BodyStream = NewChanReader(stream)
+		}
+	case chan []byte:
+		if stream != nil {
+			output.BodyStream = NewChanReader(stream)
+		}
+	case Stream:
+		output.BodyStream = stream.Reader
+	case *Stream:
+		if stream != nil {
+			output.BodyStream = stream.Reader
+		}
+	default:
+		return false
+	}
+	return true
+}
+
 func setBodyBytes(output *Response, value any) error {
 	const fName = "httpadpt.setBodyBytes"
 	if err := IsResponseNil(output); err != nil {
 		return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
 	}
 
+	if setStreamBody(output, value) {
+		return nil
+	}
+	if reader, ok := value.(io.Reader); ok {
+		bodyBytes, err := readBody(reader)
+		if err != nil {
+			return serror.CmpError.Wrap(err, "%s: failed to read value=[%T]", fName, value)
+		}
+		output.Body = bodyBytes
+		return nil
+	}
+
 	bodyBytes, err := converter.To[[]byte](Converters, value)
 	if err != nil {
 		return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
@@ -26,3 +67,11 @@ func setBodyBytes(output *Response, value any) error {
 	output.Body = bodyBytes
 	return nil
 }
+
+// readBody reads the io.Reader given as body, it is closed if it is also an io.Closer
+func readBody(reader io.Reader) ([]byte, error) {
+	if closer, ok := reader.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
+	return io.ReadAll(reader)
+}
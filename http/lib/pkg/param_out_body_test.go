@@ -1,6 +1,9 @@
 package httpadpt
 
 import (
+	"bytes"
+	"io"
+	"strings"
 	"testing"
 )
 
@@ -230,3 +233,72 @@ func Test_setBodyBytes_MultipleCalls(t *testing.T) {
 		t.Errorf("setBodyBytes() second call body = %q, want %q", string(output.Body), "second")
 	}
 }
+
+func Test_setBodyBytes_Streams(t *testing.T) {
+	t.Run("Stream", func(t *testing.T) {
+		output := &Response{}
+		reader := strings.NewReader("stream")
+		if err := setBodyBytes(output, Stream{Reader: reader}); err != nil {
+			t.Fatalf("setBodyBytes() error = %v", err)
+		}
+		if output.BodyStream != reader {
+			t.Errorf("Expected BodyStream to be the given reader, got %v", output.BodyStream)
+		}
+		if output.Body != nil {
+			t.Errorf("Expected Body to remain nil, got %q", output.Body)
+		}
+	})
+
+	t.Run("io.Reader is buffered", func(t *testing.T) {
+		output := &Response{}
+		if err := setBodyBytes(output, bytes.NewBufferString("buffered")); err != nil {
+			t.Fatalf("setBodyBytes() error = %v", err)
+		}
+		if output.IsStreaming() {
+			t.Error("Expected the io.Reader not to be streamed")
+		}
+		if string(output.Body) != "buffered" {
+			t.Errorf("Body = %q, want %q", output.Body, "buffered")
+		}
+	})
+
+	t.Run("channel of chunks", func(t *testing.T) {
+		output := &Response{}
+		chunks := make(chan []byte, 2)
+		chunks <- []byte("a")
+		chunks <- []byte("b")
+		close(chunks)
+		if err := setBodyBytes(output, (<-chan []byte)(chunks)); err != nil {
+			t.Fatalf("setBodyBytes() error = %v", err)
+		}
+		if output.BodyStream == nil {
+			t.Fatal("Expected BodyStream to be set")
+		}
+		all, _ := io.ReadAll(output.BodyStream)
+		if string(all) != "ab" {
+			t.Errorf("BodyStream content = %q, want %q", string(all), "ab")
+		}
+	})
+
+	t.Run("channel of events", func(t *testing.T) {
+		output := &Response{}
+		events := make(chan Event)
+		if err := setBodyBytes(output, events); err != nil {
+			t.Fatalf("setBodyBytes() error = %v", err)
+		}
+		if output.Events == nil {
+			t.Error("Expected Events to be set")
+		}
+	})
+
+	t.Run("nil channel of events", func(t *testing.T) {
+		output := &Response{}
+		var events <-chan Event
+		if err := setBodyBytes(output, events); err != nil {
+			t.Fatalf("setBodyBytes() error = %v", err)
+		}
+		if output.IsStreaming() || output.Body != nil {
+			t.Error("Expected no body to be set for a nil channel")
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package httpadpt
+
+import (
+	"github.com/smart-libs/go-crosscutting/assertions/lib/pkg/check"
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"net/http"
+)
+
+func init() {
+	getOutParamSpecFactoryRegistry().AddOption3(TagCookie, createCookieSetter)
+}
+
+func createCookieSetter(tagValue string) (func(*Response, any) error, error) {
+	tag, err := parseCookieTag(tagValue)
+	if err != nil {
+		return nil, err
+	}
+	return func(output *Response, value any) error {
+		return setResponseCookie(output, tag, value)
+	}, nil
+}
+
+// setResponseCookie adds a cookie to the response. When the value is an http.Cookie its attributes are used and the
+// tag only provides the name if it is empty, otherwise the value is converted to string and the tag attributes are used.
+func setResponseCookie(output *Response, tag cookieTag, value any) error {
+	const fName = "httpadpt.setResponseCookie"
+	if err := IsResponseNil(output); err != nil {
+		return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+	}
+	if check.IsNil(value) {
+		return nil
+	}
+
+	var cookie http.Cookie
+	switch v := value.(type) {
+	case *http.Cookie:
+		cookie = *v
+	case http.Cookie:
+		cookie = v
+	default:
+		str, err := converter.To[string](Converters, value)
+		if err != nil {
+			return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+		}
+		cookie = tag.template
+		cookie.Value = str
+	}
+	if cookie.Name == "" {
+		cookie.Name = tag.template.Name
+	}
+
+	encoded, err := tag.encode(output.cookieKeyRing, cookie.Name, cookie.Value)
+	if err != nil {
+		return serror.CmpError.Wrap(err, "%s: failed to encode cookie=[%s]", fName, cookie.Name)
+	}
+	cookie.Value = encoded
+	output.Cookies = append(output.Cookies, &cookie)
+	return nil
+}
@@ -0,0 +1,111 @@
+package httpadpt
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_setResponseCookie(t *testing.T) {
+	tag, _ := parseCookieTag("session,Path=/,HttpOnly,SameSite=Lax,Max-Age=60")
+	output := &Response{}
+
+	if err := setResponseCookie(output, tag, "abc"); err != nil {
+		t.Fatalf("setResponseCookie() error = %v", err)
+	}
+	if err := setResponseCookie(output, tag, &http.Cookie{Value: "xyz", Path: "/api"}); err != nil {
+		t.Fatalf("setResponseCookie() error = %v", err)
+	}
+	if err := setResponseCookie(output, tag, (*http.Cookie)(nil)); err != nil {
+		t.Fatalf("setResponseCookie() error = %v", err)
+	}
+
+	if len(output.Cookies) != 2 {
+		t.Fatalf("Cookies = %v, want 2 cookies", output.Cookies)
+	}
+	if got := output.Cookies[0].String(); got != "session=abc; Path=/; Max-Age=60; HttpOnly; SameSite=Lax" {
+		t.Errorf("Cookies[0] = %q", got)
+	}
+	if got := output.Cookies[1].String(); got != "session=xyz; Path=/api" {
+		t.Errorf("Cookies[1] = %q", got)
+	}
+}
+
+func Test_setResponseCookie_SignedAndEncrypted(t *testing.T) {
+	ring := newTestCookieKeyRing(t, 1)
+
+	signedTag, _ := parseCookieTag("session,signed")
+	encryptedTag, _ := parseCookieTag("token,encrypted")
+	output := &Response{cookieKeyRing: ring}
+	if err := setResponseCookie(output, signedTag, "user-42"); err != nil {
+		t.Fatalf("setResponseCookie() error = %v", err)
+	}
+	if err := setResponseCookie(output, encryptedTag, "secret"); err != nil {
+		t.Fatalf("setResponseCookie() error = %v", err)
+	}
+
+	if value, err := ring.Verify("session", output.Cookies[0].Value); err != nil || value != "user-42" {
+		t.Errorf("signed cookie = %q, %v, want %q", value, err, "user-42")
+	}
+	if value, err := ring.Decrypt("token", output.Cookies[1].Value); err != nil || value != "secret" {
+		t.Errorf("encrypted cookie = %q, %v, want %q", value, err, "secret")
+	}
+}
+
+func Test_signedCookie_ThroughConfigKeyRing(t *testing.T) {
+	type handlerInput struct {
+		Session string `cookie:"session,signed" assert:"mandatory"`
+	}
+	type handlerOutput struct {
+		Session string `cookie:"session,signed"`
+	}
+	ring := newTestCookieKeyRing(t, 1)
+	binding := NewBindingBuilderUsingPath("/refresh").WithMethods("POST").
+		WithHandlerFunc(func(input handlerInput) (*handlerOutput, error) {
+			return &handlerOutput{Session: input.Session + "-renewed"}, nil
+		})
+	handler := WrapBindingHandler(binding, Config{CookieKeyRing: ring})
+
+	req := &mockRequest{cookie: &mockCookieParams{values: map[string]string{"session": ring.Sign("session", "user-42")}}}
+	resp := Response{}
+	if err := handler.Invoke(t.Context(), req, &resp); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if len(resp.Cookies) != 1 {
+		t.Fatalf("Cookies = %v, want 1 cookie", resp.Cookies)
+	}
+	if value, err := ring.Verify("session", resp.Cookies[0].Value); err != nil || value != "user-42-renewed" {
+		t.Errorf("signed cookie = %q, %v, want %q", value, err, "user-42-renewed")
+	}
+
+	resp = Response{}
+	_ = HandlePanic(WrapBindingHandler(binding, Config{})).Invoke(t.Context(), req, &resp)
+	if resp.StatusCode == nil || *resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("StatusCode = %v, want %d without Config.CookieKeyRing", resp.StatusCode, http.StatusInternalServerError)
+	}
+}
+
+func Test_setResponseCookie_ThroughHandler(t *testing.T) {
+	type handlerOutput struct {
+		Session string       `cookie:"session,Path=/,HttpOnly"`
+		Theme   *http.Cookie `cookie:"theme"`
+	}
+	handler := NewBindingBuilderUsingPath("/login").WithMethods("POST").
+		WithHandlerFunc(func() (*handlerOutput, error) {
+			return &handlerOutput{Session: "abc", Theme: &http.Cookie{Value: "dark", MaxAge: 10}}, nil
+		}).Handler
+
+	resp := Response{}
+	if err := handler.Invoke(t.Context(), &mockRequest{}, &resp); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if len(resp.Cookies) != 2 {
+		t.Fatalf("Cookies = %v, want 2 cookies", resp.Cookies)
+	}
+	cookies := map[string]string{}
+	for _, cookie := range resp.Cookies {
+		cookies[cookie.Name] = cookie.String()
+	}
+	if cookies["session"] != "session=abc; Path=/; HttpOnly" || cookies["theme"] != "theme=dark; Max-Age=10" {
+		t.Errorf("Cookies = %v", cookies)
+	}
+}
@@ -1,9 +1,11 @@
 package httpadpt
 
 import (
+	"encoding/json"
 	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
 	"github.com/smart-libs/go-crosscutting/assertions/lib/pkg/check"
 	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"strconv"
 )
 
 type (
@@ -25,7 +27,16 @@ func (o OutErrorParamSpec) SetValue(output *Response, value any) error {
 		if convErr := errorToStatusCode(err, output.StatusCode); convErr != nil {
 			return convErr
 		}
-		output.Body = []byte(err.Error())
+		output.BodyStream = nil
+		output.Events = nil
+		output.WebSocket = nil
+		if output.Header == nil {
+			output.Header = make(map[string][]string)
+		}
+		output.Header["Content-Type"] = []string{ContentTypeProblemDetail}
+		problem := ProblemDetailFromError(err)
+		problem.Status = strconv.Itoa(*output.StatusCode)
+		output.Body, _ = json.Marshal(problem)
 	}
 	return nil
 }
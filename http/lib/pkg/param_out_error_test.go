@@ -1,6 +1,7 @@
 package httpadpt
 
 import (
+	"encoding/json"
 	"errors"
 	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
 	"net/http"
@@ -69,8 +70,15 @@ func TestOutErrorParamSpec_SetValue_WithError(t *testing.T) {
 		t.Errorf("Expected StatusCode = %d, got %d", http.StatusInternalServerError, *output.StatusCode)
 	}
 
-	if string(output.Body) != testErr.Error() {
-		t.Errorf("Expected Body = %q, got %q", testErr.Error(), string(output.Body))
+	if got := output.Header["Content-Type"]; len(got) != 1 || got[0] != ContentTypeProblemDetail {
+		t.Errorf("Expected Content-Type = %q, got %v", ContentTypeProblemDetail, got)
+	}
+	var problem ProblemDetail
+	if err := json.Unmarshal(output.Body, &problem); err != nil {
+		t.Fatalf("Expected a problem detail Body, got %q", string(output.Body))
+	}
+	if problem.Detail != testErr.Error() || problem.Status != "500" {
+		t.Errorf("Expected problem detail = %q with status 500, got %+v", testErr.Error(), problem)
 	}
 }
 
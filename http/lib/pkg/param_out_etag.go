@@ -0,0 +1,43 @@
+package httpadpt
+
+import (
+	"strings"
+
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	TagETag    = "etag"
+	HeaderETag = "ETag"
+)
+
+func init() {
+	getOutParamSpecFactoryRegistry().AddOption1(TagETag, "", setETag)
+}
+
+// setETag sets the ETag header, the value is quoted as a strong entity tag unless it is already quoted or weak.
+// An empty value does not set the header.
+func setETag(output *Response, value any) error {
+	const fName = "httpadpt.setETag"
+	if err := IsResponseNil(output); err != nil {
+		return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+	}
+
+	etag, err := converter.To[string](Converters, value)
+	if err != nil {
+		return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+	}
+	if etag != "" {
+		setHeaderValue(output, HeaderETag, FormatETag(etag))
+	}
+	return nil
+}
+
+// FormatETag quotes the value as a strong entity tag, the values already quoted or weak (W/"...") are kept
+func FormatETag(value string) string {
+	if strings.HasPrefix(value, `W/"`) || (len(value) > 1 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`)) {
+		return value
+	}
+	return `"` + value + `"`
+}
@@ -0,0 +1,33 @@
+package httpadpt
+
+import (
+	"testing"
+)
+
+func Test_setETag(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        any
+		expectedETag []string
+	}{
+		{name: "unquoted", value: "v1", expectedETag: []string{`"v1"`}},
+		{name: "quoted", value: `"v1"`, expectedETag: []string{`"v1"`}},
+		{name: "weak", value: `W/"v1"`, expectedETag: []string{`W/"v1"`}},
+		{name: "number", value: 42, expectedETag: []string{`"42"`}},
+		{name: "empty", value: "", expectedETag: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &Response{}
+			if err := setETag(output, tt.value); err != nil {
+				t.Fatalf("setETag() error = %v", err)
+			}
+			if got := output.Header[HeaderETag]; len(got) != len(tt.expectedETag) || (len(got) > 0 && got[0] != tt.expectedETag[0]) {
+				t.Errorf("ETag = %v, want %v", got, tt.expectedETag)
+			}
+		})
+	}
+	if err := setETag(nil, "v1"); err == nil {
+		t.Error("setETag(nil) should fail")
+	}
+}
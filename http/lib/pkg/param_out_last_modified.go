@@ -0,0 +1,53 @@
+package httpadpt
+
+import (
+	"net/http"
+	"time"
+
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	TagLastModified    = "lastmodified"
+	HeaderLastModified = "Last-Modified"
+)
+
+func init() {
+	getOutParamSpecFactoryRegistry().AddOption1(TagLastModified, "", setLastModified)
+}
+
+// setLastModified sets the Last-Modified header from a time.Time, or from a string already in the HTTP date format.
+// A zero time does not set the header.
+func setLastModified(output *Response, value any) error {
+	const fName = "httpadpt.setLastModified"
+	if err := IsResponseNil(output); err != nil {
+		return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+	}
+
+	var lastModified string
+	switch typed := value.(type) {
+	case time.Time:
+		lastModified = formatHTTPDate(typed)
+	case *time.Time:
+		if typed != nil {
+			lastModified = formatHTTPDate(*typed)
+		}
+	default:
+		var err error
+		if lastModified, err = converter.To[string](Converters, value); err != nil {
+			return serror.CmpError.Wrap(err, "%s: failed to convert value=[%v]", fName, value)
+		}
+	}
+	if lastModified != "" {
+		setHeaderValue(output, HeaderLastModified, lastModified)
+	}
+	return nil
+}
+
+func formatHTTPDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(http.TimeFormat)
+}
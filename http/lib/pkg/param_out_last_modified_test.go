@@ -0,0 +1,38 @@
+package httpadpt
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_setLastModified(t *testing.T) {
+	modified := time.Date(2024, 3, 1, 10, 30, 0, 0, time.FixedZone("BRT", -3*3600))
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "time", value: modified, expected: "Fri, 01 Mar 2024 13:30:00 GMT"},
+		{name: "pointer", value: &modified, expected: "Fri, 01 Mar 2024 13:30:00 GMT"},
+		{name: "string", value: "Fri, 01 Mar 2024 13:30:00 GMT", expected: "Fri, 01 Mar 2024 13:30:00 GMT"},
+		{name: "zero time", value: time.Time{}, expected: ""},
+		{name: "nil pointer", value: (*time.Time)(nil), expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &Response{}
+			if err := setLastModified(output, tt.value); err != nil {
+				t.Fatalf("setLastModified() error = %v", err)
+			}
+			if got := getResponseHeader(output, HeaderLastModified); got != tt.expected {
+				t.Errorf("Last-Modified = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+	if err := setLastModified(nil, modified); err == nil {
+		t.Error("setLastModified(nil) should fail")
+	}
+	if err := setLastModified(&Response{}, struct{}{}); err == nil {
+		t.Error("setLastModified(struct{}{}) should fail")
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 func Test_getOutParamSpecFactoryRegistry(t *testing.T) {
 	// Reset the global registry for testing
+	saved := outParamSpecFactoryRegistry
+	t.Cleanup(func() { outParamSpecFactoryRegistry = saved })
 	outParamSpecFactoryRegistry = nil
 
 	// First call should initialize
@@ -23,6 +25,8 @@ func Test_getOutParamSpecFactoryRegistry(t *testing.T) {
 
 func Test_createOutParamSpecFactory(t *testing.T) {
 	// Reset the global registry for testing
+	saved := outParamSpecFactoryRegistry
+	t.Cleanup(func() { outParamSpecFactoryRegistry = saved })
 	outParamSpecFactoryRegistry = nil
 
 	// This should not panic even if registry is nil (it should initialize it)
@@ -39,6 +43,8 @@ func Test_createOutParamSpecFactory(t *testing.T) {
 
 func Test_createOutParamSpecFactory_InitializesRegistry(t *testing.T) {
 	// Reset the global registry
+	saved := outParamSpecFactoryRegistry
+	t.Cleanup(func() { outParamSpecFactoryRegistry = saved })
 	outParamSpecFactoryRegistry = nil
 
 	// Call createOutParamSpecFactory which should initialize the registry
@@ -0,0 +1,77 @@
+package httpadpt
+
+import (
+	"fmt"
+
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+	"github.com/smart-libs/go-crosscutting/assertions/lib/pkg/check"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	WebSocketOutErrorParamSpec struct{}
+)
+
+var (
+	webSocketOutParamSpecFactoryRegistry tagbased.OutputParamSpecFactoryRegistry[*WebSocketReply]
+)
+
+func init() {
+	getWebSocketOutParamSpecFactoryRegistry().
+		AddOption1(TagWebSocket, "", setWebSocketReplyData).
+		AddOption1(TagWebSocketType, "", setWebSocketReplyType)
+}
+
+func getWebSocketOutParamSpecFactoryRegistry() tagbased.OutputParamSpecFactoryRegistry[*WebSocketReply] {
+	if webSocketOutParamSpecFactoryRegistry == nil {
+		webSocketOutParamSpecFactoryRegistry = tagbased.NewOutputParamSpecFactoryRegistry[*WebSocketReply](WebSocketConverters)
+	}
+
+	return webSocketOutParamSpecFactoryRegistry
+}
+
+func createWebSocketOutParamSpecFactory() tagbased.OutputParamSpecFactory[*WebSocketReply] {
+	return tagbased.NewOutputParamSpecFactory(getWebSocketOutParamSpecFactoryRegistry())
+}
+
+func setWebSocketReplyData(output *WebSocketReply, value any) error {
+	if output == nil {
+		return serror.CmpError.New("httpadpt.setWebSocketReplyData: output is nil")
+	}
+	if !check.IsNil(value) {
+		output.Data = value
+	}
+	return nil
+}
+
+func setWebSocketReplyType(output *WebSocketReply, value any) error {
+	if output == nil {
+		return serror.CmpError.New("httpadpt.setWebSocketReplyType: output is nil")
+	}
+	if messageType := fmt.Sprint(value); !check.IsNil(value) && messageType != "" {
+		output.Type = messageType
+	}
+	return nil
+}
+
+func (o WebSocketOutErrorParamSpec) Name() string               { return "error" }
+func (o WebSocketOutErrorParamSpec) Options() []sdkparam.Option { return nil }
+
+// SetValue sets the WebSocketReply error, it is sent as a WebSocketTypeError reply
+func (o WebSocketOutErrorParamSpec) SetValue(output *WebSocketReply, value any) error {
+	if check.IsNil(value) {
+		return nil // no error
+	}
+	if output == nil {
+		return serror.CmpError.New("httpadpt.WebSocketOutErrorParamSpec.SetValue: output is nil")
+	}
+	if err, ok := value.(error); ok {
+		output.Err = err
+	}
+	return nil
+}
+
+func NewWebSocketOutErrorParamSpec() sdkparam.OutputParamSpec[*WebSocketReply] {
+	return WebSocketOutErrorParamSpec{}
+}
@@ -0,0 +1,244 @@
+package httpadpt
+
+import (
+	"context"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// RateLimit is the number of Requests allowed in the Period
+	RateLimit struct {
+		Requests int
+		Period   time.Duration
+		// Burst is the token bucket capacity, zero means Requests. It is ignored by SlidingWindow.
+		Burst int
+	}
+
+	// RateLimitState is the per key state kept by the RateLimitStore, its meaning depends on the RateLimitAlgorithm
+	RateLimitState struct {
+		Value    float64
+		Previous float64
+		Time     time.Time
+	}
+
+	// RateLimitResult is the decision of the RateLimitAlgorithm for one request
+	RateLimitResult struct {
+		Allowed   bool
+		Limit     int
+		Remaining int
+		// Reset is the time until the quota is fully available again
+		Reset time.Duration
+		// RetryAfter is the time until the next request is allowed, it is zero if the request is allowed
+		RetryAfter time.Duration
+	}
+
+	// RateLimitAlgorithm consumes one request from the key state
+	RateLimitAlgorithm interface {
+		// Allow returns the new state and the decision, found is false if there is no state for the key yet
+		Allow(state RateLimitState, found bool, limit RateLimit, now time.Time) (RateLimitState, RateLimitResult)
+	}
+
+	// RateLimitStore keeps the RateLimitState of the keys, it can be shared by several instances of the application
+	RateLimitStore interface {
+		// Update atomically replaces the key state by the one returned by update, found is false if the key has no
+		// state or if it expired. The stored state shall be kept at least for the ttl.
+		Update(ctx context.Context, key string, ttl time.Duration,
+			update func(state RateLimitState, found bool) RateLimitState) error
+	}
+
+	// RateLimitKeyFunc returns the key that identifies the client, an empty key means the request is not limited
+	RateLimitKeyFunc = func(ctx context.Context, input Request) string
+
+	// TokenBucket refills the bucket continuously at Requests per Period, allowing bursts up to the capacity
+	TokenBucket struct{}
+
+	// SlidingWindow counts the requests in the current and in the previous fixed windows, weighting the previous one
+	// by how much of it is still inside the sliding window
+	SlidingWindow struct{}
+
+	// MemoryRateLimitStore is a RateLimitStore for a single instance, the expired keys are removed periodically
+	MemoryRateLimitStore struct {
+		mutex     sync.Mutex
+		entries   map[string]memoryRateLimitEntry
+		nextSweep time.Time
+	}
+
+	memoryRateLimitEntry struct {
+		state     RateLimitState
+		expiresAt time.Time
+	}
+)
+
+const memoryRateLimitSweepInterval = time.Minute
+
+func (TokenBucket) Allow(state RateLimitState, found bool, limit RateLimit, now time.Time) (RateLimitState, RateLimitResult) {
+	capacity := float64(limit.Burst)
+	if limit.Burst <= 0 {
+		capacity = float64(limit.Requests)
+	}
+	perSecond := float64(limit.Requests) / limit.Period.Seconds()
+	tokens := capacity
+	if found {
+		tokens = math.Min(capacity, state.Value+now.Sub(state.Time).Seconds()*perSecond)
+	}
+
+	result := RateLimitResult{Limit: int(capacity)}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - tokens) / perSecond)
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = secondsToDuration((capacity - tokens) / perSecond)
+	return RateLimitState{Value: tokens, Time: now}, result
+}
+
+func (SlidingWindow) Allow(state RateLimitState, found bool, limit RateLimit, now time.Time) (RateLimitState, RateLimitResult) {
+	windowStart := now.Truncate(limit.Period)
+	if !found || windowStart.Sub(state.Time) > limit.Period {
+		state = RateLimitState{Time: windowStart}
+	} else if windowStart.After(state.Time) {
+		state = RateLimitState{Previous: state.Value, Time: windowStart}
+	}
+
+	elapsed := float64(now.Sub(windowStart)) / float64(limit.Period)
+	estimated := state.Previous*(1-elapsed) + state.Value
+	requests := float64(limit.Requests)
+	result := RateLimitResult{Limit: limit.Requests, Reset: windowStart.Add(limit.Period).Sub(now)}
+	if estimated+1 <= requests {
+		state.Value++
+		estimated++
+		result.Allowed = true
+	} else if state.Value+1 > requests {
+		// the current window is full, so it waits for the next one, where the current count is the previous one
+		result.RetryAfter = result.Reset + time.Duration(float64(limit.Period)*(1-(requests-1)/state.Value))
+	} else {
+		// waits until the weight of the previous window is small enough
+		needed := 1 - (requests-1-state.Value)/state.Previous
+		result.RetryAfter = time.Duration(float64(limit.Period) * (needed - elapsed))
+	}
+	result.Remaining = int(math.Max(0, math.Floor(requests-estimated)))
+	return state, result
+}
+
+// NewMemoryRateLimitStore creates an in-memory RateLimitStore
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{entries: map[string]memoryRateLimitEntry{}}
+}
+
+func (m *MemoryRateLimitStore) Update(_ context.Context, key string, ttl time.Duration,
+	update func(state RateLimitState, found bool) RateLimitState) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	if now.After(m.nextSweep) {
+		for k, entry := range m.entries {
+			if now.After(entry.expiresAt) {
+				delete(m.entries, k)
+			}
+		}
+		m.nextSweep = now.Add(memoryRateLimitSweepInterval)
+	}
+
+	entry, found := m.entries[key]
+	if found && now.After(entry.expiresAt) {
+		found = false
+	}
+	m.entries[key] = memoryRateLimitEntry{state: update(entry.state, found), expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// RateLimitKeyByIP uses the client IP as key, it requires a Request that is a RemoteAddrRequest. Behind proxies, set
+// trustedProxies to their number: every proxy appends the address it received the request from to X-Forwarded-For,
+// so the client is the address trustedProxies entries from the right, the entries on its left can be forged by the
+// client. The remote address is used when trustedProxies is 0 or X-Forwarded-For has fewer entries.
+func RateLimitKeyByIP(trustedProxies int) RateLimitKeyFunc {
+	return func(_ context.Context, input Request) string {
+		if trustedProxies > 0 {
+			if forwarded := getForwardedFor(input); len(forwarded) >= trustedProxies {
+				return "ip:" + forwarded[len(forwarded)-trustedProxies]
+			}
+		}
+		remoteAddr := GetRemoteAddr(input)
+		if remoteAddr == "" {
+			return ""
+		}
+		host, _, err := net.SplitHostPort(remoteAddr)
+		if err != nil {
+			host = remoteAddr
+		}
+		return "ip:" + host
+	}
+}
+
+// getForwardedFor returns the addresses of all the X-Forwarded-For headers, from the leftmost to the rightmost
+func getForwardedFor(input Request) []string {
+	var addresses []string
+	for _, value := range getHeaderValues(input, HeaderXForwardedFor) {
+		for _, address := range strings.Split(value, ",") {
+			if address = strings.TrimSpace(address); address != "" {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	return addresses
+}
+
+// RateLimitKeyByHeader uses the value of the request header as key
+func RateLimitKeyByHeader(headerName string) RateLimitKeyFunc {
+	return func(_ context.Context, input Request) string {
+		if value := getFirstHeaderValue(input, headerName); value != "" {
+			return "header:" + headerName + ":" + value
+		}
+		return ""
+	}
+}
+
+// RateLimitKeyByPrincipal uses the subject of the authenticated Principal as key, see NewAuthMiddleware
+func RateLimitKeyByPrincipal() RateLimitKeyFunc {
+	return func(ctx context.Context, input Request) string {
+		principal, found := PrincipalFromContext(ctx)
+		if !found {
+			principal, found = PrincipalFromRequest(input)
+		}
+		if !found || principal.Subject == "" {
+			return ""
+		}
+		return "principal:" + principal.Subject
+	}
+}
+
+// RateLimitKeyByPathParam uses the value of the path parameter as key, like a tenant identifier
+func RateLimitKeyByPathParam(paramName string) RateLimitKeyFunc {
+	return func(_ context.Context, input Request) string {
+		if input == nil || input.Path() == nil {
+			return ""
+		}
+		if value, found := input.Path().GetValue(paramName); found && value != "" {
+			return "path:" + paramName + ":" + value
+		}
+		return ""
+	}
+}
+
+// FirstRateLimitKey returns the first non-empty key, like the principal and, for anonymous requests, the IP
+func FirstRateLimitKey(keyFuncs ...RateLimitKeyFunc) RateLimitKeyFunc {
+	return func(ctx context.Context, input Request) string {
+		for _, keyFunc := range keyFuncs {
+			if key := keyFunc(ctx, input); key != "" {
+				return key
+			}
+		}
+		return ""
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,138 @@
+package httpadpt
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var testRateLimitNow = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func allowMany(algorithm RateLimitAlgorithm, limit RateLimit, state RateLimitState, found bool, now time.Time, count int) (RateLimitState, RateLimitResult, int) {
+	var (
+		result  RateLimitResult
+		allowed int
+	)
+	for i := 0; i < count; i++ {
+		state, result = algorithm.Allow(state, found, limit, now)
+		found = true
+		if result.Allowed {
+			allowed++
+		}
+	}
+	return state, result, allowed
+}
+
+func TestTokenBucket_Allow(t *testing.T) {
+	limit := RateLimit{Requests: 10, Period: 10 * time.Second, Burst: 5}
+
+	state, result, allowed := allowMany(TokenBucket{}, limit, RateLimitState{}, false, testRateLimitNow, 6)
+	if allowed != 5 || result.Allowed {
+		t.Fatalf("allowed = %d, last = %+v, want the burst of 5 allowed", allowed, result)
+	}
+	if result.Limit != 5 || result.Remaining != 0 || result.RetryAfter != time.Second || result.Reset != 5*time.Second {
+		t.Errorf("result = %+v", result)
+	}
+
+	// one token per second is refilled
+	_, result, allowed = allowMany(TokenBucket{}, limit, state, true, testRateLimitNow.Add(2*time.Second), 3)
+	if allowed != 2 {
+		t.Errorf("allowed after 2s = %d, want 2 (%+v)", allowed, result)
+	}
+}
+
+func TestSlidingWindow_Allow(t *testing.T) {
+	limit := RateLimit{Requests: 4, Period: time.Minute}
+
+	state, result, allowed := allowMany(SlidingWindow{}, limit, RateLimitState{}, false, testRateLimitNow.Add(30*time.Second), 5)
+	if allowed != 4 || result.Allowed || result.Remaining != 0 {
+		t.Fatalf("allowed = %d, last = %+v, want 4 allowed", allowed, result)
+	}
+	if result.Reset != 30*time.Second || result.RetryAfter != 45*time.Second {
+		t.Errorf("result = %+v, want Reset 30s and RetryAfter 45s", result)
+	}
+
+	// 15s into the next window the previous 4 requests weigh 3, so one request is allowed
+	state, result, allowed = allowMany(SlidingWindow{}, limit, state, true, testRateLimitNow.Add(75*time.Second), 2)
+	if allowed != 1 || result.RetryAfter != 15*time.Second {
+		t.Errorf("allowed = %d, last = %+v, want 1 allowed and RetryAfter 15s", allowed, result)
+	}
+
+	// after two windows the counters are reset
+	_, _, allowed = allowMany(SlidingWindow{}, limit, state, true, testRateLimitNow.Add(3*time.Minute), 4)
+	if allowed != 4 {
+		t.Errorf("allowed after two windows = %d, want 4", allowed)
+	}
+}
+
+func TestMemoryRateLimitStore_Update(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	increment := func(state RateLimitState, found bool) RateLimitState {
+		if !found {
+			return RateLimitState{Value: 1}
+		}
+		return RateLimitState{Value: state.Value + 1}
+	}
+
+	for i := 0; i < 3; i++ {
+		_ = store.Update(context.Background(), "a", time.Minute, increment)
+	}
+	_ = store.Update(context.Background(), "b", -time.Second, increment)
+	if got := store.entries["a"].state.Value; got != 3 {
+		t.Errorf("state of a = %v, want 3", got)
+	}
+
+	var foundExpired bool
+	_ = store.Update(context.Background(), "b", time.Minute, func(state RateLimitState, found bool) RateLimitState {
+		foundExpired = found
+		return state
+	})
+	if foundExpired {
+		t.Error("Update() found = true for an expired key")
+	}
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	input := &mockRequest{
+		remoteAddr: "10.0.0.1:51234",
+		header: &mockHeaderParams{values: map[string][]string{
+			HeaderXForwardedFor: {"198.51.100.1, 203.0.113.7", "10.0.0.2"},
+			"X-Client":          {"mobile"},
+		}},
+		path: &mockPathParams{values: map[string]string{"tenant": "acme"}},
+	}
+	ctx := PrincipalToContext(context.Background(), Principal{Subject: "john"})
+
+	tests := []struct {
+		name     string
+		keyFunc  RateLimitKeyFunc
+		ctx      context.Context
+		expected string
+	}{
+		{name: "ip", keyFunc: RateLimitKeyByIP(0), ctx: ctx, expected: "ip:10.0.0.1"},
+		{name: "forwarded ip", keyFunc: RateLimitKeyByIP(1), ctx: ctx, expected: "ip:10.0.0.2"},
+		{name: "forwarded ip behind two proxies", keyFunc: RateLimitKeyByIP(2), ctx: ctx, expected: "ip:203.0.113.7"},
+		{name: "fewer forwarded ips than proxies", keyFunc: RateLimitKeyByIP(4), ctx: ctx, expected: "ip:10.0.0.1"},
+		{name: "header", keyFunc: RateLimitKeyByHeader("X-Client"), ctx: ctx, expected: "header:X-Client:mobile"},
+		{name: "principal", keyFunc: RateLimitKeyByPrincipal(), ctx: ctx, expected: "principal:john"},
+		{name: "path param", keyFunc: RateLimitKeyByPathParam("tenant"), ctx: ctx, expected: "path:tenant:acme"},
+		{name: "anonymous", keyFunc: RateLimitKeyByPrincipal(), ctx: context.Background(), expected: ""},
+		{
+			name:     "first key",
+			keyFunc:  FirstRateLimitKey(RateLimitKeyByPrincipal(), RateLimitKeyByIP(0)),
+			ctx:      context.Background(),
+			expected: "ip:10.0.0.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.keyFunc(tt.ctx, input); got != tt.expected {
+				t.Errorf("key = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if got := RateLimitKeyByIP(0)(ctx, struct{ Request }{Request: input}); got != "" {
+		t.Errorf("key of a Request without RemoteAddr = %q, want \"\"", got)
+	}
+}
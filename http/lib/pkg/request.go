@@ -2,6 +2,10 @@ package httpadpt
 
 import (
 	"github.com/smart-libs/go-crosscutting/assertions/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+	"io"
+	"mime/multipart"
+	"net/http"
 	"net/url"
 )
 
@@ -24,6 +28,21 @@ type (
 		GetValue(pathParamName string) (string, bool)
 	}
 
+	FormParams interface {
+		// GetValue if the field was sent in the urlencoded or multipart body, then it returns the field value and true,
+		// otherwise it returns nil and false.
+		GetValue(fieldName string) ([]string, bool)
+
+		// GetFiles if the file field was sent in the multipart body, then it returns the file headers and true,
+		// otherwise it returns nil and false.
+		GetFiles(fieldName string) ([]*multipart.FileHeader, bool)
+	}
+
+	CookieParams interface {
+		// GetValue if the cookie was sent, then it returns the cookie value and true, otherwise it returns "" and false.
+		GetValue(cookieName string) (string, bool)
+	}
+
 	Request interface {
 		Query() QueryParams
 		Header() HeaderParams
@@ -31,6 +50,35 @@ type (
 		URL() *url.URL
 		Method() string
 	}
+
+	// RemoteAddrRequest is implemented by the Request implementations that know the network address of the client,
+	// RateLimitKeyByIP requires it
+	RemoteAddrRequest interface {
+		// RemoteAddr returns the network address of the client that sent the request, usually "host:port"
+		RemoteAddr() string
+	}
+
+	// CookieRequest is implemented by the Request implementations that give access to the cookies, the cookie tag
+	// requires it
+	CookieRequest interface {
+		Cookie() CookieParams
+	}
+
+	// BodyRequest is implemented by the Request implementations that give access to the body, the bindings that read
+	// the whole body, like the JSON-RPC and GraphQL ones, require it
+	BodyRequest interface {
+		// Body returns the body reader, the one given by the middlewares that replaced it with BodyWrapper. It is
+		// empty if the request has no body.
+		Body() io.Reader
+	}
+
+	// FormRequest is implemented by the Request implementations that give access to the urlencoded and multipart
+	// body fields, the form and file tags require it
+	FormRequest interface {
+		// Form parses the request body on the first call, it returns an error if the body is not a valid form
+		// or if it exceeds the configured limits.
+		Form() (FormParams, error)
+	}
 )
 
 // IsRequestNil ensure the Request is not nil
@@ -61,3 +109,82 @@ func IsRequestPathNil(req Request, errHolder *error) bool {
 	}
 	return HandleErrorHolder(errHolder, assertions.AnyIsNotNil(req.Path()))
 }
+
+// IsRequestCookieNil ensures that the Request is a CookieRequest and Cookie() is not nil
+func IsRequestCookieNil(req Request, errHolder *error) bool {
+	if IsRequestNil(req, errHolder) {
+		return true
+	}
+	return HandleErrorHolder(errHolder, assertions.AnyIsNotNil(GetCookie(req)))
+}
+
+// GetCookie returns the cookies of the Request if it is a CookieRequest, otherwise it returns nil
+func GetCookie(req Request) CookieParams {
+	if cookieRequest, ok := req.(CookieRequest); ok {
+		return cookieRequest.Cookie()
+	}
+	return nil
+}
+
+// GetMethod returns the method of the Request, or GET if the Request is nil or has no method, as the implementations
+// handle it
+func GetMethod(req Request) string {
+	if req == nil || req.Method() == "" {
+		return http.MethodGet
+	}
+	return req.Method()
+}
+
+// GetRemoteAddr returns the client address of the Request if it is a RemoteAddrRequest, otherwise it returns ""
+func GetRemoteAddr(req Request) string {
+	if remoteAddrRequest, ok := req.(RemoteAddrRequest); ok {
+		return remoteAddrRequest.RemoteAddr()
+	}
+	return ""
+}
+
+// GetBody returns the body of the Request if it is a BodyRequest, otherwise it returns nil
+func GetBody(req Request) io.Reader {
+	if bodyRequest, ok := req.(BodyRequest); ok {
+		return bodyRequest.Body()
+	}
+	return nil
+}
+
+// ReadBody reads the body of the Request if it is a BodyRequest, otherwise it returns an error. The bodies larger than
+// maxSize return an *http.MaxBytesError, which is answered with 413.
+func ReadBody(req Request, maxSize int64) ([]byte, error) {
+	body := GetBody(req)
+	if body == nil {
+		return nil, serror.CmpError.New("httpadpt.ReadBody: the request=[%T] does not give access to the body", req)
+	}
+	payload, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(payload)) > maxSize {
+		return nil, &http.MaxBytesError{Limit: maxSize}
+	}
+	return payload, nil
+}
+
+// IsRequestFormNil ensures that the Request is a FormRequest, Form() is not nil and the form was parsed without errors
+func IsRequestFormNil(req Request, errHolder *error) bool {
+	if IsRequestNil(req, errHolder) {
+		return true
+	}
+	form, err := GetForm(req)
+	if HandleErrorHolder(errHolder, err) {
+		return true
+	}
+	return HandleErrorHolder(errHolder, assertions.AnyIsNotNil(form))
+}
+
+// GetForm returns the form of the Request if it is a FormRequest, otherwise it returns an error
+func GetForm(req Request) (FormParams, error) {
+	formRequest, ok := req.(FormRequest)
+	if !ok {
+		return nil, serror.CmpError.New("httpadpt.GetForm: the request=[%T] does not give access to the form", req)
+	}
+	return formRequest.Form()
+}
@@ -0,0 +1,89 @@
+package httpadpt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+type (
+	// RequestIDKey is used to add the request ID to the context and to the Request
+	RequestIDKey struct{}
+
+	// RequestIDGenerator creates a new request ID
+	RequestIDGenerator = func() string
+)
+
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewUUIDv7 returns a random UUID version 7 (RFC 9562), it starts with the Unix time in milliseconds, so the IDs
+// are sorted by creation time
+func NewUUIDv7() string {
+	var uuid [16]byte
+	_, _ = rand.Read(uuid[6:])
+	putMilliseconds(uuid[:6], time.Now())
+	uuid[6] = 0x70 | uuid[6]&0x0f // version 7
+	uuid[8] = 0x80 | uuid[8]&0x3f // variant 10
+
+	var text [36]byte
+	hex.Encode(text[0:8], uuid[0:4])
+	text[8] = '-'
+	hex.Encode(text[9:13], uuid[4:6])
+	text[13] = '-'
+	hex.Encode(text[14:18], uuid[6:8])
+	text[18] = '-'
+	hex.Encode(text[19:23], uuid[8:10])
+	text[23] = '-'
+	hex.Encode(text[24:], uuid[10:])
+	return string(text[:])
+}
+
+// NewULID returns a random ULID, 26 Crockford base32 characters that start with the Unix time in milliseconds
+func NewULID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[6:])
+	putMilliseconds(id[:6], time.Now())
+
+	// 128 bits encoded as 26 characters of 5 bits, the first character holds only 3 bits
+	var text [26]byte
+	high := binary.BigEndian.Uint64(id[:8])
+	low := binary.BigEndian.Uint64(id[8:])
+	for i := len(text) - 1; i >= 0; i-- {
+		text[i] = crockfordBase32[low&0x1f]
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+	return string(text[:])
+}
+
+func putMilliseconds(target []byte, now time.Time) {
+	var buffer [8]byte
+	binary.BigEndian.PutUint64(buffer[:], uint64(now.UnixMilli()))
+	copy(target, buffer[2:])
+}
+
+// RequestIDFromContext returns the request ID added by the middleware created by NewRequestIDMiddleware
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	requestID, found := ctx.Value(RequestIDKey{}).(string)
+	return requestID, found
+}
+
+// RequestIDToContext returns a copy of the context with the request ID
+func RequestIDToContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, RequestIDKey{}, requestID)
+}
+
+// RequestIDFromRequest returns the request ID added by the middleware created by NewRequestIDMiddleware
+func RequestIDFromRequest(input Request) (string, bool) {
+	value, found := GetRequestValue(input, RequestIDKey{})
+	if !found {
+		return "", false
+	}
+	requestID, found := value.(string)
+	return requestID, found
+}
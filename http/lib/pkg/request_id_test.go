@@ -0,0 +1,49 @@
+package httpadpt
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewUUIDv7(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	first, second := NewUUIDv7(), NewUUIDv7()
+	if !pattern.MatchString(first) {
+		t.Errorf("NewUUIDv7() = %q, want a UUID version 7", first)
+	}
+	if first == second {
+		t.Errorf("NewUUIDv7() returned the same ID twice: %q", first)
+	}
+
+	// the first 48 bits are the Unix time in milliseconds
+	timestamp := strings.ReplaceAll(first, "-", "")[:12]
+	expected := strings.ReplaceAll(NewUUIDv7(), "-", "")[:12]
+	if timestamp > expected {
+		t.Errorf("NewUUIDv7() timestamp %s is after %s", timestamp, expected)
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+	first := NewULID()
+	if !pattern.MatchString(first) {
+		t.Errorf("NewULID() = %q, want a ULID", first)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if second := NewULID(); first[:10] >= second[:10] {
+		t.Errorf("NewULID() time prefix %q is not before %q", first[:10], second[:10])
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	if _, found := RequestIDFromContext(context.Background()); found {
+		t.Error("RequestIDFromContext() found = true for an empty context")
+	}
+	ctx := RequestIDToContext(context.Background(), "abc")
+	if requestID, found := RequestIDFromContext(ctx); !found || requestID != "abc" {
+		t.Errorf("RequestIDFromContext() = %q, %v", requestID, found)
+	}
+}
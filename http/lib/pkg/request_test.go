@@ -1,6 +1,10 @@
 package httpadpt
 
 import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -47,6 +51,23 @@ func TestIsRequestNil(t *testing.T) {
 	}
 }
 
+func TestReadBody(t *testing.T) {
+	newRequest := func(body string) Request {
+		return WithRequestValue(&bodyWrapperRequest{body: io.NopCloser(strings.NewReader(body))}, "key", "value")
+	}
+
+	if payload, err := ReadBody(newRequest("12345"), 5); err != nil || string(payload) != "12345" {
+		t.Errorf("ReadBody() = %q, %v, want the body", payload, err)
+	}
+	var maxBytesError *http.MaxBytesError
+	if _, err := ReadBody(newRequest("123456"), 5); !errors.As(err, &maxBytesError) || maxBytesError.Limit != 5 {
+		t.Errorf("ReadBody() error = %v, want *http.MaxBytesError", err)
+	}
+	if _, err := ReadBody(struct{ Request }{Request: &mockRequest{}}, 5); err == nil {
+		t.Error("ReadBody() error = nil, want error for a Request without body access")
+	}
+}
+
 func TestIsRequestQueryNil(t *testing.T) {
 	tests := []struct {
 		name      string
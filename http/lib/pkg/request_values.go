@@ -0,0 +1,66 @@
+package httpadpt
+
+import "io"
+
+type (
+	// requestWithValue decorates a Request with a value set by a middleware, so that the input tags, which only
+	// receive the Request, can read it. It works like context.WithValue.
+	requestWithValue struct {
+		Request
+		key, value any
+	}
+
+	// requestValueGetter is implemented by the Request decorators that carry values
+	requestValueGetter interface {
+		getRequestValue(key any) (any, bool)
+	}
+)
+
+func (r requestWithValue) getRequestValue(key any) (any, bool) {
+	if r.key == key {
+		return r.value, true
+	}
+	return GetRequestValue(r.Request, key)
+}
+
+// WrapBody delegates to the decorated Request if it is a BodyWrapper, otherwise the body is kept
+func (r requestWithValue) WrapBody(wrap func(body io.ReadCloser) (io.ReadCloser, error)) error {
+	if wrapper, ok := r.Request.(BodyWrapper); ok {
+		return wrapper.WrapBody(wrap)
+	}
+	return nil
+}
+
+// Body delegates to the decorated Request if it is a BodyRequest
+func (r requestWithValue) Body() io.Reader {
+	return GetBody(r.Request)
+}
+
+// Cookie delegates to the decorated Request if it is a CookieRequest
+func (r requestWithValue) Cookie() CookieParams {
+	return GetCookie(r.Request)
+}
+
+// RemoteAddr delegates to the decorated Request if it is a RemoteAddrRequest
+func (r requestWithValue) RemoteAddr() string {
+	return GetRemoteAddr(r.Request)
+}
+
+// Form delegates to the decorated Request if it is a FormRequest
+func (r requestWithValue) Form() (FormParams, error) {
+	return GetForm(r.Request)
+}
+
+// WithRequestValue returns a Request that carries the value associated with the key, the other methods are delegated
+// to the given Request.
+func WithRequestValue(input Request, key, value any) Request {
+	return requestWithValue{Request: input, key: key, value: value}
+}
+
+// GetRequestValue returns the value associated with the key by WithRequestValue
+func GetRequestValue(input Request, key any) (any, bool) {
+	if getter, ok := input.(requestValueGetter); ok {
+		return getter.getRequestValue(key)
+	}
+	return nil, false
+}
@@ -1,6 +1,10 @@
 package httpadpt
 
-import assertions "github.com/smart-libs/go-crosscutting/assertions/lib/pkg"
+import (
+	assertions "github.com/smart-libs/go-crosscutting/assertions/lib/pkg"
+	"io"
+	"net/http"
+)
 
 type (
 	ParamName = string
@@ -9,6 +13,24 @@ type (
 		StatusCode *int
 		Body       []byte
 		Header     map[ParamName][]string
+
+		// Cookies are sent as Set-Cookie headers, they are added to the ones given in Header
+		Cookies []*http.Cookie
+
+		// BodyStream when set is copied to the client after the headers are written, instead of Body. It allows
+		// large responses to be sent without buffering them. If it is also an io.Closer, it is closed once copied.
+		BodyStream io.Reader
+
+		// Events when set turns the response into a Server-Sent Events stream. The implementation writes each Event
+		// received as a text/event-stream frame until the channel is closed or the client disconnects.
+		Events <-chan Event
+
+		// WebSocket when set makes the implementation upgrade the connection and serve the WebSocketSession, it is
+		// set by the bindings created by NewWebSocketBindingBuilder
+		WebSocket *WebSocketSession
+
+		// cookieKeyRing is set by NewCookieKeyRingMiddleware for the cookie output tags
+		cookieKeyRing *CookieKeyRing
 	}
 )
 
@@ -19,3 +41,8 @@ func IsResponseNil(resp *Response) error {
 
 	return nil
 }
+
+// IsStreaming returns true if the response body is provided by BodyStream, Events or WebSocket instead of Body
+func (r *Response) IsStreaming() bool {
+	return r != nil && (r.BodyStream != nil || r.Events != nil || r.WebSocket != nil)
+}
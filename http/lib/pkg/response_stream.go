@@ -0,0 +1,126 @@
+package httpadpt
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	// Event is a Server-Sent Event as specified by https://html.spec.whatwg.org/multipage/server-sent-events.html
+	Event struct {
+		// ID is sent as the "id" field and it is used by the client as Last-Event-ID when reconnecting
+		ID string
+		// Event is the event type name, if empty the client will handle it as a "message" event
+		Event string
+		// Data is the event payload, multiple lines are sent as multiple "data" fields
+		Data string
+		// Retry is the reconnection time the client should use, it is not sent when zero
+		Retry time.Duration
+		// Comment is sent as a comment line, it is ignored by the client and useful to keep the connection alive
+		Comment string
+	}
+
+	// Stream is the body output value streamed to the client without buffering it, the other io.Reader values are
+	// read into Response.Body. If the Reader is also an io.Closer, it is closed once copied or when the client
+	// disconnects, what unblocks the pending Read.
+	Stream struct {
+		io.Reader
+	}
+
+	// chanReader adapts a channel of chunks to io.Reader so that it can be used as Response.BodyStream. Closing it
+	// makes the pending and next reads return io.ErrClosedPipe instead of waiting for the next chunk.
+	chanReader struct {
+		chunks    <-chan []byte
+		pending   []byte
+		closed    chan struct{}
+		closeOnce sync.Once
+	}
+)
+
+const (
+	ContentTypeEventStream = "text/event-stream"
+)
+
+// WriteEvent writes the given Event to w using the text/event-stream format. The ID and Event fields cannot have line
+// breaks, that would let their values inject other fields, and the lines of Data and Comment are split on CR, LF and
+// CRLF as the client does.
+func WriteEvent(w io.Writer, event Event) error {
+	if strings.ContainsAny(event.ID, "\r\n\x00") {
+		return serror.IllegalArgumentValue("Event.ID", event.ID)
+	}
+	if strings.ContainsAny(event.Event, "\r\n") {
+		return serror.IllegalArgumentValue("Event.Event", event.Event)
+	}
+	var frame strings.Builder
+	if event.Comment != "" {
+		for _, line := range splitEventLines(event.Comment) {
+			frame.WriteString(": ")
+			frame.WriteString(line)
+			frame.WriteString("\n")
+		}
+	}
+	if event.ID != "" {
+		frame.WriteString("id: ")
+		frame.WriteString(event.ID)
+		frame.WriteString("\n")
+	}
+	if event.Event != "" {
+		frame.WriteString("event: ")
+		frame.WriteString(event.Event)
+		frame.WriteString("\n")
+	}
+	if event.Retry > 0 {
+		frame.WriteString("retry: ")
+		frame.WriteString(strconv.FormatInt(event.Retry.Milliseconds(), 10))
+		frame.WriteString("\n")
+	}
+	if event.Data != "" || (event.Comment == "" && event.ID == "" && event.Event == "" && event.Retry == 0) {
+		for _, line := range splitEventLines(event.Data) {
+			frame.WriteString("data: ")
+			frame.WriteString(line)
+			frame.WriteString("\n")
+		}
+	}
+	frame.WriteString("\n")
+	_, err := io.WriteString(w, frame.String())
+	return err
+}
+
+// splitEventLines splits the value on the line breaks recognized by the text/event-stream parsers
+func splitEventLines(value string) []string {
+	return strings.Split(strings.ReplaceAll(strings.ReplaceAll(value, "\r\n", "\n"), "\r", "\n"), "\n")
+}
+
+// NewChanReader returns an io.ReadCloser that reads the chunks received from the given channel until it is closed.
+// Closing the reader stops waiting for the chunks, the producer must stop sending them on its own, for instance when
+// the request context is done.
+func NewChanReader(chunks <-chan []byte) io.ReadCloser {
+	return &chanReader{chunks: chunks, closed: make(chan struct{})}
+}
+
+func (c *chanReader) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		select {
+		case <-c.closed:
+			return 0, io.ErrClosedPipe
+		case chunk, ok := <-c.chunks:
+			if !ok {
+				return 0, io.EOF
+			}
+			c.pending = chunk
+		}
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+func (c *chanReader) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
@@ -0,0 +1,151 @@
+package httpadpt
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWriteEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    Event
+		expected string
+	}{
+		{
+			name:     "data only",
+			event:    Event{Data: "hello"},
+			expected: "data: hello\n\n",
+		},
+		{
+			name:     "empty event",
+			event:    Event{},
+			expected: "data: \n\n",
+		},
+		{
+			name:     "multi-line data",
+			event:    Event{Data: "line1\nline2"},
+			expected: "data: line1\ndata: line2\n\n",
+		},
+		{
+			name:     "all fields",
+			event:    Event{ID: "1", Event: "update", Data: `{"a":1}`, Retry: 3 * time.Second},
+			expected: "id: 1\nevent: update\nretry: 3000\ndata: {\"a\":1}\n\n",
+		},
+		{
+			name:     "comment only",
+			event:    Event{Comment: "keep-alive"},
+			expected: ": keep-alive\n\n",
+		},
+		{
+			name:     "CR and CRLF line breaks",
+			event:    Event{Data: "line1\rid: 2\r\nline3", Comment: "a\rb"},
+			expected: ": a\n: b\ndata: line1\ndata: id: 2\ndata: line3\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteEvent(&buf, tt.event); err != nil {
+				t.Fatalf("WriteEvent() error = %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("WriteEvent() = %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteEvent_RejectsLineBreaks(t *testing.T) {
+	for _, event := range []Event{
+		{ID: "1\ndata: injected"},
+		{ID: "1\r"},
+		{ID: "1\x00"},
+		{Event: "update\nid: 2"},
+		{Event: "update\r"},
+	} {
+		var buf bytes.Buffer
+		if err := WriteEvent(&buf, event); err == nil {
+			t.Errorf("WriteEvent(%q) error = nil, want error", event)
+		}
+		if buf.Len() > 0 {
+			t.Errorf("WriteEvent(%q) wrote %q, want nothing", event, buf.String())
+		}
+	}
+}
+
+func TestNewChanReader(t *testing.T) {
+	chunks := make(chan []byte, 3)
+	chunks <- []byte("hello")
+	chunks <- []byte{}
+	chunks <- []byte(" world")
+	close(chunks)
+
+	all, err := io.ReadAll(NewChanReader(chunks))
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(all) != "hello world" {
+		t.Errorf("ReadAll() = %q, want %q", string(all), "hello world")
+	}
+}
+
+func TestNewChanReader_SmallBuffer(t *testing.T) {
+	chunks := make(chan []byte, 1)
+	chunks <- []byte("abcdef")
+	close(chunks)
+
+	reader := NewChanReader(chunks)
+	buf := make([]byte, 4)
+	n, err := reader.Read(buf)
+	if err != nil || string(buf[:n]) != "abcd" {
+		t.Fatalf("Read() = %q, %v, want %q", string(buf[:n]), err, "abcd")
+	}
+	n, err = reader.Read(buf)
+	if err != nil || string(buf[:n]) != "ef" {
+		t.Fatalf("Read() = %q, %v, want %q", string(buf[:n]), err, "ef")
+	}
+	if _, err = reader.Read(buf); err != io.EOF {
+		t.Errorf("Read() error = %v, want io.EOF", err)
+	}
+}
+
+func TestNewChanReader_Close(t *testing.T) {
+	reader := NewChanReader(make(chan []byte))
+	done := make(chan error)
+	go func() {
+		_, err := reader.Read(make([]byte, 4))
+		done <- err
+	}()
+	_ = reader.Close()
+
+	select {
+	case err := <-done:
+		if err != io.ErrClosedPipe {
+			t.Errorf("Read() error = %v, want io.ErrClosedPipe", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read() did not return after Close()")
+	}
+	if err := reader.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+func TestResponse_IsStreaming(t *testing.T) {
+	var nilResponse *Response
+	if nilResponse.IsStreaming() {
+		t.Error("nil Response should not be streaming")
+	}
+	if (&Response{Body: []byte("x")}).IsStreaming() {
+		t.Error("Response with Body should not be streaming")
+	}
+	if !(&Response{BodyStream: bytes.NewReader(nil)}).IsStreaming() {
+		t.Error("Response with BodyStream should be streaming")
+	}
+	if !(&Response{Events: make(chan Event)}).IsStreaming() {
+		t.Error("Response with Events should be streaming")
+	}
+}
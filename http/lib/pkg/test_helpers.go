@@ -2,6 +2,7 @@ package httpadpt
 
 import (
 	"github.com/smart-libs/go-crosscutting/types/lib/pkg/pointers"
+	"mime/multipart"
 	"net/url"
 )
 
@@ -13,11 +14,15 @@ var stringPtr = pointers.To[string]
 
 // mockRequest is a test implementation of Request
 type mockRequest struct {
-	query  QueryParams
-	header HeaderParams
-	path   PathParams
-	url    *url.URL
-	method string
+	query      QueryParams
+	header     HeaderParams
+	path       PathParams
+	cookie     CookieParams
+	form       FormParams
+	formErr    error
+	url        *url.URL
+	method     string
+	remoteAddr string
 }
 
 func (m *mockRequest) Query() QueryParams {
@@ -32,6 +37,14 @@ func (m *mockRequest) Path() PathParams {
 	return m.path
 }
 
+func (m *mockRequest) Cookie() CookieParams {
+	return m.cookie
+}
+
+func (m *mockRequest) Form() (FormParams, error) {
+	return m.form, m.formErr
+}
+
 func (m *mockRequest) URL() *url.URL {
 	return m.url
 }
@@ -40,6 +53,10 @@ func (m *mockRequest) Method() string {
 	return m.method
 }
 
+func (m *mockRequest) RemoteAddr() string {
+	return m.remoteAddr
+}
+
 // mockQueryParams is a test implementation of QueryParams
 type mockQueryParams struct {
 	values map[string][]string
@@ -78,3 +95,38 @@ func (m *mockHeaderParams) GetValue(name string) ([]string, bool) {
 	val, found := m.values[name]
 	return val, found
 }
+
+// mockFormParams is a test implementation of FormParams
+type mockFormParams struct {
+	values map[string][]string
+	files  map[string][]*multipart.FileHeader
+}
+
+func (m *mockFormParams) GetValue(name string) ([]string, bool) {
+	if m.values == nil {
+		return nil, false
+	}
+	val, found := m.values[name]
+	return val, found
+}
+
+func (m *mockFormParams) GetFiles(name string) ([]*multipart.FileHeader, bool) {
+	if m.files == nil {
+		return nil, false
+	}
+	val, found := m.files[name]
+	return val, found
+}
+
+// mockCookieParams is a test implementation of CookieParams
+type mockCookieParams struct {
+	values map[string]string
+}
+
+func (m *mockCookieParams) GetValue(name string) (string, bool) {
+	if m.values == nil {
+		return "", false
+	}
+	val, found := m.values[name]
+	return val, found
+}
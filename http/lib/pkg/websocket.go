@@ -0,0 +1,299 @@
+package httpadpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	sdkhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	// DefaultWebSocketTypeField is the message field that selects the handler when WebSocketConfig.TypeField is empty
+	DefaultWebSocketTypeField = "type"
+
+	// WebSocketIDField is copied from the inbound messages to their replies, so the clients can correlate them
+	WebSocketIDField = "id"
+
+	// WebSocketTypeError is the type of the replies sent when a message is invalid or its handler fails
+	WebSocketTypeError = "error"
+
+	DefaultWebSocketMaxMessageSize int64 = 1 << 20
+	DefaultWebSocketPingInterval         = 30 * time.Second
+
+	// webSocketSendBuffer is the number of frames queued to be written before Send blocks
+	webSocketSendBuffer = 16
+)
+
+// The close codes of https://www.rfc-editor.org/rfc/rfc6455#section-7.4.1
+const (
+	WebSocketCloseNormal          = 1000
+	WebSocketCloseGoingAway       = 1001
+	WebSocketClosePolicyViolation = 1008
+	WebSocketCloseMessageTooBig   = 1009
+	WebSocketCloseInternalError   = 1011
+)
+
+var (
+	// ErrWebSocketClosed is returned by WebSocketConn.Send after the connection is closed
+	ErrWebSocketClosed = errors.New("websocket connection closed")
+)
+
+type (
+	// WebSocketConfig configures the connections of a WebSocket binding
+	WebSocketConfig struct {
+		// TypeField is the message field that selects the handler, it is DefaultWebSocketTypeField if empty
+		TypeField string
+
+		// MaxMessageSize is the size limit of the inbound messages, the connection is closed with
+		// WebSocketCloseMessageTooBig if it is exceeded. Zero means DefaultWebSocketMaxMessageSize.
+		MaxMessageSize int64
+
+		// PingInterval is how often the server pings the client, zero means DefaultWebSocketPingInterval and a
+		// negative value disables the keepalive
+		PingInterval time.Duration
+
+		// PongTimeout is how long the server waits for the pong after a ping before closing the connection, zero
+		// means PingInterval
+		PongTimeout time.Duration
+
+		// CheckOrigin accepts the upgrade request by its Origin header, if nil only the requests from the same host
+		// are accepted
+		CheckOrigin func(input Request) bool
+
+		// OnConnect is invoked once the connection is upgraded, it can set the connection values. The connection is
+		// closed with WebSocketClosePolicyViolation if it returns an error.
+		OnConnect func(ctx context.Context, conn *WebSocketConn) error
+
+		// OnClose is invoked once the connection is closed
+		OnClose func(ctx context.Context, conn *WebSocketConn)
+	}
+
+	// WebSocketMessage is the input of the WebSocket message handlers
+	WebSocketMessage struct {
+		Conn *WebSocketConn
+		// Type is the value of the WebSocketConfig.TypeField
+		Type string
+		// Data is the whole message
+		Data json.RawMessage
+
+		fields map[string]json.RawMessage
+	}
+
+	// WebSocketReply is the output of the WebSocket message handlers, nothing is sent if Data is nil
+	WebSocketReply struct {
+		// Type is set in the WebSocketConfig.TypeField of the reply, it is the message type by default
+		Type string
+		// Data is sent as JSON, the type and id fields are added to it if it is an object, otherwise it is sent in
+		// the data field of an object
+		Data any
+		// Err is the handler error, it is sent as a WebSocketTypeError reply
+		Err error
+	}
+
+	WebSocketHandler = sdkhandler.Handler[WebSocketMessage, *WebSocketReply]
+
+	// WebSocketConn is the server side of a WebSocket connection. It holds the upgrade request and the connection
+	// values, shared by all the messages of the connection, and queues the frames written by the implementation.
+	WebSocketConn struct {
+		request   Request
+		typeField string
+		values    sync.Map
+		outbound  chan []byte
+
+		closeOnce   sync.Once
+		closed      chan struct{}
+		closeCode   int
+		closeReason string
+	}
+
+	// WebSocketSession is set in Response.WebSocket by the WebSocket bindings. The implementation upgrades the
+	// connection, calls Open, passes each inbound message to HandleMessage and writes the WebSocketConn.Outbound
+	// frames until the WebSocketConn is done, then it calls Close.
+	WebSocketSession struct {
+		Config   WebSocketConfig
+		Handlers map[string]WebSocketHandler
+		Conn     *WebSocketConn
+	}
+
+	webSocketConnContextKey struct{}
+
+	webSocketErrorData struct {
+		Error ProblemDetail `json:"error"`
+	}
+)
+
+// WebSocketConnFromContext returns the connection of the WebSocket message being handled
+func WebSocketConnFromContext(ctx context.Context) (*WebSocketConn, bool) {
+	conn, ok := ctx.Value(webSocketConnContextKey{}).(*WebSocketConn)
+	return conn, ok
+}
+
+func newWebSocketConn(request Request, typeField string) *WebSocketConn {
+	return &WebSocketConn{
+		request:   request,
+		typeField: typeField,
+		outbound:  make(chan []byte, webSocketSendBuffer),
+		closed:    make(chan struct{}),
+	}
+}
+
+// Request returns the upgrade request
+func (c *WebSocketConn) Request() Request {
+	return c.request
+}
+
+// Value returns the connection value set by SetValue
+func (c *WebSocketConn) Value(key string) (any, bool) {
+	return c.values.Load(key)
+}
+
+// SetValue sets a connection value, it is available to the following messages by Value and the wsvalue tag
+func (c *WebSocketConn) SetValue(key string, value any) {
+	c.values.Store(key, value)
+}
+
+// Send queues a message to the client, it can be called by any goroutine while the connection is open. The data is
+// sent like the WebSocketReply.Data.
+func (c *WebSocketConn) Send(ctx context.Context, messageType string, data any) error {
+	frame, err := c.frame(nil, messageType, data)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, frame)
+}
+
+func (c *WebSocketConn) send(ctx context.Context, frame []byte) error {
+	select {
+	case <-c.closed:
+		return ErrWebSocketClosed
+	default:
+	}
+	select {
+	case c.outbound <- frame:
+		return nil
+	case <-c.closed:
+		return ErrWebSocketClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close makes the implementation send the close frame with the code and reason after the queued frames, only the
+// first call has effect
+func (c *WebSocketConn) Close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode, c.closeReason = code, reason
+		close(c.closed)
+	})
+}
+
+// Done is closed when Close is called
+func (c *WebSocketConn) Done() <-chan struct{} {
+	return c.closed
+}
+
+// CloseStatus returns the code and reason given to Close
+func (c *WebSocketConn) CloseStatus() (int, string) {
+	<-c.closed
+	return c.closeCode, c.closeReason
+}
+
+// Outbound returns the frames to be written by the implementation
+func (c *WebSocketConn) Outbound() <-chan []byte {
+	return c.outbound
+}
+
+// frame marshals the data, adding the type and the id of the inbound message to it
+func (c *WebSocketConn) frame(inbound map[string]json.RawMessage, messageType string, data any) ([]byte, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("httpadpt.WebSocketConn: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil || fields == nil {
+		fields = map[string]json.RawMessage{"data": payload}
+	}
+	if _, found := fields[c.typeField]; !found && messageType != "" {
+		fields[c.typeField], _ = json.Marshal(messageType)
+	}
+	if id, found := inbound[WebSocketIDField]; found {
+		if _, found := fields[WebSocketIDField]; !found {
+			fields[WebSocketIDField] = id
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// Open invokes the WebSocketConfig.OnConnect
+func (s *WebSocketSession) Open(ctx context.Context) error {
+	if s.Config.OnConnect == nil {
+		return nil
+	}
+	return s.Config.OnConnect(context.WithValue(ctx, webSocketConnContextKey{}, s.Conn), s.Conn)
+}
+
+// Close invokes the WebSocketConfig.OnClose
+func (s *WebSocketSession) Close(ctx context.Context) {
+	if s.Config.OnClose != nil {
+		s.Config.OnClose(context.WithValue(ctx, webSocketConnContextKey{}, s.Conn), s.Conn)
+	}
+}
+
+// HandleMessage invokes the handler selected by the message type and queues its reply. The invalid messages, the
+// unknown types and the handler errors are replied with a WebSocketTypeError message, the connection is kept open.
+// It returns ErrWebSocketClosed if the connection is closed before the reply is queued.
+func (s *WebSocketSession) HandleMessage(ctx context.Context, data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return s.sendError(ctx, nil, serror.IllegalArgumentValueWithCause("message", "JSON object", err))
+	}
+	var messageType string
+	if err := json.Unmarshal(fields[s.Conn.typeField], &messageType); err != nil {
+		return s.sendError(ctx, fields, serror.IllegalArgumentValueWithCause(s.Conn.typeField, string(fields[s.Conn.typeField]), err))
+	}
+	handler, found := s.Handlers[messageType]
+	if !found {
+		return s.sendError(ctx, fields, serror.IllegalArgumentValue(s.Conn.typeField, messageType))
+	}
+
+	ctx = context.WithValue(ctx, webSocketConnContextKey{}, s.Conn)
+	message := WebSocketMessage{Conn: s.Conn, Type: messageType, Data: data, fields: fields}
+	reply := &WebSocketReply{Type: messageType}
+	if err := sdkhandler.Invoke(ctx, handler, message, reply); err != nil {
+		reply.Err = err
+	}
+	if reply.Err != nil {
+		return s.sendError(ctx, fields, reply.Err)
+	}
+	if reply.Data == nil {
+		return nil
+	}
+	frame, err := s.Conn.frame(fields, reply.Type, reply.Data)
+	if err != nil {
+		return s.sendError(ctx, fields, err)
+	}
+	return s.Conn.send(ctx, frame)
+}
+
+// sendError replies the error as a problem detail, with the status code the error would have in an HTTP response
+func (s *WebSocketSession) sendError(ctx context.Context, inbound map[string]json.RawMessage, err error) error {
+	var statusCode int
+	_ = errorToStatusCode(err, &statusCode)
+	problem := ProblemDetailFromError(err)
+	problem.Status = strconv.Itoa(statusCode)
+	frame, _ := s.Conn.frame(inbound, WebSocketTypeError, webSocketErrorData{Error: problem})
+	return s.Conn.send(ctx, frame)
+}
+
+func (c WebSocketConfig) typeField() string {
+	if c.TypeField != "" {
+		return c.TypeField
+	}
+	return DefaultWebSocketTypeField
+}
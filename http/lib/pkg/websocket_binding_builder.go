@@ -0,0 +1,114 @@
+package httpadpt
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	tagbasedhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler/tagbased"
+)
+
+type (
+	WebSocketBuildingStep interface {
+		// WithConfig sets the connection configuration
+		WithConfig(config WebSocketConfig) WebSocketBuildingStep
+		// WithMiddlewares sets the middlewares of the upgrade request, like the authentication ones
+		WithMiddlewares(middlewares ...Middleware) WebSocketBuildingStep
+		// WithMessageHandlerFunc sets the tagged handler function of the messages of the given type
+		WithMessageHandlerFunc(messageType string, handler any) WebSocketBuildingStep
+		Build() Binding
+	}
+
+	WebSocketBuilder struct {
+		path        string
+		config      WebSocketConfig
+		middlewares Middlewares
+		handlers    map[string]WebSocketHandler
+	}
+
+	// webSocketUpgradeHandler is the Handler of the WebSocket bindings, it sets the Response.WebSocket
+	webSocketUpgradeHandler struct {
+		config   WebSocketConfig
+		handlers map[string]WebSocketHandler
+	}
+)
+
+// NewWebSocketBindingBuilder creates the builder of a WebSocket binding. The binding handles the GET requests to the
+// path that ask for the WebSocket upgrade, and dispatches each inbound JSON message to the handler of its type.
+func NewWebSocketBindingBuilder(path string) WebSocketBuildingStep {
+	return &WebSocketBuilder{path: path, handlers: map[string]WebSocketHandler{}}
+}
+
+func (b *WebSocketBuilder) WithConfig(config WebSocketConfig) WebSocketBuildingStep {
+	b.config = config
+	return b
+}
+
+func (b *WebSocketBuilder) WithMiddlewares(middlewares ...Middleware) WebSocketBuildingStep {
+	b.middlewares = append(b.middlewares, middlewares...)
+	return b
+}
+
+func (b *WebSocketBuilder) WithMessageHandlerFunc(messageType string, handler any) WebSocketBuildingStep {
+	b.handlers[messageType] = tagbasedhandler.NewBuilderForFunc[WebSocketMessage, *WebSocketReply](handler).
+		WithInTagBasedFactory(createWebSocketInParamSpecFactory()).
+		WithOutTagBasedFactory(createWebSocketOutParamSpecFactory()).
+		WithOutErrorParamSpec(NewWebSocketOutErrorParamSpec()).
+		Build()
+	return b
+}
+
+// Build returns the binding, the WebSocketConfig zero values are replaced by the defaults
+func (b *WebSocketBuilder) Build() Binding {
+	config := b.config
+	config.TypeField = config.typeField()
+	if config.MaxMessageSize <= 0 {
+		config.MaxMessageSize = DefaultWebSocketMaxMessageSize
+	}
+	if config.PingInterval == 0 {
+		config.PingInterval = DefaultWebSocketPingInterval
+	}
+	if config.PongTimeout <= 0 {
+		config.PongTimeout = config.PingInterval
+	}
+	path := b.path
+	return Binding{
+		Condition:   Condition{Path: &path, Methods: []string{http.MethodGet}},
+		Handler:     webSocketUpgradeHandler{config: config, handlers: b.handlers},
+		Middlewares: b.middlewares,
+	}
+}
+
+// Invoke replies 426 to the requests that do not ask for the WebSocket upgrade, the implementation validates the
+// other handshake headers
+func (h webSocketUpgradeHandler) Invoke(_ context.Context, input Request, output *Response) error {
+	if !headerHasToken(input, "Connection", "upgrade") || !headerHasToken(input, "Upgrade", "websocket") {
+		statusCode := http.StatusUpgradeRequired
+		output.StatusCode = &statusCode
+		setHeaderValue(output, "Upgrade", "websocket")
+		output.Body = []byte(http.StatusText(statusCode))
+		return nil
+	}
+	output.WebSocket = &WebSocketSession{
+		Config:   h.config,
+		Handlers: h.handlers,
+		Conn:     newWebSocketConn(input, h.config.TypeField),
+	}
+	return nil
+}
+
+// headerHasToken returns true if the comma separated values of the header have the token, ignoring the case
+func headerHasToken(input Request, name, token string) bool {
+	if input == nil || input.Header() == nil {
+		return false
+	}
+	values, _ := input.Header().GetValue(name)
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(element), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package httpadpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	chatSendInput struct {
+		Room string `path:"room"`
+		User string `wsvalue:"user"`
+		Text string `ws:"text" assert:"mandatory"`
+		Type string `wstype:""`
+	}
+
+	chatSendOutput struct {
+		Type    string         `wstype:""`
+		Message map[string]any `ws:""`
+	}
+)
+
+func newTestWebSocketSession(t *testing.T) *WebSocketSession {
+	t.Helper()
+	binding := NewWebSocketBindingBuilder("/rooms/{room}").
+		WithMessageHandlerFunc("chat.send", func(in chatSendInput) (*chatSendOutput, error) {
+			if in.Text == "fail" {
+				return nil, serror.NotFoundError.New("room not found")
+			}
+			return &chatSendOutput{Type: "chat.sent", Message: map[string]any{
+				"room": in.Room, "user": in.User, "text": in.Text, "of": in.Type,
+			}}, nil
+		}).
+		WithMessageHandlerFunc("ping", func(ctx context.Context) (*chatSendOutput, error) {
+			conn, _ := WebSocketConnFromContext(ctx)
+			return nil, conn.Send(ctx, "pong", "hello")
+		}).
+		Build()
+	if binding.Methods[0] != http.MethodGet || *binding.Path != "/rooms/{room}" {
+		t.Fatalf("binding condition = %+v", binding.Condition)
+	}
+
+	request := &mockRequest{
+		header: &mockHeaderParams{values: map[string][]string{"Connection": {"keep-alive, Upgrade"}, "Upgrade": {"websocket"}}},
+		path:   &mockPathParams{values: map[string]string{"room": "lobby"}},
+	}
+	output := &Response{}
+	if err := binding.Invoke(context.Background(), request, output); err != nil || output.WebSocket == nil {
+		t.Fatalf("Invoke() = %v, WebSocket = %v", err, output.WebSocket)
+	}
+	if !output.IsStreaming() {
+		t.Error("IsStreaming() = false, want true for the WebSocket responses")
+	}
+	output.WebSocket.Conn.SetValue("user", "ana")
+	return output.WebSocket
+}
+
+func receiveFrame(t *testing.T, conn *WebSocketConn) map[string]any {
+	t.Helper()
+	select {
+	case frame := <-conn.Outbound():
+		var fields map[string]any
+		if err := json.Unmarshal(frame, &fields); err != nil {
+			t.Fatalf("frame %s: %v", frame, err)
+		}
+		return fields
+	case <-time.After(time.Second):
+		t.Fatal("no frame sent")
+	}
+	return nil
+}
+
+func Test_WebSocketSession_HandleMessage(t *testing.T) {
+	session := newTestWebSocketSession(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		message  string
+		expected map[string]any
+	}{
+		{
+			name:    "reply",
+			message: `{"type":"chat.send","id":"1","text":"hi"}`,
+			expected: map[string]any{"type": "chat.sent", "id": "1", "room": "lobby", "user": "ana", "text": "hi",
+				"of": "chat.send"},
+		},
+		{
+			name:     "sent by the handler",
+			message:  `{"type":"ping"}`,
+			expected: map[string]any{"type": "pong", "data": "hello"},
+		},
+		{
+			name:    "handler error",
+			message: `{"type":"chat.send","id":2,"text":"fail"}`,
+			expected: map[string]any{"type": "error", "id": float64(2), "error": map[string]any{
+				"status": "404", "type": "*errorx.Error", "detail": "common.not_found_error: room not found"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := session.HandleMessage(ctx, []byte(tt.message)); err != nil {
+				t.Fatalf("HandleMessage() error = %v", err)
+			}
+			if got := receiveFrame(t, session.Conn); !jsonEqual(got, tt.expected) {
+				t.Errorf("frame = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	for _, message := range []string{`not json`, `{"text":"no type"}`, `{"type":"unknown"}`, `{"type":"chat.send"}`} {
+		if err := session.HandleMessage(ctx, []byte(message)); err != nil {
+			t.Fatalf("HandleMessage() error = %v", err)
+		}
+		got := receiveFrame(t, session.Conn)
+		if problem, _ := got["error"].(map[string]any); got["type"] != WebSocketTypeError || problem["status"] != "400" &&
+			problem["status"] != "500" {
+			t.Errorf("%s: frame = %v, want an error reply", message, got)
+		}
+	}
+
+	session.Conn.Close(WebSocketCloseGoingAway, "bye")
+	if err := session.HandleMessage(ctx, []byte(`{"type":"ping"}`)); !errors.Is(err, ErrWebSocketClosed) {
+		t.Errorf("HandleMessage() after Close error = %v, want ErrWebSocketClosed", err)
+	}
+	if code, reason := session.Conn.CloseStatus(); code != WebSocketCloseGoingAway || reason != "bye" {
+		t.Errorf("CloseStatus() = %d %q", code, reason)
+	}
+}
+
+func Test_WebSocketBinding_UpgradeRequired(t *testing.T) {
+	binding := NewWebSocketBindingBuilder("/ws").Build()
+	output := &Response{}
+	if err := binding.Invoke(context.Background(), &mockRequest{header: &mockHeaderParams{}}, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if output.WebSocket != nil || *output.StatusCode != http.StatusUpgradeRequired || output.Header["Upgrade"][0] != "websocket" {
+		t.Errorf("output = %+v, want 426", output)
+	}
+}
+
+func jsonEqual(a, b map[string]any) bool {
+	encodedA, _ := json.Marshal(a)
+	encodedB, _ := json.Marshal(b)
+	return string(encodedA) == string(encodedB)
+}
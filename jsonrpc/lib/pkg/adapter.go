@@ -0,0 +1,186 @@
+package jsonrpcadpt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	// DefaultMaxMessageSize is the limit of the messages received when the transport config sets none
+	DefaultMaxMessageSize = 10 << 20
+)
+
+type (
+	Adapter interface {
+		Start(ctx context.Context) error
+		Stop(ctx context.Context) error
+	}
+
+	StreamConfig struct {
+		// Dispatcher handles the messages received
+		Dispatcher *Dispatcher
+		// Conn is the stream, each message is framed by a Content-Length header, see ReadFrame. It is closed by Stop
+		// if it is an io.Closer. Use Stdio() to serve the standard input and output.
+		Conn io.ReadWriter
+		// Concurrency is the number of messages handled in parallel, zero means 1 so that the responses follow the
+		// order of the requests
+		Concurrency int
+		// MaxMessageSize is the limit of the messages received, zero means DefaultMaxMessageSize. The larger
+		// messages are skipped and answered with a CodeInvalidRequest error.
+		MaxMessageSize int64
+		// Logger logs the write errors, it is slog.Default() if nil
+		Logger *slog.Logger
+	}
+
+	// StreamAdapter serves the JSON-RPC messages of a stream until it ends or the adapter is stopped
+	StreamAdapter struct {
+		config StreamConfig
+
+		locker       sync.Mutex
+		writeLocker  sync.Mutex
+		started      bool
+		stopped      bool
+		cancelHandle context.CancelFunc
+		handlers     sync.WaitGroup
+		slots        chan struct{}
+		done         chan struct{}
+		err          error
+	}
+)
+
+var (
+	_ Adapter = (*StreamAdapter)(nil)
+)
+
+// NewStreamAdapter creates the Adapter that reads the requests of the stream and writes back the responses
+func NewStreamAdapter(config StreamConfig) (*StreamAdapter, error) {
+	if config.Dispatcher == nil {
+		return nil, serror.IllegalConfigParamValue("Dispatcher", config.Dispatcher)
+	}
+	if config.Conn == nil {
+		return nil, serror.IllegalConfigParamValue("Conn", config.Conn)
+	}
+	config.Concurrency = max(config.Concurrency, 1)
+	if config.MaxMessageSize <= 0 {
+		config.MaxMessageSize = DefaultMaxMessageSize
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	return &StreamAdapter{config: config, done: make(chan struct{})}, nil
+}
+
+// Start reads the stream in background, Done is closed when it ends
+func (s *StreamAdapter) Start(ctx context.Context) error {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	if s.stopped {
+		return fmt.Errorf("adapter already stopped")
+	}
+	if s.started {
+		return fmt.Errorf("adapter already started")
+	}
+	handleCtx, cancelHandle := context.WithCancel(context.WithoutCancel(ctx))
+	s.cancelHandle = cancelHandle
+	s.slots = make(chan struct{}, s.config.Concurrency)
+	s.started = true
+	go s.serve(handleCtx)
+	return nil
+}
+
+// Stop stops reading the stream and waits for the messages being handled until the context is done, then the
+// handlers context is canceled
+func (s *StreamAdapter) Stop(ctx context.Context) error {
+	s.locker.Lock()
+	if !s.started || s.stopped {
+		s.stopped = true
+		s.locker.Unlock()
+		return nil
+	}
+	s.stopped = true
+	s.locker.Unlock()
+	if closer, ok := s.config.Conn.(io.Closer); ok {
+		_ = closer.Close()
+	}
+
+	handled := make(chan struct{})
+	go func() {
+		s.handlers.Wait()
+		close(handled)
+	}()
+	var err error
+	select {
+	case <-handled:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	s.cancelHandle()
+	return err
+}
+
+// Done is closed when the stream ends and the messages read were handled
+func (s *StreamAdapter) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error that ended the stream once Done is closed, it is nil if the stream reached EOF or the adapter
+// was stopped
+func (s *StreamAdapter) Err() error {
+	<-s.done
+	return s.err
+}
+
+func (s *StreamAdapter) isStopped() bool {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	return s.stopped
+}
+
+func (s *StreamAdapter) serve(ctx context.Context) {
+	defer close(s.done)
+	reader := bufio.NewReader(s.config.Conn)
+	for {
+		payload, err := ReadFrame(reader, s.config.MaxMessageSize)
+		frameErr := err
+		if errors.Is(err, ErrFrameTooLarge) {
+			err = nil
+		}
+		if err != nil || s.isStopped() {
+			if err != nil && !errors.Is(err, io.EOF) && !s.isStopped() {
+				s.err = err
+			}
+			s.handlers.Wait()
+			return
+		}
+		s.slots <- struct{}{}
+		s.handlers.Add(1)
+		go func() {
+			defer func() {
+				<-s.slots
+				s.handlers.Done()
+			}()
+			if frameErr != nil {
+				s.write(marshalResponse(newErrorResponse(nil, CodeInvalidRequest, frameErr.Error())))
+				return
+			}
+			if response := s.config.Dispatcher.Handle(ctx, payload); response != nil {
+				s.write(response)
+			}
+		}()
+	}
+}
+
+func (s *StreamAdapter) write(payload []byte) {
+	s.writeLocker.Lock()
+	defer s.writeLocker.Unlock()
+	if err := WriteFrame(s.config.Conn, payload); err != nil {
+		s.config.Logger.Error("jsonrpcadpt: failed to write the response", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,148 @@
+package jsonrpcadpt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gonethttp "github.com/smart-libs/go-adapter/http/impl/gonethttp/pkg"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+)
+
+type pipeConn struct {
+	io.Reader
+	io.Writer
+	closers []io.Closer
+}
+
+func (c pipeConn) Close() error {
+	for _, closer := range c.closers {
+		_ = closer.Close()
+	}
+	return nil
+}
+
+func Test_ReadFrame(t *testing.T) {
+	stream := "Content-Length: 2\r\nContent-Type: application/vscode-jsonrpc\r\n\r\n{}" +
+		"content-length: 12\r\n\r\n[1,2,3,4,5]6" +
+		"Content-Length: 3\r\n\r\n[1]"
+	reader := bufio.NewReader(strings.NewReader(stream))
+
+	if got, err := ReadFrame(reader, 10); err != nil || string(got) != "{}" {
+		t.Fatalf("ReadFrame() = %s, %v", got, err)
+	}
+	if _, err := ReadFrame(reader, 10); !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("ReadFrame() error = %v, want ErrFrameTooLarge", err)
+	}
+	if got, err := ReadFrame(reader, 10); err != nil || string(got) != "[1]" {
+		t.Fatalf("ReadFrame() = %s, %v", got, err)
+	}
+	if _, err := ReadFrame(reader, 10); !errors.Is(err, io.EOF) {
+		t.Fatalf("ReadFrame() error = %v, want io.EOF", err)
+	}
+
+	if _, err := ReadFrame(bufio.NewReader(strings.NewReader("Content-Length: 5\r\n\r\n{}")), 0); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadFrame() error = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if _, err := ReadFrame(bufio.NewReader(strings.NewReader("Content-Length: x\r\n\r\n")), 0); err == nil {
+		t.Error("ReadFrame() error = nil, want invalid Content-Length")
+	}
+}
+
+func Test_StreamAdapter(t *testing.T) {
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	adapter, err := NewStreamAdapter(StreamConfig{
+		Dispatcher:     newTestDispatcher(t, make(chan string, 10)),
+		Conn:           pipeConn{Reader: serverReader, Writer: serverWriter, closers: []io.Closer{serverReader, serverWriter}},
+		MaxMessageSize: 200,
+	})
+	if err != nil {
+		t.Fatalf("NewStreamAdapter() error = %v", err)
+	}
+	if err := adapter.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	go func() {
+		_ = WriteFrame(clientWriter, []byte(`{"jsonrpc":"2.0","method":"notify","params":["hello"]}`))
+		_ = WriteFrame(clientWriter, []byte(`{"jsonrpc":"2.0","method":"subtract","params":[42,23],"id":1}`))
+		_ = WriteFrame(clientWriter, bytes.Repeat([]byte(" "), 300))
+		_ = WriteFrame(clientWriter, []byte(`{"jsonrpc":"2.0","method":"subtract","params":[1,2],"id":2}`))
+	}()
+
+	reader := bufio.NewReader(clientReader)
+	for _, expected := range []string{
+		`{"jsonrpc":"2.0","result":19,"id":1}`,
+		`{"jsonrpc":"2.0","error":{"code":-32600,"message":"*"},"id":null}`,
+		`{"jsonrpc":"2.0","result":-1,"id":2}`,
+	} {
+		got, err := ReadFrame(reader, 0)
+		if err != nil {
+			t.Fatalf("ReadFrame() error = %v", err)
+		}
+		assertJSONEqual(t, got, expected)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := adapter.Stop(ctx); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+	select {
+	case <-adapter.Done():
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the adapter to stop")
+	}
+	if err := adapter.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func Test_NewHTTPBinding(t *testing.T) {
+	binding := NewHTTPBinding("/rpc", newTestDispatcher(t, make(chan string, 10)))
+
+	tests := []struct {
+		name       string
+		body       string
+		statusCode int
+		expected   string
+	}{
+		{
+			name:       "request",
+			body:       `{"jsonrpc":"2.0","method":"subtract","params":[42,23],"id":1}`,
+			statusCode: http.StatusOK,
+			expected:   `{"jsonrpc":"2.0","result":19,"id":1}`,
+		},
+		{
+			name:       "notification",
+			body:       `{"jsonrpc":"2.0","method":"notify","params":["hello"]}`,
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "too large",
+			body:       strings.Repeat(" ", DefaultMaxMessageSize+1),
+			statusCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := gonethttp.NewRequest(httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(tt.body)))
+			var response httpadpt.Response
+			if err := binding.Handler.Invoke(context.Background(), request, &response); err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if response.StatusCode == nil || *response.StatusCode != tt.statusCode {
+				t.Fatalf("StatusCode = %v, want %d", response.StatusCode, tt.statusCode)
+			}
+			assertJSONEqual(t, response.Body, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package jsonrpcadpt
+
+import "strings"
+
+type (
+	Binding struct {
+		// Method is the name of the method handled
+		Method string
+		Handler
+
+		// ParamNames names the positional params in order, it is set by WithHandlerFunc from the declaration order
+		// of the param fields
+		ParamNames []string
+	}
+
+	// Bindings are the methods served by the Dispatcher
+	Bindings []Binding
+)
+
+// IsBindingValid returns false if the binding has no handler or if the method is empty or reserved, the names
+// starting with "rpc." are reserved by the specification
+func IsBindingValid(binding Binding) bool {
+	return binding.Handler != nil && binding.Method != "" && !strings.HasPrefix(binding.Method, "rpc.")
+}
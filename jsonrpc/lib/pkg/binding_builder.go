@@ -0,0 +1,73 @@
+package jsonrpcadpt
+
+import (
+	"reflect"
+
+	tagbasedhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler/tagbased"
+)
+
+type (
+	HandlerBuildingStep interface {
+		// WithParamNames names the positional params instead of the declaration order of the param fields
+		WithParamNames(names ...string) HandlerBuildingStep
+		WithHandlerFunc(handler any) Binding
+	}
+
+	BaseBuilder struct {
+		Binding
+	}
+)
+
+func NewBindingBuilderUsingMethod(method string) HandlerBuildingStep {
+	return &BaseBuilder{Binding: Binding{Method: method}}
+}
+
+func (b *BaseBuilder) WithParamNames(names ...string) HandlerBuildingStep {
+	b.ParamNames = names
+	return b
+}
+
+func (b *BaseBuilder) WithHandlerFunc(handler any) Binding {
+	b.Handler = tagbasedhandler.NewBuilderForFunc[Request, *Response](handler).
+		WithInTagBasedFactory(createInParamSpecFactory()).
+		WithOutTagBasedFactory(createOutParamSpecFactory()).
+		WithOutErrorParamSpec(NewOutErrorParamSpec()).
+		Build()
+	if b.ParamNames == nil {
+		b.ParamNames = declaredParamNames(reflect.TypeOf(handler))
+	}
+	return b.Binding
+}
+
+// declaredParamNames returns the param tag values of the handler input structs in declaration order, including the
+// ones of the nested structs
+func declaredParamNames(funcType reflect.Type) []string {
+	var names []string
+	if funcType == nil || funcType.Kind() != reflect.Func {
+		return nil
+	}
+	visited := map[reflect.Type]bool{}
+	for i := 0; i < funcType.NumIn(); i++ {
+		names = appendParamNames(names, funcType.In(i), visited)
+	}
+	return names
+}
+
+func appendParamNames(names []string, structType reflect.Type, visited map[reflect.Type]bool) []string {
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct || visited[structType] {
+		return names
+	}
+	visited[structType] = true
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if name, found := field.Tag.Lookup(TagParam); found {
+			names = append(names, name)
+			continue
+		}
+		names = appendParamNames(names, field.Type, visited)
+	}
+	return names
+}
@@ -0,0 +1,82 @@
+package jsonrpcadpt
+
+import (
+	"context"
+	"errors"
+
+	sdkconverter "github.com/smart-libs/go-adapter/sdk/lib/pkg/converter"
+	sdkerror "github.com/smart-libs/go-adapter/sdk/lib/pkg/error"
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	converterdefault "github.com/smart-libs/go-crosscutting/converter/lib/pkg/default"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+var (
+	// ConverterRegistry it is the default converters registry for the JSON-RPC adapter
+	ConverterRegistry = converterdefault.NewRegistry()
+
+	// Converters is the list of converter.Converters used by the JSON-RPC adapter, it tries first the JSON-RPC
+	// adapter conversions and then the default converter.Converters
+	Converters = converter.NewConvertersList(
+		converterdefault.NewConverters(ConverterRegistry),
+		sdkconverter.JSONConverters{Name: "params"},
+		converterdefault.Converters,
+	)
+
+	errorCodes sdkerror.Registry[int]
+)
+
+func init() {
+	converter.AddHandler[error, Error](ConverterRegistry, errorToError)
+}
+
+// RegisterErrorCode makes the handler errors that satisfy the condition be answered with the given code. The
+// registered conditions are evaluated in the registration order before the default ones.
+func RegisterErrorCode(condition func(err error) bool, code int) {
+	errorCodes.Register(condition, code)
+}
+
+// errorToError maps the handler error to the error object, the handlers can return an *Error to choose the code. The
+// CodeInternalError errors get InternalErrorMessage, so the details of the unexpected errors and panics are not sent
+// to the clients.
+func errorToError(err error, to *Error) error {
+	if err == nil {
+		return nil
+	}
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		*to = *rpcErr
+		return nil
+	}
+	to.Message = err.Error()
+
+	callbacks := errorCodes.Callbacks(func(code int) { to.Code = code }, 5)
+
+	callbacks = append(callbacks,
+		serror.CallbackCondition{
+			Condition: serror.IsIllegalArgumentError,
+			Callback:  func(err error) { to.Code = CodeInvalidParams },
+		},
+		serror.CallbackCondition{
+			Condition: serror.IsNotFoundError,
+			Callback:  func(err error) { to.Code = CodeNotFound },
+		},
+		serror.CallbackCondition{
+			Condition: serror.IsDuplicateError,
+			Callback:  func(err error) { to.Code = CodeDuplicate },
+		},
+		serror.CallbackCondition{
+			Condition: func(err error) bool {
+				return serror.IsTimeoutError(err) || errors.Is(err, context.DeadlineExceeded)
+			},
+			Callback: func(err error) { to.Code = CodeTimeout },
+		},
+	)
+	if !serror.IdentifyRootCause(err, func(error) { to.Code = CodeInternalError }, callbacks...) {
+		to.Code = CodeInternalError
+	}
+	if to.Code == CodeInternalError {
+		to.Message = InternalErrorMessage
+	}
+	return nil
+}
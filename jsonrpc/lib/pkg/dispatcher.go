@@ -0,0 +1,145 @@
+package jsonrpcadpt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	sdkhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	// DefaultMaxBatchSize is the number of requests accepted in a batch when Config.MaxBatchSize is zero
+	DefaultMaxBatchSize = 100
+)
+
+type (
+	Config struct {
+		// Bindings are the methods served
+		Bindings
+		// MaxBatchSize is the number of requests accepted in a batch, zero means DefaultMaxBatchSize. The larger
+		// batches are answered with a single CodeInvalidRequest error.
+		MaxBatchSize int
+	}
+
+	// Dispatcher invokes the handlers of the JSON-RPC requests, the transports give it the payloads received and
+	// send back the responses, see NewHTTPBinding and NewStreamAdapter
+	Dispatcher struct {
+		config   Config
+		bindings map[string]Binding
+	}
+)
+
+// NewDispatcher creates the Dispatcher of the bindings of the config
+func NewDispatcher(config Config) (*Dispatcher, error) {
+	bindings := make(map[string]Binding, len(config.Bindings))
+	for _, binding := range config.Bindings {
+		if !IsBindingValid(binding) {
+			return nil, serror.IllegalConfigParamValue("Binding", binding.Method)
+		}
+		if _, found := bindings[binding.Method]; found {
+			return nil, serror.IllegalConfigParamValue("Binding", binding.Method)
+		}
+		bindings[binding.Method] = binding
+	}
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = DefaultMaxBatchSize
+	}
+	return &Dispatcher{config: config, bindings: bindings}, nil
+}
+
+// Handle handles the request or the batch of requests of the payload and returns the response payload. It returns
+// nil when there is nothing to answer, what happens when the payload has only notifications. The requests of a batch
+// are handled in parallel and their responses keep the batch order.
+func (d *Dispatcher) Handle(ctx context.Context, payload []byte) []byte {
+	payload = bytes.TrimSpace(payload)
+	if !json.Valid(payload) {
+		return marshalResponse(newErrorResponse(nil, CodeParseError, "Parse error"))
+	}
+	if payload[0] != '[' {
+		return marshalResponse(d.handleRequest(ctx, payload))
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(payload, &batch); err != nil || len(batch) == 0 {
+		return marshalResponse(newErrorResponse(nil, CodeInvalidRequest, "Invalid Request"))
+	}
+	if len(batch) > d.config.MaxBatchSize {
+		return marshalResponse(newErrorResponse(nil, CodeInvalidRequest,
+			fmt.Sprintf("Invalid Request: the batch exceeds %d requests", d.config.MaxBatchSize)))
+	}
+	responses := make([]*Response, len(batch))
+	var handlers sync.WaitGroup
+	for i, request := range batch {
+		handlers.Add(1)
+		go func() {
+			defer handlers.Done()
+			responses[i] = d.handleRequest(ctx, request)
+		}()
+	}
+	handlers.Wait()
+
+	var answered [][]byte
+	for _, response := range responses {
+		if response != nil {
+			answered = append(answered, marshalResponse(response))
+		}
+	}
+	if len(answered) == 0 {
+		return nil
+	}
+	return append(append([]byte{'['}, bytes.Join(answered, []byte{','})...), ']')
+}
+
+// handleRequest returns the response of the request, or nil if it is a notification
+func (d *Dispatcher) handleRequest(ctx context.Context, payload json.RawMessage) *Response {
+	var wire wireRequest
+	if len(payload) == 0 || payload[0] != '{' || json.Unmarshal(payload, &wire) != nil {
+		return newErrorResponse(nil, CodeInvalidRequest, "Invalid Request")
+	}
+	if wire.ID != nil && !isValidID(wire.ID) {
+		return newErrorResponse(nil, CodeInvalidRequest, "Invalid Request: id must be a string, a number or null")
+	}
+	if wire.JSONRPC == nil || *wire.JSONRPC != Version || wire.Method == nil || *wire.Method == "" {
+		return newErrorResponse(wire.ID, CodeInvalidRequest, "Invalid Request")
+	}
+
+	request := Request{Method: *wire.Method, Params: wire.Params, ID: wire.ID}
+	response := d.invoke(ctx, request)
+	if request.IsNotification() {
+		return nil
+	}
+	response.JSONRPC, response.ID = Version, request.ID
+	return response
+}
+
+func (d *Dispatcher) invoke(ctx context.Context, request Request) *Response {
+	binding, found := d.bindings[request.Method]
+	if !found {
+		return newErrorResponse(request.ID, CodeMethodNotFound, "Method not found: "+request.Method)
+	}
+	response := &Response{}
+	if err := request.bindParams(binding.ParamNames); err != nil {
+		response.Error = NewError(CodeInvalidParams, err.Error(), nil)
+		return response
+	}
+	if err := sdkhandler.Invoke(ctx, binding.Handler, request, response); err != nil {
+		_ = NewOutErrorParamSpec().SetValue(response, err)
+	}
+	return response
+}
+
+// marshalResponse returns the response payload, the results that cannot be marshalled are answered with an error
+func marshalResponse(response *Response) []byte {
+	if response == nil {
+		return nil
+	}
+	payload, err := json.Marshal(response)
+	if err != nil {
+		payload, _ = json.Marshal(newErrorResponse(response.ID, CodeInternalError, InternalErrorMessage))
+	}
+	return payload
+}
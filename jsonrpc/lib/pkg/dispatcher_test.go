@@ -0,0 +1,260 @@
+package jsonrpcadpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	subtractInput struct {
+		Minuend    int `param:"minuend" assert:"mandatory"`
+		Subtrahend int `param:"subtrahend"`
+	}
+
+	subtractOutput struct {
+		Result int `result:""`
+	}
+)
+
+var errQuota = errors.New("quota exceeded")
+
+func newTestDispatcher(t *testing.T, notified chan<- string) *Dispatcher {
+	t.Helper()
+	dispatcher, err := NewDispatcher(Config{Bindings: Bindings{
+		NewBindingBuilderUsingMethod("subtract").WithHandlerFunc(func(in subtractInput) (subtractOutput, error) {
+			return subtractOutput{Result: in.Minuend - in.Subtrahend}, nil
+		}),
+		NewBindingBuilderUsingMethod("notify").WithHandlerFunc(func(in struct {
+			Message string `param:"message"`
+		}) error {
+			notified <- in.Message
+			return nil
+		}),
+		NewBindingBuilderUsingMethod("fail").WithHandlerFunc(func(in struct {
+			Kind string `param:"kind"`
+		}) error {
+			switch in.Kind {
+			case "not-found":
+				return serror.NotFoundError.New("order not found")
+			case "rpc":
+				return NewError(-32050, "custom", map[string]string{"hint": "retry"})
+			case "quota":
+				return errQuota
+			case "panic":
+				panic("boom")
+			}
+			return errors.New("failed")
+		}),
+	}})
+	if err != nil {
+		t.Fatalf("NewDispatcher() error = %v", err)
+	}
+	return dispatcher
+}
+
+func Test_Dispatcher_Handle(t *testing.T) {
+	RegisterErrorCode(func(err error) bool { return errors.Is(err, errQuota) }, -32010)
+	dispatcher := newTestDispatcher(t, make(chan string, 10))
+
+	tests := []struct {
+		name     string
+		payload  string
+		expected string
+	}{
+		{
+			name:     "named params",
+			payload:  `{"jsonrpc":"2.0","method":"subtract","params":{"subtrahend":23,"minuend":42},"id":3}`,
+			expected: `{"jsonrpc":"2.0","result":19,"id":3}`,
+		},
+		{
+			name:     "positional params",
+			payload:  `{"jsonrpc":"2.0","method":"subtract","params":[42,23],"id":"a"}`,
+			expected: `{"jsonrpc":"2.0","result":19,"id":"a"}`,
+		},
+		{
+			name:     "too many positional params",
+			payload:  `{"jsonrpc":"2.0","method":"subtract","params":[42,23,1],"id":1}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32602,"message":"*"},"id":1}`,
+		},
+		{
+			name:     "missing mandatory param",
+			payload:  `{"jsonrpc":"2.0","method":"subtract","params":{"subtrahend":23},"id":1}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32602,"message":"*"},"id":1}`,
+		},
+		{
+			name:     "param of the wrong type",
+			payload:  `{"jsonrpc":"2.0","method":"subtract","params":["x",23],"id":1}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32602,"message":"*"},"id":1}`,
+		},
+		{
+			name:     "parse error",
+			payload:  `{"jsonrpc":"2.0","method":"subtract","params":[`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"},"id":null}`,
+		},
+		{
+			name:     "invalid request",
+			payload:  `{"jsonrpc":"2.0","method":1,"params":"bar"}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`,
+		},
+		{
+			name:     "wrong version",
+			payload:  `{"jsonrpc":"1.0","method":"subtract","id":7}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":7}`,
+		},
+		{
+			name:     "method not found",
+			payload:  `{"jsonrpc":"2.0","method":"foobar","id":"1"}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found: foobar"},"id":"1"}`,
+		},
+		{
+			name:     "serror mapped",
+			payload:  `{"jsonrpc":"2.0","method":"fail","params":{"kind":"not-found"},"id":1}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32001,"message":"*"},"id":1}`,
+		},
+		{
+			name:     "registered error code",
+			payload:  `{"jsonrpc":"2.0","method":"fail","params":{"kind":"quota"},"id":1}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32010,"message":"quota exceeded"},"id":1}`,
+		},
+		{
+			name:     "error object returned by the handler",
+			payload:  `{"jsonrpc":"2.0","method":"fail","params":{"kind":"rpc"},"id":1}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32050,"message":"custom","data":{"hint":"retry"}},"id":1}`,
+		},
+		{
+			name:     "unknown error",
+			payload:  `{"jsonrpc":"2.0","method":"fail","params":["other"],"id":1}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"},"id":1}`,
+		},
+		{
+			name:     "panic",
+			payload:  `{"jsonrpc":"2.0","method":"fail","params":["panic"],"id":1}`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"},"id":1}`,
+		},
+		{
+			name:     "empty batch",
+			payload:  `[]`,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`,
+		},
+		{
+			name:     "invalid batch",
+			payload:  `[1,2]`,
+			expected: `[{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null},{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}]`,
+		},
+		{
+			name: "batch",
+			payload: `[
+				{"jsonrpc":"2.0","method":"subtract","params":[1,2],"id":"1"},
+				{"jsonrpc":"2.0","method":"notify","params":["hello"]},
+				{"foo":"boo"},
+				{"jsonrpc":"2.0","method":"subtract","params":{"minuend":5},"id":"2"}
+			]`,
+			expected: `[{"jsonrpc":"2.0","result":-1,"id":"1"},{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null},{"jsonrpc":"2.0","result":5,"id":"2"}]`,
+		},
+		{
+			name:     "notification",
+			payload:  `{"jsonrpc":"2.0","method":"notify","params":["hello"]}`,
+			expected: ``,
+		},
+		{
+			name:     "batch of notifications",
+			payload:  `[{"jsonrpc":"2.0","method":"notify","params":["a"]},{"jsonrpc":"2.0","method":"foobar"}]`,
+			expected: ``,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dispatcher.Handle(context.Background(), []byte(tt.payload))
+			assertJSONEqual(t, got, tt.expected)
+		})
+	}
+}
+
+func Test_Dispatcher_Notification(t *testing.T) {
+	notified := make(chan string, 1)
+	dispatcher := newTestDispatcher(t, notified)
+	got := dispatcher.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notify","params":{"message":"hi"}}`))
+	if got != nil {
+		t.Errorf("Handle() = %s, want nil", got)
+	}
+	if message := <-notified; message != "hi" {
+		t.Errorf("message = %q, want %q", message, "hi")
+	}
+}
+
+func Test_NewDispatcher_InvalidBindings(t *testing.T) {
+	handler := MakeHandler(nil)
+	tests := []struct {
+		name     string
+		bindings Bindings
+	}{
+		{name: "reserved method", bindings: Bindings{{Method: "rpc.discover", Handler: handler}}},
+		{name: "empty method", bindings: Bindings{{Handler: handler}}},
+		{name: "no handler", bindings: Bindings{{Method: "m"}}},
+		{name: "duplicated method", bindings: Bindings{{Method: "m", Handler: handler}, {Method: "m", Handler: handler}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDispatcher(Config{Bindings: tt.bindings}); err == nil {
+				t.Error("NewDispatcher() error = nil, want error")
+			}
+		})
+	}
+}
+
+// assertJSONEqual compares the payloads ignoring the messages expected as "*"
+func assertJSONEqual(t *testing.T, got []byte, expected string) {
+	t.Helper()
+	if expected == "" {
+		if got != nil {
+			t.Errorf("payload = %s, want nil", got)
+		}
+		return
+	}
+	var gotValue, expectedValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid payload %s: %v", got, err)
+	}
+	_ = json.Unmarshal([]byte(expected), &expectedValue)
+	if !matchJSON(gotValue, expectedValue) {
+		t.Errorf("payload = %s, want %s", got, expected)
+	}
+}
+
+func matchJSON(got, expected any) bool {
+	switch expected := expected.(type) {
+	case string:
+		gotString, ok := got.(string)
+		return ok && (expected == "*" || expected == gotString)
+	case map[string]any:
+		gotMap, ok := got.(map[string]any)
+		if !ok || len(gotMap) != len(expected) {
+			return false
+		}
+		for key, value := range expected {
+			if !matchJSON(gotMap[key], value) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		gotSlice, ok := got.([]any)
+		if !ok || len(gotSlice) != len(expected) {
+			return false
+		}
+		for i := range expected {
+			if !matchJSON(gotSlice[i], expected[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	gotJSON, _ := json.Marshal(got)
+	expectedJSON, _ := json.Marshal(expected)
+	return strings.EqualFold(string(gotJSON), string(expectedJSON))
+}
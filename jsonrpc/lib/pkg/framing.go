@@ -0,0 +1,85 @@
+package jsonrpcadpt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// HeaderContentLength is the framing header that precedes each message of the streams
+	HeaderContentLength = "Content-Length"
+)
+
+var (
+	// ErrFrameTooLarge is returned by ReadFrame when the Content-Length exceeds the limit, the frame is skipped so the
+	// stream can be read on
+	ErrFrameTooLarge = errors.New("jsonrpc frame too large")
+)
+
+// Stdio returns the io.ReadWriter of the standard input and output, the transport of the language-server-like tools
+func Stdio() io.ReadWriter {
+	return struct {
+		io.Reader
+		io.Writer
+	}{Reader: os.Stdin, Writer: os.Stdout}
+}
+
+// ReadFrame reads a message framed by the "Content-Length: N" header followed by an empty line, as done by the
+// Language Server Protocol. The other headers, like Content-Type, are ignored.
+func ReadFrame(reader *bufio.Reader, maxSize int64) ([]byte, error) {
+	contentLength := int64(-1)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) && line != "" {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if contentLength >= 0 {
+				break
+			}
+			continue // blank lines between the frames
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("jsonrpcadpt.ReadFrame: malformed header=[%s]", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), HeaderContentLength) {
+			contentLength, err = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil || contentLength < 0 {
+				return nil, fmt.Errorf("jsonrpcadpt.ReadFrame: invalid %s=[%s]", HeaderContentLength, value)
+			}
+		}
+	}
+	if maxSize > 0 && contentLength > maxSize {
+		if _, err := io.CopyN(io.Discard, reader, contentLength); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %d bytes exceed the limit of %d", ErrFrameTooLarge, contentLength, maxSize)
+	}
+	payload := make([]byte, contentLength)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return payload, nil
+}
+
+// WriteFrame writes the payload preceded by the Content-Length header
+func WriteFrame(writer io.Writer, payload []byte) error {
+	if _, err := fmt.Fprintf(writer, "%s: %d\r\n\r\n", HeaderContentLength, len(payload)); err != nil {
+		return err
+	}
+	_, err := writer.Write(payload)
+	return err
+}
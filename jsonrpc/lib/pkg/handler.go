@@ -0,0 +1,27 @@
+package jsonrpcadpt
+
+import (
+	"context"
+
+	sdkhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler"
+)
+
+type (
+	funcBasedHandler struct {
+		invokeFunc func(ctx context.Context, input Request, output *Response) error
+	}
+
+	Handler = sdkhandler.Handler[Request, *Response]
+)
+
+func (f funcBasedHandler) Invoke(ctx context.Context, input Request, output *Response) error {
+	return f.invokeFunc(ctx, input, output)
+}
+
+// MakeHandler adapts a function to Handler
+func MakeHandler(invoker func(ctx context.Context, input Request, output *Response) error) Handler {
+	if invoker == nil {
+		invoker = func(context.Context, Request, *Response) error { return nil }
+	}
+	return funcBasedHandler{invokeFunc: invoker}
+}
@@ -0,0 +1,49 @@
+package jsonrpcadpt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+// NewHTTPBinding returns the httpadpt.Binding that answers the JSON-RPC requests POSTed to path. The responses are
+// sent with 200, and the payloads with only notifications get 204 without body. The bodies larger than
+// DefaultMaxMessageSize get 413.
+func NewHTTPBinding(path string, dispatcher *Dispatcher) httpadpt.Binding {
+	return httpadpt.Binding{
+		Condition: httpadpt.Condition{Path: &path, Methods: []string{http.MethodPost}},
+		Handler: httpadpt.MakeHandler(func(ctx context.Context, input httpadpt.Request, output *httpadpt.Response) error {
+			return handleHTTPRequest(ctx, dispatcher, input, output)
+		}),
+	}
+}
+
+func handleHTTPRequest(ctx context.Context, dispatcher *Dispatcher, input httpadpt.Request, output *httpadpt.Response) error {
+	const fName = "jsonrpcadpt.handleHTTPRequest"
+	if err := httpadpt.IsResponseNil(output); err != nil {
+		return serror.CmpError.Wrap(err, "%s: invalid output", fName)
+	}
+	payload, err := httpadpt.ReadBody(input, DefaultMaxMessageSize)
+	var maxBytesError *http.MaxBytesError
+	if err != nil && !errors.As(err, &maxBytesError) {
+		return serror.CmpError.Wrap(err, "%s: failed to read the body", fName)
+	}
+
+	statusCode := http.StatusOK
+	switch {
+	case maxBytesError != nil:
+		statusCode = http.StatusRequestEntityTooLarge
+	default:
+		output.Body = dispatcher.Handle(ctx, payload)
+		if output.Body == nil {
+			statusCode = http.StatusNoContent
+		} else {
+			output.Header = map[string][]string{"Content-Type": {"application/json"}}
+		}
+	}
+	output.StatusCode = &statusCode
+	return nil
+}
@@ -0,0 +1,43 @@
+package jsonrpcadpt
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+const (
+	// TagParam gets the param by name, or by position following the declaration order of the param fields
+	TagParam = "param"
+	// TagParams gets the whole params member, an object or an array
+	TagParams = "params"
+	// TagMethod gets the invoked method name
+	TagMethod = "method"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().
+		AddOption2(TagParam, getParamInParamValue).
+		AddOption1(TagParams, "", getParamsInParamValue).
+		AddOption1(TagMethod, "", func(input Request) (any, error) { return input.Method, nil })
+}
+
+// getParamInParamValue returns the param, the absent and null ones are nil so the default and mandatory options apply
+func getParamInParamValue(input Request, name string) (any, error) {
+	value, found := input.Param(name)
+	if !found || isNull(value) {
+		return nil, nil
+	}
+	return value, nil
+}
+
+func getParamsInParamValue(input Request) (any, error) {
+	if isNull(input.Params) {
+		return nil, nil
+	}
+	return input.Params, nil
+}
+
+func isNull(value json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(value)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
@@ -0,0 +1,61 @@
+package jsonrpcadpt
+
+import (
+	"reflect"
+
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	// invalidParamsSpecFactory creates the specs whose failures, like the unmet assertions, are illegal arguments so
+	// that they are answered with CodeInvalidParams
+	invalidParamsSpecFactory struct {
+		factory tagbased.InputParamSpecFactory[Request]
+	}
+
+	invalidParamsSpec struct {
+		sdkparam.InputParamSpec[Request]
+	}
+)
+
+var (
+	inParamSpecFactoryRegistry tagbased.InputParamSpecFactoryRegistry[Request]
+)
+
+func getInputParamSpecFactoryRegistry() tagbased.InputParamSpecFactoryRegistry[Request] {
+	if inParamSpecFactoryRegistry == nil {
+		inParamSpecFactoryRegistry = tagbased.NewInputParamSpecFactoryRegistry[Request](Converters)
+	}
+
+	return inParamSpecFactoryRegistry
+}
+
+func createInParamSpecFactory() tagbased.InputParamSpecFactory[Request] {
+	return invalidParamsSpecFactory{factory: tagbased.NewsInputParamSpecFactory(getInputParamSpecFactoryRegistry())}
+}
+
+func (f invalidParamsSpecFactory) CreateInputParamSpec(field reflect.StructField) (sdkparam.InputParamSpec[Request], error) {
+	spec, err := f.factory.CreateInputParamSpec(field)
+	if err != nil {
+		return nil, err
+	}
+	return invalidParamsSpec{InputParamSpec: spec}, nil
+}
+
+func (s invalidParamsSpec) CopyValue(input Request, target any) error {
+	return asInvalidParams(s.Name(), s.InputParamSpec.CopyValue(input, target))
+}
+
+func (s invalidParamsSpec) GetValue(input Request) (any, error) {
+	value, err := s.InputParamSpec.GetValue(input)
+	return value, asInvalidParams(s.Name(), err)
+}
+
+func asInvalidParams(name string, err error) error {
+	if err == nil || serror.IsIllegalArgumentError(err) {
+		return err
+	}
+	return serror.IllegalArgumentValueWithCause("params", name, err)
+}
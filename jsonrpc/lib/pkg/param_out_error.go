@@ -0,0 +1,35 @@
+package jsonrpcadpt
+
+import (
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"github.com/smart-libs/go-crosscutting/assertions/lib/pkg/check"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	OutErrorParamSpec struct{}
+)
+
+func (o OutErrorParamSpec) Name() string               { return "error" }
+func (o OutErrorParamSpec) Options() []sdkparam.Option { return nil }
+
+// SetValue sets the Response error mapped from the handler error, see RegisterErrorCode
+func (o OutErrorParamSpec) SetValue(output *Response, value any) error {
+	if check.IsNil(value) {
+		return nil // no error
+	}
+	if output == nil {
+		return serror.CmpError.New("jsonrpcadpt.OutErrorParamSpec.SetValue: output is nil")
+	}
+	if err, ok := value.(error); ok {
+		output.Error = &Error{}
+		if convErr := errorToError(err, output.Error); convErr != nil {
+			return convErr
+		}
+	}
+	return nil
+}
+
+func NewOutErrorParamSpec() sdkparam.OutputParamSpec[*Response] {
+	return OutErrorParamSpec{}
+}
@@ -0,0 +1,22 @@
+package jsonrpcadpt
+
+import (
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	// TagResult sets the result member of the response, it is marshalled as JSON
+	TagResult = "result"
+)
+
+func init() {
+	getOutParamSpecFactoryRegistry().AddOption1(TagResult, "", setResult)
+}
+
+func setResult(output *Response, value any) error {
+	if output == nil {
+		return serror.CmpError.New("jsonrpcadpt.setResult: output is nil")
+	}
+	output.Result = value
+	return nil
+}
@@ -0,0 +1,90 @@
+package jsonrpcadpt
+
+import (
+	"bytes"
+	"encoding/json"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	// Version is the value of the jsonrpc member of the requests and responses
+	Version = "2.0"
+)
+
+type (
+	// Request is the input of the JSON-RPC handlers
+	Request struct {
+		// Method is the name of the invoked method
+		Method string
+		// Params is the raw params member, an array, an object or nil if absent
+		Params json.RawMessage
+		// ID is the raw id member, nil for notifications
+		ID json.RawMessage
+
+		// named are the params by name, the positional ones are named by Binding.ParamNames
+		named map[string]json.RawMessage
+	}
+
+	// wireRequest is the request object as sent by the client, the pointers tell the absent members apart
+	wireRequest struct {
+		JSONRPC *string         `json:"jsonrpc"`
+		Method  *string         `json:"method"`
+		Params  json.RawMessage `json:"params"`
+		ID      json.RawMessage `json:"id"`
+	}
+)
+
+// IsNotification returns true if the request has no id, the notifications are not answered
+func (r Request) IsNotification() bool {
+	return r.ID == nil
+}
+
+// Param returns the param by name, the positional params are named by Binding.ParamNames
+func (r Request) Param(name string) (json.RawMessage, bool) {
+	value, found := r.named[name]
+	return value, found
+}
+
+// bindParams names the params, the positional ones take the names in the order given
+func (r *Request) bindParams(names []string) error {
+	params := bytes.TrimSpace(r.Params)
+	switch {
+	case len(params) == 0 || bytes.Equal(params, []byte("null")):
+		return nil
+	case params[0] == '{':
+		if err := json.Unmarshal(params, &r.named); err != nil {
+			return serror.IllegalArgumentValueWithCause("params", string(params), err)
+		}
+	case params[0] == '[':
+		var positional []json.RawMessage
+		if err := json.Unmarshal(params, &positional); err != nil {
+			return serror.IllegalArgumentValueWithCause("params", string(params), err)
+		}
+		if len(names) == 0 {
+			return nil // the handler gets the params as a whole
+		}
+		if len(positional) > len(names) {
+			return serror.IllegalArgumentValue("params", len(positional))
+		}
+		r.named = make(map[string]json.RawMessage, len(positional))
+		for i, value := range positional {
+			r.named[names[i]] = value
+		}
+	default:
+		return serror.IllegalArgumentValue("params", string(params))
+	}
+	return nil
+}
+
+// isValidID returns true if the id is a string, a number or null
+func isValidID(id json.RawMessage) bool {
+	if len(id) == 0 {
+		return false
+	}
+	switch id[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package jsonrpcadpt
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The error codes defined by the JSON-RPC 2.0 specification
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// InternalErrorMessage is the message of the CodeInternalError errors created from the handler errors, the one given
+// by the specification
+const InternalErrorMessage = "Internal error"
+
+// The error codes of the serror types, they are in the range reserved for implementation-defined server errors
+const (
+	CodeNotFound  = -32001
+	CodeDuplicate = -32002
+	CodeTimeout   = -32003
+)
+
+type (
+	// Response is the output of the JSON-RPC handlers, Result is set by the result tag and Error by the handler error
+	Response struct {
+		JSONRPC string
+		Result  any
+		Error   *Error
+		ID      json.RawMessage
+	}
+
+	// Error is the error object of the responses. The handlers can return it, or wrap it, to answer with a specific
+	// code and data.
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    any    `json:"data,omitempty"`
+	}
+
+	successResponse struct {
+		JSONRPC string          `json:"jsonrpc"`
+		Result  any             `json:"result"`
+		ID      json.RawMessage `json:"id"`
+	}
+
+	errorResponse struct {
+		JSONRPC string          `json:"jsonrpc"`
+		Error   *Error          `json:"error"`
+		ID      json.RawMessage `json:"id"`
+	}
+)
+
+// NewError creates the Error with the given code, message and optional data
+func NewError(code int, message string, data any) *Error {
+	return &Error{Code: code, Message: message, Data: data}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// MarshalJSON writes either the result or the error member, the result is written even if it is null
+func (r Response) MarshalJSON() ([]byte, error) {
+	id := r.ID
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	if r.Error != nil {
+		return json.Marshal(errorResponse{JSONRPC: Version, Error: r.Error, ID: id})
+	}
+	return json.Marshal(successResponse{JSONRPC: Version, Result: r.Result, ID: id})
+}
+
+// UnmarshalJSON reads the response, it allows the Response to be used by the clients and the tests
+func (r *Response) UnmarshalJSON(data []byte) error {
+	var wire struct {
+		JSONRPC string          `json:"jsonrpc"`
+		Result  json.RawMessage `json:"result"`
+		Error   *Error          `json:"error"`
+		ID      json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	r.JSONRPC, r.Error, r.ID, r.Result = wire.JSONRPC, wire.Error, wire.ID, nil
+	if wire.Result != nil {
+		r.Result = wire.Result
+	}
+	return nil
+}
+
+func newErrorResponse(id json.RawMessage, code int, message string) *Response {
+	return &Response{JSONRPC: Version, Error: NewError(code, message, nil), ID: id}
+}
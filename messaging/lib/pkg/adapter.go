@@ -0,0 +1,232 @@
+package msgadpt
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	sdkhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	// DefaultMaxDeliveries is the number of deliveries before a message that keeps failing is dead lettered
+	DefaultMaxDeliveries = 5
+	DefaultRetryDelay    = time.Second
+	DefaultMaxRetryDelay = time.Minute
+
+	// receiveErrorDelay is the wait before receiving again after the broker fails
+	receiveErrorDelay = time.Second
+)
+
+type (
+	Adapter interface {
+		Start(ctx context.Context) error
+		Stop(ctx context.Context) error
+	}
+
+	Config struct {
+		// Broker is the messaging system consumed
+		Broker Broker
+		// Bindings are the subscriptions consumed
+		Bindings
+		// Group is the consumer group of the bindings without one
+		Group string
+		// Concurrency is the number of messages handled in parallel by each binding, zero means 1
+		Concurrency int
+		// Prefetch is the number of messages delivered to each binding and not settled yet, zero means Concurrency
+		Prefetch int
+		// MaxDeliveries is the number of deliveries before a message whose handler keeps failing is dead lettered,
+		// zero means DefaultMaxDeliveries and a negative value retries forever
+		MaxDeliveries int
+		// Backoff returns the redelivery delay of the failed messages, it is
+		// ExponentialBackoff(DefaultRetryDelay, DefaultMaxRetryDelay) if nil
+		Backoff func(deliveryCount int) time.Duration
+		// Logger logs the broker errors, it is slog.Default() if nil
+		Logger *slog.Logger
+	}
+
+	DefaultAdapter struct {
+		config Config
+
+		locker        sync.Mutex
+		started       bool
+		stopped       bool
+		subscriptions []Subscription
+		cancelReceive context.CancelFunc
+		cancelHandle  context.CancelFunc
+		workers       sync.WaitGroup
+	}
+)
+
+// NewAdapter creates the Adapter that consumes the bindings of the config. The handler error settles each message,
+// see RegisterErrorAction: the message is acknowledged on success, redelivered with backoff on transient failures and
+// dead lettered on invalid messages or after Config.MaxDeliveries.
+func NewAdapter(config Config) (Adapter, error) {
+	if config.Broker == nil {
+		return nil, serror.IllegalConfigParamValue("Broker", config.Broker)
+	}
+	for _, binding := range config.Bindings {
+		if !IsBindingValid(binding) {
+			return nil, serror.IllegalConfigParamValue("Binding", binding.Topic)
+		}
+	}
+	config.Concurrency = max(config.Concurrency, 1)
+	if config.MaxDeliveries == 0 {
+		config.MaxDeliveries = DefaultMaxDeliveries
+	}
+	if config.Backoff == nil {
+		config.Backoff = ExponentialBackoff(DefaultRetryDelay, DefaultMaxRetryDelay)
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	return &DefaultAdapter{config: config}, nil
+}
+
+// Start subscribes the bindings before returning and consumes them in background
+func (d *DefaultAdapter) Start(ctx context.Context) error {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	if d.stopped {
+		return fmt.Errorf("adapter already stopped")
+	}
+	if d.started {
+		return fmt.Errorf("adapter already started")
+	}
+
+	subscriptions := make([]Subscription, 0, len(d.config.Bindings))
+	for _, binding := range d.config.Bindings {
+		subscription, err := d.config.Broker.Subscribe(ctx, binding.Topic, SubscribeOptions{
+			Group:    cmp.Or(binding.Group, d.config.Group),
+			Prefetch: d.prefetch(binding),
+		})
+		if err != nil {
+			for _, subscribed := range subscriptions {
+				_ = subscribed.Close()
+			}
+			return fmt.Errorf("msgadpt.DefaultAdapter.Start: topic=[%s]: %w", binding.Topic, err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	receiveCtx, cancelReceive := context.WithCancel(context.WithoutCancel(ctx))
+	handleCtx, cancelHandle := context.WithCancel(context.WithoutCancel(ctx))
+	d.subscriptions, d.cancelReceive, d.cancelHandle = subscriptions, cancelReceive, cancelHandle
+	for i, binding := range d.config.Bindings {
+		for range d.concurrency(binding) {
+			d.workers.Add(1)
+			go d.consume(receiveCtx, handleCtx, binding, subscriptions[i])
+		}
+	}
+	d.started = true
+	return nil
+}
+
+// Stop stops receiving messages and waits for the ones being handled until the context is done, then the handlers
+// context is canceled and the subscriptions are closed
+func (d *DefaultAdapter) Stop(ctx context.Context) error {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	if !d.started || d.stopped {
+		d.stopped = true
+		return nil
+	}
+	d.stopped = true
+	d.cancelReceive()
+
+	done := make(chan struct{})
+	go func() {
+		d.workers.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	d.cancelHandle()
+	for _, subscription := range d.subscriptions {
+		if closeErr := subscription.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (d *DefaultAdapter) consume(receiveCtx, handleCtx context.Context, binding Binding, subscription Subscription) {
+	defer d.workers.Done()
+	for receiveCtx.Err() == nil {
+		delivery, err := subscription.Receive(receiveCtx)
+		if err != nil {
+			if receiveCtx.Err() != nil || errors.Is(err, ErrSubscriptionClosed) {
+				return
+			}
+			d.config.Logger.Error("msgadpt: failed to receive", slog.String("topic", binding.Topic),
+				slog.Any("error", err))
+			select {
+			case <-receiveCtx.Done():
+				return
+			case <-time.After(receiveErrorDelay):
+			}
+			continue
+		}
+		d.handle(handleCtx, binding, delivery)
+	}
+}
+
+// handle invokes the binding handler and settles the delivery according to the Result
+func (d *DefaultAdapter) handle(ctx context.Context, binding Binding, delivery Delivery) {
+	message := delivery.Message()
+	result := &Result{}
+	if err := sdkhandler.Invoke(ctx, binding.Handler, message, result); err != nil {
+		_ = NewOutErrorParamSpec().SetValue(result, err)
+	}
+
+	action := result.Action
+	if action == ActionRetry && d.config.MaxDeliveries > 0 && message.DeliveryCount >= d.config.MaxDeliveries {
+		action = ActionDeadLetter
+	}
+	var err error
+	switch action {
+	case ActionRetry:
+		delay := result.RetryDelay
+		if delay <= 0 {
+			delay = d.config.Backoff(message.DeliveryCount)
+		}
+		err = delivery.Nack(ctx, delay)
+	case ActionDeadLetter:
+		err = delivery.DeadLetter(ctx, result.Err)
+	default:
+		err = delivery.Ack(ctx)
+	}
+	if err != nil {
+		d.config.Logger.Error("msgadpt: failed to settle the message", slog.String("topic", message.Topic),
+			slog.String("id", message.ID), slog.String("action", action.String()), slog.Any("error", err))
+	}
+}
+
+func (d *DefaultAdapter) concurrency(binding Binding) int {
+	if binding.Concurrency > 0 {
+		return binding.Concurrency
+	}
+	return d.config.Concurrency
+}
+
+func (d *DefaultAdapter) prefetch(binding Binding) int {
+	switch {
+	case binding.Prefetch > 0:
+		return binding.Prefetch
+	case binding.Concurrency > 0:
+		return binding.Concurrency
+	case d.config.Prefetch > 0:
+		return d.config.Prefetch
+	default:
+		return d.config.Concurrency
+	}
+}
@@ -0,0 +1,200 @@
+package msgadpt
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	testOrder struct {
+		ID    string  `json:"id"`
+		Total float64 `json:"total"`
+	}
+
+	orderInput struct {
+		Order         testOrder `payload:"" mime-type:"application/json"`
+		Tenant        string    `msgheader:"x-tenant"`
+		Topic         string    `topic:""`
+		Key           string    `key:""`
+		DeliveryCount int       `deliverycount:""`
+	}
+)
+
+func startTestAdapter(t *testing.T, config Config) {
+	t.Helper()
+	adapter, err := NewAdapter(config)
+	if err != nil {
+		t.Fatalf("NewAdapter() error = %v", err)
+	}
+	if err := adapter.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = adapter.Stop(context.Background()) })
+}
+
+func waitFor[T any](t *testing.T, values <-chan T) T {
+	t.Helper()
+	select {
+	case value := <-values:
+		return value
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for the handler")
+	}
+	var zero T
+	return zero
+}
+
+func Test_Adapter_Inputs(t *testing.T) {
+	broker := NewMemoryBroker()
+	received := make(chan orderInput, 1)
+	startTestAdapter(t, Config{
+		Broker: broker,
+		Bindings: Bindings{NewBindingBuilderUsingTopic("orders").WithHandlerFunc(func(in orderInput) error {
+			received <- in
+			return nil
+		})},
+	})
+
+	_ = broker.Publish(context.Background(), Message{
+		Topic:   "orders",
+		Key:     "customer-1",
+		Payload: []byte(`{"id":"o-1","total":10.5}`),
+		Headers: map[string][]string{"X-Tenant": {"acme"}},
+	})
+	got := waitFor(t, received)
+	expected := orderInput{Order: testOrder{ID: "o-1", Total: 10.5}, Tenant: "acme", Topic: "orders", Key: "customer-1", DeliveryCount: 1}
+	if got != expected {
+		t.Errorf("input = %+v, want %+v", got, expected)
+	}
+}
+
+func Test_Adapter_Settlement(t *testing.T) {
+	tests := []struct {
+		name               string
+		failures           int32
+		err                error
+		payload            string
+		expectedDeliveries int32
+		expectDeadLetter   bool
+	}{
+		{name: "retried until success", failures: 2, err: errors.New("unavailable"), expectedDeliveries: 3},
+		{name: "retry after", failures: 1, err: RetryAfter(serror.IllegalArgumentValue("id", ""), time.Millisecond), expectedDeliveries: 2},
+		{name: "dead lettered after max deliveries", failures: 10, err: errors.New("unavailable"), expectedDeliveries: 3, expectDeadLetter: true},
+		{name: "invalid message", failures: 10, err: serror.IllegalArgumentValue("id", ""), expectedDeliveries: 1, expectDeadLetter: true},
+		{name: "explicit dead letter", failures: 10, err: fmt.Errorf("unknown type: %w", ErrDeadLetter), expectedDeliveries: 1, expectDeadLetter: true},
+		{name: "duplicate", failures: 10, err: serror.DuplicateError.New("already processed"), expectedDeliveries: 1},
+		{name: "invalid payload", payload: `{`, expectedDeliveries: 0, expectDeadLetter: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			broker := NewMemoryBroker()
+			deadLetters, _ := broker.Subscribe(ctx, "orders"+DefaultDeadLetterSuffix, SubscribeOptions{})
+			var deliveries atomic.Int32
+			calls := make(chan int32, 10)
+			startTestAdapter(t, Config{
+				Broker:        broker,
+				MaxDeliveries: 3,
+				Backoff:       func(int) time.Duration { return time.Millisecond },
+				Bindings: Bindings{NewBindingBuilderUsingTopic("orders").WithHandlerFunc(func(in orderInput) error {
+					count := deliveries.Add(1)
+					defer func() { calls <- count }()
+					if count <= tt.failures {
+						return tt.err
+					}
+					return nil
+				})},
+			})
+
+			payload := cmp.Or(tt.payload, `{"id":"o-1"}`)
+			_ = broker.Publish(ctx, Message{Topic: "orders", Payload: []byte(payload)})
+
+			if tt.expectDeadLetter {
+				receiveCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+				defer cancel()
+				deadLetter, err := deadLetters.Receive(receiveCtx)
+				if err != nil {
+					t.Fatalf("dead letter not received: %v", err)
+				}
+				if reason, _ := deadLetter.Message().GetHeader(HeaderDeadLetterReason); len(reason) != 1 {
+					t.Errorf("dead letter headers = %v", deadLetter.Message().Headers)
+				}
+			} else {
+				for range tt.expectedDeliveries {
+					waitFor(t, calls)
+				}
+			}
+			select {
+			case <-calls:
+				if !tt.expectDeadLetter {
+					t.Errorf("unexpected redelivery")
+				}
+			case <-time.After(20 * time.Millisecond):
+			}
+			if got := deliveries.Load(); got != tt.expectedDeliveries {
+				t.Errorf("deliveries = %d, want %d", got, tt.expectedDeliveries)
+			}
+		})
+	}
+}
+
+func Test_Adapter_ConcurrencyAndStop(t *testing.T) {
+	broker := NewMemoryBroker()
+	var running, maxRunning atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+	adapter, err := NewAdapter(Config{
+		Broker:      broker,
+		Concurrency: 3,
+		Bindings: Bindings{NewBindingBuilderUsingTopic("orders").WithHandlerFunc(func(ctx context.Context) error {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			started <- struct{}{}
+			<-release
+			return nil
+		})},
+	})
+	if err != nil {
+		t.Fatalf("NewAdapter() error = %v", err)
+	}
+	if err := adapter.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	for range 5 {
+		_ = broker.Publish(context.Background(), Message{Topic: "orders"})
+	}
+	for range 3 {
+		waitFor(t, started)
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- adapter.Stop(context.Background()) }()
+	select {
+	case <-stopped:
+		t.Fatal("Stop() should wait for the messages being handled")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	if err := waitFor(t, stopped); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+	if got := maxRunning.Load(); got != 3 {
+		t.Errorf("max running = %d, want 3", got)
+	}
+	if got := broker.Pending("orders", ""); got != 2 {
+		t.Errorf("pending = %d, want the 2 messages not received", got)
+	}
+}
@@ -0,0 +1,28 @@
+package msgadpt
+
+type (
+	// Condition identifies the messages handled by the binding
+	Condition struct {
+		// Topic is the topic, queue or subject subscribed
+		Topic string
+		// Group is the consumer group of the subscription, Config.Group is used if empty
+		Group string
+	}
+
+	Binding struct {
+		Condition
+		Handler
+
+		// Concurrency is the number of messages handled in parallel, Config.Concurrency is used if zero
+		Concurrency int
+		// Prefetch is the number of messages delivered and not settled yet, Config.Prefetch is used if zero
+		Prefetch int
+	}
+
+	// Bindings are the subscriptions of the messaging adapter, each one is consumed independently
+	Bindings []Binding
+)
+
+func IsBindingValid(binding Binding) bool {
+	return binding.Handler != nil && binding.Topic != ""
+}
@@ -0,0 +1,43 @@
+package msgadpt
+
+import (
+	tagbasedhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler/tagbased"
+)
+
+type (
+	HandlerBuildingStep interface {
+		// WithGroup sets the consumer group of the subscription
+		WithGroup(group string) HandlerBuildingStep
+		// WithConcurrency sets the number of messages handled in parallel and how many are prefetched
+		WithConcurrency(concurrency, prefetch int) HandlerBuildingStep
+		WithHandlerFunc(handler any) Binding
+	}
+
+	BaseBuilder struct {
+		Binding
+	}
+)
+
+func NewBindingBuilderUsingTopic(topic string) HandlerBuildingStep {
+	return &BaseBuilder{Binding: Binding{Condition: Condition{Topic: topic}}}
+}
+
+func (b *BaseBuilder) WithGroup(group string) HandlerBuildingStep {
+	b.Group = group
+	return b
+}
+
+func (b *BaseBuilder) WithConcurrency(concurrency, prefetch int) HandlerBuildingStep {
+	b.Concurrency = concurrency
+	b.Prefetch = prefetch
+	return b
+}
+
+func (b *BaseBuilder) WithHandlerFunc(handler any) Binding {
+	b.Handler = tagbasedhandler.NewBuilderForFunc[Message, *Result](handler).
+		WithInTagBasedFactory(createInParamSpecFactory()).
+		WithOutTagBasedFactory(createOutParamSpecFactory()).
+		WithOutErrorParamSpec(NewOutErrorParamSpec()).
+		Build()
+	return b.Binding
+}
@@ -0,0 +1,65 @@
+package msgadpt
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// HeaderDeadLetterReason is added to the dead letters with the error that made the message be dead lettered
+	HeaderDeadLetterReason = "x-dead-letter-reason"
+	// HeaderOriginalTopic is added to the dead letters with the topic the message was consumed from
+	HeaderOriginalTopic = "x-original-topic"
+)
+
+var (
+	// ErrSubscriptionClosed is returned by Subscription.Receive after the subscription is closed
+	ErrSubscriptionClosed = errors.New("subscription closed")
+
+	// ErrAlreadySettled is returned when a Delivery is acknowledged, rejected or dead lettered twice
+	ErrAlreadySettled = errors.New("delivery already settled")
+)
+
+type (
+	// Broker is the messaging system abstraction. This module provides the MemoryBroker, the brokers like Kafka, NATS
+	// and SQS are implemented by other modules.
+	Broker interface {
+		// Publish sends the message to its topic
+		Publish(ctx context.Context, message Message) error
+
+		// Subscribe creates a subscription to the topic, the subscriptions with the same group share the messages
+		// and each group receives all the messages published
+		Subscribe(ctx context.Context, topic string, options SubscribeOptions) (Subscription, error)
+	}
+
+	SubscribeOptions struct {
+		// Group is the consumer group, durable subscription or queue group name
+		Group string
+		// Prefetch is the maximum number of messages delivered to the subscription and not settled yet, zero means 1
+		Prefetch int
+	}
+
+	Subscription interface {
+		// Receive blocks until a message is delivered, the context is done or the subscription is closed, in that case
+		// it returns ErrSubscriptionClosed
+		Receive(ctx context.Context) (Delivery, error)
+
+		// Close stops the deliveries, the messages delivered and not settled are redelivered by the broker
+		Close() error
+	}
+
+	// Delivery is a message delivered by a Subscription, it must be settled once by Ack, Nack or DeadLetter
+	Delivery interface {
+		Message() Message
+
+		// Ack removes the message from the subscription
+		Ack(ctx context.Context) error
+
+		// Nack makes the message be redelivered after the delay
+		Nack(ctx context.Context, delay time.Duration) error
+
+		// DeadLetter removes the message from the subscription and moves it to the dead letter destination
+		DeadLetter(ctx context.Context, cause error) error
+	}
+)
@@ -0,0 +1,231 @@
+package msgadpt
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDeadLetterSuffix is appended to the topic name to get the dead letter topic of the MemoryBroker
+const DefaultDeadLetterSuffix = ".dlq"
+
+type (
+	// MemoryBroker is a Broker for tests and single instance applications. The messages published to a topic without
+	// subscriptions are discarded, and the dead letters are published to the topic with the DefaultDeadLetterSuffix.
+	MemoryBroker struct {
+		mutex    sync.Mutex
+		queues   map[string]map[string]*memoryQueue // by topic and group
+		sequence atomic.Int64
+	}
+
+	// memoryQueue holds the messages of a group, its subscriptions compete for them
+	memoryQueue struct {
+		mutex   sync.Mutex
+		pending []Message
+		// changed is closed and replaced when a message is added or settled, to wake up the receivers
+		changed chan struct{}
+	}
+
+	memorySubscription struct {
+		broker   *MemoryBroker
+		queue    *memoryQueue
+		prefetch int
+		closed   bool                         // guarded by queue.mutex
+		inFlight map[*memoryDelivery]struct{} // guarded by queue.mutex
+	}
+
+	memoryDelivery struct {
+		subscription *memorySubscription
+		message      Message
+		settled      atomic.Bool
+	}
+)
+
+// NewMemoryBroker creates an in-memory Broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{queues: map[string]map[string]*memoryQueue{}}
+}
+
+func (m *MemoryBroker) Publish(_ context.Context, message Message) error {
+	if message.Topic == "" {
+		return fmt.Errorf("msgadpt.MemoryBroker.Publish: topic is empty")
+	}
+	message = message.clone()
+	if message.ID == "" {
+		message.ID = strconv.FormatInt(m.sequence.Add(1), 10)
+	}
+	if message.PublishedAt.IsZero() {
+		message.PublishedAt = time.Now()
+	}
+	message.DeliveryCount = 0
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, queue := range m.queues[message.Topic] {
+		queue.push(message.clone())
+	}
+	return nil
+}
+
+func (m *MemoryBroker) Subscribe(_ context.Context, topic string, options SubscribeOptions) (Subscription, error) {
+	if topic == "" {
+		return nil, fmt.Errorf("msgadpt.MemoryBroker.Subscribe: topic is empty")
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	groups, found := m.queues[topic]
+	if !found {
+		groups = map[string]*memoryQueue{}
+		m.queues[topic] = groups
+	}
+	queue, found := groups[options.Group]
+	if !found {
+		queue = &memoryQueue{changed: make(chan struct{})}
+		groups[options.Group] = queue
+	}
+	return &memorySubscription{
+		broker:   m,
+		queue:    queue,
+		prefetch: max(options.Prefetch, 1),
+		inFlight: map[*memoryDelivery]struct{}{},
+	}, nil
+}
+
+// Pending returns the number of messages of the topic group waiting to be delivered
+func (m *MemoryBroker) Pending(topic, group string) int {
+	m.mutex.Lock()
+	queue := m.queues[topic][group]
+	m.mutex.Unlock()
+	if queue == nil {
+		return 0
+	}
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return len(queue.pending)
+}
+
+func (q *memoryQueue) push(message Message) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.pending = append(q.pending, message)
+	q.notify()
+}
+
+// notify wakes up the receivers, the caller must hold the mutex
+func (q *memoryQueue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+func (s *memorySubscription) Receive(ctx context.Context) (Delivery, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		s.queue.mutex.Lock()
+		if s.closed {
+			s.queue.mutex.Unlock()
+			return nil, ErrSubscriptionClosed
+		}
+		if len(s.queue.pending) > 0 && len(s.inFlight) < s.prefetch {
+			message := s.queue.pending[0]
+			s.queue.pending = s.queue.pending[1:]
+			message.DeliveryCount++
+			delivery := &memoryDelivery{subscription: s, message: message}
+			s.inFlight[delivery] = struct{}{}
+			s.queue.mutex.Unlock()
+			return delivery, nil
+		}
+		changed := s.queue.changed
+		s.queue.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Close requeues the messages delivered and not settled
+func (s *memorySubscription) Close() error {
+	s.queue.mutex.Lock()
+	defer s.queue.mutex.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	var requeued []Message
+	for delivery := range s.inFlight {
+		if delivery.settled.CompareAndSwap(false, true) {
+			requeued = append(requeued, delivery.message)
+		}
+	}
+	s.inFlight = nil
+	s.queue.pending = append(requeued, s.queue.pending...)
+	s.queue.notify()
+	return nil
+}
+
+// settle removes the delivery from the in-flight ones, the message is requeued after the delay if requeue is true
+func (s *memorySubscription) settle(delivery *memoryDelivery, requeue bool, delay time.Duration) {
+	s.queue.mutex.Lock()
+	defer s.queue.mutex.Unlock()
+	delete(s.inFlight, delivery)
+	s.queue.notify()
+	if !requeue {
+		return
+	}
+	if delay <= 0 {
+		s.queue.pending = append(s.queue.pending, delivery.message)
+		return
+	}
+	time.AfterFunc(delay, func() { s.queue.push(delivery.message) })
+}
+
+func (d *memoryDelivery) Message() Message {
+	return d.message
+}
+
+func (d *memoryDelivery) Ack(_ context.Context) error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	d.subscription.settle(d, false, 0)
+	return nil
+}
+
+func (d *memoryDelivery) Nack(_ context.Context, delay time.Duration) error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	d.subscription.settle(d, true, delay)
+	return nil
+}
+
+func (d *memoryDelivery) DeadLetter(ctx context.Context, cause error) error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	d.subscription.settle(d, false, 0)
+
+	deadLetter := d.message.clone()
+	deadLetter.Topic = d.message.Topic + DefaultDeadLetterSuffix
+	if deadLetter.Headers == nil {
+		deadLetter.Headers = map[string][]string{}
+	}
+	deadLetter.Headers[HeaderOriginalTopic] = []string{d.message.Topic}
+	if cause != nil {
+		deadLetter.Headers[HeaderDeadLetterReason] = []string{cause.Error()}
+	}
+	return d.subscription.broker.Publish(ctx, deadLetter)
+}
+
+var (
+	_ Broker       = (*MemoryBroker)(nil)
+	_ Subscription = (*memorySubscription)(nil)
+	_ Delivery     = (*memoryDelivery)(nil)
+)
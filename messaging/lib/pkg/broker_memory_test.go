@@ -0,0 +1,114 @@
+package msgadpt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func receiveWithin(t *testing.T, subscription Subscription, timeout time.Duration) (Delivery, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return subscription.Receive(ctx)
+}
+
+func Test_MemoryBroker_Groups(t *testing.T) {
+	ctx := context.Background()
+	broker := NewMemoryBroker()
+	billing1, _ := broker.Subscribe(ctx, "orders", SubscribeOptions{Group: "billing"})
+	billing2, _ := broker.Subscribe(ctx, "orders", SubscribeOptions{Group: "billing"})
+	shipping, _ := broker.Subscribe(ctx, "orders", SubscribeOptions{Group: "shipping"})
+
+	for _, key := range []string{"1", "2"} {
+		if err := broker.Publish(ctx, Message{Topic: "orders", Key: key}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	first, err := receiveWithin(t, billing1, time.Second)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	second, err := receiveWithin(t, billing2, time.Second)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if first.Message().Key != "1" || second.Message().Key != "2" || first.Message().ID == "" {
+		t.Errorf("the group subscriptions should share the messages, got %+v and %+v", first.Message(), second.Message())
+	}
+	if got := broker.Pending("orders", "shipping"); got != 2 {
+		t.Errorf("shipping pending = %d, want 2", got)
+	}
+	if delivery, err := receiveWithin(t, shipping, time.Second); err != nil || delivery.Message().DeliveryCount != 1 {
+		t.Errorf("shipping Receive() = %v, %v", delivery, err)
+	}
+}
+
+func Test_MemoryBroker_PrefetchAndSettlement(t *testing.T) {
+	ctx := context.Background()
+	broker := NewMemoryBroker()
+	subscription, _ := broker.Subscribe(ctx, "orders", SubscribeOptions{Prefetch: 1})
+	_ = broker.Publish(ctx, Message{Topic: "orders", Key: "1"})
+	_ = broker.Publish(ctx, Message{Topic: "orders", Key: "2"})
+
+	delivery, err := receiveWithin(t, subscription, time.Second)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if _, err := receiveWithin(t, subscription, 20*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Receive() beyond the prefetch error = %v, want deadline exceeded", err)
+	}
+
+	if err := delivery.Nack(ctx, 0); err != nil {
+		t.Fatalf("Nack() error = %v", err)
+	}
+	if err := delivery.Ack(ctx); !errors.Is(err, ErrAlreadySettled) {
+		t.Errorf("Ack() after Nack() error = %v, want ErrAlreadySettled", err)
+	}
+
+	next, err := receiveWithin(t, subscription, time.Second)
+	if err != nil || next.Message().Key != "2" {
+		t.Fatalf("Receive() = %v, %v", next, err)
+	}
+	_ = next.Ack(ctx)
+	redelivered, err := receiveWithin(t, subscription, time.Second)
+	if err != nil || redelivered.Message().Key != "1" || redelivered.Message().DeliveryCount != 2 {
+		t.Fatalf("redelivered = %+v, %v", redelivered, err)
+	}
+}
+
+func Test_MemoryBroker_DeadLetterAndClose(t *testing.T) {
+	ctx := context.Background()
+	broker := NewMemoryBroker()
+	subscription, _ := broker.Subscribe(ctx, "orders", SubscribeOptions{Prefetch: 2})
+	deadLetters, _ := broker.Subscribe(ctx, "orders"+DefaultDeadLetterSuffix, SubscribeOptions{})
+	_ = broker.Publish(ctx, Message{Topic: "orders", Key: "1"})
+	_ = broker.Publish(ctx, Message{Topic: "orders", Key: "2"})
+
+	delivery, _ := receiveWithin(t, subscription, time.Second)
+	if err := delivery.DeadLetter(ctx, errors.New("invalid order")); err != nil {
+		t.Fatalf("DeadLetter() error = %v", err)
+	}
+	deadLetter, err := receiveWithin(t, deadLetters, time.Second)
+	if err != nil {
+		t.Fatalf("Receive() dead letter error = %v", err)
+	}
+	if reason, _ := deadLetter.Message().GetHeader(HeaderDeadLetterReason); len(reason) != 1 || reason[0] != "invalid order" {
+		t.Errorf("dead letter headers = %v", deadLetter.Message().Headers)
+	}
+
+	if _, err := receiveWithin(t, subscription, time.Second); err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if err := subscription.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := receiveWithin(t, subscription, time.Second); !errors.Is(err, ErrSubscriptionClosed) {
+		t.Errorf("Receive() after Close() error = %v", err)
+	}
+	if got := broker.Pending("orders", ""); got != 1 {
+		t.Errorf("the unsettled message should be requeued, pending = %d", got)
+	}
+}
@@ -0,0 +1,89 @@
+package msgadpt
+
+import (
+	"context"
+	"errors"
+
+	sdkconverter "github.com/smart-libs/go-adapter/sdk/lib/pkg/converter"
+	sdkerror "github.com/smart-libs/go-adapter/sdk/lib/pkg/error"
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	converterdefault "github.com/smart-libs/go-crosscutting/converter/lib/pkg/default"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+var (
+	// ConverterRegistry it is the default converters registry for the messaging adapter
+	ConverterRegistry = converterdefault.NewRegistry()
+
+	// Converters is the list of converter.Converters used by the messaging adapter, it tries first the messaging
+	// adapter conversions and then the default converter.Converters
+	Converters = converter.NewConvertersList(
+		converterdefault.NewConverters(ConverterRegistry),
+		sdkconverter.JSONConverters{Name: "payload"},
+		converterdefault.Converters,
+	)
+
+	errorActions sdkerror.Registry[Action]
+)
+
+func init() {
+	converter.AddHandler[error, Action](ConverterRegistry, errorToAction)
+	converter.AddHandler[[]string, string](ConverterRegistry, firstStringPtrFromStringArray)
+}
+
+// RegisterErrorAction makes the messages whose handler returns an error that satisfies the condition be settled with
+// the given action. The registered conditions are evaluated in the registration order before the default ones.
+func RegisterErrorAction(condition func(err error) bool, action Action) {
+	errorActions.Register(condition, action)
+}
+
+// firstStringPtrFromStringArray returns the first header value, most of the headers have only one value
+func firstStringPtrFromStringArray(values []string, first *string) error {
+	if len(values) > 0 {
+		*first = values[0]
+	} else {
+		*first = ""
+	}
+	return nil
+}
+
+func errorToAction(err error, to *Action) error {
+	if err == nil {
+		*to = ActionAck
+		return nil
+	}
+	var retryAfter retryAfterError
+	if errors.As(err, &retryAfter) {
+		*to = ActionRetry
+		return nil
+	}
+
+	callbacks := errorActions.Callbacks(func(action Action) { *to = action }, 4)
+
+	callbacks = append(callbacks,
+		serror.CallbackCondition{
+			Condition: func(err error) bool { return errors.Is(err, ErrDeadLetter) },
+			Callback:  func(err error) { *to = ActionDeadLetter },
+		},
+		serror.CallbackCondition{
+			Condition: func(err error) bool {
+				return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+			},
+			Callback: func(err error) { *to = ActionRetry },
+		},
+		// the message is invalid, the redeliveries would fail the same way
+		serror.CallbackCondition{
+			Condition: serror.IsIllegalArgumentError,
+			Callback:  func(err error) { *to = ActionDeadLetter },
+		},
+		// the message was already processed
+		serror.CallbackCondition{
+			Condition: serror.IsDuplicateError,
+			Callback:  func(err error) { *to = ActionAck },
+		},
+	)
+	if !serror.IdentifyRootCause(err, func(error) { *to = ActionRetry }, callbacks...) {
+		*to = ActionRetry
+	}
+	return nil
+}
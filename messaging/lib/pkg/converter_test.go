@@ -0,0 +1,54 @@
+package msgadpt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+var errTestPoison = errors.New("poison message")
+
+func init() {
+	RegisterErrorAction(func(err error) bool { return errors.Is(err, errTestPoison) }, ActionDeadLetter)
+}
+
+func Test_errorToAction(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected Action
+	}{
+		{name: "nil", err: nil, expected: ActionAck},
+		{name: "unknown", err: errors.New("boom"), expected: ActionRetry},
+		{name: "deadline", err: context.DeadlineExceeded, expected: ActionRetry},
+		{name: "retry after wins", err: RetryAfter(ErrDeadLetter, time.Second), expected: ActionRetry},
+		{name: "dead letter", err: fmt.Errorf("wrapped: %w", ErrDeadLetter), expected: ActionDeadLetter},
+		{name: "illegal argument", err: serror.IllegalArgumentValue("p", 1), expected: ActionDeadLetter},
+		{name: "duplicate", err: serror.DuplicateError.New("exists"), expected: ActionAck},
+		{name: "registered", err: fmt.Errorf("wrapped: %w", errTestPoison), expected: ActionDeadLetter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Action
+			if err := errorToAction(tt.err, &got); err != nil {
+				t.Fatalf("errorToAction() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("errorToAction() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_ExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 5*time.Second)
+	for deliveryCount, expected := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := backoff(deliveryCount); got != expected {
+			t.Errorf("backoff(%d) = %s, want %s", deliveryCount, got, expected)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package msgadpt
+
+import (
+	"strings"
+	"time"
+)
+
+type (
+	// Message is the input of the messaging handlers, it is the message delivered by the Broker
+	Message struct {
+		// ID identifies the message in the broker
+		ID string
+		// Topic is the topic, queue or subject the message was published to
+		Topic string
+		// Key is the partitioning or ordering key, like the Kafka record key or the SQS message group ID
+		Key string
+		// Payload is the message body
+		Payload []byte
+		// Headers are the message headers or attributes
+		Headers map[string][]string
+		// DeliveryCount is 1 on the first delivery and it is incremented on each redelivery
+		DeliveryCount int
+		// PublishedAt is when the message was published, it is zero if the broker does not provide it
+		PublishedAt time.Time
+	}
+)
+
+// GetHeader returns the values of the header, the name is matched exactly and then ignoring the case
+func (m Message) GetHeader(name string) ([]string, bool) {
+	if values, found := m.Headers[name]; found {
+		return values, true
+	}
+	for headerName, values := range m.Headers {
+		if strings.EqualFold(headerName, name) {
+			return values, true
+		}
+	}
+	return nil, false
+}
+
+// clone copies the headers and the payload, so the message published can be changed by the caller
+func (m Message) clone() Message {
+	clone := m
+	clone.Payload = append([]byte(nil), m.Payload...)
+	if m.Headers != nil {
+		clone.Headers = make(map[string][]string, len(m.Headers))
+		for name, values := range m.Headers {
+			clone.Headers[name] = append([]string(nil), values...)
+		}
+	}
+	return clone
+}
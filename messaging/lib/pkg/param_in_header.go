@@ -0,0 +1,18 @@
+package msgadpt
+
+const (
+	// TagHeader gets the values of the message header or attribute in the tag value
+	TagHeader = "msgheader"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().AddOption2(TagHeader, getHeaderInParamValue)
+}
+
+func getHeaderInParamValue(input Message, name string) (any, error) {
+	values, found := input.GetHeader(name)
+	if !found {
+		return nil, nil
+	}
+	return values, nil
+}
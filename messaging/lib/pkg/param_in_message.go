@@ -0,0 +1,28 @@
+package msgadpt
+
+const (
+	// TagTopic gets the topic the message was published to
+	TagTopic = "topic"
+	// TagKey gets the message key
+	TagKey = "key"
+	// TagMessageID gets the message ID
+	TagMessageID = "msgid"
+	// TagDeliveryCount gets the number of times the message was delivered, including the current one
+	TagDeliveryCount = "deliverycount"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().
+		AddOption1(TagTopic, "", func(input Message) (any, error) { return emptyAsNil(input.Topic), nil }).
+		AddOption1(TagKey, "", func(input Message) (any, error) { return emptyAsNil(input.Key), nil }).
+		AddOption1(TagMessageID, "", func(input Message) (any, error) { return emptyAsNil(input.ID), nil }).
+		AddOption1(TagDeliveryCount, "", func(input Message) (any, error) { return input.DeliveryCount, nil })
+}
+
+// emptyAsNil makes the empty values be absent, so the default and mandatory options apply
+func emptyAsNil(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,17 @@
+package msgadpt
+
+const (
+	// TagPayload gets the message payload as []byte, use the mime-type:"application/json" tag to unmarshal it
+	TagPayload = "payload"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().AddOption1(TagPayload, "", getPayloadInParamValue)
+}
+
+func getPayloadInParamValue(input Message) (any, error) {
+	if input.Payload == nil {
+		return nil, nil
+	}
+	return input.Payload, nil
+}
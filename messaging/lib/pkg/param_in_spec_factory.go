@@ -0,0 +1,21 @@
+package msgadpt
+
+import (
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+)
+
+var (
+	inParamSpecFactoryRegistry tagbased.InputParamSpecFactoryRegistry[Message]
+)
+
+func getInputParamSpecFactoryRegistry() tagbased.InputParamSpecFactoryRegistry[Message] {
+	if inParamSpecFactoryRegistry == nil {
+		inParamSpecFactoryRegistry = tagbased.NewInputParamSpecFactoryRegistry[Message](Converters)
+	}
+
+	return inParamSpecFactoryRegistry
+}
+
+func createInParamSpecFactory() tagbased.InputParamSpecFactory[Message] {
+	return tagbased.NewsInputParamSpecFactory(getInputParamSpecFactoryRegistry())
+}
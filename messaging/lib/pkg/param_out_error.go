@@ -0,0 +1,41 @@
+package msgadpt
+
+import (
+	"errors"
+
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+	"github.com/smart-libs/go-crosscutting/assertions/lib/pkg/check"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	OutErrorParamSpec struct{}
+)
+
+func (o OutErrorParamSpec) Name() string               { return "error" }
+func (o OutErrorParamSpec) Options() []sdkparam.Option { return nil }
+
+// SetValue sets the Result action mapped from the error, see RegisterErrorAction
+func (o OutErrorParamSpec) SetValue(output *Result, value any) error {
+	if check.IsNil(value) {
+		return nil // no error
+	}
+	if output == nil {
+		return serror.CmpError.New("msgadpt.OutErrorParamSpec.SetValue: output is nil")
+	}
+	if err, ok := value.(error); ok {
+		output.Err = err
+		if convErr := errorToAction(err, &output.Action); convErr != nil {
+			return convErr
+		}
+		var retryAfter retryAfterError
+		if errors.As(err, &retryAfter) {
+			output.RetryDelay = retryAfter.delay
+		}
+	}
+	return nil
+}
+
+func NewOutErrorParamSpec() sdkparam.OutputParamSpec[*Result] {
+	return OutErrorParamSpec{}
+}
@@ -0,0 +1,21 @@
+package msgadpt
+
+import (
+	"github.com/smart-libs/go-adapter/sdk/lib/pkg/param/tagbased"
+)
+
+var (
+	outParamSpecFactoryRegistry tagbased.OutputParamSpecFactoryRegistry[*Result]
+)
+
+func getOutParamSpecFactoryRegistry() tagbased.OutputParamSpecFactoryRegistry[*Result] {
+	if outParamSpecFactoryRegistry == nil {
+		outParamSpecFactoryRegistry = tagbased.NewOutputParamSpecFactoryRegistry[*Result](Converters)
+	}
+
+	return outParamSpecFactoryRegistry
+}
+
+func createOutParamSpecFactory() tagbased.OutputParamSpecFactory[*Result] {
+	return tagbased.NewOutputParamSpecFactory(getOutParamSpecFactoryRegistry())
+}
@@ -0,0 +1,72 @@
+package msgadpt
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// ActionAck acknowledges the message, it is the result of the handlers that succeed
+	ActionAck Action = iota
+	// ActionRetry makes the message be redelivered after a delay, up to Config.MaxDeliveries
+	ActionRetry
+	// ActionDeadLetter moves the message to the dead letter destination
+	ActionDeadLetter
+)
+
+var (
+	// ErrDeadLetter can be wrapped by the handler errors to make the message be dead lettered without retries
+	ErrDeadLetter = errors.New("dead letter")
+)
+
+type (
+	// Action is how the adapter settles the message after the handler returns
+	Action int
+
+	// Result is the output of the messaging handlers, it is set from the handler error by OutErrorParamSpec
+	Result struct {
+		Action Action
+		// RetryDelay is the delay before the redelivery, zero means the Config.Backoff delay
+		RetryDelay time.Duration
+		// Err is the handler error
+		Err error
+	}
+
+	retryAfterError struct {
+		error
+		delay time.Duration
+	}
+)
+
+func (a Action) String() string {
+	switch a {
+	case ActionAck:
+		return "ack"
+	case ActionRetry:
+		return "retry"
+	case ActionDeadLetter:
+		return "dead-letter"
+	default:
+		return "unknown"
+	}
+}
+
+// RetryAfter wraps the error to make the message be redelivered after the delay instead of the Config.Backoff one
+func RetryAfter(err error, delay time.Duration) error {
+	return retryAfterError{error: err, delay: delay}
+}
+
+func (r retryAfterError) Unwrap() error {
+	return r.error
+}
+
+// ExponentialBackoff returns a Config.Backoff that doubles the initial delay on each delivery up to the maximum
+func ExponentialBackoff(initial, maximum time.Duration) func(deliveryCount int) time.Duration {
+	return func(deliveryCount int) time.Duration {
+		delay := initial
+		for i := 1; i < deliveryCount && delay < maximum; i++ {
+			delay *= 2
+		}
+		return min(delay, maximum)
+	}
+}
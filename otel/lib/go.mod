@@ -0,0 +1,29 @@
+module github.com/smart-libs/go-adapter/otel/lib
+
+go 1.25
+
+require (
+	github.com/smart-libs/go-adapter/http/lib v0.0.3
+	github.com/smart-libs/go-adapter/sdk/lib v0.0.1
+	go.opentelemetry.io/otel v1.44.0
+	go.opentelemetry.io/otel/metric v1.44.0
+	go.opentelemetry.io/otel/sdk v1.44.0
+	go.opentelemetry.io/otel/sdk/metric v1.44.0
+	go.opentelemetry.io/otel/trace v1.44.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/joomcode/errorx v1.2.0 // indirect
+	github.com/smart-libs/go-adapter/interfaces v0.0.1 // indirect
+	github.com/smart-libs/go-crosscutting/assertions/lib v0.0.6 // indirect
+	github.com/smart-libs/go-crosscutting/converter/lib v0.0.2 // indirect
+	github.com/smart-libs/go-crosscutting/serror/lib v0.0.2 // indirect
+	github.com/smart-libs/go-crosscutting/types/lib v0.0.1 // indirect
+	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
+	golang.org/x/sys v0.45.0 // indirect
+)
+
@@ -0,0 +1,103 @@
+package oteladpt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// HTTPMetricPrefix is the prefix of the HTTP server metrics defined by the semantic conventions
+	HTTPMetricPrefix = "http.server"
+)
+
+type (
+	// HTTPConfig configures the middleware created by NewHTTPMiddleware
+	HTTPConfig struct {
+		TracerProvider trace.TracerProvider
+		MeterProvider  metric.MeterProvider
+		Propagator     propagation.TextMapPropagator
+	}
+
+	// headerCarrier reads the propagation headers from the httpadpt.Request
+	headerCarrier struct {
+		input httpadpt.Request
+	}
+)
+
+// NewHTTPMiddleware creates a middleware that traces and measures the HTTP requests following the semantic
+// conventions. The span is named by the method and the route template, like "GET /orders/{id}", and its parent is
+// read from the W3C traceparent header. Add it to httpadpt.Config.Middlewares before the other middlewares.
+func NewHTTPMiddleware(config HTTPConfig) (httpadpt.Middleware, error) {
+	return NewDecorator(Config[httpadpt.Request, *httpadpt.Response]{
+		TracerProvider:   config.TracerProvider,
+		MeterProvider:    config.MeterProvider,
+		Propagator:       config.Propagator,
+		MetricPrefix:     HTTPMetricPrefix,
+		SpanKind:         trace.SpanKindServer,
+		SpanName:         httpSpanName,
+		Carrier:          func(input httpadpt.Request) propagation.TextMapCarrier { return headerCarrier{input: input} },
+		Attributes:       httpAttributes,
+		ResultAttributes: httpResultAttributes,
+	})
+}
+
+func httpSpanName(_ context.Context, input httpadpt.Request) string {
+	method := httpadpt.GetMethod(input)
+	if route, found := httpadpt.RouteFromRequest(input); found {
+		return method + " " + route
+	}
+	return method
+}
+
+func httpAttributes(_ context.Context, input httpadpt.Request) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(httpadpt.GetMethod(input))}
+	if route, found := httpadpt.RouteFromRequest(input); found {
+		attributes = append(attributes, semconv.HTTPRoute(route))
+	}
+	return attributes
+}
+
+func httpResultAttributes(output *httpadpt.Response, err error) ([]attribute.KeyValue, bool) {
+	statusCode := http.StatusOK
+	if err != nil || output == nil {
+		statusCode = http.StatusInternalServerError
+	} else if output.StatusCode != nil {
+		statusCode = *output.StatusCode
+	}
+
+	attributes := []attribute.KeyValue{semconv.HTTPResponseStatusCode(statusCode)}
+	switch {
+	case err != nil:
+		attributes = append(attributes, semconv.ErrorTypeKey.String(fmt.Sprintf("%T", err)))
+	case statusCode >= http.StatusInternalServerError:
+		attributes = append(attributes, semconv.ErrorTypeKey.String(strconv.Itoa(statusCode)))
+	}
+	// the server spans fail only with 5xx, the 4xx are client errors
+	return attributes, err != nil || statusCode >= http.StatusInternalServerError
+}
+
+func (h headerCarrier) Get(key string) string {
+	if h.input == nil || h.input.Header() == nil {
+		return ""
+	}
+	values, found := h.input.Header().GetValue(http.CanonicalHeaderKey(key))
+	if !found || len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set is not used, the carrier is only read
+func (h headerCarrier) Set(string, string) {}
+
+// Keys is not used by the W3C propagators to extract the context
+func (h headerCarrier) Keys() []string { return nil }
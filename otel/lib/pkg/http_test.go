@@ -0,0 +1,106 @@
+package oteladpt
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	testRequest struct {
+		method string
+		header http.Header
+	}
+
+	testHeader http.Header
+)
+
+func (r testRequest) Query() httpadpt.QueryParams          { return nil }
+func (r testRequest) Header() httpadpt.HeaderParams        { return testHeader(r.header) }
+func (r testRequest) Path() httpadpt.PathParams            { return nil }
+func (r testRequest) Cookie() httpadpt.CookieParams        { return nil }
+func (r testRequest) Form() (httpadpt.FormParams, error)   { return nil, nil }
+func (r testRequest) URL() *url.URL                        { return &url.URL{Path: "/orders/1"} }
+func (r testRequest) Method() string                       { return r.method }
+func (r testRequest) RemoteAddr() string                   { return "" }
+func (h testHeader) GetValue(name string) ([]string, bool) { v, ok := h[name]; return v, ok }
+
+func TestNewHTTPMiddleware(t *testing.T) {
+	telemetry := newTestTelemetry()
+	middleware, err := NewHTTPMiddleware(HTTPConfig{
+		TracerProvider: telemetry.tracerProvider,
+		MeterProvider:  telemetry.meterProvider,
+		Propagator:     propagation.TraceContext{},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPMiddleware() error = %v", err)
+	}
+
+	path := "/orders/{id}"
+	statusCode := http.StatusServiceUnavailable
+	var handlerTraceID trace.TraceID
+	binding := httpadpt.Binding{
+		Condition: httpadpt.Condition{Path: &path, Methods: []string{http.MethodGet, http.MethodPost}},
+		Handler: httpadpt.MakeHandler(func(ctx context.Context, input httpadpt.Request, output *httpadpt.Response) error {
+			handlerTraceID = trace.SpanFromContext(ctx).SpanContext().TraceID()
+			if input.Method() == http.MethodPost {
+				output.StatusCode = &statusCode
+			}
+			return nil
+		}),
+	}
+	handler := httpadpt.WrapBindingHandler(binding, httpadpt.Config{Middlewares: httpadpt.Middlewares{middleware}})
+
+	header := http.Header{"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}}
+	if err := handler.Invoke(context.Background(), testRequest{method: http.MethodGet, header: header}, &httpadpt.Response{}); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if handlerTraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the one from traceparent", handlerTraceID)
+	}
+	_ = handler.Invoke(context.Background(), testRequest{method: http.MethodPost}, &httpadpt.Response{})
+
+	spans := telemetry.spans.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+	get, post := spans[0], spans[1]
+	if get.Name != "GET /orders/{id}" || get.SpanKind != trace.SpanKindServer || !get.Parent.IsRemote() {
+		t.Errorf("GET span = %s %v remote parent %v", get.Name, get.SpanKind, get.Parent.IsRemote())
+	}
+	if !hasAttribute(get.Attributes, semconv.HTTPRoute(path)) ||
+		!hasAttribute(get.Attributes, semconv.HTTPRequestMethodKey.String(http.MethodGet)) ||
+		!hasAttribute(get.Attributes, semconv.HTTPResponseStatusCode(http.StatusOK)) {
+		t.Errorf("GET span attributes = %v", get.Attributes)
+	}
+	if get.Status.Code != codes.Unset {
+		t.Errorf("GET span status = %v", get.Status)
+	}
+	if post.Status.Code != codes.Error || !hasAttribute(post.Attributes, semconv.ErrorTypeKey.String("503")) {
+		t.Errorf("POST span status = %v, attributes = %v", post.Status, post.Attributes)
+	}
+
+	histogram, ok := telemetry.metrics(t)["http.server.request.duration"].(metricdata.Histogram[float64])
+	if !ok || len(histogram.DataPoints) != 2 {
+		t.Fatalf("http.server.request.duration = %#v, want one data point per method", histogram)
+	}
+}
+
+func Test_httpResultAttributes(t *testing.T) {
+	notFound := http.StatusNotFound
+	if _, failed := httpResultAttributes(&httpadpt.Response{StatusCode: &notFound}, nil); failed {
+		t.Error("404 failed = true, want the client errors not to fail the server span")
+	}
+	attributes, failed := httpResultAttributes(&httpadpt.Response{}, context.Canceled)
+	if !failed || !hasAttribute(attributes, semconv.HTTPResponseStatusCode(http.StatusInternalServerError)) {
+		t.Errorf("error attributes = %v, failed = %v", attributes, failed)
+	}
+}
@@ -0,0 +1,185 @@
+package oteladpt
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdkhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// InstrumentationName is the name of the tracer and of the meter
+	InstrumentationName = "github.com/smart-libs/go-adapter/otel/lib"
+)
+
+type (
+	// Config configures how the invocations of a sdkhandler.Handler are traced and measured
+	Config[Input any, Output any] struct {
+		// TracerProvider is otel.GetTracerProvider() if nil
+		TracerProvider trace.TracerProvider
+
+		// MeterProvider is otel.GetMeterProvider() if nil
+		MeterProvider metric.MeterProvider
+
+		// Propagator is otel.GetTextMapPropagator() if nil, it extracts the parent span from the Carrier
+		Propagator propagation.TextMapPropagator
+
+		// MetricPrefix names the metrics, like "http.server" creates "http.server.request.duration" and
+		// "http.server.active_requests"
+		MetricPrefix string
+
+		// SpanKind is trace.SpanKindServer if not set
+		SpanKind trace.SpanKind
+
+		// SpanName returns the name of the span of the invocation, it is MetricPrefix if nil
+		SpanName func(ctx context.Context, input Input) string
+
+		// Carrier returns where the parent span context is read from, like the HTTP headers, it can be nil
+		Carrier func(input Input) propagation.TextMapCarrier
+
+		// Attributes returns the attributes known before the invocation, they are added to the span and the metrics
+		Attributes func(ctx context.Context, input Input) []attribute.KeyValue
+
+		// ResultAttributes returns the attributes of the invocation result and if it failed, like the HTTP status
+		// code. If nil, the invocation failed if the error is not nil.
+		ResultAttributes func(output Output, err error) (attributes []attribute.KeyValue, failed bool)
+	}
+
+	instrumentedHandler[Input any, Output any] struct {
+		config    Config[Input, Output]
+		tracer    trace.Tracer
+		duration  metric.Float64Histogram
+		active    metric.Int64UpDownCounter
+		decorated sdkhandler.Handler[Input, Output]
+	}
+)
+
+// NewDecorator returns a function that decorates a sdkhandler.Handler to create a span per invocation, to record
+// its duration and the number of invocations in progress. It can decorate the handlers of any adapter, like
+// the HTTP middlewares or the CLI bindings.
+func NewDecorator[Input any, Output any](config Config[Input, Output]) (
+	func(sdkhandler.Handler[Input, Output]) sdkhandler.Handler[Input, Output], error) {
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+	if config.MeterProvider == nil {
+		config.MeterProvider = otel.GetMeterProvider()
+	}
+	if config.Propagator == nil {
+		config.Propagator = otel.GetTextMapPropagator()
+	}
+	if config.SpanKind == trace.SpanKindUnspecified {
+		config.SpanKind = trace.SpanKindServer
+	}
+
+	meter := config.MeterProvider.Meter(InstrumentationName)
+	duration, err := meter.Float64Histogram(config.MetricPrefix+".request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of the invocations."),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10))
+	if err != nil {
+		return nil, err
+	}
+	active, err := meter.Int64UpDownCounter(config.MetricPrefix+".active_requests",
+		metric.WithUnit("{request}"),
+		metric.WithDescription("Number of invocations in progress."))
+	if err != nil {
+		return nil, err
+	}
+
+	tracer := config.TracerProvider.Tracer(InstrumentationName)
+	return func(next sdkhandler.Handler[Input, Output]) sdkhandler.Handler[Input, Output] {
+		return instrumentedHandler[Input, Output]{
+			config:    config,
+			tracer:    tracer,
+			duration:  duration,
+			active:    active,
+			decorated: next,
+		}
+	}, nil
+}
+
+// Instrument decorates the handler, see NewDecorator
+func Instrument[Input any, Output any](handler sdkhandler.Handler[Input, Output], config Config[Input, Output]) (
+	sdkhandler.Handler[Input, Output], error) {
+	decorator, err := NewDecorator(config)
+	if err != nil {
+		return nil, err
+	}
+	return decorator(handler), nil
+}
+
+func (h instrumentedHandler[Input, Output]) Invoke(ctx context.Context, input Input, output Output) (err error) {
+	start := time.Now()
+	if h.config.Carrier != nil {
+		if carrier := h.config.Carrier(input); carrier != nil {
+			ctx = h.config.Propagator.Extract(ctx, carrier)
+		}
+	}
+
+	var attributes []attribute.KeyValue
+	if h.config.Attributes != nil {
+		attributes = h.config.Attributes(ctx, input)
+	}
+	spanName := h.config.MetricPrefix
+	if h.config.SpanName != nil {
+		spanName = h.config.SpanName(ctx, input)
+	}
+	ctx, span := h.tracer.Start(ctx, spanName, trace.WithSpanKind(h.config.SpanKind), trace.WithAttributes(attributes...))
+
+	activeAttributes := metric.WithAttributeSet(attribute.NewSet(attributes...))
+	h.active.Add(ctx, 1, activeAttributes)
+	defer func() {
+		// a panic is recorded and propagated, so the panic middlewares still handle it
+		if recovered := recover(); recovered != nil {
+			span.SetStatus(codes.Error, "panic")
+			span.AddEvent("panic", trace.WithAttributes(attribute.String("panic.value", toString(recovered))))
+			h.end(ctx, span, start, attributes, activeAttributes)
+			panic(recovered)
+		}
+
+		resultAttributes, failed := h.result(output, err)
+		span.SetAttributes(resultAttributes...)
+		if err != nil {
+			span.RecordError(err)
+		}
+		if failed {
+			description := ""
+			if err != nil {
+				description = err.Error()
+			}
+			span.SetStatus(codes.Error, description)
+		}
+		h.end(ctx, span, start, append(attributes, resultAttributes...), activeAttributes)
+	}()
+
+	return h.decorated.Invoke(ctx, input, output)
+}
+
+func (h instrumentedHandler[Input, Output]) result(output Output, err error) ([]attribute.KeyValue, bool) {
+	if h.config.ResultAttributes != nil {
+		return h.config.ResultAttributes(output, err)
+	}
+	return nil, err != nil
+}
+
+func (h instrumentedHandler[Input, Output]) end(ctx context.Context, span trace.Span, start time.Time,
+	attributes []attribute.KeyValue, activeAttributes metric.AddOption) {
+	h.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributeSet(attribute.NewSet(attributes...)))
+	h.active.Add(ctx, -1, activeAttributes)
+	span.End()
+}
+
+func toString(value any) string {
+	if err, ok := value.(error); ok {
+		return err.Error()
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,164 @@
+package oteladpt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	testTelemetry struct {
+		spans          *tracetest.InMemoryExporter
+		reader         *sdkmetric.ManualReader
+		tracerProvider *sdktrace.TracerProvider
+		meterProvider  *sdkmetric.MeterProvider
+	}
+
+	testOutput struct {
+		exitCode int
+	}
+
+	testHandler func(ctx context.Context, input string, output *testOutput) error
+)
+
+func (f testHandler) Invoke(ctx context.Context, input string, output *testOutput) error {
+	return f(ctx, input, output)
+}
+
+func newTestTelemetry() testTelemetry {
+	spans := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	return testTelemetry{
+		spans:          spans,
+		reader:         reader,
+		tracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans)),
+		meterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}
+}
+
+func (tt testTelemetry) metrics(t *testing.T) map[string]metricdata.Aggregation {
+	t.Helper()
+	var data metricdata.ResourceMetrics
+	if err := tt.reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	result := map[string]metricdata.Aggregation{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			result[m.Name] = m.Data
+		}
+	}
+	return result
+}
+
+func newTestCLIConfig(telemetry testTelemetry) Config[string, *testOutput] {
+	return Config[string, *testOutput]{
+		TracerProvider: telemetry.tracerProvider,
+		MeterProvider:  telemetry.meterProvider,
+		MetricPrefix:   "cli",
+		SpanKind:       trace.SpanKindInternal,
+		SpanName:       func(_ context.Context, input string) string { return "cli " + input },
+		Attributes: func(_ context.Context, input string) []attribute.KeyValue {
+			return []attribute.KeyValue{attribute.String("cli.command", input)}
+		},
+		ResultAttributes: func(output *testOutput, err error) ([]attribute.KeyValue, bool) {
+			return []attribute.KeyValue{attribute.Int("cli.exit_code", output.exitCode)}, err != nil || output.exitCode != 0
+		},
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	telemetry := newTestTelemetry()
+	var inFlight int64
+	handler, err := Instrument[string, *testOutput](testHandler(func(ctx context.Context, input string, output *testOutput) error {
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			t.Error("the context has no span")
+		}
+		if sum, ok := telemetry.metrics(t)["cli.active_requests"].(metricdata.Sum[int64]); ok && len(sum.DataPoints) == 1 {
+			inFlight = sum.DataPoints[0].Value
+		}
+		if input == "fail" {
+			output.exitCode = 2
+			return errors.New("failed")
+		}
+		return nil
+	}), newTestCLIConfig(telemetry))
+	if err != nil {
+		t.Fatalf("Instrument() error = %v", err)
+	}
+
+	_ = handler.Invoke(context.Background(), "run", &testOutput{})
+	if err := handler.Invoke(context.Background(), "fail", &testOutput{}); err == nil {
+		t.Error("Invoke() error = nil, want the handler error")
+	}
+	if inFlight != 1 {
+		t.Errorf("active requests during the invocation = %d, want 1", inFlight)
+	}
+
+	spans := telemetry.spans.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+	if spans[0].Name != "cli run" || spans[0].SpanKind != trace.SpanKindInternal || spans[0].Status.Code != codes.Unset {
+		t.Errorf("span = %s %v %v", spans[0].Name, spans[0].SpanKind, spans[0].Status)
+	}
+	if spans[1].Status.Code != codes.Error || spans[1].Status.Description != "failed" || len(spans[1].Events) != 1 {
+		t.Errorf("failed span status = %v, events = %v", spans[1].Status, spans[1].Events)
+	}
+	if !hasAttribute(spans[1].Attributes, attribute.Int("cli.exit_code", 2)) {
+		t.Errorf("failed span attributes = %v", spans[1].Attributes)
+	}
+
+	metrics := telemetry.metrics(t)
+	histogram, ok := metrics["cli.request.duration"].(metricdata.Histogram[float64])
+	if !ok || len(histogram.DataPoints) != 2 {
+		t.Fatalf("cli.request.duration = %#v, want 2 data points", metrics["cli.request.duration"])
+	}
+	active, ok := metrics["cli.active_requests"].(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("cli.active_requests = %#v", metrics["cli.active_requests"])
+	}
+	for _, point := range active.DataPoints {
+		if point.Value != 0 {
+			t.Errorf("active requests after the invocations = %d, want 0", point.Value)
+		}
+	}
+}
+
+func TestInstrument_Panic(t *testing.T) {
+	telemetry := newTestTelemetry()
+	handler, _ := Instrument[string, *testOutput](testHandler(func(context.Context, string, *testOutput) error {
+		panic("boom")
+	}), newTestCLIConfig(telemetry))
+
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "boom" {
+				t.Errorf("recovered = %v, want the panic to be propagated", recovered)
+			}
+		}()
+		_ = handler.Invoke(context.Background(), "run", &testOutput{})
+	}()
+
+	spans := telemetry.spans.GetSpans()
+	if len(spans) != 1 || spans[0].Status.Code != codes.Error || spans[0].Events[0].Name != "panic" {
+		t.Errorf("spans = %v, want one failed span with a panic event", spans)
+	}
+}
+
+func hasAttribute(attributes []attribute.KeyValue, expected attribute.KeyValue) bool {
+	for _, kv := range attributes {
+		if kv == expected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+module github.com/smart-libs/go-adapter/prometheus/lib
+
+go 1.25
+
+require (
+	github.com/prometheus/client_golang v1.23.2
+	github.com/prometheus/common v0.66.1
+	github.com/smart-libs/go-adapter/http/lib v0.0.3
+	github.com/smart-libs/go-adapter/sdk/lib v0.0.1
+	github.com/smart-libs/go-crosscutting/serror/lib v0.0.2
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/joomcode/errorx v1.2.0 // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.2 // indirect
+	github.com/prometheus/procfs v0.16.1 // indirect
+	github.com/smart-libs/go-adapter/interfaces v0.0.1 // indirect
+	github.com/smart-libs/go-crosscutting/assertions/lib v0.0.6 // indirect
+	github.com/smart-libs/go-crosscutting/converter/lib v0.0.2 // indirect
+	github.com/smart-libs/go-crosscutting/types/lib v0.0.1 // indirect
+	go.yaml.in/yaml/v2 v2.4.2 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	google.golang.org/protobuf v1.36.8 // indirect
+)
+
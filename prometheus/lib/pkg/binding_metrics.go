@@ -0,0 +1,68 @@
+package promadpt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+)
+
+const (
+	// DefaultMetricsPath is the path usually scraped by Prometheus
+	DefaultMetricsPath = "/metrics"
+)
+
+// NewMetricsBinding creates a GET binding that serves the metrics of the gatherer. The format is negotiated from the
+// Accept header as promhttp does with EnableOpenMetrics, so it is OpenMetrics, protobuf or, by default, the text
+// exposition format. The path is DefaultMetricsPath if empty and the gatherer is prometheus.DefaultGatherer if nil.
+func NewMetricsBinding(path string, gatherer prometheus.Gatherer) httpadpt.Binding {
+	if path == "" {
+		path = DefaultMetricsPath
+	}
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+	return httpadpt.Binding{
+		Condition: httpadpt.Condition{Path: &path, Methods: []string{http.MethodGet}},
+		Handler: httpadpt.MakeHandler(func(_ context.Context, input httpadpt.Request, output *httpadpt.Response) error {
+			families, err := gatherer.Gather()
+			if err != nil {
+				return fmt.Errorf("promadpt.NewMetricsBinding: %w", err)
+			}
+			format := expfmt.NegotiateIncludingOpenMetrics(getAcceptHeader(input))
+			var buffer bytes.Buffer
+			encoder := expfmt.NewEncoder(&buffer, format)
+			for _, family := range families {
+				if err := encoder.Encode(family); err != nil {
+					return fmt.Errorf("promadpt.NewMetricsBinding: %w", err)
+				}
+			}
+			if closer, ok := encoder.(expfmt.Closer); ok {
+				if err := closer.Close(); err != nil {
+					return fmt.Errorf("promadpt.NewMetricsBinding: %w", err)
+				}
+			}
+			statusCode := http.StatusOK
+			output.StatusCode = &statusCode
+			output.Header = map[string][]string{"Content-Type": {string(format)}}
+			output.Body = buffer.Bytes()
+			return nil
+		}),
+	}
+}
+
+// getAcceptHeader returns the Accept header of the request as an http.Header, the way expfmt negotiates the format
+func getAcceptHeader(input httpadpt.Request) http.Header {
+	header := http.Header{}
+	if input == nil || input.Header() == nil {
+		return header
+	}
+	if values, found := input.Header().GetValue(httpadpt.HeaderAccept); found {
+		header[httpadpt.HeaderAccept] = values
+	}
+	return header
+}
@@ -0,0 +1,53 @@
+package promadpt
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+)
+
+func TestNewMetricsBinding(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "jobs_total", Help: "Number of jobs."})
+	registry.MustRegister(counter)
+	counter.Add(3)
+
+	binding := NewMetricsBinding("", registry)
+	if *binding.Condition.Path != DefaultMetricsPath || binding.Condition.Methods[0] != http.MethodGet {
+		t.Errorf("Condition = %s %v", *binding.Condition.Path, binding.Condition.Methods)
+	}
+
+	output := &httpadpt.Response{}
+	if err := binding.Handler.Invoke(context.Background(), testRequest{}, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if *output.StatusCode != http.StatusOK || !strings.HasPrefix(output.Header["Content-Type"][0], "text/plain; version=0.0.4") {
+		t.Errorf("StatusCode = %d, Content-Type = %v", *output.StatusCode, output.Header["Content-Type"])
+	}
+	if body := string(output.Body); !strings.Contains(body, "# TYPE jobs_total counter\njobs_total 3\n") {
+		t.Errorf("Body = %s", body)
+	}
+}
+
+func TestNewMetricsBinding_OpenMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "jobs_total", Help: "Number of jobs."})
+	registry.MustRegister(counter)
+
+	binding := NewMetricsBinding("", registry)
+	input := testRequest{header: testHeader{httpadpt.HeaderAccept: {"application/openmetrics-text;version=1.0.0,text/plain;q=0.5"}}}
+	output := &httpadpt.Response{}
+	if err := binding.Handler.Invoke(context.Background(), input, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if contentType := output.Header["Content-Type"][0]; !strings.HasPrefix(contentType, "application/openmetrics-text; version=1.0.0") {
+		t.Errorf("Content-Type = %s, want OpenMetrics", contentType)
+	}
+	if body := string(output.Body); !strings.Contains(body, "# TYPE jobs counter\n") || !strings.HasSuffix(body, "# EOF\n") {
+		t.Errorf("Body = %s", body)
+	}
+}
@@ -0,0 +1,168 @@
+package promadpt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	sdkparam "github.com/smart-libs/go-adapter/sdk/lib/pkg/param"
+)
+
+const (
+	LabelRoute  = "route"
+	LabelMethod = "method"
+	LabelStatus = "status"
+	LabelType   = "type"
+
+	// UnknownRoute is the route label of the requests handled without a binding path, like the Condition.Other ones
+	UnknownRoute = "unknown"
+)
+
+type (
+	// Config configures the metrics created by NewMetrics
+	Config struct {
+		// Registerer is prometheus.DefaultRegisterer if nil
+		Registerer prometheus.Registerer
+
+		// Namespace is prepended to the metric names, like "shop" creates "shop_http_requests_total"
+		Namespace string
+
+		// Buckets of the latency histogram in seconds, it is prometheus.DefBuckets if nil
+		Buckets []float64
+	}
+
+	// Metrics holds the HTTP server collectors, labelled by the binding path template, the method and the status
+	Metrics struct {
+		requests *prometheus.CounterVec
+		duration *prometheus.HistogramVec
+		inFlight *prometheus.GaugeVec
+		errors   *prometheus.CounterVec
+	}
+
+	metricsMiddleware struct {
+		metrics   *Metrics
+		decorated httpadpt.Handler
+	}
+
+	// countingOutErrorParamSpec counts the errors written by the decorated spec
+	countingOutErrorParamSpec struct {
+		sdkparam.OutputParamSpec[*httpadpt.Response]
+		errors *prometheus.CounterVec
+	}
+)
+
+// NewMetrics creates and registers the collectors:
+//   - http_requests_total{route,method,status}
+//   - http_request_duration_seconds{route,method,status}
+//   - http_requests_in_flight{route,method}
+//   - http_handler_errors_total{type,status}, see Metrics.OutErrorParamSpec
+func NewMetrics(config Config) (*Metrics, error) {
+	if config.Registerer == nil {
+		config.Registerer = prometheus.DefaultRegisterer
+	}
+	if config.Buckets == nil {
+		config.Buckets = prometheus.DefBuckets
+	}
+
+	metrics := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests handled.",
+		}, []string{LabelRoute, LabelMethod, LabelStatus}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of the HTTP requests.",
+			Buckets:   config.Buckets,
+		}, []string{LabelRoute, LabelMethod, LabelStatus}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: config.Namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests in progress.",
+		}, []string{LabelRoute, LabelMethod}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "http_handler_errors_total",
+			Help:      "Number of errors returned by the handlers, by problem detail type.",
+		}, []string{LabelType, LabelStatus}),
+	}
+	for _, collector := range []prometheus.Collector{metrics.requests, metrics.duration, metrics.inFlight, metrics.errors} {
+		if err := config.Registerer.Register(collector); err != nil {
+			return nil, fmt.Errorf("promadpt.NewMetrics: %w", err)
+		}
+	}
+	return metrics, nil
+}
+
+// Middleware records the requests. The route label is the binding path template read by httpadpt.RouteFromRequest,
+// never the raw URL, so the number of series is bounded. Add it before the other middlewares, so it wraps them and the
+// status of the responses written by them, like the 401 or the 429, is recorded.
+func (m *Metrics) Middleware() httpadpt.Middleware {
+	return func(next httpadpt.Handler) httpadpt.Handler {
+		return metricsMiddleware{metrics: m, decorated: next}
+	}
+}
+
+// OutErrorParamSpec decorates the spec to count the errors by the httpadpt.ProblemDetail type and the status code,
+// use it with httpadpt.Group.WithOutErrorParamSpec. If spec is nil, httpadpt.NewOutErrorParamSpec() is decorated.
+func (m *Metrics) OutErrorParamSpec(spec sdkparam.OutputParamSpec[*httpadpt.Response]) sdkparam.OutputParamSpec[*httpadpt.Response] {
+	if spec == nil {
+		spec = httpadpt.NewOutErrorParamSpec()
+	}
+	return countingOutErrorParamSpec{OutputParamSpec: spec, errors: m.errors}
+}
+
+func (c countingOutErrorParamSpec) SetValue(output *httpadpt.Response, value any) error {
+	if err := c.OutputParamSpec.SetValue(output, value); err != nil {
+		return err
+	}
+	if err, ok := value.(error); ok && err != nil {
+		c.errors.WithLabelValues(httpadpt.ProblemDetailFromError(err).Type, strconv.Itoa(getStatus(output, nil))).Inc()
+	}
+	return nil
+}
+
+func (m metricsMiddleware) Invoke(ctx context.Context, input httpadpt.Request, output *httpadpt.Response) (err error) {
+	start := time.Now()
+	route, found := httpadpt.RouteFromRequest(input)
+	if !found {
+		route = UnknownRoute
+	}
+	method := httpadpt.GetMethod(input)
+
+	inFlight := m.metrics.inFlight.WithLabelValues(route, method)
+	inFlight.Inc()
+	defer func() {
+		inFlight.Dec()
+		status := getStatus(output, err)
+		recovered := recover()
+		if recovered != nil {
+			status = http.StatusInternalServerError
+		}
+		m.metrics.requests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+		m.metrics.duration.WithLabelValues(route, method, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+		if recovered != nil {
+			// the panic is propagated, so the panic middlewares still handle it
+			panic(recovered)
+		}
+	}()
+
+	return m.decorated.Invoke(ctx, input, output)
+}
+
+// getStatus returns the status code the implementation writes for the response
+func getStatus(output *httpadpt.Response, err error) int {
+	switch {
+	case err != nil || output == nil:
+		return http.StatusInternalServerError
+	case output.StatusCode != nil:
+		return *output.StatusCode
+	default:
+		return http.StatusOK
+	}
+}
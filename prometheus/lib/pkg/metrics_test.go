@@ -0,0 +1,139 @@
+package promadpt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	testRequest struct {
+		method string
+		header testHeader
+	}
+
+	testHeader map[string][]string
+)
+
+func (r testRequest) Query() httpadpt.QueryParams   { return nil }
+func (r testRequest) Header() httpadpt.HeaderParams { return r.header }
+func (r testRequest) Path() httpadpt.PathParams     { return nil }
+func (r testRequest) URL() *url.URL                 { return &url.URL{Path: "/orders/123"} }
+func (r testRequest) Method() string                { return r.method }
+
+func (h testHeader) GetValue(name string) ([]string, bool) {
+	values, found := h[name]
+	return values, found
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewMetrics(Config{Registerer: registry, Namespace: "shop"})
+	if err != nil {
+		t.Fatalf("NewMetrics() error = %v", err)
+	}
+
+	path := "/orders/{id}"
+	var inFlight float64
+	binding := httpadpt.Binding{
+		Condition: httpadpt.Condition{Path: &path, Methods: []string{http.MethodGet, http.MethodDelete}},
+		Handler: httpadpt.MakeHandler(func(_ context.Context, input httpadpt.Request, output *httpadpt.Response) error {
+			inFlight = testutil.ToFloat64(metrics.inFlight.WithLabelValues(path, input.Method()))
+			if input.Method() == http.MethodDelete {
+				statusCode := http.StatusNotFound
+				output.StatusCode = &statusCode
+			}
+			return nil
+		}),
+	}
+	handler := httpadpt.WrapBindingHandler(binding, httpadpt.Config{Middlewares: httpadpt.Middlewares{metrics.Middleware()}})
+
+	for _, method := range []string{http.MethodGet, http.MethodGet, http.MethodDelete} {
+		if err := handler.Invoke(context.Background(), testRequest{method: method}, &httpadpt.Response{}); err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+	}
+	unbound := metrics.Middleware()(httpadpt.MakeHandler(func(context.Context, httpadpt.Request, *httpadpt.Response) error {
+		return errors.New("failed")
+	}))
+	_ = unbound.Invoke(context.Background(), testRequest{method: http.MethodPost}, &httpadpt.Response{})
+
+	if inFlight != 1 {
+		t.Errorf("in flight during the request = %v, want 1", inFlight)
+	}
+	expected := `
+# HELP shop_http_requests_total Number of HTTP requests handled.
+# TYPE shop_http_requests_total counter
+shop_http_requests_total{method="DELETE",route="/orders/{id}",status="404"} 1
+shop_http_requests_total{method="GET",route="/orders/{id}",status="200"} 2
+shop_http_requests_total{method="POST",route="unknown",status="500"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "shop_http_requests_total"); err != nil {
+		t.Error(err)
+	}
+	if count := testutil.CollectAndCount(metrics.duration); count != 3 {
+		t.Errorf("duration series = %d, want 3", count)
+	}
+	if value := testutil.ToFloat64(metrics.inFlight.WithLabelValues(path, http.MethodGet)); value != 0 {
+		t.Errorf("in flight after the requests = %v, want 0", value)
+	}
+}
+
+func TestMetrics_MiddlewarePanic(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, _ := NewMetrics(Config{Registerer: registry})
+	handler := metrics.Middleware()(httpadpt.MakeHandler(func(context.Context, httpadpt.Request, *httpadpt.Response) error {
+		panic("boom")
+	}))
+
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "boom" {
+				t.Errorf("recovered = %v, want the panic to be propagated", recovered)
+			}
+		}()
+		_ = handler.Invoke(context.Background(), testRequest{}, &httpadpt.Response{})
+	}()
+
+	if value := testutil.ToFloat64(metrics.requests.WithLabelValues(UnknownRoute, http.MethodGet, "500")); value != 1 {
+		t.Errorf("requests with status 500 = %v, want 1", value)
+	}
+}
+
+func TestMetrics_OutErrorParamSpec(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, _ := NewMetrics(Config{Registerer: registry})
+	api := httpadpt.NewGroup("/api").WithOutErrorParamSpec(metrics.OutErrorParamSpec(nil))
+	binding := api.Route("/orders").WithMethods(http.MethodGet).WithHandlerFunc(func() error {
+		return serror.NotFoundError.New("order not found")
+	})
+
+	output := &httpadpt.Response{}
+	if err := binding.Handler.Invoke(context.Background(), testRequest{}, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if output.StatusCode == nil || *output.StatusCode != http.StatusNotFound {
+		t.Errorf("StatusCode = %v, want 404", output.StatusCode)
+	}
+	if value := testutil.ToFloat64(metrics.errors.WithLabelValues("*errorx.Error", "404")); value != 1 {
+		t.Errorf("handler errors = %v, want 1", value)
+	}
+}
+
+func TestNewMetrics_AlreadyRegistered(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := NewMetrics(Config{Registerer: registry}); err != nil {
+		t.Fatalf("NewMetrics() error = %v", err)
+	}
+	if _, err := NewMetrics(Config{Registerer: registry}); err == nil {
+		t.Error("NewMetrics() error = nil, want the duplicated registration error")
+	}
+}
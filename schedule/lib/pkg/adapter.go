@@ -0,0 +1,234 @@
+package schedadpt
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdkhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	Adapter interface {
+		Start(ctx context.Context) error
+		Stop(ctx context.Context) error
+	}
+
+	Config struct {
+		// Bindings are the jobs scheduled
+		Bindings
+		// Clock is the source of time, it is SystemClock if nil
+		Clock Clock
+		// Location is where the schedules of the bindings without one are evaluated, it is time.Local if nil
+		Location *time.Location
+		// Overlap is the policy of the bindings without one, zero means OverlapSkip
+		Overlap OverlapPolicy
+		// Jitter is the maximum random delay added to the fire times of the bindings without one
+		Jitter time.Duration
+		// Logger logs the handler errors and the skipped runs, it is slog.Default() if nil
+		Logger *slog.Logger
+	}
+
+	DefaultAdapter struct {
+		config Config
+		jobs   []*job
+
+		locker         sync.Mutex
+		started        bool
+		stopped        bool
+		draining       atomic.Bool
+		cancelSchedule context.CancelFunc
+		cancelRun      context.CancelFunc
+		loops          sync.WaitGroup
+		runs           sync.WaitGroup
+	}
+
+	// job is the scheduling state of a binding
+	job struct {
+		Binding
+		name string
+
+		mutex   sync.Mutex
+		running int
+		// queued is the run fired while the previous one was in progress, with OverlapQueue
+		queued *Input
+		// runs counts the runs started, it numbers them
+		runs int
+	}
+)
+
+// NewAdapter creates the Adapter that runs the handlers of the config bindings at the times of their schedules
+func NewAdapter(config Config) (Adapter, error) {
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	if config.Location == nil {
+		config.Location = time.Local
+	}
+	config.Overlap = cmp.Or(config.Overlap, OverlapSkip)
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	adapter := &DefaultAdapter{config: config}
+	for _, binding := range config.Bindings {
+		if !IsBindingValid(binding) {
+			return nil, serror.IllegalConfigParamValue("Binding", binding.Name)
+		}
+		binding.Overlap = cmp.Or(binding.Overlap, config.Overlap)
+		binding.Jitter = cmp.Or(binding.Jitter, config.Jitter)
+		if binding.Location == nil {
+			binding.Location = config.Location
+		}
+		adapter.jobs = append(adapter.jobs, &job{Binding: binding, name: binding.jobName()})
+	}
+	return adapter, nil
+}
+
+// Start schedules the bindings in background, the first fire time of each one is computed from the current time
+func (d *DefaultAdapter) Start(ctx context.Context) error {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	if d.stopped {
+		return fmt.Errorf("adapter already stopped")
+	}
+	if d.started {
+		return fmt.Errorf("adapter already started")
+	}
+
+	scheduleCtx, cancelSchedule := context.WithCancel(context.WithoutCancel(ctx))
+	runCtx, cancelRun := context.WithCancel(context.WithoutCancel(ctx))
+	d.cancelSchedule, d.cancelRun = cancelSchedule, cancelRun
+	for _, j := range d.jobs {
+		d.loops.Add(1)
+		go d.schedule(scheduleCtx, runCtx, j)
+	}
+	d.started = true
+	return nil
+}
+
+// Stop stops scheduling, drops the queued runs and waits for the runs in progress until the context is done, then
+// the handlers context is canceled
+func (d *DefaultAdapter) Stop(ctx context.Context) error {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	if !d.started || d.stopped {
+		d.stopped = true
+		return nil
+	}
+	d.stopped = true
+	d.cancelSchedule()
+	d.loops.Wait()
+	d.draining.Store(true)
+
+	done := make(chan struct{})
+	go func() {
+		d.runs.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	d.cancelRun()
+	return err
+}
+
+// schedule fires the job at each time of its schedule. The runs missed while the adapter was behind, like when the
+// host was suspended, are not fired: the schedule continues from the current time.
+func (d *DefaultAdapter) schedule(scheduleCtx, runCtx context.Context, j *job) {
+	defer d.loops.Done()
+	clock := d.config.Clock
+	scheduled := j.Schedule.Next(clock.Now().In(j.Location))
+	for !scheduled.IsZero() {
+		timer := clock.NewTimer(scheduled.Add(j.jitter()).Sub(clock.Now()))
+		select {
+		case <-scheduleCtx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+
+		now := clock.Now().In(j.Location)
+		d.fire(runCtx, j, Input{Job: j.name, ScheduledAt: scheduled, FiredAt: now})
+		if scheduled = j.Schedule.Next(scheduled); !scheduled.IsZero() && scheduled.Before(now) {
+			scheduled = j.Schedule.Next(now)
+		}
+	}
+}
+
+// fire starts the run, unless the previous one is in progress and the overlap policy skips or queues it. Only one run
+// is queued: the runs fired while there is one queued are coalesced into the latest, so a job slower than its
+// schedule does not build up a backlog.
+func (d *DefaultAdapter) fire(ctx context.Context, j *job, input Input) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.running > 0 {
+		switch j.Overlap {
+		case OverlapQueue:
+			if j.queued != nil {
+				d.config.Logger.Warn("schedadpt: queued run coalesced, the previous one is in progress",
+					slog.String("job", j.name), slog.Time("scheduled", j.queued.ScheduledAt))
+			}
+			j.queued = &input
+			return
+		case OverlapAllow:
+		default:
+			d.config.Logger.Warn("schedadpt: run skipped, the previous one is in progress", slog.String("job", j.name),
+				slog.Time("scheduled", input.ScheduledAt))
+			return
+		}
+	}
+	j.runs++
+	input.Run = j.runs
+	j.running++
+	d.runs.Add(1)
+	go d.run(ctx, j, input)
+}
+
+// run invokes the handler and then the run queued meanwhile
+func (d *DefaultAdapter) run(ctx context.Context, j *job, input Input) {
+	defer d.runs.Done()
+	for {
+		d.invoke(ctx, j, input)
+
+		j.mutex.Lock()
+		if j.queued == nil || d.draining.Load() {
+			j.queued = nil
+			j.running--
+			j.mutex.Unlock()
+			return
+		}
+		input, j.queued = *j.queued, nil
+		input.FiredAt = d.config.Clock.Now().In(j.Location)
+		j.runs++
+		input.Run = j.runs
+		j.mutex.Unlock()
+	}
+}
+
+func (d *DefaultAdapter) invoke(ctx context.Context, j *job, input Input) {
+	result := &Result{}
+	if err := sdkhandler.Invoke(ctx, j.Handler, input, result); err != nil {
+		_ = NewOutErrorParamSpec().SetValue(result, err)
+	}
+	if result.Err != nil {
+		d.config.Logger.Error("schedadpt: job failed", slog.String("job", j.name), slog.Int("run", input.Run),
+			slog.Time("scheduled", input.ScheduledAt), slog.Any("error", result.Err))
+	}
+}
+
+func (j *job) jitter() time.Duration {
+	if j.Jitter <= 0 {
+		return 0
+	}
+	return rand.N(j.Jitter)
+}
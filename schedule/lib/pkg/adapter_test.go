@@ -0,0 +1,272 @@
+package schedadpt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type jobInput struct {
+	Job       string    `job:""`
+	Scheduled time.Time `scheduled:""`
+	Fired     time.Time `fired:""`
+	Run       int       `run:""`
+	Attempt   int       `attempt:""`
+}
+
+var (
+	testStart  = time.Date(2026, 3, 10, 8, 59, 30, 0, time.UTC)
+	testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+)
+
+func startTestAdapter(t *testing.T, clock *FakeClock, bindings ...Binding) Adapter {
+	t.Helper()
+	adapter, err := NewAdapter(Config{Bindings: bindings, Clock: clock, Location: time.UTC, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("NewAdapter() error = %v", err)
+	}
+	if err := adapter.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = adapter.Stop(context.Background()) })
+	return adapter
+}
+
+// advance waits for the jobs to arm their timers, moves the clock and waits for the jobs to fire and arm the next ones
+func advance(t *testing.T, clock *FakeClock, timers int, d time.Duration) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := clock.BlockUntil(ctx, timers); err != nil {
+		t.Fatalf("BlockUntil() error = %v", err)
+	}
+	clock.Advance(d)
+	if err := clock.BlockUntil(ctx, timers); err != nil {
+		t.Fatalf("BlockUntil() error = %v", err)
+	}
+}
+
+func waitFor[T any](t *testing.T, values <-chan T) T {
+	t.Helper()
+	select {
+	case value := <-values:
+		return value
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for the handler")
+	}
+	var zero T
+	return zero
+}
+
+func Test_Adapter_Inputs(t *testing.T) {
+	clock := NewFakeClock(testStart)
+	received := make(chan jobInput, 1)
+	startTestAdapter(t, clock, NewBindingBuilderUsingCron("0 9 * * *").WithName("report").
+		WithHandlerFunc(func(in jobInput) error {
+			received <- in
+			return nil
+		}))
+
+	advance(t, clock, 1, 45*time.Second)
+	in := waitFor(t, received)
+	expected := jobInput{
+		Job:       "report",
+		Scheduled: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC),
+		Fired:     testStart.Add(45 * time.Second),
+		Run:       1,
+		Attempt:   1,
+	}
+	if in != expected {
+		t.Errorf("input = %+v, want %+v", in, expected)
+	}
+
+	advance(t, clock, 1, 24*time.Hour)
+	if in = waitFor(t, received); in.Run != 2 || !in.Scheduled.Equal(expected.Scheduled.AddDate(0, 0, 1)) {
+		t.Errorf("second input = %+v, want the next day run", in)
+	}
+}
+
+func Test_Adapter_Location(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := NewFakeClock(testStart)
+	received := make(chan jobInput, 1)
+	startTestAdapter(t, clock, NewBindingBuilderUsingCron("0 9 * * *").WithLocation(newYork).
+		WithHandlerFunc(func(in jobInput) error {
+			received <- in
+			return nil
+		}))
+
+	advance(t, clock, 1, 4*time.Hour+30*time.Second)
+	in := waitFor(t, received)
+	if !in.Scheduled.Equal(time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC)) || in.Scheduled.Location() != newYork {
+		t.Errorf("scheduled = %v, want 09:00 in New York", in.Scheduled)
+	}
+	if in.Job != "0 9 * * *" {
+		t.Errorf("job = %q, want the cron expression", in.Job)
+	}
+}
+
+func Test_Adapter_Overlap(t *testing.T) {
+	tests := []struct {
+		overlap  OverlapPolicy
+		expected []int // the minutes scheduled of the runs, in the start order
+	}{
+		{overlap: OverlapSkip, expected: []int{1, 3}},
+		{overlap: OverlapQueue, expected: []int{1, 2, 3}},
+		{overlap: OverlapAllow, expected: []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.overlap.String(), func(t *testing.T) {
+			clock := NewFakeClock(testStart)
+			started := make(chan jobInput, 3)
+			finished := make(chan int, 3)
+			release := make(chan struct{})
+			startTestAdapter(t, clock, NewBindingBuilderUsingInterval(time.Minute).WithOverlap(tt.overlap).
+				WithHandlerFunc(func(in jobInput) error {
+					started <- in
+					if in.Run == 1 {
+						<-release
+					}
+					finished <- in.Run
+					return nil
+				}))
+
+			advance(t, clock, 1, time.Minute)
+			var runs []jobInput
+			runs = append(runs, waitFor(t, started))
+			advance(t, clock, 1, time.Minute)
+			if tt.overlap == OverlapAllow {
+				// the second run starts while the first one is in progress
+				runs = append(runs, waitFor(t, started))
+			}
+			close(release)
+			for len(runs) < len(tt.expected)-1 {
+				runs = append(runs, waitFor(t, started))
+			}
+			for range len(runs) {
+				waitFor(t, finished)
+			}
+			advance(t, clock, 1, time.Minute)
+			runs = append(runs, waitFor(t, started))
+
+			for i, run := range runs {
+				if scheduled := testStart.Add(time.Duration(tt.expected[i]) * time.Minute); !run.Scheduled.Equal(scheduled) ||
+					run.Run != i+1 {
+					t.Errorf("run %d = %+v, want scheduled at %v", i+1, run, scheduled)
+				}
+			}
+		})
+	}
+}
+
+func Test_Adapter_OverlapQueue_Coalesces(t *testing.T) {
+	clock := NewFakeClock(testStart)
+	started := make(chan jobInput, 3)
+	release := make(chan struct{})
+	startTestAdapter(t, clock, NewBindingBuilderUsingInterval(time.Minute).WithOverlap(OverlapQueue).
+		WithHandlerFunc(func(in jobInput) error {
+			started <- in
+			if in.Run == 1 {
+				<-release
+			}
+			return nil
+		}))
+
+	advance(t, clock, 1, time.Minute)
+	first := waitFor(t, started)
+	advance(t, clock, 1, time.Minute)
+	advance(t, clock, 1, time.Minute)
+	clock.Advance(20 * time.Second)
+	close(release)
+	second := waitFor(t, started)
+	if !first.Scheduled.Equal(testStart.Add(time.Minute)) || second.Run != 2 ||
+		!second.Scheduled.Equal(testStart.Add(3*time.Minute)) {
+		t.Errorf("runs = %+v, %+v, want the queued runs coalesced into the latest", first, second)
+	}
+	if !second.Fired.Equal(testStart.Add(3*time.Minute + 20*time.Second)) {
+		t.Errorf("queued run fired = %v, want the time it started", second.Fired)
+	}
+	select {
+	case in := <-started:
+		t.Errorf("run = %+v, want no other queued run", in)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func Test_Adapter_Jitter(t *testing.T) {
+	clock := NewFakeClock(testStart)
+	received := make(chan jobInput, 1)
+	startTestAdapter(t, clock, NewBindingBuilderUsingInterval(time.Minute).WithJitter(10*time.Second).
+		WithHandlerFunc(func(in jobInput) error {
+			received <- in
+			return nil
+		}))
+
+	advance(t, clock, 1, time.Minute+10*time.Second)
+	in := waitFor(t, received)
+	if delay := in.Fired.Sub(in.Scheduled); !in.Scheduled.Equal(testStart.Add(time.Minute)) || delay < 0 || delay > 10*time.Second {
+		t.Errorf("input = %+v, want fired up to 10s after the scheduled time", in)
+	}
+}
+
+func Test_Adapter_HandlerFailures(t *testing.T) {
+	clock := NewFakeClock(testStart)
+	received := make(chan int, 3)
+	startTestAdapter(t, clock, NewBindingBuilderUsingInterval(time.Minute).
+		WithHandlerFunc(func(in jobInput) error {
+			received <- in.Run
+			switch in.Run {
+			case 1:
+				return errors.New("failed")
+			case 2:
+				panic("broken")
+			}
+			return nil
+		}))
+
+	for run := 1; run <= 3; run++ {
+		advance(t, clock, 1, time.Minute)
+		if got := waitFor(t, received); got != run {
+			t.Errorf("run = %d, want %d", got, run)
+		}
+	}
+}
+
+func Test_Adapter_Stop(t *testing.T) {
+	clock := NewFakeClock(testStart)
+	started := make(chan struct{}, 1)
+	canceled := make(chan error, 1)
+	adapter := startTestAdapter(t, clock, NewBindingBuilderUsingInterval(time.Minute).
+		WithHandlerFunc(func(ctx context.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			canceled <- ctx.Err()
+			return nil
+		}))
+
+	advance(t, clock, 1, time.Minute)
+	waitFor(t, started)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := adapter.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop() error = %v, want the context error", err)
+	}
+	if err := waitFor(t, canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("handler context error = %v, want canceled after Stop", err)
+	}
+	if err := adapter.Start(context.Background()); err == nil {
+		t.Error("Start() after Stop() should fail")
+	}
+}
+
+func Test_NewAdapter_InvalidBinding(t *testing.T) {
+	if _, err := NewAdapter(Config{Bindings: Bindings{{Condition: Condition{Name: "none"}}}}); err == nil {
+		t.Error("NewAdapter() error = nil, want an invalid binding error")
+	}
+}
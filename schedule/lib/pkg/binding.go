@@ -0,0 +1,69 @@
+package schedadpt
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// OverlapSkip drops the runs that fire while the previous one is in progress, it is the default
+	OverlapSkip OverlapPolicy = iota + 1
+	// OverlapQueue delays the runs that fire while the previous one is in progress until it finishes, the runs fired
+	// while one is already delayed are coalesced into the latest
+	OverlapQueue
+	// OverlapAllow starts the runs even if the previous one is in progress
+	OverlapAllow
+)
+
+type (
+	// OverlapPolicy is what is done when a job fires while its previous run is in progress
+	OverlapPolicy int
+
+	// Condition identifies when the binding handler runs
+	Condition struct {
+		// Name identifies the job in the logs and the job tag, the Schedule is used if empty
+		Name string
+		// Schedule gives the fire times
+		Schedule Schedule
+	}
+
+	Binding struct {
+		Condition
+		Handler
+
+		// Overlap is the policy of the runs that fire while the previous one is in progress, Config.Overlap is used
+		// if zero
+		Overlap OverlapPolicy
+		// Jitter is the maximum random delay added to each fire time, Config.Jitter is used if zero
+		Jitter time.Duration
+		// Location is where the Schedule is evaluated, Config.Location is used if nil
+		Location *time.Location
+	}
+
+	// Bindings are the jobs of the schedule adapter, each one is scheduled independently
+	Bindings []Binding
+)
+
+func IsBindingValid(binding Binding) bool {
+	return binding.Handler != nil && binding.Schedule != nil
+}
+
+func (c Condition) jobName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return fmt.Sprint(c.Schedule)
+}
+
+func (o OverlapPolicy) String() string {
+	switch o {
+	case OverlapSkip:
+		return "skip"
+	case OverlapQueue:
+		return "queue"
+	case OverlapAllow:
+		return "allow"
+	default:
+		return fmt.Sprintf("OverlapPolicy(%d)", int(o))
+	}
+}
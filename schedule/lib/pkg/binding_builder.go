@@ -0,0 +1,69 @@
+package schedadpt
+
+import (
+	"time"
+
+	tagbasedhandler "github.com/smart-libs/go-adapter/sdk/lib/pkg/handler/tagbased"
+)
+
+type (
+	HandlerBuildingStep interface {
+		// WithName sets the job name
+		WithName(name string) HandlerBuildingStep
+		// WithOverlap sets what is done when the job fires while its previous run is in progress
+		WithOverlap(overlap OverlapPolicy) HandlerBuildingStep
+		// WithJitter sets the maximum random delay added to each fire time
+		WithJitter(jitter time.Duration) HandlerBuildingStep
+		// WithLocation sets where the schedule is evaluated
+		WithLocation(location *time.Location) HandlerBuildingStep
+		WithHandlerFunc(handler any) Binding
+	}
+
+	BaseBuilder struct {
+		Binding
+	}
+)
+
+func NewBindingBuilderUsingSchedule(schedule Schedule) HandlerBuildingStep {
+	return &BaseBuilder{Binding: Binding{Condition: Condition{Schedule: schedule}}}
+}
+
+// NewBindingBuilderUsingCron creates the builder of a job scheduled by the cron expression, it panics if the
+// expression is invalid, see ParseCron
+func NewBindingBuilderUsingCron(expression string) HandlerBuildingStep {
+	return NewBindingBuilderUsingSchedule(MustParseCron(expression))
+}
+
+// NewBindingBuilderUsingInterval creates the builder of a job that runs at fixed intervals, see Every
+func NewBindingBuilderUsingInterval(interval time.Duration) HandlerBuildingStep {
+	return NewBindingBuilderUsingSchedule(Every(interval))
+}
+
+func (b *BaseBuilder) WithName(name string) HandlerBuildingStep {
+	b.Name = name
+	return b
+}
+
+func (b *BaseBuilder) WithOverlap(overlap OverlapPolicy) HandlerBuildingStep {
+	b.Overlap = overlap
+	return b
+}
+
+func (b *BaseBuilder) WithJitter(jitter time.Duration) HandlerBuildingStep {
+	b.Jitter = jitter
+	return b
+}
+
+func (b *BaseBuilder) WithLocation(location *time.Location) HandlerBuildingStep {
+	b.Location = location
+	return b
+}
+
+func (b *BaseBuilder) WithHandlerFunc(handler any) Binding {
+	b.Handler = tagbasedhandler.NewBuilderForFunc[Input, *Result](handler).
+		WithInTagBasedFactory(createInParamSpecFactory()).
+		WithOutTagBasedFactory(createOutParamSpecFactory()).
+		WithOutErrorParamSpec(NewOutErrorParamSpec()).
+		Build()
+	return b.Binding
+}
@@ -0,0 +1,35 @@
+package schedadpt
+
+import (
+	"time"
+)
+
+type (
+	// Clock is the source of time of the adapter, it is replaced by the FakeClock in the tests
+	Clock interface {
+		Now() time.Time
+		// NewTimer creates a Timer that sends the time on its channel after the duration
+		NewTimer(d time.Duration) Timer
+	}
+
+	// Timer is the time.Timer created by the Clock
+	Timer interface {
+		C() <-chan time.Time
+		Stop() bool
+	}
+
+	systemClock struct{}
+
+	systemTimer struct {
+		*time.Timer
+	}
+)
+
+// SystemClock is the Clock of the time package, it is the Config.Clock default
+var SystemClock Clock = systemClock{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{Timer: time.NewTimer(d)} }
+
+func (t systemTimer) C() <-chan time.Time { return t.Timer.C }
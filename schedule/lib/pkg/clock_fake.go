@@ -0,0 +1,118 @@
+package schedadpt
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	// FakeClock is a Clock whose time only changes by Advance and Set, so the jobs fire deterministically in the
+	// tests. BlockUntil waits for the adapter to arm its timers before the time is changed.
+	FakeClock struct {
+		mutex   sync.Mutex
+		now     time.Time
+		timers  []*fakeTimer
+		changed chan struct{}
+	}
+
+	fakeTimer struct {
+		clock    *FakeClock
+		deadline time.Time
+		c        chan time.Time
+	}
+)
+
+// NewFakeClock creates a FakeClock at the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan struct{})}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	timer := &fakeTimer{clock: c, deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		timer.c <- c.now
+		return timer
+	}
+	c.timers = append(c.timers, timer)
+	c.notify()
+	return timer
+}
+
+// Advance moves the time forward, firing the timers whose deadline is reached
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.setLocked(c.now.Add(d))
+}
+
+// Set changes the time, firing the timers whose deadline is reached
+func (c *FakeClock) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.setLocked(now)
+}
+
+// BlockUntil waits until there are at least the given number of timers armed, or the context is done
+func (c *FakeClock) BlockUntil(ctx context.Context, timers int) error {
+	for {
+		c.mutex.Lock()
+		armed, changed := len(c.timers), c.changed
+		c.mutex.Unlock()
+		if armed >= timers {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (c *FakeClock) setLocked(now time.Time) {
+	c.now = now
+	fired := false
+	c.timers = slices.DeleteFunc(c.timers, func(timer *fakeTimer) bool {
+		if timer.deadline.After(now) {
+			return false
+		}
+		timer.c <- now
+		fired = true
+		return true
+	})
+	if fired {
+		c.notify()
+	}
+}
+
+// notify wakes up the BlockUntil callers, it must be called with the mutex locked
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	index := slices.Index(t.clock.timers, t)
+	if index < 0 {
+		return false
+	}
+	t.clock.timers = slices.Delete(t.clock.timers, index, index+1)
+	t.clock.notify()
+	return true
+}
+
+var _ Clock = (*FakeClock)(nil)
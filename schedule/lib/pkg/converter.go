@@ -0,0 +1,18 @@
+package schedadpt
+
+import (
+	converter "github.com/smart-libs/go-crosscutting/converter/lib/pkg"
+	converterdefault "github.com/smart-libs/go-crosscutting/converter/lib/pkg/default"
+)
+
+var (
+	// ConverterRegistry it is the default converters registry for the schedule adapter
+	ConverterRegistry = converterdefault.NewRegistry()
+
+	// Converters is the list of converter.Converters used by the schedule adapter, it tries first the schedule
+	// adapter conversions and then the default converter.Converters
+	Converters = converter.NewConvertersList(
+		converterdefault.NewConverters(ConverterRegistry),
+		converterdefault.Converters,
+	)
+)
@@ -0,0 +1,262 @@
+package schedadpt
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+const (
+	descriptorEvery = "@every"
+
+	// cronTimezonePrefix sets the location of the expression, like in `CRON_TZ=Europe/Lisbon 0 9 * * MON-FRI`
+	cronTimezonePrefix = "CRON_TZ="
+
+	// cronSearchYears limits the search of the expressions that never match, like `0 0 30 2 *`
+	cronSearchYears = 5
+)
+
+type (
+	// cronSchedule has a bit set for each value of the fields that matches
+	cronSchedule struct {
+		expression                    string
+		minute, hour, dom, month, dow uint64
+		domRestricted, dowRestricted  bool
+		location                      *time.Location
+	}
+
+	cronField struct {
+		name     string
+		min, max int
+		names    map[string]int
+	}
+)
+
+var (
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// cronDow accepts 7 as Sunday, it is moved to 0 by parseCronFields
+	cronDow = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseCron parses the standard cron expression with the minute, hour, day of month, month and day of week fields.
+// The fields accept `*`, `?`, lists, ranges, steps and the month and day names. The `@yearly`, `@monthly`, `@weekly`,
+// `@daily`, `@hourly` and `@every <duration>` descriptors are accepted too. As in cron, a day matches if either the
+// day of month or the day of week matches when both are restricted, and a field starting with `*`, like `*/2`, is not
+// restricted.
+//
+// The expression is evaluated in the location of the time given to Next, unless it starts with CRON_TZ=<location>.
+func ParseCron(expression string) (Schedule, error) {
+	spec := strings.TrimSpace(expression)
+	var location *time.Location
+	if strings.HasPrefix(spec, cronTimezonePrefix) {
+		name, rest, _ := strings.Cut(spec[len(cronTimezonePrefix):], " ")
+		var err error
+		if location, err = time.LoadLocation(name); err != nil {
+			return nil, serror.IllegalArgumentValueWithCause("cron location", name, err)
+		}
+		spec = strings.TrimSpace(rest)
+	}
+
+	if strings.HasPrefix(spec, descriptorEvery+" ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(spec[len(descriptorEvery):]))
+		if err != nil {
+			return nil, serror.IllegalArgumentValueWithCause("cron", expression, err)
+		}
+		if interval <= 0 {
+			return nil, serror.IllegalArgumentValue("cron", expression)
+		}
+		return Every(interval), nil
+	}
+	if descriptor, found := cronDescriptors[strings.ToLower(spec)]; found {
+		spec = descriptor
+	}
+
+	schedule, err := parseCronFields(strings.Fields(spec))
+	if err != nil {
+		return nil, serror.IllegalArgumentValueWithCause("cron", expression, err)
+	}
+	schedule.expression = expression
+	schedule.location = location
+	return schedule, nil
+}
+
+// MustParseCron is like ParseCron but panics if the expression is invalid
+func MustParseCron(expression string) Schedule {
+	schedule, err := ParseCron(expression)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func parseCronFields(fields []string) (cronSchedule, error) {
+	if len(fields) != 5 {
+		return cronSchedule{}, serror.IllegalArgumentValue("fields", strconv.Itoa(len(fields)))
+	}
+	var schedule cronSchedule
+	var err error
+	for i, target := range []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{field: cronMinute, bits: &schedule.minute},
+		{field: cronHour, bits: &schedule.hour},
+		{field: cronDom, bits: &schedule.dom},
+		{field: cronMonth, bits: &schedule.month},
+		{field: cronDow, bits: &schedule.dow},
+	} {
+		if *target.bits, err = target.field.parse(fields[i]); err != nil {
+			return cronSchedule{}, err
+		}
+	}
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow = schedule.dow&^(1<<7) | 1
+	}
+	schedule.domRestricted = !isCronUnrestricted(fields[2])
+	schedule.dowRestricted = !isCronUnrestricted(fields[4])
+	return schedule, nil
+}
+
+// isCronUnrestricted tells if the day field does not restrict the days, like in Vixie cron it is the case of the
+// fields starting with `*`: `*/2` in the day of month matches the odd days of the days of week matched.
+func isCronUnrestricted(field string) bool {
+	return strings.HasPrefix(field, "*") || strings.HasPrefix(field, "?")
+}
+
+func isCronWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parse returns the bits of the values matched by the comma separated ranges
+func (f cronField) parse(value string) (uint64, error) {
+	var result uint64
+	for _, element := range strings.Split(value, ",") {
+		rangeValue, stepValue, hasStep := strings.Cut(element, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepValue); err != nil || step <= 0 {
+				return 0, serror.IllegalArgumentValue(f.name, element)
+			}
+		}
+
+		first, last := f.min, f.max
+		if !isCronWildcard(rangeValue) {
+			firstValue, lastValue, isRange := strings.Cut(rangeValue, "-")
+			var err error
+			if first, err = f.value(firstValue); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if last, err = f.value(lastValue); err != nil {
+					return 0, err
+				}
+			case !hasStep:
+				last = first
+			}
+			if first > last {
+				return 0, serror.IllegalArgumentValue(f.name, element)
+			}
+		}
+		for v := first; v <= last; v += step {
+			result |= 1 << uint(v)
+		}
+	}
+	return result, nil
+}
+
+func (f cronField) value(value string) (int, error) {
+	if number, found := f.names[strings.ToLower(value)]; found {
+		return number, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < f.min || number > f.max {
+		return 0, serror.IllegalArgumentValue(f.name, value)
+	}
+	return number, nil
+}
+
+// Next searches the first minute after the given time that matches the fields, moving the month, day, hour and minute
+// forward until all of them match. The times are created with time.Date, so the hours skipped by a daylight saving
+// change are not matched and the repeated ones are matched only once.
+func (s cronSchedule) Next(after time.Time) time.Time {
+	location := after.Location()
+	if s.location != nil {
+		location = s.location
+	}
+	t := after.In(location)
+	t = s.forward(after, time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, location))
+	yearLimit := t.Year() + cronSearchYears
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+	for s.month&(1<<uint(t.Month())) == 0 {
+		t = s.forward(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location))
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+	for !s.dayMatches(t) {
+		t = s.forward(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location))
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		t = s.forward(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location))
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		t = s.forward(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, location))
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+	return t
+}
+
+// forward returns next, unless the daylight saving change made it go back, then it returns the minute after from
+func (s cronSchedule) forward(from, next time.Time) time.Time {
+	if next.After(from) {
+		return next
+	}
+	return from.Truncate(time.Minute).Add(time.Minute)
+}
+
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	domMatches := s.dom&(1<<uint(t.Day())) != 0
+	dowMatches := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domMatches || dowMatches
+	}
+	return domMatches && dowMatches
+}
+
+func (s cronSchedule) String() string {
+	return s.expression
+}
@@ -0,0 +1,82 @@
+package schedadpt
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func Test_ParseCron_Next(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	inNewYork := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, newYork)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		expression string
+		after      time.Time
+		expected   time.Time
+	}{
+		{expression: "* * * * *", after: utc("2026-03-10 08:59").Add(30 * time.Second), expected: utc("2026-03-10 09:00")},
+		{expression: "*/15 * * * *", after: utc("2026-03-10 08:59"), expected: utc("2026-03-10 09:00")},
+		{expression: "*/15 * * * *", after: utc("2026-03-10 09:00"), expected: utc("2026-03-10 09:15")},
+		{expression: "5/20 9-10 * * *", after: utc("2026-03-10 09:45"), expected: utc("2026-03-10 10:05")},
+		{expression: "0 9 * * MON-FRI", after: utc("2026-03-13 09:00"), expected: utc("2026-03-16 09:00")},
+		{expression: "0 0 * * 7", after: utc("2026-03-10 00:00"), expected: utc("2026-03-15 00:00")},
+		{expression: "0 0 1,15 * *", after: utc("2026-03-10 00:00"), expected: utc("2026-03-15 00:00")},
+		{expression: "30 12 31 * *", after: utc("2026-04-01 00:00"), expected: utc("2026-05-31 12:30")},
+		{expression: "0 0 29 feb *", after: utc("2026-03-01 00:00"), expected: utc("2028-02-29 00:00")},
+		// the day matches if either the day of month or the day of week matches
+		{expression: "0 0 13 * FRI", after: utc("2026-03-10 00:00"), expected: utc("2026-03-13 00:00")},
+		{expression: "0 0 20 * FRI", after: utc("2026-03-13 00:00"), expected: utc("2026-03-20 00:00")},
+		// the fields starting with * are not restricted, both have to match
+		{expression: "0 0 */2 * MON", after: utc("2026-03-10 00:00"), expected: utc("2026-03-23 00:00")},
+		{expression: "0 0 13 * */2", after: utc("2026-03-10 00:00"), expected: utc("2026-06-13 00:00")},
+		{expression: "@daily", after: utc("2026-12-31 10:00"), expected: utc("2027-01-01 00:00")},
+		{expression: "@every 90m", after: utc("2026-03-10 10:00"), expected: utc("2026-03-10 11:30")},
+		{expression: "0 0 30 2 *", after: utc("2026-03-10 00:00"), expected: time.Time{}},
+
+		// the time location is used, unless CRON_TZ is given
+		{expression: "0 9 * * *", after: inNewYork("2026-03-10 08:00"), expected: inNewYork("2026-03-10 09:00")},
+		{expression: "CRON_TZ=America/New_York 0 9 * * *", after: utc("2026-03-10 12:00"), expected: utc("2026-03-10 13:00")},
+
+		// the hour skipped by the daylight saving change is not matched, the repeated one is matched once
+		{expression: "30 2 * * *", after: inNewYork("2026-03-07 03:00"), expected: inNewYork("2026-03-09 02:30")},
+		{expression: "30 1 * * *", after: inNewYork("2026-11-01 01:30"), expected: inNewYork("2026-11-02 01:30")},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.expression)
+		if err != nil {
+			t.Errorf("ParseCron(%q) error = %v", tt.expression, err)
+			continue
+		}
+		if got := schedule.Next(tt.after); !got.Equal(tt.expected) {
+			t.Errorf("ParseCron(%q).Next(%v) = %v, want %v", tt.expression, tt.after, got, tt.expected)
+		}
+	}
+}
+
+func Test_ParseCron_Invalid(t *testing.T) {
+	for _, expression := range []string{
+		"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"*/0 * * * *", "10-5 * * * *", "a * * * *", "@every", "@every -1m", "CRON_TZ=Nowhere/City * * * * *",
+	} {
+		if _, err := ParseCron(expression); err == nil {
+			t.Errorf("ParseCron(%q) error = nil, want an error", expression)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package schedadpt
+
+import (
+	"time"
+)
+
+type (
+	// Input is the input of the scheduled job handlers
+	Input struct {
+		// Job is the Binding name
+		Job string
+		// ScheduledAt is the time the run was scheduled to, in the Binding location
+		ScheduledAt time.Time
+		// FiredAt is the time the run actually fired, it is after ScheduledAt by the jitter and the timer delay. A run
+		// queued by OverlapQueue fires when it starts, after the previous one.
+		FiredAt time.Time
+		// Run is the run number since the adapter started, 1 on the first run. The handler errors do not retry the
+		// run, the next one has the next number.
+		Run int
+	}
+
+	// Result is the output of the scheduled job handlers, it is set from the handler error by OutErrorParamSpec
+	Result struct {
+		// Err is the handler error
+		Err error
+	}
+)
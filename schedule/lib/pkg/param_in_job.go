@@ -0,0 +1,23 @@
+package schedadpt
+
+const (
+	// TagJob gets the name of the job
+	TagJob = "job"
+	// TagScheduled gets the time the run was scheduled to
+	TagScheduled = "scheduled"
+	// TagFired gets the time the run actually fired
+	TagFired = "fired"
+	// TagRun gets the run number, 1 on the first run
+	TagRun = "run"
+	// TagAttempt is an alias of TagRun
+	TagAttempt = "attempt"
+)
+
+func init() {
+	getInputParamSpecFactoryRegistry().
+		AddOption1(TagJob, "", func(input Input) (any, error) { return input.Job, nil }).
+		AddOption1(TagScheduled, "", func(input Input) (any, error) { return input.ScheduledAt, nil }).
+		AddOption1(TagFired, "", func(input Input) (any, error) { return input.FiredAt, nil }).
+		AddOption1(TagRun, "", func(input Input) (any, error) { return input.Run, nil }).
+		AddOption1(TagAttempt, "", func(input Input) (any, error) { return input.Run, nil })
+}
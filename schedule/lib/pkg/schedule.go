@@ -0,0 +1,35 @@
+package schedadpt
+
+import (
+	"time"
+)
+
+type (
+	// Schedule gives the times a job fires
+	Schedule interface {
+		// Next returns the first fire time after the given one, in its location. The zero time means the job does
+		// not fire anymore.
+		Next(after time.Time) time.Time
+	}
+
+	intervalSchedule struct {
+		interval time.Duration
+	}
+)
+
+// Every creates the Schedule that fires at fixed intervals, the first time one interval after the adapter starts.
+// The job never fires if the interval is not positive.
+func Every(interval time.Duration) Schedule {
+	return intervalSchedule{interval: interval}
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	if s.interval <= 0 {
+		return time.Time{}
+	}
+	return after.Add(s.interval)
+}
+
+func (s intervalSchedule) String() string {
+	return descriptorEvery + " " + s.interval.String()
+}
@@ -6,6 +6,9 @@ require (
 	github.com/smart-libs/go-adapter/interfaces v0.0.1
 	github.com/smart-libs/go-crosscutting/assertions/lib v0.0.6
 	github.com/smart-libs/go-crosscutting/converter/lib v0.0.2
+	github.com/smart-libs/go-crosscutting/serror/lib v0.0.2
 )
 
+require github.com/joomcode/errorx v1.2.0 // indirect
+
 replace github.com/smart-libs/go-adapter/interfaces => ../../interfaces
@@ -0,0 +1,51 @@
+package sdkconverter
+
+import (
+	"encoding/json"
+	"reflect"
+
+	convertererror "github.com/smart-libs/go-crosscutting/converter/lib/pkg/error"
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	// JSONConverters unmarshals the json.RawMessage returned by the mime-type:"application/json" option, or given by
+	// the adapter, into any target type. It is needed because the converter registries hold conversion functions for
+	// specific types only. The []byte and json.RawMessage targets are left to the next converters of the list.
+	JSONConverters struct {
+		// Name is the name of the illegal argument returned when the JSON does not fit the target type
+		Name string
+	}
+)
+
+var (
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+	bytesType      = reflect.TypeFor[[]byte]()
+)
+
+func (c JSONConverters) Convert(from any, to any) error {
+	raw, ok := from.(json.RawMessage)
+	toValue := reflect.ValueOf(to)
+	if !ok || toValue.Kind() != reflect.Pointer || toValue.IsNil() || isBytesType(toValue.Type().Elem()) {
+		return convertererror.NewConversionNotFoundError(from, to)
+	}
+	if err := json.Unmarshal(raw, to); err != nil {
+		return serror.IllegalArgumentValueWithCause(c.Name, string(raw), err)
+	}
+	return nil
+}
+
+func (c JSONConverters) ConvertToType(from any, toType reflect.Type) (any, error) {
+	if _, ok := from.(json.RawMessage); !ok || isBytesType(toType) {
+		return nil, convertererror.NewConversionNotFoundError(from, toType)
+	}
+	target := reflect.New(toType)
+	if err := c.Convert(from, target.Interface()); err != nil {
+		return nil, err
+	}
+	return target.Elem().Interface(), nil
+}
+
+func isBytesType(valueType reflect.Type) bool {
+	return valueType == rawMessageType || valueType == bytesType
+}
@@ -0,0 +1,45 @@
+package sdkerror
+
+import (
+	"sync"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+type (
+	// Mapping maps the errors that satisfy Condition to Value
+	Mapping[T any] struct {
+		Condition func(err error) bool
+		Value     T
+	}
+
+	// Registry holds the mappings of the errors registered by the app, like the codes of the gRPC and GraphQL adapters
+	// or the actions of the messaging adapter. It is safe for concurrent use.
+	Registry[T any] struct {
+		locker   sync.RWMutex
+		mappings []Mapping[T]
+	}
+)
+
+// Register adds the mapping of the errors that satisfy the condition to the value
+func (r *Registry[T]) Register(condition func(err error) bool, value T) {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	r.mappings = append(r.mappings, Mapping[T]{Condition: condition, Value: value})
+}
+
+// Callbacks returns the serror.CallbackCondition of the mappings in the registration order, each one gives its value
+// to set. The extra capacity is for the default conditions the adapter appends after the registered ones.
+func (r *Registry[T]) Callbacks(set func(value T), extra int) []serror.CallbackCondition {
+	r.locker.RLock()
+	defer r.locker.RUnlock()
+	callbacks := make([]serror.CallbackCondition, 0, len(r.mappings)+extra)
+	for _, mapping := range r.mappings {
+		value := mapping.Value
+		callbacks = append(callbacks, serror.CallbackCondition{
+			Condition: mapping.Condition,
+			Callback:  func(error) { set(value) },
+		})
+	}
+	return callbacks
+}
@@ -0,0 +1,23 @@
+package sdkhandler
+
+import (
+	"context"
+	"fmt"
+
+	serror "github.com/smart-libs/go-crosscutting/serror/lib/pkg"
+)
+
+// Invoke invokes the handler and returns its error or the panic, the panic args that are not errors are returned as
+// internal errors. The adapters use it so that a panicking use case fails only the input it was handling.
+func Invoke[Input any, Output any](ctx context.Context, handler Handler[Input, Output], input Input, output Output) (err error) {
+	defer func() {
+		if panicArg := recover(); panicArg != nil {
+			panicErr, ok := panicArg.(error)
+			if !ok {
+				panicErr = serror.WrapAsInternalError(fmt.Errorf("%v", panicArg))
+			}
+			err = panicErr
+		}
+	}()
+	return handler.Invoke(ctx, input, output)
+}
@@ -43,7 +43,7 @@ func (i defaultInputParam[Input]) CopyValue(input Input, target any) error {
 func (i defaultInputParam[Input]) GetValue(input Input) (any, error) {
 	inputValue, err := i.getValueFunc(input)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get param=[%s] value from input=[%T]", i.Spec.Name(), input)
+		return nil, fmt.Errorf("failed to get param=[%s] value from input=[%T]: %w", i.Spec.Name(), input, err)
 	}
 	return AsSingleOptions(i.Spec.Options()...)(i.Spec, inputValue)
 }
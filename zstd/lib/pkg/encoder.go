@@ -0,0 +1,27 @@
+package zstdadpt
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+)
+
+const (
+	// EncodingZstd is the name of the zstd content coding
+	EncodingZstd = "zstd"
+
+	// maxWindowSize is the 8MB window limit that RFC 8878 recommends for the HTTP content coding
+	maxWindowSize = 8 << 20
+)
+
+// NewEncoder creates the zstd encoder to be added to the httpadpt.CompressionConfig.Encoders
+func NewEncoder(level zstd.EncoderLevel) httpadpt.CompressionEncoder {
+	return httpadpt.CompressionEncoder{
+		Encoding: EncodingZstd,
+		NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(w, zstd.WithEncoderLevel(level), zstd.WithWindowSize(maxWindowSize),
+				zstd.WithEncoderConcurrency(1))
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package zstdadpt
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	httpadpt "github.com/smart-libs/go-adapter/http/lib/pkg"
+)
+
+type (
+	testRequest struct {
+		httpadpt.Request
+		header testHeader
+	}
+	testHeader map[string][]string
+)
+
+func (r testRequest) Header() httpadpt.HeaderParams { return r.header }
+
+func (h testHeader) GetValue(name string) ([]string, bool) {
+	values, found := h[name]
+	return values, found
+}
+
+func TestNewEncoder(t *testing.T) {
+	body := []byte(strings.Repeat("zstd compressed body ", 200))
+	middleware := httpadpt.NewCompressionMiddleware(httpadpt.CompressionConfig{
+		Encoders: []httpadpt.CompressionEncoder{NewEncoder(zstd.SpeedDefault), httpadpt.GzipEncoder(-1)},
+	})
+	handler := middleware(httpadpt.MakeHandler(func(_ context.Context, _ httpadpt.Request, output *httpadpt.Response) error {
+		output.Header = map[string][]string{"Content-Type": {"text/plain"}}
+		output.Body = body
+		return nil
+	}))
+
+	output := &httpadpt.Response{}
+	input := testRequest{header: testHeader{httpadpt.HeaderAcceptEncoding: {"gzip, zstd"}}}
+	if err := handler.Invoke(context.Background(), input, output); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if got := output.Header[httpadpt.HeaderContentEncoding]; len(got) != 1 || got[0] != EncodingZstd {
+		t.Fatalf("Content-Encoding = %v, want zstd", got)
+	}
+
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer decoder.Close()
+	decoded, err := decoder.DecodeAll(output.Body, nil)
+	if err != nil || !bytes.Equal(decoded, body) {
+		t.Errorf("decoded body differs, err = %v", err)
+	}
+}